@@ -0,0 +1,123 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client provides the helpers to build on Capsule programmatically with the controller-runtime client: it
+// belongs to the API module, separate from the operator one, and its functions are kept stable.
+package client
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// DefaultConfigurationName is the name of the CapsuleConfiguration used by Capsule, unless set otherwise with the
+// --configuration-name flag.
+const DefaultConfigurationName = "default"
+
+// AddToScheme adds the Capsule API types to the given scheme.
+func AddToScheme(scheme *runtime.Scheme) error {
+	if err := capsulev1alpha1.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	return capsulev1beta1.AddToScheme(scheme)
+}
+
+// New returns a client for the given configuration serving both the Kubernetes and the Capsule API types.
+func New(config *rest.Config) (client.Client, error) {
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+// TenantForNamespace returns the Tenant the given Namespace belongs to, or nil if it doesn't belong to any Tenant.
+func TenantForNamespace(ctx context.Context, c client.Reader, namespace string) (*capsulev1beta1.Tenant, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return nil, err
+	}
+
+	for _, ref := range ns.GetOwnerReferences() {
+		if ref.APIVersion != capsulev1beta1.GroupVersion.String() || ref.Kind != "Tenant" {
+			continue
+		}
+
+		tnt := &capsulev1beta1.Tenant{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, tnt); err != nil {
+			return nil, err
+		}
+
+		return tnt, nil
+	}
+
+	return nil, nil
+}
+
+// TenantsOwnedBy returns the Tenants owned by the given user, or by any of the given groups: the ServiceAccounts are
+// identified by their user name, as system:serviceaccount:<namespace>:<name>.
+func TenantsOwnedBy(ctx context.Context, c client.Reader, user string, groups ...string) ([]capsulev1beta1.Tenant, error) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList); err != nil {
+		return nil, err
+	}
+
+	owned := make([]capsulev1beta1.Tenant, 0, len(tntList.Items))
+
+	for _, tnt := range tntList.Items {
+		if isOwner(tnt.Spec.Owners, user, groups) {
+			owned = append(owned, tnt)
+		}
+	}
+
+	return owned, nil
+}
+
+// Configuration returns the CapsuleConfiguration with the given name, the default one when empty.
+func Configuration(ctx context.Context, c client.Reader, name string) (*capsulev1alpha1.CapsuleConfiguration, error) {
+	if len(name) == 0 {
+		name = DefaultConfigurationName
+	}
+
+	cfg := &capsulev1alpha1.CapsuleConfiguration{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func isOwner(owners capsulev1beta1.OwnerListSpec, user string, groups []string) bool {
+	for _, owner := range owners {
+		switch owner.Kind {
+		case capsulev1beta1.UserOwner, capsulev1beta1.ServiceAccountOwner:
+			if owner.Name == user {
+				return true
+			}
+		case capsulev1beta1.GroupOwner:
+			for _, group := range groups {
+				if owner.Name == group {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
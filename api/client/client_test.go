@@ -0,0 +1,77 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestTenantForNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-production", OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: capsulev1beta1.GroupVersion.String(),
+			Kind:       "Tenant",
+			Name:       "oil",
+		}}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	).Build()
+
+	tnt, err := TenantForNamespace(context.Background(), c, "oil-production")
+	if assert.NoError(t, err) && assert.NotNil(t, tnt) {
+		assert.Equal(t, "oil", tnt.GetName())
+	}
+
+	tnt, err = TenantForNamespace(context.Background(), c, "kube-system")
+	assert.NoError(t, err)
+	assert.Nil(t, tnt)
+
+	_, err = TenantForNamespace(context.Background(), c, "missing")
+	assert.Error(t, err)
+}
+
+func TestTenantsOwnedBy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, AddToScheme(scheme))
+
+	tenant := func(name string, owners ...capsulev1beta1.OwnerSpec) *capsulev1beta1.Tenant {
+		return &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: capsulev1beta1.TenantSpec{Owners: owners}}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		tenant("oil", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: "alice"}),
+		tenant("gas", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.GroupOwner, Name: "gas-admins"}),
+		tenant("water", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.ServiceAccountOwner, Name: "system:serviceaccount:ci:deployer"}),
+	).Build()
+
+	names := func(user string, groups ...string) (names []string) {
+		tenants, err := TenantsOwnedBy(context.Background(), c, user, groups...)
+		assert.NoError(t, err)
+
+		for _, tnt := range tenants {
+			names = append(names, tnt.GetName())
+		}
+
+		return names
+	}
+
+	assert.Equal(t, []string{"oil"}, names("alice"))
+	assert.Equal(t, []string{"gas", "oil"}, names("alice", "gas-admins"))
+	assert.Equal(t, []string{"water"}, names("system:serviceaccount:ci:deployer"))
+	assert.Empty(t, names("bob"))
+}
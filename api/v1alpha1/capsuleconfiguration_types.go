@@ -5,6 +5,8 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
 )
 
 // CapsuleConfigurationSpec defines the Capsule configuration
@@ -18,6 +20,52 @@ type CapsuleConfigurationSpec struct {
 	ForceTenantPrefix bool `json:"forceTenantPrefix,omitempty"`
 	// Disallow creation of namespaces, whose name matches this regexp
 	ProtectedNamespaceRegexpString string `json:"protectedNamespaceRegex,omitempty"`
+	// Rules protecting the system and platform Namespaces from being created by the Tenant owners,
+	// evaluated along with the protectedNamespaceRegex one.
+	ProtectedNamespaces []ProtectedNamespaceSpec `json:"protectedNamespaces,omitempty"`
+	// Identities bypassing the Capsule webhooks, as the backup controllers or the CI deployers acting on behalf
+	// of the cluster administrators.
+	Exemptions *ExemptionsSpec `json:"exemptions,omitempty"`
+	// Keys of the Node taints the Tenant owners and the Node identities cannot add, change or remove, along with the
+	// ones matching the labels of the Tenants node selector.
+	ProtectedNodeTaints []string `json:"protectedNodeTaints,omitempty"`
+	// Values filled in the Tenants created without them, so that minimal Tenant manifests are usable and the
+	// defaults are visible in the stored objects.
+	TenantDefaults *TenantDefaultsSpec `json:"tenantDefaults,omitempty"`
+	// Names of the groups whose members can approve the TenantRequests: when empty, the TenantRequests cannot be
+	// approved.
+	TenantRequestApproverGroups []string `json:"tenantRequestApproverGroups,omitempty"`
+	// Levels of the Capsule logs, adjustable at runtime for the whole manager, for the single controllers and webhooks,
+	// or for the single Tenants, so that debugging a Tenant doesn't require the debug logs of the whole cluster.
+	Logging *LoggingSpec `json:"logging,omitempty"`
+}
+
+type TenantDefaultsSpec struct {
+	// Maximum number of Namespaces of the Tenants not specifying it.
+	// +kubebuilder:validation:Minimum=1
+	NamespaceQuota *int32 `json:"namespaceQuota,omitempty"`
+	// ResourceQuotas assigned to the Tenants not specifying any.
+	ResourceQuotas *capsulev1beta1.ResourceQuotaSpec `json:"resourceQuotas,omitempty"`
+	// NetworkPolicies assigned to the Tenants not specifying any.
+	NetworkPolicies *capsulev1beta1.NetworkPolicySpec `json:"networkPolicies,omitempty"`
+	// PriorityClasses allowed to the Tenants not specifying them.
+	PriorityClasses *capsulev1beta1.AllowedListSpec `json:"priorityClasses,omitempty"`
+}
+
+type ProtectedNamespaceSpec struct {
+	// Regular expression matching the names of the protected Namespaces.
+	Regex string `json:"regex"`
+	// Regular expressions matching the names of the Namespaces excepted from the rule.
+	Exceptions []string `json:"exceptions,omitempty"`
+}
+
+type ExemptionsSpec struct {
+	// Names of the users bypassing the Capsule webhooks.
+	Users []string `json:"users,omitempty"`
+	// Names of the groups whose members bypass the Capsule webhooks.
+	Groups []string `json:"groups,omitempty"`
+	// ServiceAccounts bypassing the Capsule webhooks, in the <namespace>:<name> form.
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,19 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// LogLevel is one of error, info, debug or a verbosity from 1 to 10.
+// +kubebuilder:validation:Pattern="^(error|info|debug|[1-9]|10)$"
+type LogLevel string
+
+type LoggingSpec struct {
+	// Level of the Capsule logs, overriding the one set by the --zap-log-level flag.
+	Level LogLevel `json:"level,omitempty"`
+	// Levels of the named loggers, as controllers.Tenant or webhooks, applied to their children loggers too:
+	// the most specific name wins.
+	Loggers map[string]LogLevel `json:"loggers,omitempty"`
+	// Levels of the logs referring to the given Tenants, as their reconciliations and the admission requests of their
+	// Namespaces, taking precedence over the loggers ones.
+	Tenants map[string]LogLevel `json:"tenants,omitempty"`
+}
@@ -5,12 +5,13 @@ package v1alpha1
 
 import (
 	"io/ioutil"
+	"path/filepath"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 func (t *Tenant) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	certData, _ := ioutil.ReadFile("/tmp/k8s-webhook-server/serving-certs/tls.crt")
+	certData, _ := ioutil.ReadFile(filepath.Join(mgr.GetWebhookServer().CertDir, "tls.crt"))
 	if len(certData) == 0 {
 		return nil
 	}
@@ -0,0 +1,17 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AccessBundleSpec struct {
+	// The Tenant Namespace targeted by the kubeconfig files of the owners. Required.
+	// +kubebuilder:validation:MinLength=1
+	HomeNamespace string `json:"homeNamespace"`
+	// The requested validity of the minted credentials, renewed once two thirds of it elapsed: the signer of the
+	// cluster could issue shorter ones. Optional, defaults to 24h.
+	Validity *metav1.Duration `json:"validity,omitempty"`
+}
@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+type APIPriorityAndFairnessSpec struct {
+	// The name of an existing PriorityLevelConfiguration the Tenant requests are assigned to. If not specified, Capsule creates a dedicated one. Optional.
+	PriorityLevel string `json:"priorityLevel,omitempty"`
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// The concurrency shares of the dedicated PriorityLevelConfiguration, ignored when an existing priority level is assigned. Optional.
+	AssuredConcurrencyShares int32 `json:"assuredConcurrencyShares,omitempty"`
+	// +kubebuilder:default=5000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// The matching precedence of the FlowSchema: the lower, the sooner it's evaluated by the API server. Optional.
+	MatchingPrecedence int32 `json:"matchingPrecedence,omitempty"`
+}
@@ -0,0 +1,16 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type BackupSpec struct {
+	// The Cron expression defining when the backups of the Tenant Namespaces are taken, such as "0 1 * * *". Required.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// The amount of time the backups are retained before being garbage collected by Velero. Optional, defaults to the Velero server one.
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
@@ -0,0 +1,38 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// QueueNameLabel is the label selecting the Kueue LocalQueue a Job is submitted to.
+const QueueNameLabel = "kueue.x-k8s.io/queue-name"
+
+type BatchQueueingSpec struct {
+	// The name of the LocalQueue created in each Tenant Namespace, and set on the Tenant Jobs not declaring a queue.
+	// Optional, defaults to the Tenant name.
+	LocalQueueName string `json:"localQueueName,omitempty"`
+	// The cohort of the Tenant ClusterQueue: the ClusterQueues of the same cohort borrow the unused quota of each other. Optional.
+	Cohort string `json:"cohort,omitempty"`
+	// +kubebuilder:default=default-flavor
+	// The ResourceFlavor the quota of the Tenant ClusterQueue is assigned to. Optional.
+	ResourceFlavor string `json:"resourceFlavor,omitempty"`
+	// The nominal quota of the Tenant ClusterQueue, defaulting to the CPU, memory and extended resources requests
+	// allowed by the Tenant ResourceQuotas. Optional.
+	NominalQuota corev1.ResourceList `json:"nominalQuota,omitempty"`
+}
+
+// QueueName returns the name of the LocalQueue of the Tenant, if the Tenant declares the batch queueing.
+func (t *Tenant) QueueName() string {
+	if t.Spec.BatchQueueing == nil {
+		return ""
+	}
+
+	if name := t.Spec.BatchQueueing.LocalQueueName; len(name) > 0 {
+		return name
+	}
+
+	return t.GetName()
+}
@@ -0,0 +1,13 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+type CapacityHintsSpec struct {
+	// The annotations set on the Tenant Pods, overriding the ones declared by the Pods,
+	// such as cluster-autoscaler.kubernetes.io/safe-to-evict. Optional.
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// The annotations set on the ResourceQuotas generated for the Tenant, as the ones read by the
+	// capacity planning tools. Optional.
+	ResourceQuotaAnnotations map[string]string `json:"resourceQuotaAnnotations,omitempty"`
+}
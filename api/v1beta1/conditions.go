@@ -0,0 +1,72 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types of the conditions reported in the Tenant status.
+const (
+	// TenantConditionReady reports if the last reconciliation of the Tenant succeeded, the objects generated in its
+	// Namespaces being in sync with its spec.
+	TenantConditionReady = "Ready"
+	// TenantConditionCordoned reports if the Tenant is cordoned, its owners not being allowed to change the objects of
+	// its Namespaces.
+	TenantConditionCordoned = "Cordoned"
+)
+
+// The reasons of the conditions reported in the Tenant status.
+const (
+	TenantReasonReconciled           = "Reconciled"
+	TenantReasonReconciliationFailed = "ReconciliationFailed"
+	TenantReasonCordoned             = "Cordoned"
+	TenantReasonActive               = "Active"
+)
+
+// IsReady reports if the Tenant has been reconciled successfully with its current spec.
+func (t *Tenant) IsReady() bool {
+	condition := meta.FindStatusCondition(t.Status.Conditions, TenantConditionReady)
+
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == t.GetGeneration()
+}
+
+// SetReadyCondition sets the Ready condition of the Tenant according to the given reconciliation error, if any.
+func (t *Tenant) SetReadyCondition(err error) {
+	condition := metav1.Condition{
+		Type:               TenantConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: t.GetGeneration(),
+		Reason:             TenantReasonReconciled,
+		Message:            "The Tenant has been reconciled",
+	}
+
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = TenantReasonReconciliationFailed
+		condition.Message = err.Error()
+	}
+
+	meta.SetStatusCondition(&t.Status.Conditions, condition)
+}
+
+// SetCordonedCondition sets the Cordoned condition of the Tenant according to its spec.
+func (t *Tenant) SetCordonedCondition() {
+	condition := metav1.Condition{
+		Type:               TenantConditionCordoned,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: t.GetGeneration(),
+		Reason:             TenantReasonActive,
+		Message:            "The Tenant owners can change the objects of its Namespaces",
+	}
+
+	if t.IsCordoned() {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = TenantReasonCordoned
+		condition.Message = "The Tenant owners cannot change the objects of its Namespaces"
+	}
+
+	meta.SetStatusCondition(&t.Status.Conditions, condition)
+}
@@ -0,0 +1,36 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTenant_Conditions(t *testing.T) {
+	tnt := &Tenant{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+
+	assert.False(t, tnt.IsReady())
+
+	tnt.SetReadyCondition(errors.New("cannot sync ResourceQuota items"))
+	assert.False(t, tnt.IsReady())
+	assert.Equal(t, TenantReasonReconciliationFailed, meta.FindStatusCondition(tnt.Status.Conditions, TenantConditionReady).Reason)
+
+	tnt.SetReadyCondition(nil)
+	assert.True(t, tnt.IsReady())
+	// the changed spec is not reconciled yet
+	tnt.SetGeneration(3)
+	assert.False(t, tnt.IsReady())
+
+	tnt.SetCordonedCondition()
+	assert.True(t, meta.IsStatusConditionFalse(tnt.Status.Conditions, TenantConditionCordoned))
+
+	tnt.SetLabels(map[string]string{"capsule.clastix.io/cordon": "enabled"})
+	tnt.SetCordonedCondition()
+	assert.True(t, meta.IsStatusConditionTrue(tnt.Status.Conditions, TenantConditionCordoned))
+}
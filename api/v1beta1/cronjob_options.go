@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+type CronJobOptions struct {
+	// +kubebuilder:validation:Minimum=0
+	// Maximum number of Jobs spawned by the CronJobs that can be active at the same time in the Tenant. Optional.
+	MaxConcurrentJobs *int32 `json:"maxConcurrentJobs,omitempty"`
+	// Specifies the schedules the CronJobs cannot use, such as the ones running every minute. Optional.
+	ForbiddenSchedules *ForbiddenListSpec `json:"forbiddenSchedules,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Maximum number of successful and failed finished Jobs retained by the CronJobs, higher values are lowered to this one. Optional.
+	MaxHistoryLimit *int32 `json:"maxHistoryLimit,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// Maximum duration in seconds of the Jobs spawned by the CronJobs: it's applied to the CronJobs not specifying the activeDeadlineSeconds, while the higher values are denied. Optional.
+	MaxActiveDeadlineSeconds *int64 `json:"maxActiveDeadlineSeconds,omitempty"`
+}
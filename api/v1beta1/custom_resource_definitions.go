@@ -0,0 +1,35 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"strings"
+)
+
+type CustomResourceDefinitionsSpec struct {
+	// The API groups the Tenant owners can install CustomResourceDefinitions for, along with their subdomains,
+	// e.g. team-x.example.com allows both team-x.example.com and ci.team-x.example.com.
+	// +kubebuilder:validation:MinItems=1
+	AllowedGroups []string `json:"allowedGroups"`
+}
+
+// AllowsGroup returns true when the given API group is one of the allowed groups or a subdomain of them.
+func (in CustomResourceDefinitionsSpec) AllowsGroup(group string) bool {
+	for _, allowed := range in.AllowedGroups {
+		if GroupOverlaps(allowed, group) && len(group) >= len(allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GroupOverlaps returns true when the given API groups are the same, or one is a subdomain of the other.
+func GroupOverlaps(a, b string) bool {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	return a == b || strings.HasSuffix(a, "."+b)
+}
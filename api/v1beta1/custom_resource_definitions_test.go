@@ -0,0 +1,33 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomResourceDefinitionsSpec_AllowsGroup(t *testing.T) {
+	spec := CustomResourceDefinitionsSpec{AllowedGroups: []string{"team-x.example.com"}}
+
+	for group, allowed := range map[string]bool{
+		"team-x.example.com":         true,
+		"ci.team-x.example.com":      true,
+		"example.com":                false,
+		"team-xy.example.com":        false,
+		"myteam-x.example.com":       false,
+		"team-x.example.com.evil.io": false,
+	} {
+		assert.Equal(t, allowed, spec.AllowsGroup(group), group)
+	}
+}
+
+func TestGroupOverlaps(t *testing.T) {
+	assert.True(t, GroupOverlaps("example.com", "team-x.example.com"))
+	assert.True(t, GroupOverlaps("team-x.example.com", "example.com"))
+	assert.True(t, GroupOverlaps("example.com", "example.com"))
+	assert.False(t, GroupOverlaps("team-x.example.com", "team-y.example.com"))
+	assert.False(t, GroupOverlaps("ample.com", "example.com"))
+}
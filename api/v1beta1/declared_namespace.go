@@ -0,0 +1,17 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// DeclaredNamespaceAdoptAnnotation lets a Tenant claim the declared Namespace it finds existing, rather than created
+// by Capsule: its value must be the Tenant name, set by the cluster administrators.
+const DeclaredNamespaceAdoptAnnotation = "capsule.clastix.io/adopt"
+
+type DeclaredNamespaceSpec struct {
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// Name of the Namespace created by Capsule and bound to the Tenant.
+	Name string `json:"name"`
+	// Specifies the labels and annotations of the Namespace, in addition to the ones inherited by the Tenant. Optional.
+	AdditionalMetadata *AdditionalMetadataSpec `json:"additionalMetadata,omitempty"`
+}
@@ -8,8 +8,18 @@ const (
 )
 
 func (t *Tenant) IsWildcardDenied() bool {
+	if allowed := t.Spec.IngressOptions.AllowWildcardHostnames; allowed != nil {
+		return !*allowed
+	}
 	if v, ok := t.Annotations[denyWildcard]; ok && v == "true" {
 		return true
 	}
 	return false
 }
+
+func (t *Tenant) IsApexDenied() bool {
+	if allowed := t.Spec.IngressOptions.AllowApexHostnames; allowed != nil {
+		return !*allowed
+	}
+	return false
+}
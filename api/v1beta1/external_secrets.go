@@ -0,0 +1,21 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+type ExternalSecretsSpec struct {
+	// Specifies the ClusterSecretStores the ExternalSecrets of the Tenant can reference, by default none. Optional.
+	AllowedClusterSecretStores *AllowedListSpec `json:"allowedClusterSecretStores,omitempty"`
+	// The prefix the remote keys and paths referenced by the ExternalSecrets of the Tenant must start with, by default the Tenant name followed by a slash. Optional.
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+}
+
+// ExternalSecretsKeyPrefix returns the prefix the remote keys referenced by the ExternalSecrets of the Tenant must start
+// with, the Tenant name followed by a slash unless a different one is specified.
+func (in *Tenant) ExternalSecretsKeyPrefix() string {
+	if in.Spec.ExternalSecrets != nil && len(in.Spec.ExternalSecrets.KeyPrefix) > 0 {
+		return in.Spec.ExternalSecrets.KeyPrefix
+	}
+
+	return in.GetName() + "/"
+}
@@ -0,0 +1,16 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type GarbageCollectionOptions struct {
+	// +kubebuilder:validation:Minimum=0
+	// Seconds the finished Jobs are retained before being deleted along with their Pods: it's applied to the Jobs not specifying the ttlSecondsAfterFinished, besides the ones spawned by the CronJobs, retained according to their history limits. Optional.
+	JobTTLSecondsAfterFinished *int32 `json:"jobTTLSecondsAfterFinished,omitempty"`
+	// Age the Succeeded and Failed Pods not belonging to a Job are deleted at, keeping etcd and the quotas healthy. Requires Capsule to be started with the --enable-pod-garbage-collection flag. Optional.
+	FinishedPodsMaxAge *metav1.Duration `json:"finishedPodsMaxAge,omitempty"`
+}
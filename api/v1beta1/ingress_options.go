@@ -21,4 +21,8 @@ type IngressOptions struct {
 	HostnameCollisionScope HostnameCollisionScope `json:"hostnameCollisionScope,omitempty"`
 	// Specifies the allowed hostnames in Ingresses for the given Tenant. Capsule assures that all Ingress resources created in the Tenant can use only one of the allowed hostnames. Optional.
 	AllowedHostnames *AllowedListSpec `json:"allowedHostnames,omitempty"`
+	// Toggles the wildcard hostnames, as *.bigorg.com, in the Ingress and Gateway API resources: a wildcard claimed by a Tenant shadows the specific hostnames of the other ones. If unset, they're allowed unless the capsule.clastix.io/deny-wildcard annotation is set. Optional.
+	AllowWildcardHostnames *bool `json:"allowWildcardHostnames,omitempty"`
+	// Toggles the zone apex hostnames, as bigorg.com, in the Ingress and Gateway API resources. If unset, they're allowed. Optional.
+	AllowApexHostnames *bool `json:"allowApexHostnames,omitempty"`
 }
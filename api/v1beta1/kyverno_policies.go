@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// +kubebuilder:validation:Enum=audit;enforce
+type KyvernoValidationFailureAction string
+
+const (
+	KyvernoValidationFailureActionAudit   KyvernoValidationFailureAction = "audit"
+	KyvernoValidationFailureActionEnforce KyvernoValidationFailureAction = "enforce"
+)
+
+type KyvernoPoliciesSpec struct {
+	// +kubebuilder:default=audit
+	// Defines how Kyverno handles the violations of the generated validation rules: "audit" only reports them in the PolicyReports, "enforce" blocks the offending requests. Capsule keeps enforcing its own rules regardless of this value. Optional.
+	ValidationFailureAction KyvernoValidationFailureAction `json:"validationFailureAction,omitempty"`
+}
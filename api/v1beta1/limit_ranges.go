@@ -5,6 +5,33 @@ package v1beta1
 
 import corev1 "k8s.io/api/core/v1"
 
+// LimitRangeProfileLabel is the Namespace label selecting the LimitRange profile to apply in place of the default items.
+const LimitRangeProfileLabel = "capsule.clastix.io/limit-range-profile"
+
 type LimitRangesSpec struct {
 	Items []corev1.LimitRangeSpec `json:"items,omitempty"`
+	// Named sets of LimitRange, applied to the Tenant Namespaces selecting them with the capsule.clastix.io/limit-range-profile
+	// label in place of the items. Namespaces without the label, or selecting a missing profile, get the items. Optional.
+	Profiles []LimitRangeProfile `json:"profiles,omitempty"`
+}
+
+type LimitRangeProfile struct {
+	// +kubebuilder:validation:MinLength=1
+	Name  string                  `json:"name"`
+	Items []corev1.LimitRangeSpec `json:"items,omitempty"`
+}
+
+// ItemsFor returns the LimitRange items of the given profile, falling back to the default ones.
+func (in LimitRangesSpec) ItemsFor(profile string) []corev1.LimitRangeSpec {
+	if len(profile) == 0 {
+		return in.Items
+	}
+
+	for _, p := range in.Profiles {
+		if p.Name == profile {
+			return p.Items
+		}
+	}
+
+	return in.Items
 }
@@ -0,0 +1,29 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLimitRangesSpec_ItemsFor(t *testing.T) {
+	defaults := []corev1.LimitRangeSpec{{Limits: []corev1.LimitRangeItem{{Type: corev1.LimitTypeContainer}}}}
+	large := []corev1.LimitRangeSpec{{Limits: []corev1.LimitRangeItem{{Type: corev1.LimitTypePod}}}}
+
+	spec := LimitRangesSpec{
+		Items: defaults,
+		Profiles: []LimitRangeProfile{
+			{Name: "large", Items: large},
+			{Name: "none"},
+		},
+	}
+
+	assert.Equal(t, defaults, spec.ItemsFor(""))
+	assert.Equal(t, defaults, spec.ItemsFor("missing"))
+	assert.Equal(t, large, spec.ItemsFor("large"))
+	assert.Empty(t, spec.ItemsFor("none"))
+}
@@ -0,0 +1,108 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+type Weekday string
+
+type MaintenanceWindow struct {
+	// +kubebuilder:validation:MinItems=1
+	// The days of the week the window opens at, such as Saturday.
+	Days []Weekday `json:"days"`
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// The UTC time the window opens at, in the HH:MM format.
+	Start string `json:"start"`
+	// The duration of the window, such as 2h.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// PendingChange is a disruptive change of an object managed by Capsule, deferred to the next maintenance window.
+type PendingChange struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Why the change is disruptive.
+	Reason string `json:"reason"`
+}
+
+// starts returns the times the window opens at from the day before the given time, for a week.
+func (in MaintenanceWindow) starts(t time.Time) (starts []time.Time) {
+	start, err := time.Parse("15:04", in.Start)
+	if err != nil {
+		return nil
+	}
+
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+	// the windows opened in the previous days can still be open, as the ones crossing midnight
+	for i := -int(in.Duration.Hours()/24) - 1; i <= 7; i++ {
+		current := day.AddDate(0, 0, i)
+
+		for _, weekday := range in.Days {
+			if string(weekday) == current.Weekday().String() {
+				starts = append(starts, current)
+
+				break
+			}
+		}
+	}
+
+	return starts
+}
+
+// Contains returns true if the window is open at the given time.
+func (in MaintenanceWindow) Contains(t time.Time) bool {
+	for _, start := range in.starts(t) {
+		if !t.Before(start) && t.Before(start.Add(in.Duration.Duration)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Next returns the first time the window opens at after the given time.
+func (in MaintenanceWindow) Next(t time.Time) (next time.Time) {
+	for _, start := range in.starts(t) {
+		if start.After(t) && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return next
+}
+
+// InMaintenanceWindow returns true if any maintenance window of the Tenant is open at the given time, or if the Tenant
+// doesn't declare any, applying the disruptive changes at any time.
+func (t *Tenant) InMaintenanceWindow(now time.Time) bool {
+	if len(t.Spec.MaintenanceWindows) == 0 {
+		return true
+	}
+
+	for _, window := range t.Spec.MaintenanceWindows {
+		if window.Contains(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextMaintenanceWindow returns the first time a maintenance window of the Tenant opens at after the given time, zero
+// if the Tenant doesn't declare any.
+func (t *Tenant) NextMaintenanceWindow(now time.Time) (next time.Time) {
+	for _, window := range t.Spec.MaintenanceWindows {
+		if start := window.Next(now); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return next
+}
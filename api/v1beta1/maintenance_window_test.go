@@ -0,0 +1,57 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMaintenanceWindow_Contains(t *testing.T) {
+	// Saturday 22:00 UTC for 4 hours, crossing midnight
+	window := MaintenanceWindow{Days: []Weekday{"Saturday"}, Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+
+	for at, expected := range map[string]bool{
+		"2021-09-04T21:59:00Z": false,
+		"2021-09-04T22:00:00Z": true,
+		"2021-09-05T01:59:00Z": true,
+		"2021-09-05T02:00:00Z": false,
+		"2021-09-06T23:00:00Z": false,
+	} {
+		now, _ := time.Parse(time.RFC3339, at)
+		assert.Equal(t, expected, window.Contains(now), at)
+	}
+}
+
+func TestMaintenanceWindow_Next(t *testing.T) {
+	window := MaintenanceWindow{Days: []Weekday{"Saturday", "Wednesday"}, Start: "02:30", Duration: metav1.Duration{Duration: time.Hour}}
+
+	now, _ := time.Parse(time.RFC3339, "2021-09-04T03:00:00Z")
+	expected, _ := time.Parse(time.RFC3339, "2021-09-08T02:30:00Z")
+	assert.Equal(t, expected, window.Next(now))
+
+	now, _ = time.Parse(time.RFC3339, "2021-09-08T03:00:00Z")
+	expected, _ = time.Parse(time.RFC3339, "2021-09-11T02:30:00Z")
+	assert.Equal(t, expected, window.Next(now))
+}
+
+func TestTenant_InMaintenanceWindow(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2021-09-06T10:00:00Z")
+
+	tnt := &Tenant{}
+	assert.True(t, tnt.InMaintenanceWindow(now))
+	assert.True(t, tnt.NextMaintenanceWindow(now).IsZero())
+
+	tnt.Spec.MaintenanceWindows = []MaintenanceWindow{
+		{Days: []Weekday{"Sunday"}, Start: "00:00", Duration: metav1.Duration{Duration: time.Hour}},
+		{Days: []Weekday{"Tuesday"}, Start: "23:00", Duration: metav1.Duration{Duration: time.Hour}},
+	}
+	assert.False(t, tnt.InMaintenanceWindow(now))
+
+	expected, _ := time.Parse(time.RFC3339, "2021-09-07T23:00:00Z")
+	assert.Equal(t, expected, tnt.NextMaintenanceWindow(now))
+}
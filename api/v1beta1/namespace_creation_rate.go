@@ -0,0 +1,84 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NamespaceCreationRate struct {
+	// +kubebuilder:validation:Minimum=1
+	// The maximum number of Namespaces created in the Tenant within the period.
+	Limit int32 `json:"limit"`
+	// The period the Namespace creations are counted over, such as 1h.
+	Period metav1.Duration `json:"period"`
+}
+
+type NamespaceCreation struct {
+	// The name of the created Namespace.
+	Name string `json:"name"`
+	// The time the Namespace has been created at.
+	Time metav1.Time `json:"time"`
+}
+
+// NamespaceCreationRate returns the Namespace creation rate of the Tenant, if limited.
+func (t *Tenant) NamespaceCreationRate() *NamespaceCreationRate {
+	if t.Spec.NamespaceOptions == nil {
+		return nil
+	}
+
+	return t.Spec.NamespaceOptions.CreationRate
+}
+
+// RecentNamespaceCreations returns the Namespace creations of the Tenant within the period of its creation rate ending
+// at the given time, from the oldest one: the ones reported in the status, including the deleted Namespaces, are
+// merged with the given Tenant Namespaces, the status lagging behind the admission.
+func (t *Tenant) RecentNamespaceCreations(namespaces []corev1.Namespace, now time.Time) (creations []NamespaceCreation) {
+	rate := t.NamespaceCreationRate()
+	if rate == nil {
+		return nil
+	}
+
+	since := now.Add(-rate.Period.Duration)
+
+	seen := map[NamespaceCreation]struct{}{}
+
+	add := func(creation NamespaceCreation) {
+		if !creation.Time.Time.After(since) {
+			return
+		}
+		// the creation times are compared as serialized, with the seconds precision
+		creation.Time = metav1.NewTime(creation.Time.Time.Truncate(time.Second))
+
+		if _, ok := seen[creation]; ok {
+			return
+		}
+
+		seen[creation] = struct{}{}
+
+		creations = append(creations, creation)
+	}
+
+	for _, creation := range t.Status.NamespaceCreations {
+		add(creation)
+	}
+
+	for _, ns := range namespaces {
+		add(NamespaceCreation{Name: ns.GetName(), Time: ns.GetCreationTimestamp()})
+	}
+
+	sort.SliceStable(creations, func(i, j int) bool {
+		if creations[i].Time.Equal(&creations[j].Time) {
+			return creations[i].Name < creations[j].Name
+		}
+
+		return creations[i].Time.Before(&creations[j].Time)
+	})
+
+	return creations
+}
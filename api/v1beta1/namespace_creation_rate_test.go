@@ -0,0 +1,49 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTenant_RecentNamespaceCreations(t *testing.T) {
+	now := time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	at := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(now.Add(-d))
+	}
+
+	namespace := func(name string, created metav1.Time) corev1.Namespace {
+		return corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created}}
+	}
+
+	tnt := &Tenant{
+		Status: TenantStatus{NamespaceCreations: []NamespaceCreation{
+			{Name: "oil-old", Time: at(2 * time.Hour)},
+			{Name: "oil-deleted", Time: at(30 * time.Minute)},
+			{Name: "oil-dev", Time: at(20 * time.Minute)},
+		}},
+	}
+
+	namespaces := []corev1.Namespace{
+		namespace("oil-production", at(3*time.Hour)),
+		namespace("oil-dev", at(20*time.Minute)),
+		namespace("oil-test", at(time.Minute)),
+	}
+
+	assert.Nil(t, tnt.RecentNamespaceCreations(namespaces, now))
+
+	tnt.Spec.NamespaceOptions = &NamespaceOptions{CreationRate: &NamespaceCreationRate{Limit: 5, Period: metav1.Duration{Duration: time.Hour}}}
+
+	assert.Equal(t, []NamespaceCreation{
+		{Name: "oil-deleted", Time: at(30 * time.Minute)},
+		{Name: "oil-dev", Time: at(20 * time.Minute)},
+		{Name: "oil-test", Time: at(time.Minute)},
+	}, tnt.RecentNamespaceCreations(namespaces, now))
+}
@@ -8,6 +8,8 @@ type NamespaceOptions struct {
 	Quota *int32 `json:"quota,omitempty"`
 	// Specifies additional labels and annotations the Capsule operator places on any Namespace resource in the Tenant. Optional.
 	AdditionalMetadata *AdditionalMetadataSpec `json:"additionalMetadata,omitempty"`
+	// Limits the rate the Namespaces are created at in the Tenant, protecting the cluster from runaway automation running with the Tenant owners credentials: the Namespaces created in the period are counted, including the deleted ones, besides the quota. Optional.
+	CreationRate *NamespaceCreationRate `json:"creationRate,omitempty"`
 }
 
 func (t *Tenant) hasForbiddenNamespaceLabelsAnnotations() bool {
@@ -0,0 +1,73 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type NamespaceOverrideSpec struct {
+	// Selects the Tenant Namespaces the override applies to.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// Replaces the ResourceQuota items of the Tenant in the selected Namespaces. Applied only with the Namespace scope,
+	// since the Tenant one shares the same budget across all the Namespaces. Optional.
+	ResourceQuotas []corev1.ResourceQuotaSpec `json:"resourceQuotas,omitempty"`
+	// Replaces the default LimitRange items of the Tenant in the selected Namespaces: the profiles can still be selected. Optional.
+	LimitRanges []corev1.LimitRangeSpec `json:"limitRanges,omitempty"`
+}
+
+// NamespaceOverride returns the first override selecting the given Namespace, if any.
+func (t *Tenant) NamespaceOverride(namespace *corev1.Namespace) (*NamespaceOverrideSpec, error) {
+	for i := range t.Spec.NamespaceOverrides {
+		override := t.Spec.NamespaceOverrides[i]
+
+		selector, err := metav1.LabelSelectorAsSelector(&override.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		if selector.Matches(labels.Set(namespace.GetLabels())) {
+			return &override, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// LimitRangesFor returns the LimitRange items to apply in the given Namespace, according to the overrides and
+// the selected profile.
+func (t *Tenant) LimitRangesFor(namespace *corev1.Namespace) ([]corev1.LimitRangeSpec, error) {
+	spec := t.Spec.LimitRanges
+
+	override, err := t.NamespaceOverride(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if override != nil && override.LimitRanges != nil {
+		spec.Items = override.LimitRanges
+	}
+
+	return spec.ItemsFor(namespace.GetLabels()[LimitRangeProfileLabel]), nil
+}
+
+// ResourceQuotasFor returns the ResourceQuota items to apply in the given Namespace, according to the overrides.
+func (t *Tenant) ResourceQuotasFor(namespace *corev1.Namespace) ([]corev1.ResourceQuotaSpec, error) {
+	if t.Spec.ResourceQuota.Scope != ResourceQuotaScopeNamespace {
+		return t.Spec.ResourceQuota.Items, nil
+	}
+
+	override, err := t.NamespaceOverride(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if override != nil && override.ResourceQuotas != nil {
+		return override.ResourceQuotas, nil
+	}
+
+	return t.Spec.ResourceQuota.Items, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTenant_NamespaceOverrides(t *testing.T) {
+	defaultQuota := []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}}
+	prodQuota := []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("20")}}}
+	defaultLimits := []corev1.LimitRangeSpec{{Limits: []corev1.LimitRangeItem{{Type: corev1.LimitTypeContainer}}}}
+	prodLimits := []corev1.LimitRangeSpec{{Limits: []corev1.LimitRangeItem{{Type: corev1.LimitTypePod}}}}
+
+	tnt := &Tenant{
+		Spec: TenantSpec{
+			ResourceQuota: ResourceQuotaSpec{Scope: ResourceQuotaScopeNamespace, Items: defaultQuota},
+			LimitRanges:   LimitRangesSpec{Items: defaultLimits},
+			NamespaceOverrides: []NamespaceOverrideSpec{
+				{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"environment": "production"}},
+					ResourceQuotas:    prodQuota,
+					LimitRanges:       prodLimits,
+				},
+			},
+		},
+	}
+
+	dev := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"environment": "development"}}}
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"environment": "production"}}}
+
+	quota, err := tnt.ResourceQuotasFor(dev)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultQuota, quota)
+
+	quota, err = tnt.ResourceQuotasFor(prod)
+	assert.Nil(t, err)
+	assert.Equal(t, prodQuota, quota)
+
+	limits, err := tnt.LimitRangesFor(dev)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultLimits, limits)
+
+	limits, err = tnt.LimitRangesFor(prod)
+	assert.Nil(t, err)
+	assert.Equal(t, prodLimits, limits)
+
+	// the Tenant scope shares the budget across the Namespaces, ignoring the overrides
+	tnt.Spec.ResourceQuota.Scope = ResourceQuotaScopeTenant
+
+	quota, err = tnt.ResourceQuotasFor(prod)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultQuota, quota)
+
+	tnt.Spec.NamespaceOverrides[0].NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "environment", Operator: "Invalid"}}
+
+	_, err = tnt.LimitRangesFor(prod)
+	assert.NotNil(t, err)
+}
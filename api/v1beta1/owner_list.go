@@ -2,6 +2,8 @@ package v1beta1
 
 import (
 	"sort"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
 )
 
 type OwnerListSpec []OwnerSpec
@@ -18,6 +20,26 @@ func (o OwnerListSpec) FindOwner(name string, kind OwnerKind) (owner OwnerSpec)
 	return
 }
 
+// IsOwner reports if the given user is an owner, by name or through one of the groups they are member of.
+func (o OwnerListSpec) IsOwner(user authenticationv1.UserInfo) bool {
+	for _, owner := range o {
+		switch owner.Kind {
+		case UserOwner, ServiceAccountOwner:
+			if user.Username == owner.Name {
+				return true
+			}
+		case GroupOwner:
+			for _, group := range user.Groups {
+				if group == owner.Name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 type ByKindAndName OwnerListSpec
 
 func (b ByKindAndName) Len() int {
@@ -0,0 +1,20 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type PersistentVolumeOptions struct {
+	// Converts the Retain reclaim policy of the PersistentVolumes bound by the Tenant to Delete,
+	// so that the data is never left behind, and reused by other Tenants, once the claims are removed. Optional.
+	ForceDeleteReclaimPolicy bool `json:"forceDeleteReclaimPolicy,omitempty"`
+	// The StorageClass set on the volume claim templates of the Tenant StatefulSets not declaring any,
+	// rather than leaving their claims to the cluster default one. Optional.
+	DefaultStorageClass string `json:"defaultStorageClass,omitempty"`
+	// The maximum storage each PersistentVolumeClaim of the Tenant, and each volume claim template
+	// of its StatefulSets, can request. Optional.
+	MaxStorageRequest *resource.Quantity `json:"maxStorageRequest,omitempty"`
+}
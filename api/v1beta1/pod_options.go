@@ -0,0 +1,27 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type PodOptions struct {
+	// Requires the containers and the init containers of the Tenant Pods to declare the ephemeral-storage limits,
+	// so that the local storage consumption is bounded and accounted by the Tenant ResourceQuotas. Optional.
+	RequireEphemeralStorageLimits bool `json:"requireEphemeralStorageLimits,omitempty"`
+	// Denies the ephemeral containers added to the Tenant Pods, as the ones used by kubectl debug. Optional.
+	ForbidEphemeralContainers bool `json:"forbidEphemeralContainers,omitempty"`
+	// Warns upon the creation of the Tenant Pods that don't fit in the capacity left in the Tenant node pool, rather
+	// than leaving them pending silently: the feasibility is checked against the Nodes and their Pods, as the
+	// scheduler does. Optional.
+	WarnOverCapacity bool `json:"warnOverCapacity,omitempty"`
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	// The DNS policy set on the Tenant Pods using the default ClusterFirst one, such as None to resolve the names
+	// through the nameservers of the Tenant DNS config only. Optional.
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// The DNS config set on the Tenant Pods not declaring one, such as the nameservers and the search domains of
+	// the Tenant corporate resolvers. Optional.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+}
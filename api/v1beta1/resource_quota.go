@@ -18,4 +18,19 @@ type ResourceQuotaSpec struct {
 	// Define if the Resource Budget should compute resource across all Namespaces in the Tenant or individually per cluster. Default is Tenant
 	Scope ResourceQuotaScope         `json:"scope,omitempty"`
 	Items []corev1.ResourceQuotaSpec `json:"items,omitempty"`
+	// Specifies the maximum number of objects of the given resources across all the Tenant Namespaces, regardless of the scope, in the count/<resource>.<group> form, as count/deployments.apps, or count/<resource> for the core group, as count/secrets. Optional.
+	ObjectCounts corev1.ResourceList `json:"objectCounts,omitempty"`
+}
+
+// ObjectCountsQuotaType is the type label value of the ResourceQuotas enforcing the Tenant object counts.
+const ObjectCountsQuotaType = "object-counts"
+
+// ObjectCountResourceName returns the name of the object count quota of the given resource, in the
+// count/<resource>.<group> form, or count/<resource> for the core group.
+func ObjectCountResourceName(group, resource string) corev1.ResourceName {
+	if len(group) == 0 {
+		return corev1.ResourceName("count/" + resource)
+	}
+
+	return corev1.ResourceName("count/" + resource + "." + group)
 }
@@ -0,0 +1,57 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// +kubebuilder:validation:Enum=Immediate;WhenCompliant
+type RestrictionsRollout string
+
+const (
+	// RestrictionsRolloutImmediate enforces the Pod restrictions of the Tenant as soon as they change.
+	RestrictionsRolloutImmediate RestrictionsRollout = "Immediate"
+	// RestrictionsRolloutWhenCompliant delays the enforcement of the changed Pod restrictions of the Tenant until
+	// none of its existing Pods violates them.
+	RestrictionsRolloutWhenCompliant RestrictionsRollout = "WhenCompliant"
+)
+
+// PodRestrictions are the restrictions of the Tenant enforced upon the admission of its Pods.
+type PodRestrictions struct {
+	ContainerRegistries           *AllowedListSpec      `json:"containerRegistries,omitempty"`
+	ImagePullPolicies             []ImagePullPolicySpec `json:"imagePullPolicies,omitempty"`
+	PriorityClasses               *AllowedListSpec      `json:"priorityClasses,omitempty"`
+	RequireEphemeralStorageLimits bool                  `json:"requireEphemeralStorageLimits,omitempty"`
+}
+
+// PolicyViolation is an existing object of the Tenant violating its current restrictions.
+type PolicyViolation struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Which restriction is violated.
+	Reason string `json:"reason"`
+}
+
+// PodRestrictions returns the Pod restrictions declared by the Tenant.
+func (t *Tenant) PodRestrictions() PodRestrictions {
+	restrictions := PodRestrictions{
+		ContainerRegistries: t.Spec.ContainerRegistries,
+		ImagePullPolicies:   t.Spec.ImagePullPolicies,
+		PriorityClasses:     t.Spec.PriorityClasses,
+	}
+
+	if t.Spec.PodOptions != nil {
+		restrictions.RequireEphemeralStorageLimits = t.Spec.PodOptions.RequireEphemeralStorageLimits
+	}
+
+	return restrictions
+}
+
+// EnforcedPodRestrictions returns the Pod restrictions of the Tenant enforced upon the admission: the ones recorded in
+// the status when the Tenant delays the enforcement until its Pods are compliant, the declared ones otherwise.
+func (t *Tenant) EnforcedPodRestrictions() PodRestrictions {
+	if t.Spec.RestrictionsRollout == RestrictionsRolloutWhenCompliant && t.Status.EnforcedPodRestrictions != nil {
+		return *t.Status.EnforcedPodRestrictions
+	}
+
+	return t.PodRestrictions()
+}
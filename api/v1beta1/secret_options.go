@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type SecretOptions struct {
+	// Specifies the types the Secrets of the Tenant cannot use, such as kubernetes.io/tls or bootstrap.kubernetes.io/token. Optional.
+	ForbiddenTypes *ForbiddenListSpec `json:"forbiddenTypes,omitempty"`
+	// Maximum size of the data of each Secret of the Tenant, lower than the API server one. Optional.
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Maximum number of Secrets the Tenant can hold, not counting the ServiceAccount token ones managed by Kubernetes. Optional.
+	MaxCount *int32 `json:"maxCount,omitempty"`
+}
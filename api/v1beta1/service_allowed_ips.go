@@ -9,3 +9,8 @@ type AllowedIP string
 type ExternalServiceIPsSpec struct {
 	Allowed []AllowedIP `json:"allowed"`
 }
+
+type ClusterIPsSpec struct {
+	// The addresses and CIDR ranges the cluster IPs set by the Services must belong to.
+	Allowed []AllowedIP `json:"allowed"`
+}
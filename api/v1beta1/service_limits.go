@@ -0,0 +1,50 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type ServiceLimitsSpec struct {
+	// The maximum number of LoadBalancer Services across the Tenant Namespaces. Optional.
+	// +kubebuilder:validation:Minimum=0
+	LoadBalancers *int32 `json:"loadBalancers,omitempty"`
+	// The maximum number of node ports allocated by the NodePort and LoadBalancer Services across the Tenant Namespaces. Optional.
+	// +kubebuilder:validation:Minimum=0
+	NodePorts *int32 `json:"nodePorts,omitempty"`
+}
+
+type ServiceUsage struct {
+	// How many LoadBalancer Services the Tenant has.
+	LoadBalancers int32 `json:"loadBalancers"`
+	// How many node ports the Services of the Tenant allocate.
+	NodePorts int32 `json:"nodePorts"`
+}
+
+// Add counts the LoadBalancer and the node ports of the given Service: the LoadBalancer Services not allocating the
+// node ports count only the ones explicitly set.
+func (in *ServiceUsage) Add(svc *corev1.Service) {
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeNodePort:
+		in.NodePorts += int32(len(svc.Spec.Ports))
+	case corev1.ServiceTypeLoadBalancer:
+		in.LoadBalancers++
+
+		for _, port := range svc.Spec.Ports {
+			if port.NodePort != 0 || svc.Spec.AllocateLoadBalancerNodePorts == nil || *svc.Spec.AllocateLoadBalancerNodePorts {
+				in.NodePorts++
+			}
+		}
+	}
+}
+
+// ServiceLimits returns the limits of the Tenant Services, if any.
+func (t *Tenant) ServiceLimits() *ServiceLimitsSpec {
+	if t.Spec.ServiceOptions == nil {
+		return nil
+	}
+
+	return t.Spec.ServiceOptions.Limits
+}
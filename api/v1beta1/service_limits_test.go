@@ -0,0 +1,27 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServiceUsage_Add(t *testing.T) {
+	allocate := false
+
+	usage := ServiceUsage{}
+	usage.Add(&corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Port: 80}}}})
+	usage.Add(&corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 80}, {Port: 443}}}})
+	usage.Add(&corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: []corev1.ServicePort{{Port: 80}}}})
+	usage.Add(&corev1.Service{Spec: corev1.ServiceSpec{
+		Type:                          corev1.ServiceTypeLoadBalancer,
+		AllocateLoadBalancerNodePorts: &allocate,
+		Ports:                         []corev1.ServicePort{{Port: 80}, {Port: 443, NodePort: 30443}},
+	}})
+
+	assert.Equal(t, ServiceUsage{LoadBalancers: 2, NodePorts: 4}, usage)
+}
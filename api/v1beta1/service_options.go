@@ -10,4 +10,10 @@ type ServiceOptions struct {
 	AllowedServices *AllowedServices `json:"allowedServices,omitempty"`
 	// Specifies the external IPs that can be used in Services with type ClusterIP. An empty list means no IPs are allowed. Optional.
 	ExternalServiceIPs *ExternalServiceIPsSpec `json:"externalIPs,omitempty"`
+	// Specifies the ranges the cluster IPs set by the Services must belong to: since the cluster IPs are allocated from the cluster Service CIDR, only the ones set by the Services are validated. Optional.
+	ClusterIPs *ClusterIPsSpec `json:"clusterIPs,omitempty"`
+	// Specifies the maximum number of LoadBalancer Services and of node ports of the Tenant, counted across all its Namespaces. Optional.
+	Limits *ServiceLimitsSpec `json:"limits,omitempty"`
+	// Specifies the internal and external traffic policies required for the Services. Optional.
+	TrafficPolicies *ServiceTrafficPoliciesSpec `json:"trafficPolicies,omitempty"`
 }
@@ -0,0 +1,19 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type ServiceTrafficPoliciesSpec struct {
+	// Requires the internal traffic policy of the Tenant Services, as Local to keep the in-cluster traffic on the
+	// originating node. Enforced only when the API server supports the field. Optional.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	Internal *corev1.ServiceInternalTrafficPolicyType `json:"internal,omitempty"`
+	// Requires the external traffic policy of the Tenant NodePort and LoadBalancer Services, as Local to preserve
+	// the client source IP. Optional.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	External *corev1.ServiceExternalTrafficPolicyType `json:"external,omitempty"`
+}
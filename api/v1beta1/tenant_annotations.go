@@ -18,6 +18,7 @@ const (
 	ForbiddenNamespaceLabelsRegexpAnnotation      = "capsule.clastix.io/forbidden-namespace-labels-regexp"
 	ForbiddenNamespaceAnnotationsAnnotation       = "capsule.clastix.io/forbidden-namespace-annotations"
 	ForbiddenNamespaceAnnotationsRegexpAnnotation = "capsule.clastix.io/forbidden-namespace-annotations-regexp"
+	OverrideImmutableFieldsAnnotation             = "capsule.clastix.io/override-immutable-fields"
 )
 
 func UsedQuotaFor(resource fmt.Stringer) string {
@@ -0,0 +1,27 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+type TenantPeerSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// The name of the peer Tenant, which must declare this Tenant as a peer as well.
+	Tenant string `json:"tenant"`
+	// The ports of the Tenant Pods the peer Tenant Pods can connect to, all of them if empty. Optional.
+	Ports []networkingv1.NetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// Peer returns the peering of the Tenant with the given one, if declared.
+func (t *Tenant) Peer(name string) (TenantPeerSpec, bool) {
+	for _, peer := range t.Spec.Peers {
+		if peer.Tenant == name {
+			return peer, true
+		}
+	}
+
+	return TenantPeerSpec{}, false
+}
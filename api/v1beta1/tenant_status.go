@@ -3,6 +3,11 @@
 
 package v1beta1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // +kubebuilder:validation:Enum=Cordoned;Active
 type tenantState string
 
@@ -20,4 +25,68 @@ type TenantStatus struct {
 	Size uint `json:"size"`
 	// List of namespaces assigned to the Tenant.
 	Namespaces []string `json:"namespaces,omitempty"`
+	// Reports the Tenant status in each member cluster, when the Tenant is replicated by the federation hub.
+	Clusters []ClusterStatus `json:"clusters,omitempty"`
+	// Reports the resources requested and used by the Tenant, when the chargeback collection is enabled.
+	Usage *TenantUsage `json:"usage,omitempty"`
+	// Reports the periodic usage snapshots of the Tenant, from the oldest one, when the usage history is enabled.
+	UsageHistory []UsageSnapshot `json:"usageHistory,omitempty"`
+	// Reports the disruptive changes deferred to the next maintenance window of the Tenant.
+	PendingChanges []PendingChange `json:"pendingChanges,omitempty"`
+	// The time the next maintenance window of the Tenant opens at, when any change is pending.
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+	// Reports the LoadBalancer Services and the node ports of the Tenant, when their number is limited.
+	Services *ServiceUsage `json:"services,omitempty"`
+	// Reports the existing Pods of the Tenant violating its current Pod restrictions, up to 50 of them.
+	Violations []PolicyViolation `json:"violations,omitempty"`
+	// The Pod restrictions enforced upon the admission, when the Tenant delays the changed ones until its Pods are compliant.
+	EnforcedPodRestrictions *PodRestrictions `json:"enforcedPodRestrictions,omitempty"`
+	// Reports the objects created by Capsule for the Tenant outside of its Namespaces not yet removed, upon the Tenant deletion.
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+	// Reports the Namespaces created in the Tenant within the period of its Namespace creation rate, from the oldest one, when the rate is limited.
+	NamespaceCreations []NamespaceCreation `json:"namespaceCreations,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// The latest observations of the Tenant state, as the Ready and the Cordoned conditions.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type TenantUsage struct {
+	// The sum of the resources requested by the Pods and the PersistentVolumeClaims of the Tenant.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// The sum of the resources used by the Pods of the Tenant, as reported by the metrics-server.
+	Used corev1.ResourceList `json:"used,omitempty"`
+	// The last time the usage has been collected.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+type UsageSnapshot struct {
+	// The time of the snapshot.
+	Time metav1.Time `json:"time"`
+	// How many namespaces are assigned to the Tenant.
+	Namespaces int `json:"namespaces"`
+	// How many Pods are running in the Tenant.
+	Pods int `json:"pods"`
+	// The CPU and memory requested by the running Pods of the Tenant.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+}
+
+type ClusterStatus struct {
+	// The name of the member cluster.
+	Name string `json:"name"`
+	// Whether the Tenant has been replicated to the member cluster with the last synchronization.
+	Synced bool `json:"synced"`
+	// The reason of the failed synchronization, if any.
+	Message string `json:"message,omitempty"`
+	// The operational state of the Tenant in the member cluster.
+	State tenantState `json:"state,omitempty"`
+	// How many namespaces are assigned to the Tenant in the member cluster.
+	Size uint `json:"size,omitempty"`
+}
+
+type CleanupStatus struct {
+	// The objects pending removal, in the Kind/name or Kind/namespace/name form.
+	Pending []string `json:"pending,omitempty"`
+	// The reason of the failed removal, if any.
+	Message string `json:"message,omitempty"`
 }
@@ -11,30 +11,76 @@ import (
 type TenantSpec struct {
 	// Specifies the owners of the Tenant. Mandatory.
 	Owners OwnerListSpec `json:"owners"`
+	// Specifies the parent Tenant: the Tenant inherits the restrictions of the parent it doesn't declare, such as the quotas and the allowed lists, and cannot exceed them. The owners of the parent Tenant can manage it. Optional.
+	Parent string `json:"parent,omitempty"`
 	// Specifies options for the Namespaces, such as additional metadata or maximum number of namespaces allowed for that Tenant. Once the namespace quota assigned to the Tenant has been reached, the Tenant owner cannot create further namespaces. Optional.
 	NamespaceOptions *NamespaceOptions `json:"namespaceOptions,omitempty"`
-	// Specifies options for the Service, such as additional metadata or block of certain type of Services. Optional.
+	// Overrides the forceTenantPrefix option of the Capsule configuration for the Tenant, enforcing or relaxing the Tenant name as prefix of its Namespaces. Can be changed only by the cluster administrators with the capsule.clastix.io/override-immutable-fields annotation. Optional.
+	ForceTenantPrefix *bool `json:"forceTenantPrefix,omitempty"`
+	// Specifies options for the Service, such as additional metadata, block of certain type of Services, the allowed cluster and external IPs, the required traffic policies or the maximum number of LoadBalancers and node ports. Optional.
 	ServiceOptions *ServiceOptions `json:"serviceOptions,omitempty"`
 	// Specifies the allowed StorageClasses assigned to the Tenant. Capsule assures that all PersistentVolumeClaim resources created in the Tenant can use only one of the allowed StorageClasses. Optional.
 	StorageClasses *AllowedListSpec `json:"storageClasses,omitempty"`
+	// Specifies options for the PersistentVolumes bound by the Tenant, such as the enforcement of the Delete reclaim policy, the default StorageClass of the StatefulSet volume claim templates or the maximum storage requested by the claims. Optional.
+	PersistentVolumeOptions *PersistentVolumeOptions `json:"persistentVolumeOptions,omitempty"`
 	// Specifies options for the Ingress resources, such as allowed hostnames and IngressClass. Optional.
 	IngressOptions IngressOptions `json:"ingressOptions,omitempty"`
 	// Specifies the trusted Image Registries assigned to the Tenant. Capsule assures that all Pods resources created in the Tenant can use only one of the allowed trusted registries. Optional.
 	ContainerRegistries *AllowedListSpec `json:"containerRegistries,omitempty"`
-	// Specifies the label to control the placement of pods on a given pool of worker nodes. All namesapces created within the Tenant will have the node selector annotation. This annotation tells the Kubernetes scheduler to place pods on the nodes having the selector label. Optional.
+	// Specifies the label to control the placement of pods on a given pool of worker nodes. All namesapces created within the Tenant will have the node selector annotation. This annotation tells the Kubernetes scheduler to place pods on the nodes having the selector label. Can be changed only by the cluster administrators with the capsule.clastix.io/override-immutable-fields annotation. Optional.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// Specifies the NetworkPolicies assigned to the Tenant. The assigned NetworkPolicies are inherited by any namespace created in the Tenant. Optional.
 	NetworkPolicies NetworkPolicySpec `json:"networkPolicies,omitempty"`
+	// Specifies the Tenants whose Pods can connect to the Tenant ones, on the given ports: Capsule generates the NetworkPolicies opening the traffic in the Namespaces of both the Tenants only when the peer Tenant declares this one as well. Optional.
+	Peers []TenantPeerSpec `json:"peers,omitempty"`
 	// Specifies the NetworkPolicies assigned to the Tenant. The assigned NetworkPolicies are inherited by any namespace created in the Tenant. Optional.
 	LimitRanges LimitRangesSpec `json:"limitRanges,omitempty"`
 	// Specifies a list of ResourceQuota resources assigned to the Tenant. The assigned values are inherited by any namespace created in the Tenant. The Capsule operator aggregates ResourceQuota at Tenant level, so that the hard quota is never crossed for the given Tenant. This permits the Tenant owner to consume resources in the Tenant regardless of the namespace. Optional.
 	ResourceQuota ResourceQuotaSpec `json:"resourceQuotas,omitempty"`
 	// Specifies additional RoleBindings assigned to the Tenant. Capsule will ensure that all namespaces in the Tenant always contain the RoleBinding for the given ClusterRole. Optional.
 	AdditionalRoleBindings []AdditionalRoleBindingsSpec `json:"additionalRoleBindings,omitempty"`
+	// Specifies the rules for the Pod resources, such as the mandatory ephemeral-storage limits, the denial of the ephemeral containers and of the Pods exceeding the node pool capacity, or the default DNS policy and config. Optional.
+	PodOptions *PodOptions `json:"podOptions,omitempty"`
 	// Specify the allowed values for the imagePullPolicies option in Pod resources. Capsule assures that all Pod resources created in the Tenant can use only one of the allowed policy. Optional.
 	ImagePullPolicies []ImagePullPolicySpec `json:"imagePullPolicies,omitempty"`
 	// Specifies the allowed priorityClasses assigned to the Tenant. Capsule assures that all Pods resources created in the Tenant can use only one of the allowed PriorityClasses. Optional.
 	PriorityClasses *AllowedListSpec `json:"priorityClasses,omitempty"`
+	// Specifies if Capsule should emit a Kyverno ClusterPolicy mirroring the Tenant rules, scoped to its Namespaces. Requires Capsule to be started with the --enable-kyverno-policies flag. Optional.
+	KyvernoPolicies *KyvernoPoliciesSpec `json:"kyvernoPolicies,omitempty"`
+	// Specifies the Velero backup schedule of the Tenant Namespaces. Requires Capsule to be started with the --enable-velero-backups flag. Optional.
+	Backup *BackupSpec `json:"backup,omitempty"`
+	// Specifies the API Priority and Fairness settings of the Tenant: the requests of the owners and of the Tenant ServiceAccounts are isolated in a FlowSchema, preventing a noisy Tenant from exhausting the API server concurrency. Requires Capsule to be started with the --enable-api-priority-and-fairness flag. Optional.
+	APIPriorityAndFairness *APIPriorityAndFairnessSpec `json:"apiPriorityAndFairness,omitempty"`
+	// Specifies the home Namespace of the ready-to-use kubeconfig Secrets Capsule publishes in its own Namespace for each User and ServiceAccount owner, readable only by their owner and backed by short-lived credentials minted on their behalf. Requires Capsule to be started with the --enable-access-bundles flag. Optional.
+	AccessBundle *AccessBundleSpec `json:"accessBundle,omitempty"`
+	// Specifies the rules for the CronJob resources, such as the forbidden schedules or the maximum number of concurrent Jobs, preventing runaway Jobs from overwhelming the shared capacity. Optional.
+	CronJobOptions *CronJobOptions `json:"cronJobOptions,omitempty"`
+	// Specifies the labels the Deployments, StatefulSets and Pods of the Tenant must declare, and the default ones set on the workloads created without them. Optional.
+	WorkloadLabels *WorkloadLabelsSpec `json:"workloadLabels,omitempty"`
+	// Specifies the rules for the Secret resources, such as the forbidden types and the maximum size and number of Secrets, denying the exceeding ones at admission. Optional.
+	SecretOptions *SecretOptions `json:"secretOptions,omitempty"`
+	// Specifies the ClusterSecretStores and the remote key prefix the External Secrets Operator resources of the Tenant can reference, preventing the Tenant from reading the secrets of the other ones through the shared operator. Optional.
+	ExternalSecrets *ExternalSecretsSpec `json:"externalSecrets,omitempty"`
+	// Specifies the weekly windows the disruptive changes of the Tenant, such as the lowered ResourceQuota limits or the changed NetworkPolicy rules, are applied in: outside of them the changes are deferred and reported in the status. If empty, the changes are applied at any time. Optional.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// Specifies when the changed Pod restrictions of the Tenant, such as the container registries, the image pull policies, the PriorityClasses and the ephemeral-storage limits, are enforced: Immediate, the default, or WhenCompliant, delaying the enforcement until none of the existing Tenant Pods violates them. The violations are reported in the status either way. Requires Capsule to be started with the --enable-restrictions-impact flag, the WhenCompliant rollout behaving as the Immediate one otherwise. Optional.
+	RestrictionsRollout RestrictionsRollout `json:"restrictionsRollout,omitempty"`
+	// Specifies the garbage collection of the finished Jobs and Pods of the Tenant, such as the default Jobs TTL. Optional.
+	GarbageCollection *GarbageCollectionOptions `json:"garbageCollection,omitempty"`
+	// Specifies if the Tenant owners can register their own admission webhooks, as the ones of the operators they run, restricted by Capsule to the Tenant Namespaces. Optional.
+	WebhookConfigurations *WebhookConfigurationsSpec `json:"webhookConfigurations,omitempty"`
+	// Specifies the API groups the Tenant owners can install CustomResourceDefinitions for: Capsule binds the installed CustomResourceDefinitions to the Tenant, deleting them along with it. Optional.
+	CustomResourceDefinitions *CustomResourceDefinitionsSpec `json:"customResourceDefinitions,omitempty"`
+	// Specifies the Namespaces Capsule creates and keeps bound to the Tenant, in addition to the ones created by the Tenant owners: removing an item doesn't delete the Namespace. Optional.
+	Namespaces []DeclaredNamespaceSpec `json:"namespaces,omitempty"`
+	// Specifies the ResourceQuota and LimitRange items replacing the Tenant ones in the Namespaces matching a selector, such as stricter limits for the production ones. The first matching override applies. Optional.
+	NamespaceOverrides []NamespaceOverrideSpec `json:"namespaceOverrides,omitempty"`
+	// Specifies the labels and annotations stamped on the objects generated by Capsule in the Tenant Namespaces, such as ResourceQuotas, LimitRanges, NetworkPolicies and RoleBindings: these are restored upon any change. Optional.
+	GeneratedObjectsMetadata *AdditionalMetadataSpec `json:"generatedObjectsMetadata,omitempty"`
+	// Specifies the annotations read by the cluster autoscaler and the capacity management tools, enforced on the Tenant Pods and on the generated ResourceQuotas, such as the cluster-autoscaler safe-to-evict one: the capacity tooling behaves per Tenant without the cooperation of the Tenant owners. Optional.
+	CapacityHints *CapacityHintsSpec `json:"capacityHints,omitempty"`
+	// Specifies the Kueue ClusterQueue of the Tenant, bound to its quota, and the LocalQueue created in each Tenant Namespace and set on the Tenant Jobs: the batch workloads of the Tenant are fairly queued without maintaining a parallel queue topology. Requires Capsule to be started with the --enable-kueue-queues flag. Optional.
+	BatchQueueing *BatchQueueingSpec `json:"batchQueueing,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -44,6 +90,7 @@ type TenantSpec struct {
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="The actual state of the Tenant"
 // +kubebuilder:printcolumn:name="Namespace quota",type="integer",JSONPath=".spec.namespaceOptions.quota",description="The max amount of Namespaces can be created"
 // +kubebuilder:printcolumn:name="Namespace count",type="integer",JSONPath=".status.size",description="The total amount of Namespaces in use"
+// +kubebuilder:printcolumn:name="Parent",type="string",JSONPath=".spec.parent",description="The parent Tenant",priority=1
 // +kubebuilder:printcolumn:name="Node selector",type="string",JSONPath=".spec.nodeSelector",description="Node Selector applied to Pods"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
 
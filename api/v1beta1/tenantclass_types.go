@@ -0,0 +1,63 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantClassSpec defines the restrictions of the Tenants materialized from the approved TenantRequests of the class.
+type TenantClassSpec struct {
+	// Owners added to the Tenants of the class, along with the ones of the TenantRequest, such as the platform team. Optional.
+	AdditionalOwners OwnerListSpec `json:"additionalOwners,omitempty"`
+	// Specifies options for the Namespaces, such as additional metadata or maximum number of namespaces allowed for the Tenants of the class. Optional.
+	NamespaceOptions *NamespaceOptions `json:"namespaceOptions,omitempty"`
+	// Specifies the options for the Services of the Tenants of the class. Optional.
+	ServiceOptions *ServiceOptions `json:"serviceOptions,omitempty"`
+	// Specifies the allowed StorageClasses assigned to the Tenants of the class. Optional.
+	StorageClasses *AllowedListSpec `json:"storageClasses,omitempty"`
+	// Specifies options for the Ingress resources, such as allowed hostnames and IngressClass. Optional.
+	IngressOptions IngressOptions `json:"ingressOptions,omitempty"`
+	// Specifies the trusted Image Registries assigned to the Tenants of the class. Optional.
+	ContainerRegistries *AllowedListSpec `json:"containerRegistries,omitempty"`
+	// Specifies the label to control the placement of pods on a given pool of worker nodes. Optional.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Specifies the NetworkPolicies assigned to the Tenants of the class. Optional.
+	NetworkPolicies NetworkPolicySpec `json:"networkPolicies,omitempty"`
+	// Specifies the resource min/max usage restrictions to the Tenants of the class. Optional.
+	LimitRanges LimitRangesSpec `json:"limitRanges,omitempty"`
+	// Specifies a list of ResourceQuota resources assigned to the Tenants of the class. Optional.
+	ResourceQuota ResourceQuotaSpec `json:"resourceQuotas,omitempty"`
+	// Specifies the rules for the Pod resources of the Tenants of the class. Optional.
+	PodOptions *PodOptions `json:"podOptions,omitempty"`
+	// Specify the allowed values for the imagePullPolicies option in Pod resources. Optional.
+	ImagePullPolicies []ImagePullPolicySpec `json:"imagePullPolicies,omitempty"`
+	// Specifies the allowed priorityClasses assigned to the Tenants of the class. Optional.
+	PriorityClasses *AllowedListSpec `json:"priorityClasses,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=tc
+
+// TenantClass is the Schema for the Tenant classes API: the cluster administrators define the classes of service
+// the approved TenantRequests are materialized from.
+type TenantClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TenantClassSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantClassList contains a list of TenantClass
+type TenantClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantClass{}, &TenantClassList{})
+}
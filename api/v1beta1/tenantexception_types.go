@@ -0,0 +1,66 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantExceptionSpec defines the requests of a Tenant Namespace exempted from the Capsule webhooks.
+type TenantExceptionSpec struct {
+	// The Tenant Namespace the exempted requests are performed in.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// The paths of the webhooks the requests are exempted from, such as /pods.
+	// +kubebuilder:validation:MinItems=1
+	Webhooks []string `json:"webhooks"`
+	// The kind of the exempted objects, such as Pod, all of them if empty. Optional.
+	Kind string `json:"kind,omitempty"`
+	// The name of the exempted object, all of them if empty. Optional.
+	Name string `json:"name,omitempty"`
+	// Selects the exempted objects by their labels, such as the Pods of a Deployment. Optional.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// The time the exception expires at: the requests are no longer exempted afterwards.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+	// Why the exception has been granted, such as the emergency it has been granted for.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+}
+
+//+kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=texc
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.namespace",description="The Namespace of the exempted requests"
+// +kubebuilder:printcolumn:name="Webhooks",type="string",JSONPath=".spec.webhooks",description="The webhooks the requests are exempted from"
+// +kubebuilder:printcolumn:name="Expires",type="string",JSONPath=".spec.expiresAt",description="The time the exception expires at"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason",description="Why the exception has been granted",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// TenantException is the Schema for the Tenant exceptions API: until its expiration, the matching requests of the
+// Tenant Namespace denied by the selected webhooks are allowed, with an Event recorded on the exception.
+type TenantException struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TenantExceptionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantExceptionList contains a list of TenantException
+type TenantExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantException `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantException{}, &TenantExceptionList{})
+}
+
+// IsExpired returns true if the TenantException is expired at the given time.
+func (in *TenantException) IsExpired(now time.Time) bool {
+	return !now.Before(in.Spec.ExpiresAt.Time)
+}
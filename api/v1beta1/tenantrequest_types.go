@@ -0,0 +1,82 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TenantRequestApprovedLabel approves a TenantRequest when set to true: only the approvers can set or remove it.
+	TenantRequestApprovedLabel = "capsule.clastix.io/approved"
+	// TenantRequestAnnotation references the TenantRequest a Tenant has been materialized from, in the <namespace>/<name> form.
+	TenantRequestAnnotation = "capsule.clastix.io/tenant-request"
+	// TenantClassLabel references the TenantClass a Tenant has been materialized from.
+	TenantClassLabel = "capsule.clastix.io/tenant-class"
+)
+
+// TenantRequestSpec defines the Tenant requested for the self-service onboarding.
+type TenantRequestSpec struct {
+	// Name of the requested Tenant.
+	// +kubebuilder:validation:MinLength=1
+	TenantName string `json:"tenantName"`
+	// Name of the TenantClass the requested Tenant is materialized from.
+	// +kubebuilder:validation:MinLength=1
+	TenantClassName string `json:"tenantClassName"`
+	// Owners of the requested Tenant, defaulting to the requester. Optional.
+	Owners OwnerListSpec `json:"owners,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Pending;Provisioned;Failed
+type TenantRequestPhase string
+
+const (
+	TenantRequestPending     TenantRequestPhase = "Pending"
+	TenantRequestProvisioned TenantRequestPhase = "Provisioned"
+	TenantRequestFailed      TenantRequestPhase = "Failed"
+)
+
+// TenantRequestStatus defines the observed state of the TenantRequest.
+type TenantRequestStatus struct {
+	// The phase of the TenantRequest: Pending until approved and materialized, then Provisioned, or Failed when the Tenant cannot be created.
+	Phase TenantRequestPhase `json:"phase,omitempty"`
+	// Human readable details of the phase.
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=treq
+// +kubebuilder:printcolumn:name="Tenant",type="string",JSONPath=".spec.tenantName",description="The requested Tenant"
+// +kubebuilder:printcolumn:name="Class",type="string",JSONPath=".spec.tenantClassName",description="The TenantClass of the requested Tenant"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of the TenantRequest"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// TenantRequest is the Schema for the Tenant requests API: once approved, Capsule materializes the requested Tenant
+// from the selected TenantClass.
+type TenantRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantRequestSpec   `json:"spec,omitempty"`
+	Status TenantRequestStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantRequestList contains a list of TenantRequest
+type TenantRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantRequest{}, &TenantRequestList{})
+}
+
+// IsApproved returns true when the TenantRequest has been approved.
+func (in *TenantRequest) IsApproved() bool {
+	return in.GetLabels()[TenantRequestApprovedLabel] == "true"
+}
@@ -0,0 +1,19 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// GeneratedName returns the name of the webhook configurations generated from the TenantWebhookConfiguration:
+// hashing its namespaced name, the generated configurations cannot collide with the ones named by other parties,
+// as the Capsule ones, whatever the names of the Namespace and of the TenantWebhookConfiguration are.
+func (in *TenantWebhookConfiguration) GeneratedName() string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(in.GetNamespace() + "/" + in.GetName()))
+
+	return fmt.Sprintf("capsule-tenant-%x", h.Sum64())
+}
@@ -0,0 +1,22 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTenantWebhookConfiguration_GeneratedName(t *testing.T) {
+	// the Namespace and the name joined as the Capsule webhook configurations ones
+	twc := &TenantWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "validating", Name: "webhook-configuration"}}
+	assert.NotEqual(t, "capsule-validating-webhook-configuration", twc.GeneratedName())
+	assert.True(t, strings.HasPrefix(twc.GeneratedName(), "capsule-tenant-"))
+
+	other := &TenantWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "validating-webhook", Name: "configuration"}}
+	assert.NotEqual(t, twc.GeneratedName(), other.GeneratedName())
+}
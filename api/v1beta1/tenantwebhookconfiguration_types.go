@@ -0,0 +1,42 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantWebhookConfigurationSpec defines the admission webhooks registered by the Tenant owners.
+type TenantWebhookConfigurationSpec struct {
+	// Validating webhooks registered in a ValidatingWebhookConfiguration restricted to the Tenant Namespaces. Optional.
+	ValidatingWebhooks []admissionregistrationv1.ValidatingWebhook `json:"validatingWebhooks,omitempty"`
+	// Mutating webhooks registered in a MutatingWebhookConfiguration restricted to the Tenant Namespaces. Optional.
+	MutatingWebhooks []admissionregistrationv1.MutatingWebhook `json:"mutatingWebhooks,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=twc
+
+// TenantWebhookConfiguration is the Schema for the Tenant webhook configurations API: Capsule registers the
+// declared webhooks in cluster-wide configurations, restricting them to the Namespaces of the Tenant.
+type TenantWebhookConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TenantWebhookConfigurationSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantWebhookConfigurationList contains a list of TenantWebhookConfiguration
+type TenantWebhookConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantWebhookConfiguration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantWebhookConfiguration{}, &TenantWebhookConfigurationList{})
+}
@@ -0,0 +1,10 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+type WebhookConfigurationsSpec struct {
+	// Allows the Tenant owners to register admission webhooks through the TenantWebhookConfiguration resources,
+	// restricted to the Tenant Namespaces. Optional.
+	Allowed bool `json:"allowed,omitempty"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"strings"
+)
+
+const (
+	// WorkloadLabelTenantPlaceholder is replaced by the Tenant name in the default workload labels.
+	WorkloadLabelTenantPlaceholder = "$(tenant)"
+	// WorkloadLabelNamespacePlaceholder is replaced by the workload Namespace in the default workload labels.
+	WorkloadLabelNamespacePlaceholder = "$(namespace)"
+)
+
+type WorkloadLabelsSpec struct {
+	// Keys of the labels the Deployments, StatefulSets and Pods of the Tenant must declare, along with their Pod templates, such as cost-center. Optional.
+	Required []string `json:"required,omitempty"`
+	// Labels set on the Deployments, StatefulSets and Pods of the Tenant created without them, along with their Pod templates. The values can reference the Tenant and the Namespace with the $(tenant) and $(namespace) placeholders. Optional.
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// DefaultValue returns the value of the default label, replacing the placeholders.
+func (in *WorkloadLabelsSpec) DefaultValue(key, tenant, namespace string) string {
+	return strings.NewReplacer(WorkloadLabelTenantPlaceholder, tenant, WorkloadLabelNamespacePlaceholder, namespace).Replace(in.Defaults[key])
+}
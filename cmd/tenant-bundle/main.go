@@ -0,0 +1,117 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	goflag "flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/bundle"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(capsulev1beta1.AddToScheme(scheme))
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  %s export --tenant <name> [--file <path>]\tExport the Tenant and its Capsule managed state\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s import --file <path>\t\t\tImport a previously exported Tenant\n\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func main() {
+	var tenantName, file string
+
+	flag.StringVar(&tenantName, "tenant", "", "The name of the Tenant to export")
+	flag.StringVar(&file, "file", "-", "The bundle file, - stands for the standard input or output")
+	flag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create the client: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	switch flag.Arg(0) {
+	case "export":
+		err = export(c, tenantName, file)
+	case "import":
+		err = load(c, file)
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s failed: %s\n", flag.Arg(0), err.Error())
+		os.Exit(1)
+	}
+}
+
+func export(c client.Client, tenantName, file string) error {
+	if len(tenantName) == 0 {
+		return fmt.Errorf("missing Tenant name")
+	}
+
+	list, err := bundle.Export(context.Background(), c, scheme, tenantName)
+	if err != nil {
+		return err
+	}
+
+	data, err := bundle.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	if file == "-" {
+		_, err = os.Stdout.Write(data)
+
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0600)
+}
+
+func load(c client.Client, file string) (err error) {
+	var data []byte
+
+	if file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	list, err := bundle.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	return bundle.Import(context.Background(), c, scheme, list)
+}
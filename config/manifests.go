@@ -0,0 +1,12 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "embed"
+
+// Manifests embeds the Capsule CustomResourceDefinitions and the webhook configurations generated by controller-gen,
+// installed by the test environment.
+//
+//go:embed crd/bases/*.yaml webhook/manifests.yaml
+var Manifests embed.FS
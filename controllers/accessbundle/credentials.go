@@ -0,0 +1,190 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package accessbundle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// credentials are the ones minted on behalf of a Tenant owner.
+type credentials struct {
+	authInfo   clientcmdv1.AuthInfo
+	expiration time.Time
+}
+
+// mint issues the credentials of the given owner: a client certificate signed by the cluster for the User owners,
+// and a bound token for the ServiceAccount ones.
+func (r *Manager) mint(ctx context.Context, tnt *capsulev1beta1.Tenant, owner capsulev1beta1.OwnerSpec, validity time.Duration) (*credentials, error) {
+	switch owner.Kind {
+	case capsulev1beta1.UserOwner:
+		return r.mintCertificate(ctx, tnt, owner.Name, validity)
+	case capsulev1beta1.ServiceAccountOwner:
+		return r.mintToken(ctx, tnt, owner.Name, validity)
+	default:
+		return nil, fmt.Errorf("cannot mint credentials for the %s owners", owner.Kind)
+	}
+}
+
+// mintCertificate requests a client certificate for the given user through a CertificateSigningRequest, approved
+// by Capsule: the certificate carries the Capsule user groups, as expected by the Tenant policies.
+func (r *Manager) mintCertificate(ctx context.Context, tnt *capsulev1beta1.Tenant, user string, validity time.Duration) (*credentials, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   user,
+			Organization: r.Configuration.UserGroups(),
+		},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	expirationSeconds := int32(validity.Seconds())
+
+	csr, err := r.Clientset.CertificatesV1().CertificateSigningRequests().Create(ctx, &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("capsule-%s-", tnt.GetName()),
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request}),
+			SignerName:        certificatesv1.KubeAPIServerClientSignerName,
+			ExpirationSeconds: &expirationSeconds,
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// the CertificateSigningRequest is not needed anymore once the certificate has been retrieved
+	defer func() {
+		_ = r.Clientset.CertificatesV1().CertificateSigningRequests().Delete(context.Background(), csr.GetName(), metav1.DeleteOptions{})
+	}()
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "CapsuleAccessBundle",
+		Message: fmt.Sprintf("Approved by Capsule for the owner %s of the Tenant %s", user, tnt.GetName()),
+	})
+
+	if _, err = r.Clientset.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.GetName(), csr, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	var certificate []byte
+
+	if err = wait.PollImmediate(time.Second, 30*time.Second, func() (bool, error) {
+		issued, err := r.Clientset.CertificatesV1().CertificateSigningRequests().Get(ctx, csr.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		for _, condition := range issued.Status.Conditions {
+			if condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed {
+				return false, fmt.Errorf("the CertificateSigningRequest %s has not been signed: %s", csr.GetName(), condition.Message)
+			}
+		}
+
+		certificate = issued.Status.Certificate
+
+		return len(certificate) > 0, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil, fmt.Errorf("the CertificateSigningRequest %s has an invalid certificate", csr.GetName())
+	}
+
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentials{
+		authInfo: clientcmdv1.AuthInfo{
+			ClientCertificateData: certificate,
+			ClientKeyData:         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+		},
+		expiration: parsed.NotAfter,
+	}, nil
+}
+
+// mintToken requests a bound token for the given ServiceAccount, in the system:serviceaccount:<namespace>:<name> form.
+func (r *Manager) mintToken(ctx context.Context, tnt *capsulev1beta1.Tenant, serviceAccount string, validity time.Duration) (*credentials, error) {
+	namespace, name, err := tenantServiceAccount(tnt, serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	expirationSeconds := int64(validity.Seconds())
+
+	token, err := r.Clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentials{
+		authInfo: clientcmdv1.AuthInfo{
+			Token: token.Status.Token,
+		},
+		expiration: token.Status.ExpirationTimestamp.Time,
+	}, nil
+}
+
+// tenantServiceAccount returns the Namespace and the name of the given ServiceAccount owner, which must belong to the
+// Tenant Namespaces: the tokens of the ServiceAccounts of the other Namespaces, as the kube-system ones, are never
+// minted, since published in the Tenant.
+func tenantServiceAccount(tnt *capsulev1beta1.Tenant, serviceAccount string) (namespace, name string, err error) {
+	parts := strings.Split(strings.TrimPrefix(serviceAccount, serviceAccountPrefix), ":")
+	if !strings.HasPrefix(serviceAccount, serviceAccountPrefix) || len(parts) != 2 {
+		return "", "", fmt.Errorf("the ServiceAccount owner %s is not in the %s<namespace>:<name> form", serviceAccount, serviceAccountPrefix)
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		if ns == parts[0] {
+			return parts[0], parts[1], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("the ServiceAccount owner %s doesn't belong to the Tenant Namespaces", serviceAccount)
+}
@@ -0,0 +1,396 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package accessbundle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+const (
+	// AccessBundleLabel marks the kubeconfig Secrets published by Capsule.
+	AccessBundleLabel = "capsule.clastix.io/access-bundle"
+	// OwnerAnnotation reports the Tenant owner the kubeconfig Secret has been issued for.
+	OwnerAnnotation = "capsule.clastix.io/access-bundle-owner"
+	// IssuedAnnotation and ExpirationAnnotation report the validity of the credentials, in the RFC 3339 format.
+	IssuedAnnotation     = "capsule.clastix.io/access-bundle-issued"
+	ExpirationAnnotation = "capsule.clastix.io/access-bundle-expiration"
+	// KubeconfigKey is the Secret key holding the kubeconfig file.
+	KubeconfigKey = "kubeconfig"
+
+	defaultValidity = 24 * time.Hour
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Manager publishes a kubeconfig Secret, targeting the home Namespace of the Tenants declaring an accessBundle, for
+// each User and ServiceAccount owner, renewing the credentials before their expiration.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+	// APIReader retrieves the Secrets and the Roles, which are not cached.
+	APIReader client.Reader
+	// The Namespace where the kubeconfig Secrets are published, since the co-owners can read any Secret of the Tenant
+	// Namespaces: each Secret is readable only by its owner, through a Role and a RoleBinding named as the Secret.
+	Namespace     string
+	Clientset     kubernetes.Interface
+	Configuration configuration.Configuration
+	// Server is the API server URL set in the kubeconfig files, trusting the CAData certificate authority.
+	Server string
+	CAData []byte
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("accessbundle").
+		For(&capsulev1beta1.Tenant{}).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	secretList := &corev1.SecretList{}
+	if err = r.APIReader.List(ctx, secretList, client.MatchingLabels{capsuleLabel: tnt.GetName(), AccessBundleLabel: "true"}); err != nil {
+		log.Error(err, "Cannot list the kubeconfig Secrets")
+		return
+	}
+
+	published := make(map[string]*corev1.Secret)
+	for i := range secretList.Items {
+		secret := secretList.Items[i]
+		published[secret.GetNamespace()+"/"+secret.GetName()] = &secret
+	}
+
+	desired := make(map[string]struct{})
+
+	if spec := tnt.Spec.AccessBundle; spec != nil {
+		if !sets.NewString(tnt.Status.Namespaces...).Has(spec.HomeNamespace) {
+			log.Info("The home Namespace doesn't belong to the Tenant, skipping the kubeconfig Secrets", "namespace", spec.HomeNamespace)
+		} else {
+			validity := defaultValidity
+			if spec.Validity != nil && spec.Validity.Duration > 0 {
+				validity = spec.Validity.Duration
+			}
+
+			for _, owner := range tnt.Spec.Owners {
+				if owner.Kind != capsulev1beta1.UserOwner && owner.Kind != capsulev1beta1.ServiceAccountOwner {
+					continue
+				}
+
+				name := secretName(tnt.GetName(), owner)
+				desired[r.Namespace+"/"+name] = struct{}{}
+
+				if syncErr := r.syncAccess(ctx, tnt, owner, name); syncErr != nil {
+					log.Error(syncErr, "Cannot sync the kubeconfig Secret access", "owner", owner.Name)
+					err = syncErr
+
+					continue
+				}
+
+				secret := published[r.Namespace+"/"+name]
+
+				renewal, syncErr := r.syncSecret(ctx, tnt, owner, secret, spec.HomeNamespace, name, validity)
+				if syncErr != nil {
+					log.Error(syncErr, "Cannot sync the kubeconfig Secret", "owner", owner.Name)
+					err = syncErr
+
+					continue
+				}
+
+				if after := time.Until(renewal); result.RequeueAfter == 0 || after < result.RequeueAfter {
+					result.RequeueAfter = after
+				}
+			}
+		}
+	}
+	// the Secrets of the removed owners, or published in the home Namespace by the former releases, are deleted,
+	// along with the Role and the RoleBinding granting their access
+	for key, secret := range published {
+		if _, ok := desired[key]; ok || !metav1.IsControlledBy(secret, tnt) {
+			continue
+		}
+
+		objs := []client.Object{secret}
+		if secret.GetNamespace() == r.Namespace {
+			objs = append(objs, &rbacv1.RoleBinding{}, &rbacv1.Role{})
+		}
+
+		for _, obj := range objs {
+			obj.SetNamespace(secret.GetNamespace())
+			obj.SetName(secret.GetName())
+
+			if deleteErr := r.Delete(ctx, obj); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+				log.Error(deleteErr, "Cannot delete the kubeconfig Secret", "secret", key)
+				err = deleteErr
+			}
+		}
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if result.RequeueAfter < 0 {
+		result.RequeueAfter = 0
+		result.Requeue = true
+	}
+
+	return result, nil
+}
+
+// syncAccess grants the given owner, and only them, the read access to their kubeconfig Secret.
+func (r *Manager) syncAccess(ctx context.Context, tnt *capsulev1beta1.Tenant, owner capsulev1beta1.OwnerSpec, name string) error {
+	subject, err := ownerSubject(owner)
+	if err != nil {
+		return err
+	}
+
+	role := &rbacv1.Role{}
+	role.SetNamespace(r.Namespace)
+	role.SetName(name)
+
+	if err = r.createOrUpdate(ctx, tnt, role, func() {
+		role.Rules = []rbacv1.PolicyRule{{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{name},
+			Verbs:         []string{"get"},
+		}}
+	}); err != nil {
+		return err
+	}
+
+	roleBinding := &rbacv1.RoleBinding{}
+	roleBinding.SetNamespace(r.Namespace)
+	roleBinding.SetName(name)
+
+	return r.createOrUpdate(ctx, tnt, roleBinding, func() {
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		}
+		roleBinding.Subjects = []rbacv1.Subject{subject}
+	})
+}
+
+// createOrUpdate retrieves the given object through the APIReader, mutates it and sets the Tenant labels and
+// controller reference, creating or updating it.
+func (r *Manager) createOrUpdate(ctx context.Context, tnt *capsulev1beta1.Tenant, obj client.Object, mutate func()) (err error) {
+	exists := true
+
+	if err = r.APIReader.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		exists = false
+	}
+
+	mutate()
+
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[capsuleLabel] = tnt.GetName()
+	labels[AccessBundleLabel] = "true"
+	obj.SetLabels(labels)
+
+	if err = controllerutil.SetControllerReference(tnt, obj, r.Scheme); err != nil {
+		return err
+	}
+
+	if !exists {
+		return r.Create(ctx, obj)
+	}
+
+	return r.Update(ctx, obj)
+}
+
+// ownerSubject returns the RBAC subject of the given User or ServiceAccount owner.
+func ownerSubject(owner capsulev1beta1.OwnerSpec) (rbacv1.Subject, error) {
+	if owner.Kind == capsulev1beta1.ServiceAccountOwner {
+		parts := strings.Split(strings.TrimPrefix(owner.Name, serviceAccountPrefix), ":")
+		if !strings.HasPrefix(owner.Name, serviceAccountPrefix) || len(parts) != 2 {
+			return rbacv1.Subject{}, fmt.Errorf("the ServiceAccount owner %s is not in the %s<namespace>:<name> form", owner.Name, serviceAccountPrefix)
+		}
+
+		return rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: parts[0], Name: parts[1]}, nil
+	}
+
+	return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: owner.Name}, nil
+}
+
+// syncSecret publishes the kubeconfig Secret of the given owner, minting new credentials only once the current
+// ones have to be renewed, and returns when the next renewal is due.
+func (r *Manager) syncSecret(ctx context.Context, tnt *capsulev1beta1.Tenant, owner capsulev1beta1.OwnerSpec, secret *corev1.Secret, namespace, name string, validity time.Duration) (time.Time, error) {
+	if secret != nil && secret.GetAnnotations()[OwnerAnnotation] == owner.Name {
+		issued, issuedErr := time.Parse(time.RFC3339, secret.GetAnnotations()[IssuedAnnotation])
+		expiration, expirationErr := time.Parse(time.RFC3339, secret.GetAnnotations()[ExpirationAnnotation])
+
+		if issuedErr == nil && expirationErr == nil && time.Now().Before(renewAt(issued, expiration)) {
+			return renewAt(issued, expiration), nil
+		}
+	}
+
+	issued := time.Now()
+
+	creds, err := r.mint(ctx, tnt, owner, validity)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	kubeconfig, err := renderKubeconfig(r.Server, r.CAData, tnt.GetName(), namespace, owner.Name, creds.authInfo)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.Namespace,
+				Name:      name,
+			},
+		}
+	}
+
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	labels := secret.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[capsuleLabel] = tnt.GetName()
+	labels[AccessBundleLabel] = "true"
+	secret.SetLabels(labels)
+
+	annotations := secret.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[OwnerAnnotation] = owner.Name
+	annotations[IssuedAnnotation] = issued.UTC().Format(time.RFC3339)
+	annotations[ExpirationAnnotation] = creds.expiration.UTC().Format(time.RFC3339)
+	secret.SetAnnotations(annotations)
+
+	secret.Type = corev1.SecretTypeOpaque
+	secret.Data = map[string][]byte{
+		KubeconfigKey: kubeconfig,
+	}
+
+	if err = controllerutil.SetControllerReference(tnt, secret, r.Scheme); err != nil {
+		return time.Time{}, err
+	}
+
+	if len(secret.GetResourceVersion()) == 0 {
+		err = r.Create(ctx, secret)
+	} else {
+		err = r.Update(ctx, secret)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	r.Log.Info("kubeconfig Secret published", "tenant", tnt.GetName(), "owner", owner.Name, "expiration", creds.expiration)
+
+	return renewAt(issued, creds.expiration), nil
+}
+
+// secretName returns the name of the kubeconfig Secret of the given Tenant owner, as oil-alice-kubeconfig for the
+// User alice, or oil-sa-ci-deployer-kubeconfig for the ServiceAccount system:serviceaccount:ci:deployer.
+func secretName(tenant string, owner capsulev1beta1.OwnerSpec) string {
+	name := strings.ToLower(owner.Name)
+	if owner.Kind == capsulev1beta1.ServiceAccountOwner {
+		name = "sa-" + strings.TrimPrefix(name, serviceAccountPrefix)
+	}
+	name = tenant + "-" + name
+
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+
+	const suffix = "-kubeconfig"
+	if max := 253 - len(suffix); len(name) > max {
+		name = name[:max]
+	}
+
+	return name + suffix
+}
+
+// renewAt returns when the credentials have to be renewed, once two thirds of their validity elapsed.
+func renewAt(issued, expiration time.Time) time.Time {
+	return issued.Add(expiration.Sub(issued) * 2 / 3)
+}
+
+// renderKubeconfig renders a kubeconfig file with a single context, named as the Tenant, targeting the home Namespace.
+func renderKubeconfig(server string, caData []byte, tenant, namespace, user string, authInfo clientcmdv1.AuthInfo) ([]byte, error) {
+	b, err := yaml.Marshal(clientcmdv1.Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []clientcmdv1.NamedCluster{{
+			Name: tenant,
+			Cluster: clientcmdv1.Cluster{
+				Server:                   server,
+				CertificateAuthorityData: caData,
+			},
+		}},
+		AuthInfos: []clientcmdv1.NamedAuthInfo{{
+			Name:     user,
+			AuthInfo: authInfo,
+		}},
+		Contexts: []clientcmdv1.NamedContext{{
+			Name: tenant,
+			Context: clientcmdv1.Context{
+				Cluster:   tenant,
+				AuthInfo:  user,
+				Namespace: namespace,
+			},
+		}},
+		CurrentContext: tenant,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot render the kubeconfig: %w", err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package accessbundle
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestSecretName(t *testing.T) {
+	assert.Equal(t, "oil-alice-kubeconfig", secretName("oil", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: "alice"}))
+	assert.Equal(t, "oil-alice-bigorg-com-kubeconfig", secretName("oil", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: "Alice@bigorg.com"}))
+	assert.Equal(t, "oil-sa-ci-deployer-kubeconfig", secretName("oil", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.ServiceAccountOwner, Name: "system:serviceaccount:ci:deployer"}))
+	assert.Len(t, secretName("oil", capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: strings.Repeat("a", 300)}), 253)
+}
+
+func TestSyncAccess(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "oil-uid"}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &Manager{Client: c, APIReader: c, Scheme: scheme, Namespace: "capsule-system"}
+
+	for _, tc := range []struct {
+		owner   capsulev1beta1.OwnerSpec
+		subject rbacv1.Subject
+	}{
+		{
+			owner:   capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+			subject: rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "alice"},
+		},
+		{
+			owner:   capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.ServiceAccountOwner, Name: "system:serviceaccount:ci:deployer"},
+			subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "ci", Name: "deployer"},
+		},
+	} {
+		name := secretName(tnt.GetName(), tc.owner)
+		// the second sync updates the existing objects
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, r.syncAccess(context.Background(), tnt, tc.owner, name))
+		}
+
+		role := &rbacv1.Role{}
+		assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "capsule-system", Name: name}, role))
+		assert.Equal(t, []string{name}, role.Rules[0].ResourceNames)
+		assert.Equal(t, []string{"get"}, role.Rules[0].Verbs)
+		assert.True(t, metav1.IsControlledBy(role, tnt))
+
+		roleBinding := &rbacv1.RoleBinding{}
+		assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "capsule-system", Name: name}, roleBinding))
+		assert.Equal(t, name, roleBinding.RoleRef.Name)
+		assert.Equal(t, []rbacv1.Subject{tc.subject}, roleBinding.Subjects)
+	}
+}
+
+func TestRenewAt(t *testing.T) {
+	issued := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, issued.Add(20*24*time.Hour), renewAt(issued, issued.Add(30*24*time.Hour)))
+}
+
+func TestRenderKubeconfig(t *testing.T) {
+	b, err := renderKubeconfig("https://k8s.bigorg.com:6443", []byte("ca"), "oil", "oil-production", "alice", clientcmdv1.AuthInfo{Token: "token"})
+	assert.NoError(t, err)
+
+	config := clientcmdv1.Config{}
+	assert.NoError(t, yaml.Unmarshal(b, &config))
+	assert.Equal(t, "oil", config.CurrentContext)
+	assert.Equal(t, "oil-production", config.Contexts[0].Context.Namespace)
+	assert.Equal(t, "alice", config.Contexts[0].Context.AuthInfo)
+	assert.Equal(t, "https://k8s.bigorg.com:6443", config.Clusters[0].Cluster.Server)
+	assert.Equal(t, []byte("ca"), config.Clusters[0].Cluster.CertificateAuthorityData)
+	assert.Equal(t, "token", config.AuthInfos[0].AuthInfo.Token)
+}
+
+func TestTenantServiceAccount(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{Status: capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production"}}}
+
+	namespace, name, err := tenantServiceAccount(tnt, "system:serviceaccount:oil-production:deployer")
+	assert.NoError(t, err)
+	assert.Equal(t, "oil-production", namespace)
+	assert.Equal(t, "deployer", name)
+
+	for _, serviceAccount := range []string{"system:serviceaccount:kube-system:default", "system:serviceaccount:oil-production", "deployer"} {
+		_, _, err = tenantServiceAccount(tnt, serviceAccount)
+		assert.Error(t, err, serviceAccount)
+	}
+}
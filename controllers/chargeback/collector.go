@@ -0,0 +1,171 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+var podMetricsListGVK = schema.GroupVersionKind{
+	Group:   "metrics.k8s.io",
+	Version: "v1beta1",
+	Kind:    "PodMetricsList",
+}
+
+// Collector periodically aggregates the resources requested and used by each Tenant, reporting them in the
+// Tenant status and as Prometheus metrics, and serving the last report for the chargeback export.
+type Collector struct {
+	Client client.Client
+	// Reader retrieves the Pods, the PersistentVolumeClaims and their metrics of the Tenant Namespaces, rather than
+	// caching them cluster-wide.
+	Reader client.Reader
+	Log    logr.Logger
+	// How often the usage is collected.
+	Period time.Duration
+
+	mutex  sync.RWMutex
+	report []Entry
+}
+
+// Entry is the usage of a single Tenant, as served by the export endpoint.
+type Entry struct {
+	Tenant   string              `json:"tenant"`
+	Requests corev1.ResourceList `json:"requests"`
+	Used     corev1.ResourceList `json:"used"`
+	Time     metav1.Time         `json:"time"`
+}
+
+// InjectClient injects the Client interface, required by the Runnable interface
+func (c *Collector) InjectClient(client client.Client) error {
+	c.Client = client
+
+	return nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Period)
+	defer ticker.Stop()
+
+	for {
+		c.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Collector) collect(ctx context.Context) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.Client.List(ctx, tntList); err != nil {
+		c.Log.Error(err, "Cannot list Tenants")
+
+		return
+	}
+
+	report := make([]Entry, 0, len(tntList.Items))
+
+	tenantRequests.Reset()
+	tenantUsage.Reset()
+
+	for i := range tntList.Items {
+		tnt := tntList.Items[i]
+
+		usage, err := c.usage(ctx, tnt)
+		if err != nil {
+			c.Log.Error(err, "Cannot collect the Tenant usage", "tenant", tnt.GetName())
+
+			continue
+		}
+
+		err = retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+			found := &capsulev1beta1.Tenant{}
+			if err = c.Client.Get(ctx, types.NamespacedName{Name: tnt.GetName()}, found); err != nil {
+				return
+			}
+
+			found.Status.Usage = usage
+
+			return c.Client.Status().Update(ctx, found)
+		})
+		if err != nil {
+			c.Log.Error(err, "Cannot update the Tenant usage", "tenant", tnt.GetName())
+		}
+
+		for _, name := range resourceNames {
+			if value, ok := usage.Requests[name]; ok {
+				tenantRequests.WithLabelValues(tnt.GetName(), name.String()).Set(value.AsApproximateFloat64())
+			}
+			if value, ok := usage.Used[name]; ok {
+				tenantUsage.WithLabelValues(tnt.GetName(), name.String()).Set(value.AsApproximateFloat64())
+			}
+		}
+
+		report = append(report, Entry{
+			Tenant:   tnt.GetName(),
+			Requests: usage.Requests,
+			Used:     usage.Used,
+			Time:     usage.LastUpdateTime,
+		})
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Tenant < report[j].Tenant
+	})
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.report = report
+}
+
+func (c *Collector) usage(ctx context.Context, tnt capsulev1beta1.Tenant) (*capsulev1beta1.TenantUsage, error) {
+	requests, used := corev1.ResourceList{}, corev1.ResourceList{}
+
+	for _, ns := range tnt.Status.Namespaces {
+		pods := &corev1.PodList{}
+		if err := c.Reader.List(ctx, pods, client.InNamespace(ns)); err != nil {
+			return nil, err
+		}
+		podRequests(requests, pods.Items)
+
+		claims := &corev1.PersistentVolumeClaimList{}
+		if err := c.Reader.List(ctx, claims, client.InNamespace(ns)); err != nil {
+			return nil, err
+		}
+		claimRequests(requests, claims.Items)
+
+		metrics := &unstructured.UnstructuredList{}
+		metrics.SetGroupVersionKind(podMetricsListGVK)
+		// the metrics-server could be not installed, reporting just the requests
+		if err := c.Reader.List(ctx, metrics, client.InNamespace(ns)); err != nil {
+			c.Log.V(5).Info("Cannot retrieve the Pod metrics", "namespace", ns, "error", err.Error())
+
+			continue
+		}
+		podMetricsUsage(used, metrics.Items)
+	}
+
+	return &capsulev1beta1.TenantUsage{
+		Requests:       requests,
+		Used:           used,
+		LastUpdateTime: metav1.Now(),
+	}, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ServeHTTP exports the last collected report, as JSON or CSV according to the format query parameter.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var err error
+
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		err = writeCSV(w, c.report)
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(c.report)
+	default:
+		http.Error(w, "unsupported format, use json or csv", http.StatusBadRequest)
+
+		return
+	}
+
+	if err != nil {
+		c.Log.Error(err, "Cannot export the chargeback report")
+	}
+}
+
+func writeCSV(w io.Writer, report []Entry) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"tenant", "time"}
+	for _, name := range resourceNames {
+		header = append(header, "requests."+name.String())
+	}
+	for _, name := range resourceNames {
+		header = append(header, "used."+name.String())
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range report {
+		record := []string{entry.Tenant, entry.Time.UTC().Format(time.RFC3339)}
+
+		for _, name := range resourceNames {
+			value := entry.Requests[name]
+			record = append(record, value.String())
+		}
+		for _, name := range resourceNames {
+			value := entry.Used[name]
+			record = append(record, value.String())
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
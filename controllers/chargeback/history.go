@@ -0,0 +1,134 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// History periodically records a snapshot of the namespaces, the running Pods and the CPU and memory requested by
+// each Tenant in its status, keeping the last ones, so that the growth trends are visible without an external TSDB.
+type History struct {
+	Client client.Client
+	Log    logr.Logger
+	// How often the snapshots are recorded.
+	Period time.Duration
+	// How many snapshots are kept for each Tenant.
+	Size int
+}
+
+// InjectClient injects the Client interface, required by the Runnable interface
+func (h *History) InjectClient(client client.Client) error {
+	h.Client = client
+
+	return nil
+}
+
+func (h *History) Start(ctx context.Context) error {
+	ticker := time.NewTicker(h.Period)
+	defer ticker.Stop()
+
+	for {
+		h.record(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *History) record(ctx context.Context) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := h.Client.List(ctx, tntList); err != nil {
+		h.Log.Error(err, "Cannot list Tenants")
+
+		return
+	}
+
+	for i := range tntList.Items {
+		tnt := tntList.Items[i]
+
+		snapshot, err := h.snapshot(ctx, tnt)
+		if err != nil {
+			h.Log.Error(err, "Cannot take the Tenant usage snapshot", "tenant", tnt.GetName())
+
+			continue
+		}
+
+		err = retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+			found := &capsulev1beta1.Tenant{}
+			if err = h.Client.Get(ctx, types.NamespacedName{Name: tnt.GetName()}, found); err != nil {
+				return
+			}
+
+			found.Status.UsageHistory = appendSnapshot(found.Status.UsageHistory, snapshot, h.Size)
+
+			return h.Client.Status().Update(ctx, found)
+		})
+		if err != nil {
+			h.Log.Error(err, "Cannot update the Tenant usage history", "tenant", tnt.GetName())
+		}
+	}
+}
+
+func (h *History) snapshot(ctx context.Context, tnt capsulev1beta1.Tenant) (snapshot capsulev1beta1.UsageSnapshot, err error) {
+	var pods []corev1.Pod
+
+	for _, ns := range tnt.Status.Namespaces {
+		podList := &corev1.PodList{}
+		if err = h.Client.List(ctx, podList, client.InNamespace(ns)); err != nil {
+			return
+		}
+
+		pods = append(pods, podList.Items...)
+	}
+
+	return takeSnapshot(len(tnt.Status.Namespaces), pods, metav1.Now()), nil
+}
+
+// takeSnapshot counts the running Pods, the terminated ones not reserving capacity anymore, and sums their CPU and
+// memory requests.
+func takeSnapshot(namespaces int, pods []corev1.Pod, now metav1.Time) capsulev1beta1.UsageSnapshot {
+	requests := corev1.ResourceList{}
+	podRequests(requests, pods)
+	delete(requests, corev1.ResourceStorage)
+
+	running := 0
+
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			running++
+		}
+	}
+
+	return capsulev1beta1.UsageSnapshot{
+		Time:       now,
+		Namespaces: namespaces,
+		Pods:       running,
+		Requests:   requests,
+	}
+}
+
+// appendSnapshot appends the snapshot to the history, dropping the oldest ones exceeding the size.
+func appendSnapshot(history []capsulev1beta1.UsageSnapshot, snapshot capsulev1beta1.UsageSnapshot, size int) []capsulev1beta1.UsageSnapshot {
+	history = append(history, snapshot)
+
+	if len(history) > size {
+		history = append([]capsulev1beta1.UsageSnapshot{}, history[len(history)-size:]...)
+	}
+
+	return history
+}
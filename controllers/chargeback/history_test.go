@@ -0,0 +1,57 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestTakeSnapshot(t *testing.T) {
+	pod := func(phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:     resource.MustParse("100m"),
+					corev1.ResourceMemory:  resource.MustParse("64Mi"),
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	now := metav1.Now()
+	snapshot := takeSnapshot(2, []corev1.Pod{pod(corev1.PodRunning), pod(corev1.PodPending), pod(corev1.PodSucceeded)}, now)
+
+	assert.Equal(t, now, snapshot.Time)
+	assert.Equal(t, 2, snapshot.Namespaces)
+	assert.Equal(t, 2, snapshot.Pods)
+	assert.Equal(t, "200m", snapshot.Requests.Cpu().String())
+	assert.Equal(t, "128Mi", snapshot.Requests.Memory().String())
+	assert.NotContains(t, snapshot.Requests, corev1.ResourceStorage)
+}
+
+func TestAppendSnapshot(t *testing.T) {
+	snapshot := func(minutes int) capsulev1beta1.UsageSnapshot {
+		return capsulev1beta1.UsageSnapshot{Time: metav1.NewTime(time.Unix(0, 0).Add(time.Duration(minutes) * time.Minute)), Pods: minutes}
+	}
+
+	var history []capsulev1beta1.UsageSnapshot
+	for i := 0; i < 5; i++ {
+		history = appendSnapshot(history, snapshot(i), 3)
+	}
+
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, 2, history[0].Pods)
+		assert.Equal(t, 4, history[2].Pods)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	tenantRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capsule_tenant_resource_requests",
+		Help: "The resources requested by the Tenant, CPU in cores, memory and storage in bytes.",
+	}, []string{"tenant", "resource"})
+
+	tenantUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "capsule_tenant_resource_usage",
+		Help: "The resources used by the Tenant as reported by the metrics-server, CPU in cores, memory in bytes.",
+	}, []string{"tenant", "resource"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(tenantRequests, tenantUsage)
+}
@@ -0,0 +1,83 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The resources collected for the chargeback.
+var resourceNames = []corev1.ResourceName{
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+	corev1.ResourceStorage,
+}
+
+func add(total corev1.ResourceList, list corev1.ResourceList) {
+	for _, name := range resourceNames {
+		value, ok := list[name]
+		if !ok {
+			continue
+		}
+
+		current := total[name]
+		current.Add(value)
+		total[name] = current
+	}
+}
+
+// podRequests sums the requests of the containers and the overhead of the running Pods,
+// the terminated ones are not accounted since they don't reserve capacity anymore.
+func podRequests(total corev1.ResourceList, pods []corev1.Pod) {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			add(total, container.Resources.Requests)
+		}
+
+		add(total, pod.Spec.Overhead)
+	}
+}
+
+// claimRequests sums the storage requested by the PersistentVolumeClaims.
+func claimRequests(total corev1.ResourceList, claims []corev1.PersistentVolumeClaim) {
+	for _, claim := range claims {
+		add(total, corev1.ResourceList{
+			corev1.ResourceStorage: claim.Spec.Resources.Requests[corev1.ResourceStorage],
+		})
+	}
+}
+
+// podMetricsUsage sums the usage of the containers reported by the metrics.k8s.io PodMetrics objects.
+func podMetricsUsage(total corev1.ResourceList, items []unstructured.Unstructured) {
+	for _, item := range items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			usage, _, _ := unstructured.NestedStringMap(container, "usage")
+
+			list := corev1.ResourceList{}
+			for k, v := range usage {
+				quantity, err := resource.ParseQuantity(v)
+				if err != nil {
+					continue
+				}
+
+				list[corev1.ResourceName(k)] = quantity
+			}
+
+			add(total, list)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package chargeback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPodRequests(t *testing.T) {
+	container := func(cpu, memory string) corev1.Container {
+		return corev1.Container{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+
+	pods := []corev1.Pod{
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{container("100m", "128Mi"), container("200m", "128Mi")}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{container("500m", "1Gi")},
+				Overhead:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{container("1", "1Gi")}},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+
+	total := corev1.ResourceList{}
+	podRequests(total, pods)
+
+	cpu, memory := total[corev1.ResourceCPU], total[corev1.ResourceMemory]
+	assert.Equal(t, int64(850), cpu.MilliValue())
+	assert.Equal(t, int64(1280*1024*1024), memory.Value())
+}
+
+func TestClaimRequests(t *testing.T) {
+	claim := func(storage string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+				},
+			},
+		}
+	}
+
+	total := corev1.ResourceList{}
+	claimRequests(total, []corev1.PersistentVolumeClaim{claim("1Gi"), claim("3Gi")})
+
+	storage := total[corev1.ResourceStorage]
+	assert.Equal(t, int64(4*1024*1024*1024), storage.Value())
+}
+
+func TestPodMetricsUsage(t *testing.T) {
+	metrics := []unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "usage": map[string]interface{}{"cpu": "12m", "memory": "10Mi"}},
+				map[string]interface{}{"name": "b", "usage": map[string]interface{}{"cpu": "3m", "memory": "6Mi"}},
+			},
+		}},
+	}
+
+	total := corev1.ResourceList{}
+	podMetricsUsage(total, metrics)
+
+	cpu, memory := total[corev1.ResourceCPU], total[corev1.ResourceMemory]
+	assert.Equal(t, int64(15), cpu.MilliValue())
+	assert.Equal(t, int64(16*1024*1024), memory.Value())
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := []Entry{
+		{
+			Tenant:   "oil",
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("850m")},
+			Used:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("15m")},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, writeCSV(buf, report))
+	assert.Equal(t, "tenant,time,requests.cpu,requests.memory,requests.storage,used.cpu,used.memory,used.storage\n"+
+		"oil,0001-01-01T00:00:00Z,850m,0,0,15m,0,0\n", buf.String())
+}
@@ -0,0 +1,224 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// Finalizer ensures the objects created by Capsule for the Tenant outside of its Namespaces are removed.
+const Finalizer = "capsule.clastix.io/cleanup"
+
+// pendingRequeue is how often the removal of the pending objects is checked, since their deletion isn't watched.
+const pendingRequeue = 30 * time.Second
+
+// residueKinds are the kinds of the objects Capsule creates for the Tenants outside of their Namespaces, as the
+// cluster-scoped ones: the kinds whose API is not installed are skipped.
+var residueKinds = []schema.GroupVersionKind{
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+	{Group: "kyverno.io", Version: "v1", Kind: "ClusterPolicy"},
+	{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "ClusterQueue"},
+	{Group: "velero.io", Version: "v1", Kind: "Schedule"},
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+}
+
+// Manager removes, upon the Tenant deletion, the objects created by Capsule for the Tenant outside of its Namespaces,
+// rather than relying on the garbage collector, which orphans them when the Tenant is deleted with the orphan
+// propagation policy: the Tenant deletion is held by a finalizer until they're gone, reporting the pending ones in the
+// Tenant status.
+type Manager struct {
+	client.Client
+	Log      logr.Logger
+	Options  controller.Options
+	Recorder record.EventRecorder
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cleanup").
+		For(&capsulev1beta1.Tenant{}).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		log.Error(err, "Error reading the object")
+
+		return
+	}
+
+	if tnt.GetDeletionTimestamp().IsZero() {
+		if !controllerutil.ContainsFinalizer(tnt, Finalizer) {
+			controllerutil.AddFinalizer(tnt, Finalizer)
+
+			if err = r.Update(ctx, tnt); err != nil {
+				log.Error(err, "Cannot add the cleanup finalizer")
+			}
+		}
+
+		return
+	}
+
+	if !controllerutil.ContainsFinalizer(tnt, Finalizer) {
+		return
+	}
+
+	pending, removalErr := r.removeResidues(ctx, log, tnt)
+
+	var status *capsulev1beta1.CleanupStatus
+	if len(pending) > 0 || removalErr != nil {
+		status = &capsulev1beta1.CleanupStatus{Pending: pending}
+
+		if removalErr != nil {
+			status.Message = removalErr.Error()
+		}
+	}
+
+	if err = r.updateStatus(ctx, tnt, status); err != nil {
+		log.Error(err, "Cannot update the Tenant cleanup status")
+
+		return
+	}
+
+	if status != nil {
+		if removalErr != nil {
+			r.Recorder.Eventf(tnt, corev1.EventTypeWarning, "CleanupFailed", "Cannot remove the objects created for the Tenant: %s", removalErr.Error())
+		}
+
+		log.Info("Waiting for the removal of the objects created for the Tenant", "pending", pending)
+
+		return ctrl.Result{RequeueAfter: pendingRequeue}, nil
+	}
+
+	// the Tenant status may have been updated meanwhile
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), tnt); err != nil {
+			return
+		}
+
+		controllerutil.RemoveFinalizer(tnt, Finalizer)
+
+		return r.Update(ctx, tnt)
+	})
+	if err = client.IgnoreNotFound(err); err != nil {
+		log.Error(err, "Cannot remove the cleanup finalizer")
+	}
+
+	return
+}
+
+// removeResidues deletes the objects controlled by the Tenant among the residue kinds, returning the ones still
+// existing, as the ones held by their own finalizers.
+func (r *Manager) removeResidues(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant) (pending []string, err error) {
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+
+	for _, gvk := range residueKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		if err = r.List(ctx, list, client.MatchingLabels{tenantLabel: tnt.GetName()}); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("cannot list %s: %w", gvk.Kind, err))
+
+			continue
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			// removing only the objects managed by Capsule
+			if !metav1.IsControlledBy(obj, tnt) {
+				continue
+			}
+
+			if !obj.GetDeletionTimestamp().IsZero() {
+				pending = append(pending, residueName(obj))
+
+				continue
+			}
+
+			log.Info("Removing the object created for the Tenant", "kind", gvk.Kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+
+			if err = r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("cannot delete %s: %w", residueName(obj), err))
+				pending = append(pending, residueName(obj))
+
+				continue
+			}
+			// the objects held by their own finalizers are not removed straight away
+			if err == nil && len(obj.GetFinalizers()) > 0 {
+				pending = append(pending, residueName(obj))
+			}
+		}
+	}
+
+	sort.Strings(pending)
+
+	return pending, utilerrors.NewAggregate(errs)
+}
+
+func (r *Manager) updateStatus(ctx context.Context, tnt *capsulev1beta1.Tenant, status *capsulev1beta1.CleanupStatus) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), found); err != nil {
+			return
+		}
+
+		if equality.Semantic.DeepEqual(found.Status.Cleanup, status) {
+			return nil
+		}
+
+		found.Status.Cleanup = status
+
+		return r.Client.Status().Update(ctx, found)
+	})
+}
+
+// residueName returns the name of the object in the Kind/name or Kind/namespace/name form.
+func residueName(obj *unstructured.Unstructured) string {
+	if len(obj.GetNamespace()) > 0 {
+		return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+}
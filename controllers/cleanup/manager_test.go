@@ -0,0 +1,58 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	flowcontrolv1beta1 "k8s.io/api/flowcontrol/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestRemoveResidues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+	assert.NoError(t, apiextensionsv1.AddToScheme(scheme))
+
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "oil-uid"}}
+	labels := map[string]string{"capsule.clastix.io/tenant": "oil"}
+	controlled := []metav1.OwnerReference{{
+		APIVersion: capsulev1beta1.GroupVersion.String(),
+		Kind:       "Tenant",
+		Name:       "oil",
+		UID:        "oil-uid",
+		Controller: pointer.BoolPtr(true),
+	}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		tnt,
+		&flowcontrolv1beta1.FlowSchema{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil", Labels: labels, OwnerReferences: controlled}},
+		&flowcontrolv1beta1.PriorityLevelConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil", Labels: labels, OwnerReferences: controlled, Finalizers: []string{"example.com/hold"}}},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "oil-policies", Labels: labels}},
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "pipelines.oil.bigorg.com", Labels: labels, OwnerReferences: controlled}},
+	).Build()
+
+	pending, err := (&Manager{Client: c}).removeResidues(context.Background(), logr.Discard(), tnt)
+	assert.NoError(t, err)
+	// the objects held by their own finalizers are pending
+	assert.Equal(t, []string{"PriorityLevelConfiguration/capsule-oil"}, pending)
+
+	assert.Error(t, c.Get(context.Background(), client.ObjectKey{Name: "capsule-oil"}, &flowcontrolv1beta1.FlowSchema{}))
+	assert.Error(t, c.Get(context.Background(), client.ObjectKey{Name: "pipelines.oil.bigorg.com"}, &apiextensionsv1.CustomResourceDefinition{}))
+	// the objects not controlled by the Tenant are left untouched
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "oil-policies"}, &admissionregistrationv1.ValidatingWebhookConfiguration{}))
+}
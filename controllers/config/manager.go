@@ -11,17 +11,21 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
 	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/logging"
 )
 
 type Manager struct {
-	Log    logr.Logger
-	Client client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Client  client.Client
+	Levels  *logging.Levels
 }
 
 // InjectClient injects the Client interface, required by the Runnable interface
@@ -55,6 +59,7 @@ func forOptionPerInstanceName(instanceName string) builder.ForOption {
 func (c *Manager) SetupWithManager(mgr ctrl.Manager, configurationName string) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&capsulev1alpha1.CapsuleConfiguration{}, forOptionPerInstanceName(configurationName)).
+		WithOptions(c.Options).
 		Complete(c)
 }
 
@@ -67,6 +72,16 @@ func (c *Manager) Reconcile(ctx context.Context, request reconcile.Request) (res
 		panic(errors.Wrap(err, "Invalid configuration for protected Namespace regex"))
 	}
 
+	if _, err = cfg.ProtectedNamespaces(); err != nil {
+		panic(errors.Wrap(err, "Invalid configuration for protected Namespaces rules"))
+	}
+	// the logging levels are applied at runtime, keeping the current ones if not valid
+	if c.Levels != nil {
+		if levelsErr := c.Levels.Set(cfg.Logging()); levelsErr != nil {
+			c.Log.Error(levelsErr, "Invalid logging levels, keeping the current ones", "request.name", request.Name)
+		}
+	}
+
 	c.Log.Info("CapsuleConfiguration reconciliation finished", "request.name", request.Name)
 
 	return
@@ -0,0 +1,72 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The Secret keys containing the kubeconfig of the member cluster: the latter is the one used by Cluster API.
+var kubeconfigKeys = []string{"kubeconfig", "value"}
+
+type memberClient struct {
+	resourceVersion string
+	client          client.Client
+}
+
+// memberClients caches the clients of the member clusters, rebuilding them only upon a change of the Secret.
+type memberClients struct {
+	mutex   sync.Mutex
+	scheme  *runtime.Scheme
+	clients map[string]memberClient
+}
+
+func (m *memberClients) get(secret *corev1.Secret) (client.Client, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.clients == nil {
+		m.clients = make(map[string]memberClient)
+	}
+
+	if cached, ok := m.clients[secret.GetName()]; ok && cached.resourceVersion == secret.GetResourceVersion() {
+		return cached.client, nil
+	}
+
+	var kubeconfig []byte
+	for _, key := range kubeconfigKeys {
+		if value, ok := secret.Data[key]; ok {
+			kubeconfig = value
+
+			break
+		}
+	}
+
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("the Secret %s doesn't contain any kubeconfig", secret.GetName())
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(config, client.Options{Scheme: m.scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	m.clients[secret.GetName()] = memberClient{
+		resourceVersion: secret.GetResourceVersion(),
+		client:          c,
+	}
+
+	return c, nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/utils"
+)
+
+const (
+	// MemberClusterLabel marks the Secrets containing the kubeconfig of a member cluster, the value is the cluster name.
+	MemberClusterLabel = "capsule.clastix.io/member-cluster"
+	// HubAnnotation marks the Tenants of a member cluster replicated by the federation hub.
+	HubAnnotation = "capsule.clastix.io/federation-hub"
+	// Finalizer ensures the replicated Tenants are removed from the member clusters.
+	Finalizer = "capsule.clastix.io/federation"
+)
+
+// Manager replicates the Tenant definitions from the hub cluster to the member ones,
+// reporting back the status of each replica into the hub Tenant status.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+	// The Namespace where the member cluster Secrets are looked up.
+	Namespace string
+	// How often the Tenants are replicated, regardless of any change.
+	SyncPeriod time.Duration
+	// The fraction of the SyncPeriod randomly subtracted from it, so that the Tenants are not replicated in lockstep.
+	SyncJitter float64
+
+	clients memberClients
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	r.clients = memberClients{scheme: r.Scheme}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("federation").
+		For(&capsulev1beta1.Tenant{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) (requests []reconcile.Request) {
+			tntList := &capsulev1beta1.TenantList{}
+			if err := r.List(context.Background(), tntList); err != nil {
+				r.Log.Error(err, "Cannot list Tenants upon member cluster change")
+
+				return
+			}
+
+			for _, tnt := range tntList.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: tnt.GetName()}})
+			}
+
+			return
+		}), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, ok := object.GetLabels()[MemberClusterLabel]
+
+			return ok && object.GetNamespace() == r.Namespace
+		}))).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+
+	secretList := &corev1.SecretList{}
+	if err = r.List(ctx, secretList, client.InNamespace(r.Namespace), client.HasLabels{MemberClusterLabel}); err != nil {
+		log.Error(err, "Cannot list member clusters")
+		return
+	}
+
+	sort.SliceStable(secretList.Items, func(i, j int) bool {
+		return clusterName(secretList.Items[i]) < clusterName(secretList.Items[j])
+	})
+
+	if !tnt.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.removeReplicas(ctx, log, tnt, secretList.Items)
+	}
+
+	if !controllerutil.ContainsFinalizer(tnt, Finalizer) {
+		controllerutil.AddFinalizer(tnt, Finalizer)
+
+		if err = r.Update(ctx, tnt); err != nil {
+			log.Error(err, "Cannot add the federation finalizer")
+			return
+		}
+	}
+
+	statuses := make([]capsulev1beta1.ClusterStatus, 0, len(secretList.Items))
+	for _, secret := range secretList.Items {
+		statuses = append(statuses, r.syncReplica(ctx, log, tnt, secret))
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, types.NamespacedName{Name: tnt.GetName()}, found); err != nil {
+			return
+		}
+
+		found.Status.Clusters = statuses
+
+		return r.Status().Update(ctx, found)
+	})
+	if err != nil {
+		log.Error(err, "Cannot update the member clusters status")
+		return
+	}
+
+	return ctrl.Result{RequeueAfter: utils.Jitter(r.SyncPeriod, r.SyncJitter)}, nil
+}
+
+func (r *Manager) syncReplica(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, secret corev1.Secret) (status capsulev1beta1.ClusterStatus) {
+	status.Name = clusterName(secret)
+
+	c, err := r.clients.get(&secret)
+	if err != nil {
+		status.Message = err.Error()
+
+		return
+	}
+
+	replica := &capsulev1beta1.Tenant{}
+	replica.SetName(tnt.GetName())
+
+	var res controllerutil.OperationResult
+	res, err = controllerutil.CreateOrUpdate(ctx, c, replica, func() error {
+		if len(replica.GetResourceVersion()) > 0 {
+			if _, ok := replica.GetAnnotations()[HubAnnotation]; !ok {
+				return fmt.Errorf("the Tenant %s already exists and it's not managed by the federation hub", replica.GetName())
+			}
+		}
+
+		labels := make(map[string]string)
+		for k, v := range tnt.GetLabels() {
+			labels[k] = v
+		}
+		replica.SetLabels(labels)
+
+		annotations := make(map[string]string)
+		for k, v := range tnt.GetAnnotations() {
+			annotations[k] = v
+		}
+		annotations[HubAnnotation] = "true"
+		replica.SetAnnotations(annotations)
+
+		replica.Spec = tnt.Spec
+
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Cannot replicate Tenant", "cluster", status.Name)
+
+		status.Message = err.Error()
+
+		return
+	}
+
+	log.Info("Tenant replica synced", "result", res, "cluster", status.Name)
+
+	status.Synced = true
+	status.State = replica.Status.State
+	status.Size = replica.Status.Size
+
+	return
+}
+
+func (r *Manager) removeReplicas(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, secrets []corev1.Secret) (err error) {
+	if !controllerutil.ContainsFinalizer(tnt, Finalizer) {
+		return nil
+	}
+
+	for i := range secrets {
+		var c client.Client
+		if c, err = r.clients.get(&secrets[i]); err != nil {
+			return
+		}
+
+		replica := &capsulev1beta1.Tenant{}
+		if err = c.Get(ctx, types.NamespacedName{Name: tnt.GetName()}, replica); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return
+		}
+
+		if _, ok := replica.GetAnnotations()[HubAnnotation]; !ok {
+			continue
+		}
+
+		if err = client.IgnoreNotFound(c.Delete(ctx, replica)); err != nil {
+			return
+		}
+
+		log.Info("Tenant replica removed", "cluster", clusterName(secrets[i]))
+	}
+
+	controllerutil.RemoveFinalizer(tnt, Finalizer)
+
+	return r.Update(ctx, tnt)
+}
+
+func clusterName(secret corev1.Secret) string {
+	if name := secret.GetLabels()[MemberClusterLabel]; len(name) > 0 {
+		return name
+	}
+
+	return secret.GetName()
+}
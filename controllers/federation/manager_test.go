@@ -0,0 +1,73 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestSyncReplica(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	member := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "gas"}},
+	).Build()
+
+	secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:            "eu-west",
+		ResourceVersion: "1",
+		Labels:          map[string]string{MemberClusterLabel: "europe"},
+	}}
+
+	r := &Manager{Scheme: scheme}
+	r.clients.clients = map[string]memberClient{
+		secret.GetName(): {resourceVersion: secret.GetResourceVersion(), client: member},
+	}
+
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil", Labels: map[string]string{"env": "production"}},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{{Kind: capsulev1beta1.UserOwner, Name: "alice"}},
+		},
+	}
+
+	status := r.syncReplica(context.Background(), logr.Discard(), tnt, secret)
+	assert.Equal(t, "europe", status.Name)
+	assert.True(t, status.Synced)
+	assert.Empty(t, status.Message)
+
+	replica := &capsulev1beta1.Tenant{}
+	assert.NoError(t, member.Get(context.Background(), client.ObjectKey{Name: "oil"}, replica))
+	assert.Equal(t, tnt.Spec, replica.Spec)
+	assert.Equal(t, "production", replica.GetLabels()["env"])
+	assert.Equal(t, "true", replica.GetAnnotations()[HubAnnotation])
+	// the Tenants of the member cluster not managed by the hub are never overwritten
+	tnt.SetName("gas")
+
+	status = r.syncReplica(context.Background(), logr.Discard(), tnt, secret)
+	assert.False(t, status.Synced)
+	assert.NotEmpty(t, status.Message)
+}
+
+func TestClusterName(t *testing.T) {
+	secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "eu-west-kubeconfig"}}
+	assert.Equal(t, "eu-west-kubeconfig", clusterName(secret))
+
+	secret.SetLabels(map[string]string{MemberClusterLabel: "europe"})
+	assert.Equal(t, "europe", clusterName(secret))
+}
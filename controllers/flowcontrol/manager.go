@@ -0,0 +1,236 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package flowcontrol
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	flowcontrolv1beta1 "k8s.io/api/flowcontrol/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// Manager isolates the API requests of each Tenant opting in through the apiPriorityAndFairness field
+// in a dedicated FlowSchema, assigned to the requested PriorityLevelConfiguration or to a dedicated one.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("flowcontrol").
+		For(&capsulev1beta1.Tenant{}).
+		Owns(&flowcontrolv1beta1.FlowSchema{}).
+		Owns(&flowcontrolv1beta1.PriorityLevelConfiguration{}).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+	// the objects of the Tenants being deleted are removed by the cleanup controller
+	if !tnt.GetDeletionTimestamp().IsZero() {
+		return
+	}
+
+	name := fmt.Sprintf("capsule-%s", tnt.GetName())
+
+	spec := tnt.Spec.APIPriorityAndFairness
+	if spec == nil {
+		if err = r.remove(ctx, tnt, &flowcontrolv1beta1.FlowSchema{}, name); err != nil {
+			log.Error(err, "Cannot remove FlowSchema")
+			return
+		}
+
+		return ctrl.Result{}, r.remove(ctx, tnt, &flowcontrolv1beta1.PriorityLevelConfiguration{}, name)
+	}
+
+	priorityLevel := spec.PriorityLevel
+	if len(priorityLevel) == 0 {
+		priorityLevel = name
+
+		if err = r.syncPriorityLevel(ctx, log, tnt, name); err != nil {
+			log.Error(err, "Cannot sync PriorityLevelConfiguration")
+			return
+		}
+	} else if err = r.remove(ctx, tnt, &flowcontrolv1beta1.PriorityLevelConfiguration{}, name); err != nil {
+		log.Error(err, "Cannot remove PriorityLevelConfiguration")
+		return
+	}
+
+	if err = r.syncFlowSchema(ctx, log, tnt, name, priorityLevel); err != nil {
+		log.Error(err, "Cannot sync FlowSchema")
+	}
+
+	return
+}
+
+func (r *Manager) syncPriorityLevel(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, name string) error {
+	plc := &flowcontrolv1beta1.PriorityLevelConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	res, err := controllerutil.CreateOrUpdate(ctx, r.Client, plc, func() error {
+		r.setLabels(plc, tnt)
+
+		plc.Spec = flowcontrolv1beta1.PriorityLevelConfigurationSpec{
+			Type: flowcontrolv1beta1.PriorityLevelEnablementLimited,
+			Limited: &flowcontrolv1beta1.LimitedPriorityLevelConfiguration{
+				AssuredConcurrencyShares: tnt.Spec.APIPriorityAndFairness.AssuredConcurrencyShares,
+				LimitResponse: flowcontrolv1beta1.LimitResponse{
+					Type: flowcontrolv1beta1.LimitResponseTypeQueue,
+					Queuing: &flowcontrolv1beta1.QueuingConfiguration{
+						Queues:           64,
+						HandSize:         6,
+						QueueLengthLimit: 50,
+					},
+				},
+			},
+		}
+
+		return controllerutil.SetControllerReference(tnt, plc, r.Scheme)
+	})
+
+	log.Info("PriorityLevelConfiguration synced", "result", res, "name", plc.GetName())
+
+	return err
+}
+
+func (r *Manager) syncFlowSchema(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, name, priorityLevel string) error {
+	fs := &flowcontrolv1beta1.FlowSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	res, err := controllerutil.CreateOrUpdate(ctx, r.Client, fs, func() error {
+		r.setLabels(fs, tnt)
+
+		fs.Spec = flowcontrolv1beta1.FlowSchemaSpec{
+			PriorityLevelConfiguration: flowcontrolv1beta1.PriorityLevelConfigurationReference{
+				Name: priorityLevel,
+			},
+			MatchingPrecedence: tnt.Spec.APIPriorityAndFairness.MatchingPrecedence,
+			DistinguisherMethod: &flowcontrolv1beta1.FlowDistinguisherMethod{
+				Type: flowcontrolv1beta1.FlowDistinguisherMethodByUserType,
+			},
+			Rules: []flowcontrolv1beta1.PolicyRulesWithSubjects{
+				{
+					Subjects: subjects(tnt),
+					ResourceRules: []flowcontrolv1beta1.ResourcePolicyRule{
+						{
+							Verbs:        []string{flowcontrolv1beta1.VerbAll},
+							APIGroups:    []string{flowcontrolv1beta1.APIGroupAll},
+							Resources:    []string{flowcontrolv1beta1.ResourceAll},
+							ClusterScope: true,
+							Namespaces:   []string{flowcontrolv1beta1.NamespaceEvery},
+						},
+					},
+					NonResourceRules: []flowcontrolv1beta1.NonResourcePolicyRule{
+						{
+							Verbs:           []string{flowcontrolv1beta1.VerbAll},
+							NonResourceURLs: []string{flowcontrolv1beta1.NonResourceAll},
+						},
+					},
+				},
+			},
+		}
+
+		return controllerutil.SetControllerReference(tnt, fs, r.Scheme)
+	})
+
+	log.Info("FlowSchema synced", "result", res, "name", fs.GetName())
+
+	return err
+}
+
+func (r *Manager) setLabels(obj client.Object, tnt *capsulev1beta1.Tenant) {
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[capsuleLabel] = tnt.GetName()
+
+	obj.SetLabels(labels)
+}
+
+// remove deletes the given object only if managed by the Tenant.
+func (r *Manager) remove(ctx context.Context, tnt *capsulev1beta1.Tenant, obj client.Object, name string) (err error) {
+	if err = r.Get(ctx, types.NamespacedName{Name: name}, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(obj, tnt) {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
+
+// subjects returns the Tenant owners along with all the ServiceAccounts of the Tenant Namespaces.
+func subjects(tnt *capsulev1beta1.Tenant) (subjects []flowcontrolv1beta1.Subject) {
+	for _, owner := range tnt.Spec.Owners {
+		switch owner.Kind {
+		case capsulev1beta1.UserOwner:
+			subjects = append(subjects, flowcontrolv1beta1.Subject{
+				Kind: flowcontrolv1beta1.SubjectKindUser,
+				User: &flowcontrolv1beta1.UserSubject{Name: owner.Name},
+			})
+		case capsulev1beta1.GroupOwner:
+			subjects = append(subjects, flowcontrolv1beta1.Subject{
+				Kind:  flowcontrolv1beta1.SubjectKindGroup,
+				Group: &flowcontrolv1beta1.GroupSubject{Name: owner.Name},
+			})
+		case capsulev1beta1.ServiceAccountOwner:
+			parts := strings.Split(strings.TrimPrefix(owner.Name, serviceAccountPrefix), ":")
+			if len(parts) != 2 {
+				continue
+			}
+
+			subjects = append(subjects, flowcontrolv1beta1.Subject{
+				Kind:           flowcontrolv1beta1.SubjectKindServiceAccount,
+				ServiceAccount: &flowcontrolv1beta1.ServiceAccountSubject{Namespace: parts[0], Name: parts[1]},
+			})
+		}
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		subjects = append(subjects, flowcontrolv1beta1.Subject{
+			Kind:           flowcontrolv1beta1.SubjectKindServiceAccount,
+			ServiceAccount: &flowcontrolv1beta1.ServiceAccountSubject{Namespace: ns, Name: flowcontrolv1beta1.NameAll},
+		})
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package flowcontrol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	flowcontrolv1beta1 "k8s.io/api/flowcontrol/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestSubjects(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{
+				{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+				{Kind: capsulev1beta1.GroupOwner, Name: "oil-admins"},
+				{Kind: capsulev1beta1.ServiceAccountOwner, Name: "system:serviceaccount:ci:deployer"},
+				{Kind: capsulev1beta1.ServiceAccountOwner, Name: "deployer"},
+			},
+		},
+		Status: capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production"}},
+	}
+
+	assert.Equal(t, []flowcontrolv1beta1.Subject{
+		{Kind: flowcontrolv1beta1.SubjectKindUser, User: &flowcontrolv1beta1.UserSubject{Name: "alice"}},
+		{Kind: flowcontrolv1beta1.SubjectKindGroup, Group: &flowcontrolv1beta1.GroupSubject{Name: "oil-admins"}},
+		{Kind: flowcontrolv1beta1.SubjectKindServiceAccount, ServiceAccount: &flowcontrolv1beta1.ServiceAccountSubject{Namespace: "ci", Name: "deployer"}},
+		// all the ServiceAccounts of the Tenant Namespaces
+		{Kind: flowcontrolv1beta1.SubjectKindServiceAccount, ServiceAccount: &flowcontrolv1beta1.ServiceAccountSubject{Namespace: "oil-production", Name: flowcontrolv1beta1.NameAll}},
+	}, subjects(tnt))
+}
+
+func TestReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "oil-uid"},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{{Kind: capsulev1beta1.UserOwner, Name: "alice"}},
+			APIPriorityAndFairness: &capsulev1beta1.APIPriorityAndFairnessSpec{
+				AssuredConcurrencyShares: 20,
+				MatchingPrecedence:       1000,
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tnt).Build()
+	r := &Manager{Client: c, Log: logr.Discard(), Scheme: scheme}
+
+	reconcile := func() {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "oil"}})
+		assert.NoError(t, err)
+	}
+
+	reconcile()
+	// a dedicated priority level is created, unless an existing one is requested
+	plc := &flowcontrolv1beta1.PriorityLevelConfiguration{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "capsule-oil"}, plc))
+	assert.Equal(t, int32(20), plc.Spec.Limited.AssuredConcurrencyShares)
+	assert.True(t, metav1.IsControlledBy(plc, tnt))
+
+	fs := &flowcontrolv1beta1.FlowSchema{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "capsule-oil"}, fs))
+	assert.Equal(t, "capsule-oil", fs.Spec.PriorityLevelConfiguration.Name)
+	assert.Equal(t, int32(1000), fs.Spec.MatchingPrecedence)
+	assert.Equal(t, subjects(tnt), fs.Spec.Rules[0].Subjects)
+
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(tnt), tnt))
+	tnt.Spec.APIPriorityAndFairness.PriorityLevel = "workload-low"
+	assert.NoError(t, c.Update(context.Background(), tnt))
+
+	reconcile()
+	assert.Error(t, c.Get(context.Background(), types.NamespacedName{Name: "capsule-oil"}, &flowcontrolv1beta1.PriorityLevelConfiguration{}))
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "capsule-oil"}, fs))
+	assert.Equal(t, "workload-low", fs.Spec.PriorityLevelConfiguration.Name)
+
+	tnt.Spec.APIPriorityAndFairness = nil
+	assert.NoError(t, c.Update(context.Background(), tnt))
+
+	reconcile()
+	assert.Error(t, c.Get(context.Background(), types.NamespacedName{Name: "capsule-oil"}, &flowcontrolv1beta1.FlowSchema{}))
+}
@@ -0,0 +1,254 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package impact
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	podwebhook "github.com/clastix/capsule/pkg/webhook/pod"
+)
+
+// maxViolations is the maximum number of violations reported in the Tenant status.
+const maxViolations = 50
+
+// Manager analyses the impact of the Pod restrictions of each Tenant, reporting the existing Pods violating them in
+// the Tenant status and Events: for the Tenants delaying the changed restrictions until their Pods are compliant,
+// the enforced ones are recorded in the status as soon as no Pod violates the declared ones.
+type Manager struct {
+	client.Client
+	Log      logr.Logger
+	Options  controller.Options
+	Recorder record.EventRecorder
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("impact").
+		For(&capsulev1beta1.Tenant{}).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			tntList := &capsulev1beta1.TenantList{}
+			if err := r.List(context.Background(), tntList, client.MatchingFieldsSelector{
+				Selector: fields.OneTermEqualSelector(".status.namespaces", object.GetNamespace()),
+			}); err != nil {
+				r.Log.Error(err, "Cannot list the Tenants", "namespace", object.GetNamespace())
+
+				return nil
+			}
+
+			if len(tntList.Items) == 0 {
+				return nil
+			}
+
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: tntList.Items[0].GetName()}}}
+		}), builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldPod, ok := e.ObjectOld.(*corev1.Pod)
+				if !ok {
+					return false
+				}
+
+				pod, ok := e.ObjectNew.(*corev1.Pod)
+				if !ok {
+					return false
+				}
+				// the status changes of the Pods are ignored, but for the finished ones
+				return !equality.Semantic.DeepEqual(oldPod.Spec, pod.Spec) || active(oldPod) != active(pod)
+			},
+		})).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		log.Error(err, "Error reading the object")
+
+		return
+	}
+
+	restrictions := tnt.PodRestrictions()
+
+	var violations []capsulev1beta1.PolicyViolation
+
+	for _, ns := range tnt.Status.Namespaces {
+		podList := &corev1.PodList{}
+		if err = r.List(ctx, podList, client.InNamespace(ns)); err != nil {
+			log.Error(err, "Cannot list the Tenant Pods", "namespace", ns)
+
+			return
+		}
+
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+
+			if !active(pod) {
+				continue
+			}
+
+			if reasons := podViolations(pod, restrictions); len(reasons) > 0 {
+				violations = append(violations, capsulev1beta1.PolicyViolation{
+					Kind:      "Pod",
+					Namespace: pod.GetNamespace(),
+					Name:      pod.GetName(),
+					Reason:    strings.Join(reasons, "; "),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Namespace != violations[j].Namespace {
+			return violations[i].Namespace < violations[j].Namespace
+		}
+
+		return violations[i].Name < violations[j].Name
+	})
+
+	compliant := len(violations) == 0
+
+	if len(violations) > maxViolations {
+		violations = violations[:maxViolations]
+	}
+
+	reported := make(map[string]struct{}, len(tnt.Status.Violations))
+	for _, violation := range tnt.Status.Violations {
+		reported[violation.Namespace+"/"+violation.Name+"/"+violation.Reason] = struct{}{}
+	}
+
+	for _, violation := range violations {
+		if _, ok := reported[violation.Namespace+"/"+violation.Name+"/"+violation.Reason]; !ok {
+			r.Recorder.Eventf(tnt, corev1.EventTypeWarning, "PolicyViolation", "%s %s/%s violates the Tenant restrictions: %s", violation.Kind, violation.Namespace, violation.Name, violation.Reason)
+		}
+	}
+
+	enforced := enforcedRestrictions(tnt, restrictions, compliant)
+
+	if tnt.Spec.RestrictionsRollout == capsulev1beta1.RestrictionsRolloutWhenCompliant {
+		switch {
+		case !equality.Semantic.DeepEqual(enforced, tnt.Status.EnforcedPodRestrictions):
+			r.Recorder.Event(tnt, corev1.EventTypeNormal, "RestrictionsEnforced", "The Pod restrictions of the Tenant are enforced")
+		case !compliant && !equality.Semantic.DeepEqual(restrictions, *enforced):
+			log.Info("Delaying the enforcement of the Pod restrictions", "violations", len(violations))
+		}
+	}
+
+	if err = r.updateStatus(ctx, tnt, violations, enforced); err != nil {
+		log.Error(err, "Cannot update the Tenant violations")
+	}
+
+	return
+}
+
+func (r *Manager) updateStatus(ctx context.Context, tnt *capsulev1beta1.Tenant, violations []capsulev1beta1.PolicyViolation, enforced *capsulev1beta1.PodRestrictions) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), found); err != nil {
+			return
+		}
+
+		if equality.Semantic.DeepEqual(found.Status.Violations, violations) && equality.Semantic.DeepEqual(found.Status.EnforcedPodRestrictions, enforced) {
+			return nil
+		}
+
+		found.Status.Violations = violations
+		found.Status.EnforcedPodRestrictions = enforced
+
+		return r.Client.Status().Update(ctx, found)
+	})
+}
+
+// enforcedRestrictions returns the Pod restrictions to record as enforced in the Tenant status: the declared ones
+// when the Tenant Pods are compliant, or when none has been recorded yet, the recorded ones otherwise. Nothing is
+// recorded when the Tenant enforces the declared restrictions straight away.
+func enforcedRestrictions(tnt *capsulev1beta1.Tenant, restrictions capsulev1beta1.PodRestrictions, compliant bool) *capsulev1beta1.PodRestrictions {
+	if tnt.Spec.RestrictionsRollout != capsulev1beta1.RestrictionsRolloutWhenCompliant {
+		return nil
+	}
+
+	if compliant || tnt.Status.EnforcedPodRestrictions == nil {
+		return restrictions.DeepCopy()
+	}
+
+	return tnt.Status.EnforcedPodRestrictions
+}
+
+// active returns if the Pod is neither finished nor terminating.
+func active(pod *corev1.Pod) bool {
+	return pod.GetDeletionTimestamp() == nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// podViolations returns the reasons the Pod violates the given restrictions, as enforced upon its admission.
+func podViolations(pod *corev1.Pod, restrictions capsulev1beta1.PodRestrictions) (reasons []string) {
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+
+	var missingLimits []string
+
+	for _, container := range containers {
+		if registries := restrictions.ContainerRegistries; registries != nil {
+			switch registry := podwebhook.NewRegistry(container.Image).Registry(); {
+			case len(registry) == 0:
+				reasons = append(reasons, fmt.Sprintf("container %s image %s is not fully qualified", container.Name, container.Image))
+			case !registries.ExactMatch(registry) && !registries.RegexMatch(registry):
+				reasons = append(reasons, fmt.Sprintf("container %s uses the forbidden registry %s", container.Name, registry))
+			}
+		}
+
+		if len(restrictions.ImagePullPolicies) > 0 && !allowedPullPolicy(restrictions.ImagePullPolicies, container.ImagePullPolicy) {
+			reasons = append(reasons, fmt.Sprintf("container %s uses the forbidden pull policy %s", container.Name, container.ImagePullPolicy))
+		}
+
+		if _, ok := container.Resources.Limits[corev1.ResourceEphemeralStorage]; restrictions.RequireEphemeralStorageLimits && !ok {
+			missingLimits = append(missingLimits, container.Name)
+		}
+	}
+
+	if classes, name := restrictions.PriorityClasses, pod.Spec.PriorityClassName; classes != nil && len(name) > 0 && !classes.ExactMatch(name) && !classes.RegexMatch(name) {
+		reasons = append(reasons, fmt.Sprintf("uses the forbidden PriorityClass %s", name))
+	}
+
+	if len(missingLimits) > 0 {
+		reasons = append(reasons, fmt.Sprintf("containers %s miss the ephemeral-storage limits", strings.Join(missingLimits, ", ")))
+	}
+
+	return reasons
+}
+
+func allowedPullPolicy(policies []capsulev1beta1.ImagePullPolicySpec, policy corev1.PullPolicy) bool {
+	for _, allowed := range policies {
+		if strings.EqualFold(allowed.String(), string(policy)) {
+			return true
+		}
+	}
+
+	return false
+}
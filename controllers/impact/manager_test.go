@@ -0,0 +1,68 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package impact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestPodViolations(t *testing.T) {
+	restrictions := capsulev1beta1.PodRestrictions{
+		ContainerRegistries:           &capsulev1beta1.AllowedListSpec{Exact: []string{"docker.io"}, Regex: `^.*\.acme\.corp$`},
+		ImagePullPolicies:             []capsulev1beta1.ImagePullPolicySpec{"Always"},
+		PriorityClasses:               &capsulev1beta1.AllowedListSpec{Exact: []string{"gold"}},
+		RequireEphemeralStorageLimits: true,
+	}
+
+	limits := corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")}}
+
+	compliant := &corev1.Pod{Spec: corev1.PodSpec{
+		PriorityClassName: "gold",
+		Containers: []corev1.Container{
+			{Name: "nginx", Image: "docker.io/nginx:latest", ImagePullPolicy: corev1.PullAlways, Resources: limits},
+			{Name: "proxy", Image: "registry.acme.corp/proxy:1.0", ImagePullPolicy: corev1.PullAlways, Resources: limits},
+		},
+	}}
+	assert.Empty(t, podViolations(compliant, restrictions))
+	assert.Empty(t, podViolations(&corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}}}, capsulev1beta1.PodRestrictions{}))
+
+	violating := &corev1.Pod{Spec: corev1.PodSpec{
+		PriorityClassName: "silver",
+		InitContainers: []corev1.Container{
+			{Name: "init", Image: "busybox", ImagePullPolicy: corev1.PullAlways, Resources: limits},
+		},
+		Containers: []corev1.Container{
+			{Name: "nginx", Image: "quay.io/nginx:latest", ImagePullPolicy: corev1.PullIfNotPresent},
+		},
+	}}
+	assert.Equal(t, []string{
+		"container init image busybox is not fully qualified",
+		"container nginx uses the forbidden registry quay.io",
+		"container nginx uses the forbidden pull policy IfNotPresent",
+		"uses the forbidden PriorityClass silver",
+		"containers nginx miss the ephemeral-storage limits",
+	}, podViolations(violating, restrictions))
+}
+
+func TestEnforcedRestrictions(t *testing.T) {
+	previous := &capsulev1beta1.PodRestrictions{ImagePullPolicies: []capsulev1beta1.ImagePullPolicySpec{"Always", "IfNotPresent"}}
+	restrictions := capsulev1beta1.PodRestrictions{ImagePullPolicies: []capsulev1beta1.ImagePullPolicySpec{"Always"}}
+
+	tnt := &capsulev1beta1.Tenant{Status: capsulev1beta1.TenantStatus{EnforcedPodRestrictions: previous}}
+	assert.Nil(t, enforcedRestrictions(tnt, restrictions, false))
+
+	tnt.Spec.RestrictionsRollout = capsulev1beta1.RestrictionsRolloutWhenCompliant
+	assert.Equal(t, previous, enforcedRestrictions(tnt, restrictions, false))
+	assert.Equal(t, &restrictions, enforcedRestrictions(tnt, restrictions, true))
+
+	tnt.Status.EnforcedPodRestrictions = nil
+	assert.Equal(t, &restrictions, enforcedRestrictions(tnt, restrictions, false))
+	assert.Equal(t, tnt.PodRestrictions(), tnt.EnforcedPodRestrictions())
+}
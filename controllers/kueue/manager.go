@@ -0,0 +1,292 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+var (
+	clusterQueueGVK = schema.GroupVersionKind{
+		Group:   "kueue.x-k8s.io",
+		Version: "v1beta1",
+		Kind:    "ClusterQueue",
+	}
+	localQueueGVK = schema.GroupVersionKind{
+		Group:   "kueue.x-k8s.io",
+		Version: "v1beta1",
+		Kind:    "LocalQueue",
+	}
+)
+
+// Manager keeps a Kueue ClusterQueue for each Tenant declaring the batch queueing, bound to the Tenant quota and
+// selecting the Tenant Namespaces, and a LocalQueue pointing to it in each Tenant Namespace.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+}
+
+func newUnstructured(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+
+	return obj
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("kueue").
+		For(&capsulev1beta1.Tenant{}).
+		Owns(newUnstructured(clusterQueueGVK)).
+		Owns(newUnstructured(localQueueGVK)).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+	// the objects of the Tenants being deleted are removed by the cleanup controller
+	if !tnt.GetDeletionTimestamp().IsZero() {
+		return
+	}
+
+	quota := nominalQuota(tnt)
+
+	if tnt.Spec.BatchQueueing == nil || len(quota) == 0 {
+		if tnt.Spec.BatchQueueing != nil {
+			log.Info("Skipping the Kueue queues, the Tenant has no quota")
+		}
+
+		return ctrl.Result{}, r.removeQueues(ctx, log, tnt, nil)
+	}
+
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return
+	}
+
+	clusterQueue := newUnstructured(clusterQueueGVK)
+	clusterQueue.SetName(fmt.Sprintf("capsule-%s", tnt.GetName()))
+
+	var res controllerutil.OperationResult
+	res, err = controllerutil.CreateOrUpdate(ctx, r.Client, clusterQueue, func() (err error) {
+		clusterQueue.SetLabels(map[string]string{tenantLabel: tnt.GetName()})
+
+		if err = unstructured.SetNestedField(clusterQueue.Object, clusterQueueSpec(tnt, tenantLabel, quota), "spec"); err != nil {
+			return
+		}
+
+		return controllerutil.SetControllerReference(tnt, clusterQueue, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "Cannot sync Kueue ClusterQueue")
+		return
+	}
+
+	log.Info("Kueue ClusterQueue synced", "result", res, "name", clusterQueue.GetName())
+
+	keep := make(map[string]struct{}, len(tnt.Status.Namespaces))
+
+	for _, ns := range tnt.Status.Namespaces {
+		localQueue := newUnstructured(localQueueGVK)
+		localQueue.SetNamespace(ns)
+		localQueue.SetName(tnt.QueueName())
+
+		res, err = controllerutil.CreateOrUpdate(ctx, r.Client, localQueue, func() (err error) {
+			localQueue.SetLabels(map[string]string{tenantLabel: tnt.GetName()})
+
+			if err = unstructured.SetNestedField(localQueue.Object, clusterQueue.GetName(), "spec", "clusterQueue"); err != nil {
+				return
+			}
+
+			return controllerutil.SetControllerReference(tnt, localQueue, r.Scheme)
+		})
+		if err != nil {
+			log.Error(err, "Cannot sync Kueue LocalQueue", "namespace", ns)
+			return
+		}
+
+		log.Info("Kueue LocalQueue synced", "result", res, "name", localQueue.GetName(), "namespace", ns)
+
+		keep[ns+"/"+localQueue.GetName()] = struct{}{}
+	}
+
+	return ctrl.Result{}, r.removeQueues(ctx, log, tnt, keep)
+}
+
+// removeQueues deletes the Kueue queues controlled by the Tenant, but the LocalQueues to keep:
+// the ClusterQueue is deleted only when no LocalQueue is kept.
+func (r *Manager) removeQueues(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, keep map[string]struct{}) error {
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	localQueues := &unstructured.UnstructuredList{}
+	localQueues.SetGroupVersionKind(localQueueGVK.GroupVersion().WithKind(localQueueGVK.Kind + "List"))
+
+	if err = r.List(ctx, localQueues, client.MatchingLabels{tenantLabel: tnt.GetName()}); err != nil {
+		return err
+	}
+
+	for i := range localQueues.Items {
+		localQueue := &localQueues.Items[i]
+
+		if _, ok := keep[localQueue.GetNamespace()+"/"+localQueue.GetName()]; ok || !metav1.IsControlledBy(localQueue, tnt) {
+			continue
+		}
+
+		log.Info("Removing Kueue LocalQueue, no more requested", "name", localQueue.GetName(), "namespace", localQueue.GetNamespace())
+
+		if err = r.Delete(ctx, localQueue); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	if len(keep) > 0 {
+		return nil
+	}
+
+	clusterQueue := newUnstructured(clusterQueueGVK)
+	if err = r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("capsule-%s", tnt.GetName())}, clusterQueue); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// removing only the ClusterQueue objects managed by Capsule
+	if !metav1.IsControlledBy(clusterQueue, tnt) {
+		return nil
+	}
+
+	log.Info("Removing Kueue ClusterQueue, no more requested")
+
+	return client.IgnoreNotFound(r.Delete(ctx, clusterQueue))
+}
+
+// nominalQuota returns the nominal quota of the Tenant ClusterQueue: the declared one, or the requests allowed by the
+// Tenant ResourceQuotas, multiplied by the Tenant Namespaces when enforced in each of them.
+func nominalQuota(tnt *capsulev1beta1.Tenant) corev1.ResourceList {
+	if tnt.Spec.BatchQueueing == nil {
+		return nil
+	}
+
+	if len(tnt.Spec.BatchQueueing.NominalQuota) > 0 {
+		return tnt.Spec.BatchQueueing.NominalQuota
+	}
+
+	quota := corev1.ResourceList{}
+
+	for _, item := range tnt.Spec.ResourceQuota.Items {
+		for name, quantity := range item.Hard {
+			var covered corev1.ResourceName
+
+			switch {
+			case name == corev1.ResourceCPU || name == corev1.ResourceMemory:
+				covered = name
+			case strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix):
+				covered = corev1.ResourceName(strings.TrimPrefix(string(name), corev1.DefaultResourceRequestsPrefix))
+			default:
+				continue
+			}
+			// the ephemeral storage and the storage are not accounted by the Kueue workloads
+			if covered == corev1.ResourceStorage || covered == corev1.ResourceEphemeralStorage {
+				continue
+			}
+
+			sum := quota[covered]
+			sum.Add(quantity)
+			quota[covered] = sum
+		}
+	}
+
+	if tnt.Spec.ResourceQuota.Scope == capsulev1beta1.ResourceQuotaScopeNamespace {
+		for name, quantity := range quota {
+			quota[name] = *resource.NewMilliQuantity(quantity.MilliValue()*int64(len(tnt.Status.Namespaces)), quantity.Format)
+		}
+	}
+
+	return quota
+}
+
+// clusterQueueSpec returns the spec of the Tenant ClusterQueue, admitting the workloads of the Tenant Namespaces only.
+func clusterQueueSpec(tnt *capsulev1beta1.Tenant, tenantLabel string, quota corev1.ResourceList) map[string]interface{} {
+	names := make([]string, 0, len(quota))
+	for name := range quota {
+		names = append(names, string(name))
+	}
+
+	sort.Strings(names)
+
+	covered := make([]interface{}, 0, len(names))
+	resources := make([]interface{}, 0, len(names))
+
+	for _, name := range names {
+		nominal := quota[corev1.ResourceName(name)]
+
+		covered = append(covered, name)
+		resources = append(resources, map[string]interface{}{
+			"name":         name,
+			"nominalQuota": nominal.String(),
+		})
+	}
+
+	flavor := tnt.Spec.BatchQueueing.ResourceFlavor
+	if len(flavor) == 0 {
+		flavor = "default-flavor"
+	}
+
+	spec := map[string]interface{}{
+		"namespaceSelector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				tenantLabel: tnt.GetName(),
+			},
+		},
+		"resourceGroups": []interface{}{
+			map[string]interface{}{
+				"coveredResources": covered,
+				"flavors": []interface{}{
+					map[string]interface{}{
+						"name":      flavor,
+						"resources": resources,
+					},
+				},
+			},
+		},
+	}
+
+	if cohort := tnt.Spec.BatchQueueing.Cohort; len(cohort) > 0 {
+		spec["cohort"] = cohort
+	}
+
+	return spec
+}
@@ -0,0 +1,55 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package kueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestNominalQuota(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			BatchQueueing: &capsulev1beta1.BatchQueueingSpec{},
+			ResourceQuota: capsulev1beta1.ResourceQuotaSpec{
+				Scope: capsulev1beta1.ResourceQuotaScopeTenant,
+				Items: []corev1.ResourceQuotaSpec{
+					{Hard: corev1.ResourceList{
+						corev1.ResourceRequestsCPU:              resource.MustParse("8"),
+						corev1.ResourceLimitsCPU:                resource.MustParse("16"),
+						corev1.ResourceRequestsStorage:          resource.MustParse("100Gi"),
+						corev1.ResourcePods:                     resource.MustParse("50"),
+						"requests.nvidia.com/gpu":               resource.MustParse("2"),
+						corev1.ResourceRequestsEphemeralStorage: resource.MustParse("10Gi"),
+					}},
+					{Hard: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Gi")}},
+				},
+			},
+		},
+	}
+
+	quota := nominalQuota(tnt)
+	assert.Len(t, quota, 3)
+	assert.Equal(t, "8", quota.Cpu().String())
+	assert.Equal(t, "32Gi", quota.Memory().String())
+	assert.Equal(t, "2", quota.Name("nvidia.com/gpu", resource.DecimalSI).String())
+
+	tnt.Spec.ResourceQuota.Scope = capsulev1beta1.ResourceQuotaScopeNamespace
+	tnt.Status.Namespaces = []string{"oil-production", "oil-development"}
+
+	quota = nominalQuota(tnt)
+	assert.Equal(t, int64(16), quota.Cpu().Value())
+	assert.Equal(t, int64(64<<30), quota.Memory().Value())
+
+	tnt.Spec.BatchQueueing.NominalQuota = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+	assert.Equal(t, tnt.Spec.BatchQueueing.NominalQuota, nominalQuota(tnt))
+
+	tnt.Spec.BatchQueueing = nil
+	assert.Empty(t, nominalQuota(tnt))
+}
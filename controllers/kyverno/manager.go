@@ -0,0 +1,123 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package kyverno
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+var clusterPolicyGVK = schema.GroupVersionKind{
+	Group:   "kyverno.io",
+	Version: "v1",
+	Kind:    "ClusterPolicy",
+}
+
+// Manager emits a Kyverno ClusterPolicy for each Tenant opting in through the kyvernoPolicies field,
+// mirroring the Capsule rules so they're reported by Kyverno too.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+}
+
+func newClusterPolicy(tenantName string) *unstructured.Unstructured {
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(clusterPolicyGVK)
+	policy.SetName(fmt.Sprintf("capsule-%s", tenantName))
+
+	return policy
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(clusterPolicyGVK)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("kyverno").
+		For(&capsulev1beta1.Tenant{}).
+		Owns(policy).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+	// the objects of the Tenants being deleted are removed by the cleanup controller
+	if !tnt.GetDeletionTimestamp().IsZero() {
+		return
+	}
+
+	policy := newClusterPolicy(tnt.GetName())
+
+	var spec map[string]interface{}
+	if tnt.Spec.KyvernoPolicies != nil {
+		spec = policySpec(tnt)
+	}
+
+	if spec == nil {
+		if err = r.Get(ctx, types.NamespacedName{Name: policy.GetName()}, policy); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		// removing only the ClusterPolicy objects managed by Capsule
+		if !metav1.IsControlledBy(policy, tnt) {
+			return
+		}
+
+		log.Info("Removing Kyverno ClusterPolicy, no more requested or without rules")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, policy))
+	}
+
+	var res controllerutil.OperationResult
+	res, err = controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() (err error) {
+		capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+		labels := policy.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[capsuleLabel] = tnt.GetName()
+		policy.SetLabels(labels)
+
+		if err = unstructured.SetNestedField(policy.Object, spec, "spec"); err != nil {
+			return
+		}
+
+		return controllerutil.SetControllerReference(tnt, policy, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "Cannot sync Kyverno ClusterPolicy")
+		return
+	}
+
+	log.Info("Kyverno ClusterPolicy synced", "result", res, "name", policy.GetName())
+
+	return
+}
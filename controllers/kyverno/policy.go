@@ -0,0 +1,183 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package kyverno
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// policySpec translates the Tenant rules into the spec of a Kyverno ClusterPolicy: each rule is matching the
+// resources of the Namespaces labelled with the Tenant name.
+// Only the exact values are translated, since regular expressions are not supported by the Kyverno patterns.
+// Without any rule to translate, nil is returned, since Kyverno rejects the policies without rules.
+func policySpec(tnt *capsulev1beta1.Tenant) map[string]interface{} {
+	action := tnt.Spec.KyvernoPolicies.ValidationFailureAction
+	if len(action) == 0 {
+		action = capsulev1beta1.KyvernoValidationFailureActionAudit
+	}
+
+	rules := make([]interface{}, 0)
+
+	if len(tnt.Spec.NodeSelector) > 0 {
+		nodeSelector := make(map[string]interface{})
+		for k, v := range tnt.Spec.NodeSelector {
+			nodeSelector[k] = v
+		}
+
+		rules = append(rules, mutateRule(tnt, "node-selector", "Pod", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"nodeSelector": nodeSelector,
+			},
+		}))
+	}
+
+	if len(tnt.Spec.ImagePullPolicies) == 1 {
+		policy := tnt.Spec.ImagePullPolicies[0].String()
+
+		rules = append(rules, mutateRule(tnt, "image-pull-policy", "Pod", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"(name)":          "*",
+						"imagePullPolicy": policy,
+					},
+				},
+			},
+		}))
+	}
+
+	if len(tnt.Spec.ImagePullPolicies) > 1 {
+		policies := make([]string, 0, len(tnt.Spec.ImagePullPolicies))
+		for _, p := range tnt.Spec.ImagePullPolicies {
+			policies = append(policies, p.String())
+		}
+
+		rules = append(rules, validateRule(tnt, "image-pull-policy", "Pod", fmt.Sprintf("The allowed imagePullPolicy values are: %s", strings.Join(policies, ", ")), map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"imagePullPolicy": anyOf(policies),
+					},
+				},
+			},
+		}))
+	}
+
+	if tnt.Spec.ServiceOptions != nil && tnt.Spec.ServiceOptions.AdditionalMetadata != nil {
+		metadata := make(map[string]interface{})
+
+		if labels := stringMap(tnt.Spec.ServiceOptions.AdditionalMetadata.Labels); len(labels) > 0 {
+			metadata["labels"] = labels
+		}
+		if annotations := stringMap(tnt.Spec.ServiceOptions.AdditionalMetadata.Annotations); len(annotations) > 0 {
+			metadata["annotations"] = annotations
+		}
+
+		if len(metadata) > 0 {
+			rules = append(rules, mutateRule(tnt, "service-metadata", "Service", map[string]interface{}{
+				"metadata": metadata,
+			}))
+		}
+	}
+
+	if tnt.Spec.ContainerRegistries != nil && len(tnt.Spec.ContainerRegistries.Exact) > 0 {
+		registries := make([]string, 0, len(tnt.Spec.ContainerRegistries.Exact))
+		for _, registry := range tnt.Spec.ContainerRegistries.Exact {
+			registries = append(registries, fmt.Sprintf("%s/*", registry))
+		}
+
+		rules = append(rules, validateRule(tnt, "container-registries", "Pod", fmt.Sprintf("The allowed container registries are: %s", strings.Join(tnt.Spec.ContainerRegistries.Exact, ", ")), map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"image": anyOf(registries),
+					},
+				},
+			},
+		}))
+	}
+
+	if tnt.Spec.PriorityClasses != nil && len(tnt.Spec.PriorityClasses.Exact) > 0 {
+		rules = append(rules, validateRule(tnt, "priority-classes", "Pod", fmt.Sprintf("The allowed PriorityClasses are: %s", strings.Join(tnt.Spec.PriorityClasses.Exact, ", ")), map[string]interface{}{
+			"spec": map[string]interface{}{
+				"=(priorityClassName)": anyOf(tnt.Spec.PriorityClasses.Exact),
+			},
+		}))
+	}
+
+	if tnt.Spec.StorageClasses != nil && len(tnt.Spec.StorageClasses.Exact) > 0 {
+		rules = append(rules, validateRule(tnt, "storage-classes", "PersistentVolumeClaim", fmt.Sprintf("The allowed StorageClasses are: %s", strings.Join(tnt.Spec.StorageClasses.Exact, ", ")), map[string]interface{}{
+			"spec": map[string]interface{}{
+				"storageClassName": anyOf(tnt.Spec.StorageClasses.Exact),
+			},
+		}))
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"validationFailureAction": string(action),
+		"background":              true,
+		"rules":                   rules,
+	}
+}
+
+func match(tnt *capsulev1beta1.Tenant, kind string) map[string]interface{} {
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	return map[string]interface{}{
+		"resources": map[string]interface{}{
+			"kinds": []interface{}{kind},
+			"namespaceSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					capsuleLabel: tnt.GetName(),
+				},
+			},
+		},
+	}
+}
+
+func mutateRule(tnt *capsulev1beta1.Tenant, name, kind string, patch map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  fmt.Sprintf("capsule-%s", name),
+		"match": match(tnt, kind),
+		"mutate": map[string]interface{}{
+			"patchStrategicMerge": patch,
+		},
+	}
+}
+
+func validateRule(tnt *capsulev1beta1.Tenant, name, kind, message string, pattern map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  fmt.Sprintf("capsule-%s", name),
+		"match": match(tnt, kind),
+		"validate": map[string]interface{}{
+			"message": message,
+			"pattern": pattern,
+		},
+	}
+}
+
+// anyOf returns the Kyverno pattern matching one of the given values, sorted to avoid useless updates.
+func anyOf(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, " | ")
+}
+
+func stringMap(in map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+
+	return out
+}
@@ -0,0 +1,101 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package kyverno
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestPolicySpec(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec: capsulev1beta1.TenantSpec{
+			KyvernoPolicies: &capsulev1beta1.KyvernoPoliciesSpec{},
+		},
+	}
+	// Kyverno rejects the policies without rules
+	assert.Nil(t, policySpec(tnt))
+
+	tnt.Spec.NodeSelector = map[string]string{"pool": "oil"}
+	tnt.Spec.ContainerRegistries = &capsulev1beta1.AllowedListSpec{Exact: []string{"quay.io", "docker.io"}, Regex: "internal.*"}
+	tnt.Spec.StorageClasses = &capsulev1beta1.AllowedListSpec{Regex: "ssd.*"}
+
+	spec := policySpec(tnt)
+	assert.Equal(t, string(capsulev1beta1.KyvernoValidationFailureActionAudit), spec["validationFailureAction"])
+
+	rules, ok := spec["rules"].([]interface{})
+	assert.True(t, ok)
+	// the regular expressions are not translated
+	assert.Len(t, rules, 2)
+
+	nodeSelector := rules[0].(map[string]interface{})
+	assert.Equal(t, "capsule-node-selector", nodeSelector["name"])
+	assert.Equal(t, map[string]interface{}{
+		"resources": map[string]interface{}{
+			"kinds":             []interface{}{"Pod"},
+			"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"capsule.clastix.io/tenant": "oil"}},
+		},
+	}, nodeSelector["match"])
+	assert.Equal(t, map[string]interface{}{
+		"patchStrategicMerge": map[string]interface{}{
+			"spec": map[string]interface{}{"nodeSelector": map[string]interface{}{"pool": "oil"}},
+		},
+	}, nodeSelector["mutate"])
+
+	registries := rules[1].(map[string]interface{})
+	assert.Equal(t, "capsule-container-registries", registries["name"])
+	assert.Equal(t, map[string]interface{}{
+		"message": "The allowed container registries are: quay.io, docker.io",
+		"pattern": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"image": "docker.io/* | quay.io/*"},
+				},
+			},
+		},
+	}, registries["validate"])
+
+	tnt.Spec.KyvernoPolicies.ValidationFailureAction = capsulev1beta1.KyvernoValidationFailureActionEnforce
+	assert.Equal(t, string(capsulev1beta1.KyvernoValidationFailureActionEnforce), policySpec(tnt)["validationFailureAction"])
+}
+
+func TestPolicySpec_ImagePullPolicies(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec: capsulev1beta1.TenantSpec{
+			KyvernoPolicies:   &capsulev1beta1.KyvernoPoliciesSpec{},
+			ImagePullPolicies: []capsulev1beta1.ImagePullPolicySpec{"Always"},
+		},
+	}
+	// a single policy is enforced by mutating the Pods
+	rule := policySpec(tnt)["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Contains(t, rule, "mutate")
+
+	tnt.Spec.ImagePullPolicies = append(tnt.Spec.ImagePullPolicies, "IfNotPresent")
+	// more policies are validated
+	rule = policySpec(tnt)["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"message": "The allowed imagePullPolicy values are: Always, IfNotPresent",
+		"pattern": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"imagePullPolicy": "Always | IfNotPresent"},
+				},
+			},
+		},
+	}, rule["validate"])
+}
+
+func TestAnyOf(t *testing.T) {
+	values := []string{"standard", "fast"}
+
+	assert.Equal(t, "fast | standard", anyOf(values))
+	// the given values are left untouched
+	assert.Equal(t, []string{"standard", "fast"}, values)
+}
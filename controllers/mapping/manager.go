@@ -0,0 +1,295 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// ConfigMapName is the name of the ConfigMap of the Capsule Namespace publishing the Tenant mappings, with a key for
+// each Tenant holding its mapping as JSON.
+const ConfigMapName = "capsule-tenant-mapping"
+
+// TenantMapping lists the resources selected by a Tenant, as published in the mapping ConfigMap.
+type TenantMapping struct {
+	Namespaces     []string `json:"namespaces"`
+	IngressClasses []string `json:"ingressClasses"`
+	StorageClasses []string `json:"storageClasses"`
+	// Nodes lists the Nodes selected by the Tenant node selector, left out when the Tenant can use all of them.
+	Nodes []string `json:"nodes,omitempty"`
+	// AllNodes reports the Tenant can use all the Nodes, having no node selector.
+	AllNodes bool `json:"allNodes,omitempty"`
+}
+
+// Manager publishes the Namespaces, IngressClasses, StorageClasses and Nodes selected by each Tenant in the
+// capsule-tenant-mapping ConfigMap, so that capsule-proxy and the other tenant-aware tools can consume them
+// without re-implementing the Capsule selection logic.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	// The Namespace of the mapping ConfigMap.
+	Namespace string
+
+	mutex     sync.Mutex
+	mappings  map[string]string
+	published map[string]string
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	allTenants := handler.EnqueueRequestsFromMapFunc(func(client.Object) (requests []reconcile.Request) {
+		tntList := &capsulev1beta1.TenantList{}
+		if err := r.List(context.Background(), tntList); err != nil {
+			r.Log.Error(err, "Cannot list the Tenants")
+
+			return nil
+		}
+
+		for _, tnt := range tntList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: tnt.GetName()}})
+		}
+
+		return requests
+	})
+	// the Tenants without a node selector don't list the Nodes
+	selectingTenants := handler.EnqueueRequestsFromMapFunc(func(client.Object) (requests []reconcile.Request) {
+		tntList := &capsulev1beta1.TenantList{}
+		if err := r.List(context.Background(), tntList); err != nil {
+			r.Log.Error(err, "Cannot list the Tenants")
+
+			return nil
+		}
+
+		for _, tnt := range tntList.Items {
+			if len(tnt.Spec.NodeSelector) > 0 {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: tnt.GetName()}})
+			}
+		}
+
+		return requests
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("mapping").
+		For(&capsulev1beta1.Tenant{}).
+		Watches(&source.Kind{Type: &corev1.Node{}}, selectingTenants, builder.WithPredicates(predicate.Funcs{
+			// the Nodes are selected by their labels only
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return !equality.Semantic.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+			},
+			GenericFunc: func(event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Watches(&source.Kind{Type: &networkingv1.IngressClass{}}, allTenants).
+		Watches(&source.Kind{Type: &storagev1.StorageClass{}}, allTenants).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "Error reading the object")
+
+			return
+		}
+
+		tnt = nil
+	}
+
+	ingressClassList := &networkingv1.IngressClassList{}
+	if err = r.List(ctx, ingressClassList); err != nil {
+		log.Error(err, "Cannot list the IngressClasses")
+
+		return
+	}
+
+	storageClassList := &storagev1.StorageClassList{}
+	if err = r.List(ctx, storageClassList); err != nil {
+		log.Error(err, "Cannot list the StorageClasses")
+
+		return
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err = r.List(ctx, nodeList); err != nil {
+		log.Error(err, "Cannot list the Nodes")
+
+		return
+	}
+
+	ingressClasses := make([]string, 0, len(ingressClassList.Items))
+	for _, class := range ingressClassList.Items {
+		ingressClasses = append(ingressClasses, class.GetName())
+	}
+
+	storageClasses := make([]string, 0, len(storageClassList.Items))
+	for _, class := range storageClassList.Items {
+		storageClasses = append(storageClasses, class.GetName())
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// the mappings of all the Tenants are computed before publishing the first time, not to publish the partial ones
+	if r.mappings == nil {
+		tntList := &capsulev1beta1.TenantList{}
+		if err = r.List(ctx, tntList); err != nil {
+			log.Error(err, "Cannot list the Tenants")
+
+			return
+		}
+
+		r.mappings = make(map[string]string, len(tntList.Items))
+
+		for i := range tntList.Items {
+			if err = r.setMapping(tntList.Items[i].GetName(), tenantMapping(&tntList.Items[i], ingressClasses, storageClasses, nodeList.Items)); err != nil {
+				log.Error(err, "Cannot compute the Tenant mapping", "tenant", tntList.Items[i].GetName())
+
+				return
+			}
+		}
+	}
+
+	if tnt == nil {
+		delete(r.mappings, request.Name)
+	} else if err = r.setMapping(tnt.GetName(), tenantMapping(tnt, ingressClasses, storageClasses, nodeList.Items)); err != nil {
+		log.Error(err, "Cannot compute the Tenant mapping")
+
+		return
+	}
+
+	if err = r.publish(ctx); err != nil {
+		log.Error(err, "Cannot publish the Tenant mappings")
+	}
+
+	return
+}
+
+func (r *Manager) setMapping(tenant string, mapping TenantMapping) error {
+	data, err := json.Marshal(mapping)
+	if err != nil {
+		return err
+	}
+
+	r.mappings[tenant] = string(data)
+
+	return nil
+}
+
+// publish applies the mapping ConfigMap when any mapping changed since the last time it was published.
+func (r *Manager) publish(ctx context.Context) error {
+	if r.published != nil && equalData(r.published, r.mappings) {
+		return nil
+	}
+
+	data := make(map[string]string, len(r.mappings))
+	for key, value := range r.mappings {
+		data[key] = value
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: r.Namespace,
+		},
+		Data: data,
+	}
+
+	if err := r.Client.Patch(ctx, cm, client.Apply, client.FieldOwner("capsule"), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	r.published = data
+
+	return nil
+}
+
+// tenantMapping returns the resources selected by the Tenant among the given ones: the IngressClasses and the
+// StorageClasses matching the allowed ones, all of them if unrestricted, and the Nodes matching the node selector,
+// not listed if unrestricted to keep the mappings of the large clusters within the ConfigMap size limit.
+func tenantMapping(tnt *capsulev1beta1.Tenant, ingressClasses, storageClasses []string, nodes []corev1.Node) TenantMapping {
+	mapping := TenantMapping{
+		Namespaces:     append([]string{}, tnt.Status.Namespaces...),
+		IngressClasses: allowed(tnt.Spec.IngressOptions.AllowedClasses, ingressClasses),
+		StorageClasses: allowed(tnt.Spec.StorageClasses, storageClasses),
+	}
+
+	sort.Strings(mapping.Namespaces)
+
+	if len(tnt.Spec.NodeSelector) == 0 {
+		mapping.AllNodes = true
+
+		return mapping
+	}
+
+	selector := labels.SelectorFromSet(tnt.Spec.NodeSelector)
+
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.GetLabels())) {
+			mapping.Nodes = append(mapping.Nodes, node.GetName())
+		}
+	}
+
+	sort.Strings(mapping.Nodes)
+
+	return mapping
+}
+
+// allowed filters the names matching the allowed list, all of them if nil.
+func allowed(spec *capsulev1beta1.AllowedListSpec, names []string) []string {
+	filtered := []string{}
+
+	for _, name := range names {
+		if spec == nil || spec.ExactMatch(name) || spec.RegexMatch(name) {
+			filtered = append(filtered, name)
+		}
+	}
+
+	sort.Strings(filtered)
+
+	return filtered
+}
+
+func equalData(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package mapping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestTenantMapping(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker-2", Labels: map[string]string{"pool": "oil"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"pool": "oil"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker-3", Labels: map[string]string{"pool": "gas"}}},
+	}
+
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec: capsulev1beta1.TenantSpec{
+			NodeSelector: map[string]string{"pool": "oil"},
+			IngressOptions: capsulev1beta1.IngressOptions{
+				AllowedClasses: &capsulev1beta1.AllowedListSpec{Exact: []string{"nginx"}},
+			},
+			StorageClasses: &capsulev1beta1.AllowedListSpec{Regex: "^ceph-.*"},
+		},
+		Status: capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production", "oil-development"}},
+	}
+
+	assert.Equal(t, TenantMapping{
+		Namespaces:     []string{"oil-development", "oil-production"},
+		IngressClasses: []string{"nginx"},
+		StorageClasses: []string{"ceph-fs", "ceph-rbd"},
+		Nodes:          []string{"worker-1", "worker-2"},
+	}, tenantMapping(tnt, []string{"traefik", "nginx"}, []string{"standard", "ceph-rbd", "ceph-fs"}, nodes))
+
+	unrestricted := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "gas"}}
+
+	assert.Equal(t, TenantMapping{
+		Namespaces:     []string{},
+		IngressClasses: []string{"nginx", "traefik"},
+		StorageClasses: []string{"standard"},
+		AllNodes:       true,
+	}, tenantMapping(unrestricted, []string{"traefik", "nginx"}, []string{"standard"}, nodes))
+}
@@ -0,0 +1,98 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package namespacerate
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// Manager reports in the status of the Tenants limiting their Namespace creation rate the Namespaces created within
+// the rate period, as enforced upon the Namespaces admission: the status keeps track of the deleted Namespaces too.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("namespacerate").
+		For(&capsulev1beta1.Tenant{}).
+		Owns(&corev1.Namespace{}).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		log.Error(err, "Error reading the object")
+
+		return
+	}
+
+	var creations []capsulev1beta1.NamespaceCreation
+
+	if rate := tnt.NamespaceCreationRate(); rate != nil {
+		nsList := &corev1.NamespaceList{}
+		if err = r.List(ctx, nsList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".metadata.ownerReferences[*].capsule", tnt.GetName()),
+		}); err != nil {
+			log.Error(err, "Cannot list the Tenant Namespaces")
+
+			return
+		}
+
+		now := time.Now()
+
+		creations = tnt.RecentNamespaceCreations(nsList.Items, now)
+		// pruning the oldest creation once out of the period
+		if len(creations) > 0 {
+			result.RequeueAfter = creations[0].Time.Add(rate.Period.Duration).Sub(now) + time.Second
+		}
+	}
+
+	if err = r.updateStatus(ctx, tnt, creations); err != nil {
+		log.Error(err, "Cannot update the Tenant Namespace creations")
+	}
+
+	return
+}
+
+func (r *Manager) updateStatus(ctx context.Context, tnt *capsulev1beta1.Tenant, creations []capsulev1beta1.NamespaceCreation) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), found); err != nil {
+			return
+		}
+
+		if equality.Semantic.DeepEqual(found.Status.NamespaceCreations, creations) {
+			return nil
+		}
+
+		found.Status.NamespaceCreations = creations
+
+		return r.Client.Status().Update(ctx, found)
+	})
+}
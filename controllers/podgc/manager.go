@@ -0,0 +1,139 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package podgc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// Manager deletes the finished Pods of the Tenant Namespaces older than the Tenant maximum age,
+// the ones belonging to a Job are left to the Job TTL.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("podgc").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			pod, ok := object.(*corev1.Pod)
+
+			return ok && collectable(pod)
+		}))).
+		Watches(&source.Kind{Type: &capsulev1beta1.Tenant{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) (requests []reconcile.Request) {
+			tnt, ok := object.(*capsulev1beta1.Tenant)
+			if !ok || tnt.Spec.GarbageCollection == nil || tnt.Spec.GarbageCollection.FinishedPodsMaxAge == nil {
+				return nil
+			}
+
+			for _, ns := range tnt.Status.Namespaces {
+				podList := &corev1.PodList{}
+				if err := r.List(context.Background(), podList, client.InNamespace(ns)); err != nil {
+					r.Log.Error(err, "Cannot list the Tenant Pods", "tenant", tnt.GetName(), "namespace", ns)
+
+					continue
+				}
+
+				for i := range podList.Items {
+					if collectable(&podList.Items[i]) {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: podList.Items[i].GetName()}})
+					}
+				}
+			}
+
+			return requests
+		})).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("namespace", request.Namespace, "name", request.Name)
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, request.NamespacedName, pod); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !collectable(pod) || pod.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	tntList := &capsulev1beta1.TenantList{}
+	if err := r.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", pod.GetNamespace()),
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if len(tntList.Items) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	gc := tntList.Items[0].Spec.GarbageCollection
+	if gc == nil || gc.FinishedPodsMaxAge == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if remaining := gc.FinishedPodsMaxAge.Duration - time.Since(finishedAt(pod)); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	log.Info("Deleting the finished Pod", "tenant", tntList.Items[0].GetName(), "phase", pod.Status.Phase)
+
+	return reconcile.Result{}, client.IgnoreNotFound(r.Delete(ctx, pod, client.Preconditions{UID: &pod.UID}))
+}
+
+// collectable returns if the Pod is finished and doesn't belong to a Job, deleted along with its Pods.
+func collectable(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		return false
+	}
+
+	owner := metav1.GetControllerOf(pod)
+
+	return owner == nil || owner.Kind != "Job"
+}
+
+// finishedAt returns the time the last container of the Pod terminated at, falling back to the Pod start time
+// and creation for the Pods failed before running any container, as the evicted ones.
+func finishedAt(pod *corev1.Pod) time.Time {
+	var last time.Time
+
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.Time.After(last) {
+				last = terminated.FinishedAt.Time
+			}
+		}
+	}
+
+	switch {
+	case !last.IsZero():
+		return last
+	case pod.Status.StartTime != nil:
+		return pod.Status.StartTime.Time
+	default:
+		return pod.GetCreationTimestamp().Time
+	}
+}
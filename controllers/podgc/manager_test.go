@@ -0,0 +1,47 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package podgc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestCollectable(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	assert.False(t, collectable(pod))
+
+	pod.Status.Phase = corev1.PodFailed
+	assert.True(t, collectable(pod))
+
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "backup", Controller: pointer.BoolPtr(true)}}
+	assert.False(t, collectable(pod))
+
+	pod.OwnerReferences[0].Kind = "Workflow"
+	assert.True(t, collectable(pod))
+}
+
+func TestFinishedAt(t *testing.T) {
+	created := time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	assert.Equal(t, created, finishedAt(pod))
+
+	started := metav1.NewTime(created.Add(time.Minute))
+	pod.Status.StartTime = &started
+	assert.Equal(t, started.Time, finishedAt(pod))
+
+	terminated := func(at time.Time) corev1.ContainerStatus {
+		return corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(at)}}}
+	}
+
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{terminated(created.Add(2 * time.Minute))}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{terminated(created.Add(10 * time.Minute)), terminated(created.Add(5 * time.Minute))}
+	assert.Equal(t, created.Add(10*time.Minute), finishedAt(pod))
+}
@@ -0,0 +1,123 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pv
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// Manager labels the PersistentVolumes bound by the Tenant claims with the Tenant name, keeping track of their
+// ownership once released, and enforces the reclaim policy required by the Tenant.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	capsuleLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("persistentvolume").
+		For(&corev1.PersistentVolume{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			pv, ok := object.(*corev1.PersistentVolume)
+
+			return ok && pv.Spec.ClaimRef != nil
+		}))).
+		Watches(&source.Kind{Type: &capsulev1beta1.Tenant{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) (requests []reconcile.Request) {
+			pvList := &corev1.PersistentVolumeList{}
+			if err := r.List(context.Background(), pvList, client.MatchingLabels{capsuleLabel: object.GetName()}); err != nil {
+				r.Log.Error(err, "Cannot list the Tenant PersistentVolumes", "tenant", object.GetName())
+
+				return nil
+			}
+
+			for _, pv := range pvList.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: pv.GetName()}})
+			}
+
+			return requests
+		})).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("PersistentVolume", request.Name)
+
+	pv := &corev1.PersistentVolume{}
+	if err := r.Get(ctx, request.NamespacedName, pv); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if pv.Spec.ClaimRef == nil {
+		return reconcile.Result{}, nil
+	}
+
+	capsuleLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	// the Namespace of a released claim could be gone: the label set upon the binding keeps track of the Tenant
+	ns := &corev1.Namespace{}
+	if err = r.Get(ctx, types.NamespacedName{Name: pv.Spec.ClaimRef.Namespace}, ns); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	tenantName, ok := ns.GetLabels()[capsuleLabel]
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, types.NamespacedName{Name: tenantName}, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	// the PersistentVolumes reserved for a different Tenant are left to the cluster administrators
+	if owner, ok := pv.GetLabels()[capsuleLabel]; ok && owner != tnt.GetName() {
+		log.Info("PersistentVolume bound by a claim of a different Tenant", "tenant", tnt.GetName(), "owner", owner)
+
+		return reconcile.Result{}, nil
+	}
+
+	patch := client.MergeFrom(pv.DeepCopy())
+
+	labels := pv.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[capsuleLabel] = tnt.GetName()
+	pv.SetLabels(labels)
+
+	if opts := tnt.Spec.PersistentVolumeOptions; opts != nil && opts.ForceDeleteReclaimPolicy && pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+		log.Info("Converting the reclaim policy to Delete", "tenant", tnt.GetName())
+
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimDelete
+	}
+
+	return reconcile.Result{}, r.Patch(ctx, pv, patch)
+}
@@ -16,6 +16,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -31,6 +32,8 @@ type Manager struct {
 	Log           logr.Logger
 	Client        client.Client
 	Configuration configuration.Configuration
+	// Options are shared by the ClusterRole and the ClusterRoleBinding controllers.
+	Options controller.Options
 }
 
 // InjectClient injects the Client interface, required by the Runnable interface
@@ -60,6 +63,7 @@ func (r *Manager) SetupWithManager(mgr ctrl.Manager, configurationName string) (
 				return r.filterByNames(genericEvent.Object.GetName())
 			},
 		})).
+		WithOptions(r.Options).
 		Complete(r)
 	if crErr != nil {
 		err = multierror.Append(err, crErr)
@@ -88,6 +92,7 @@ func (r *Manager) SetupWithManager(mgr ctrl.Manager, configurationName string) (
 				}
 			},
 		}).
+		WithOptions(r.Options).
 		Complete(r)
 	if crbErr != nil {
 		err = multierror.Append(err, crbErr)
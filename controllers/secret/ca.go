@@ -20,10 +20,12 @@ import (
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/clastix/capsule/pkg/cert"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
 type CAReconciler struct {
@@ -31,11 +33,17 @@ type CAReconciler struct {
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
 	Namespace string
+	Options   controller.Options
+	// The fraction of the CA lifetime after which it's renewed.
+	RenewalThreshold float64
+	// The fraction of the renewal interval randomly subtracted from it, spreading the renewals.
+	RequeueJitter float64
 }
 
 func (r *CAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Secret{}, forOptionPerInstanceName(CASecretName)).
+		WithOptions(r.Options).
 		Complete(r)
 }
 
@@ -51,17 +59,22 @@ func (r *CAReconciler) UpdateCustomResourceDefinition(caBundle []byte) error {
 		}
 
 		_, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, crd, func() error {
+			var existing []byte
+			if conversion := crd.Spec.Conversion; conversion != nil && conversion.Webhook != nil && conversion.Webhook.ClientConfig != nil {
+				existing = conversion.Webhook.ClientConfig.CABundle
+			}
+
 			crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
 				Strategy: "Webhook",
 				Webhook: &apiextensionsv1.WebhookConversion{
 					ClientConfig: &apiextensionsv1.WebhookClientConfig{
 						Service: &apiextensionsv1.ServiceReference{
 							Namespace: r.Namespace,
-							Name:      "capsule-webhook-service",
+							Name:      webhookServiceName,
 							Path:      pointer.StringPtr("/convert"),
 							Port:      pointer.Int32Ptr(443),
 						},
-						CABundle: caBundle,
+						CABundle: cert.MergeBundle(caBundle, existing, time.Now()),
 					},
 					ConversionReviewVersions: []string{"v1alpha1", "v1beta1"},
 				},
@@ -78,7 +91,7 @@ func (r *CAReconciler) UpdateCustomResourceDefinition(caBundle []byte) error {
 func (r CAReconciler) UpdateValidatingWebhookConfiguration(caBundle []byte) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
 		vw := &admissionregistrationv1.ValidatingWebhookConfiguration{}
-		err = r.Get(context.TODO(), types.NamespacedName{Name: "capsule-validating-webhook-configuration"}, vw)
+		err = r.Get(context.TODO(), types.NamespacedName{Name: ValidatingWebhookConfigurationName}, vw)
 		if err != nil {
 			r.Log.Error(err, "cannot retrieve ValidatingWebhookConfiguration")
 			return err
@@ -86,7 +99,7 @@ func (r CAReconciler) UpdateValidatingWebhookConfiguration(caBundle []byte) erro
 		for i, w := range vw.Webhooks {
 			// Updating CABundle only in case of an internal service reference
 			if w.ClientConfig.Service != nil {
-				vw.Webhooks[i].ClientConfig.CABundle = caBundle
+				vw.Webhooks[i].ClientConfig.CABundle = cert.MergeBundle(caBundle, w.ClientConfig.CABundle, time.Now())
 			}
 		}
 		return r.Update(context.TODO(), vw, &client.UpdateOptions{})
@@ -97,7 +110,7 @@ func (r CAReconciler) UpdateValidatingWebhookConfiguration(caBundle []byte) erro
 func (r CAReconciler) UpdateMutatingWebhookConfiguration(caBundle []byte) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
 		mw := &admissionregistrationv1.MutatingWebhookConfiguration{}
-		err = r.Get(context.TODO(), types.NamespacedName{Name: "capsule-mutating-webhook-configuration"}, mw)
+		err = r.Get(context.TODO(), types.NamespacedName{Name: MutatingWebhookConfigurationName}, mw)
 		if err != nil {
 			r.Log.Error(err, "cannot retrieve MutatingWebhookConfiguration")
 			return err
@@ -105,7 +118,7 @@ func (r CAReconciler) UpdateMutatingWebhookConfiguration(caBundle []byte) error
 		for i, w := range mw.Webhooks {
 			// Updating CABundle only in case of an internal service reference
 			if w.ClientConfig.Service != nil {
-				mw.Webhooks[i].ClientConfig.CABundle = caBundle
+				mw.Webhooks[i].ClientConfig.CABundle = cert.MergeBundle(caBundle, w.ClientConfig.CABundle, time.Now())
 			}
 		}
 		return r.Update(context.TODO(), mw, &client.UpdateOptions{})
@@ -115,7 +128,7 @@ func (r CAReconciler) UpdateMutatingWebhookConfiguration(caBundle []byte) error
 func (r CAReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	var err error
 
-	r.Log = r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	r.Log = r.Log.WithValues("namespace", request.Namespace, "name", request.Name)
 	r.Log.Info("Reconciling CA Secret")
 
 	// Fetch the CA instance
@@ -140,39 +153,45 @@ func (r CAReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl
 
 	r.Log.Info("Handling CA Secret")
 
-	rq, err = ca.ExpiresIn(time.Now())
-	if err != nil {
-		r.Log.Info("CA is expired, cleaning to obtain a new one")
-		instance.Data = map[string][]byte{}
-	} else {
-		r.Log.Info("Updating CA secret with new PEM and RSA")
-
-		var crt *bytes.Buffer
-		var key *bytes.Buffer
-		crt, _ = ca.CACertificatePem()
-		key, _ = ca.CAPrivateKeyPem()
-
-		instance.Data = map[string][]byte{
-			certSecretKey:       crt.Bytes(),
-			privateKeySecretKey: key.Bytes(),
-		}
-
-		group := new(errgroup.Group)
-		group.Go(func() error {
-			return r.UpdateMutatingWebhookConfiguration(crt.Bytes())
-		})
-		group.Go(func() error {
-			return r.UpdateValidatingWebhookConfiguration(crt.Bytes())
-		})
-		group.Go(func() error {
-			return r.UpdateCustomResourceDefinition(crt.Bytes())
-		})
+	// The CA is renewed before its expiration: the webhooks trust both the current and the renewed one
+	// until the serving certificate signed by the latter is rolled.
+	if _, err = ca.ExpiresIn(time.Now()); err != nil || time.Now().After(ca.RenewAt(r.RenewalThreshold)) {
+		r.Log.Info("CA is expired or approaching its expiration, generating a new one")
 
-		if err = group.Wait(); err != nil {
+		if ca, err = cert.GenerateCertificateAuthority(); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
+	rq = time.Until(ca.RenewAt(r.RenewalThreshold))
+
+	r.Log.Info("Updating CA secret with new PEM and RSA")
+
+	var crt *bytes.Buffer
+	var key *bytes.Buffer
+	crt, _ = ca.CACertificatePem()
+	key, _ = ca.CAPrivateKeyPem()
+
+	instance.Data = map[string][]byte{
+		certSecretKey:       crt.Bytes(),
+		privateKeySecretKey: key.Bytes(),
+	}
+
+	group := new(errgroup.Group)
+	group.Go(func() error {
+		return r.UpdateMutatingWebhookConfiguration(crt.Bytes())
+	})
+	group.Go(func() error {
+		return r.UpdateValidatingWebhookConfiguration(crt.Bytes())
+	})
+	group.Go(func() error {
+		return r.UpdateCustomResourceDefinition(crt.Bytes())
+	})
+
+	if err = group.Wait(); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	var res controllerutil.OperationResult
 	t := &corev1.Secret{ObjectMeta: instance.ObjectMeta}
 	res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() error {
@@ -184,29 +203,12 @@ func (r CAReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl
 		return reconcile.Result{}, err
 	}
 
+	// the TLS reconciler, watching the CA Secret, re-issues the serving certificate from the renewed CA,
+	// while the webhooks keep trusting the previous one
 	if res == controllerutil.OperationResultUpdated {
-		r.Log.Info("Capsule CA has been updated, we need to trigger TLS update too")
-		tls := &corev1.Secret{}
-		err = r.Get(ctx, types.NamespacedName{
-			Namespace: r.Namespace,
-			Name:      tlsSecretName,
-		}, tls)
-		if err != nil {
-			r.Log.Error(err, "Capsule TLS Secret missing")
-		}
-		err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-			_, err = controllerutil.CreateOrUpdate(ctx, r.Client, tls, func() error {
-				tls.Data = map[string][]byte{}
-				return nil
-			})
-			return err
-		})
-		if err != nil {
-			r.Log.Error(err, "Cannot clean Capsule TLS Secret due to CA update")
-			return reconcile.Result{}, err
-		}
+		r.Log.Info("Capsule CA has been updated, the TLS certificate will be re-issued")
 	}
 
-	r.Log.Info("Reconciliation completed, processing back in " + rq.String())
-	return reconcile.Result{Requeue: true, RequeueAfter: rq}, nil
+	r.Log.Info("Reconciliation completed", "requeueAfter", rq.String())
+	return reconcile.Result{Requeue: true, RequeueAfter: utils.Jitter(rq, r.RequeueJitter)}, nil
 }
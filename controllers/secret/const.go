@@ -9,4 +9,10 @@ const (
 
 	CASecretName  = "capsule-ca"
 	tlsSecretName = "capsule-tls"
+
+	webhookServiceName = "capsule-webhook-service"
+	metricsServiceName = "capsule-controller-manager-metrics-service"
+
+	MutatingWebhookConfigurationName   = "capsule-mutating-webhook-configuration"
+	ValidatingWebhookConfigurationName = "capsule-validating-webhook-configuration"
 )
@@ -14,14 +14,19 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/clastix/capsule/pkg/cert"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
 type TLSReconciler struct {
@@ -29,18 +34,32 @@ type TLSReconciler struct {
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
 	Namespace string
+	Options   controller.Options
+	// The fraction of the certificate lifetime after which it's renewed.
+	RenewalThreshold float64
+	// The fraction of the renewal interval randomly subtracted from it, spreading the renewals.
+	RequeueJitter float64
 }
 
 func (r *TLSReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Secret{}, forOptionPerInstanceName(tlsSecretName)).
+		// re-issuing the certificate once the CA is renewed
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			if object.GetName() != CASecretName || object.GetNamespace() != r.Namespace {
+				return nil
+			}
+
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: r.Namespace, Name: tlsSecretName}}}
+		})).
+		WithOptions(r.Options).
 		Complete(r)
 }
 
 func (r TLSReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	var err error
 
-	r.Log = r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	r.Log = r.Log.WithValues("namespace", request.Namespace, "name", request.Name)
 	r.Log.Info("Reconciling TLS Secret")
 
 	// Fetch the Secret instance
@@ -59,7 +78,7 @@ func (r TLSReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctr
 		return reconcile.Result{}, err
 	}
 
-	var shouldCreate bool
+	var shouldCreate, renewal bool
 	for _, key := range []string{certSecretKey, privateKeySecretKey} {
 		if _, ok := instance.Data[key]; !ok {
 			shouldCreate = true
@@ -67,11 +86,40 @@ func (r TLSReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctr
 		}
 	}
 
+	if !shouldCreate {
+		var c *x509.Certificate
+		var b *pem.Block
+		b, _ = pem.Decode(instance.Data[certSecretKey])
+		c, err = x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			r.Log.Error(err, "cannot parse Capsule TLS")
+			return reconcile.Result{}, err
+		}
+
+		rq = time.Until(cert.RenewAt(c, r.RenewalThreshold))
+
+		switch invalid := ca.ValidateCert(c); {
+		case invalid != nil && time.Now().Before(c.NotAfter):
+			// signed by the previous CA, still trusted by the webhooks along with the renewed one
+			r.Log.Info("Capsule TLS is not signed by the current CA, renewing it")
+			shouldCreate, renewal = true, true
+		case invalid != nil:
+			r.Log.Info("Capsule TLS is expired or invalid, generating a new one")
+			shouldCreate = true
+		case !r.hasDNSNames(c):
+			r.Log.Info("Capsule TLS is missing some of the Capsule Services names, generating a new one")
+			shouldCreate = true
+		case rq <= 0:
+			r.Log.Info("Capsule TLS is approaching its expiration, renewing it")
+			shouldCreate, renewal = true, true
+		}
+	}
+
 	if shouldCreate {
-		r.Log.Info("Missing Capsule TLS certificate")
-		rq = 6 * 30 * 24 * time.Hour
+		r.Log.Info("Generating Capsule TLS certificate")
+		lifetime := 6 * 30 * 24 * time.Hour
 
-		opts := cert.NewCertOpts(time.Now().Add(rq), fmt.Sprintf("capsule-webhook-service.%s.svc", r.Namespace))
+		opts := cert.NewCertOpts(time.Now().Add(lifetime), r.dnsNames()...)
 		var crt, key *bytes.Buffer
 		crt, key, err = ca.GenerateCertificate(opts)
 		if err != nil {
@@ -82,37 +130,37 @@ func (r TLSReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctr
 			certSecretKey:       crt.Bytes(),
 			privateKeySecretKey: key.Bytes(),
 		}
-	} else {
+
+		b, _ := pem.Decode(crt.Bytes())
+
 		var c *x509.Certificate
-		var b *pem.Block
-		b, _ = pem.Decode(instance.Data[certSecretKey])
-		c, err = x509.ParseCertificate(b.Bytes)
-		if err != nil {
-			r.Log.Error(err, "cannot parse Capsule TLS")
+		if c, err = x509.ParseCertificate(b.Bytes); err != nil {
 			return reconcile.Result{}, err
 		}
 
-		rq = time.Until(c.NotAfter)
-
-		err = ca.ValidateCert(c)
-		if err != nil {
-			r.Log.Info("Capsule TLS is expired or invalid, cleaning to obtain a new one")
-			instance.Data = map[string][]byte{}
-		}
+		rq = time.Until(cert.RenewAt(c, r.RenewalThreshold))
 	}
 
 	var res controllerutil.OperationResult
-	t := &corev1.Secret{ObjectMeta: instance.ObjectMeta}
-	res, err = controllerutil.CreateOrUpdate(ctx, r.Client, t, func() error {
-		t.Data = instance.Data
-		return nil
+	res, err = utils.Apply(ctx, r.Client, r.Scheme, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetName(),
+			Namespace: instance.GetNamespace(),
+		},
+		Data: instance.Data,
 	})
 	if err != nil {
 		r.Log.Error(err, "cannot update Capsule TLS")
 		return reconcile.Result{}, err
 	}
 
-	if instance.Name == tlsSecretName && res == controllerutil.OperationResultUpdated {
+	// The renewed certificate is rolled by the webhook server watching the mounted Secret,
+	// while the current one is still valid: no restart is required.
+	if renewal && res == controllerutil.OperationResultUpdated {
+		r.Log.Info("Capsule TLS certificate has been renewed, it will be loaded by the webhook server once the mounted Secret is updated")
+	}
+
+	if instance.Name == tlsSecretName && res == controllerutil.OperationResultUpdated && !renewal {
 		r.Log.Info("Capsule TLS certificates has been updated, Controller pods must be restarted to load new certificate")
 
 		hostname, _ := os.Hostname()
@@ -147,6 +195,25 @@ func (r TLSReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctr
 		}
 	}
 
-	r.Log.Info("Reconciliation completed, processing back in " + rq.String())
-	return reconcile.Result{Requeue: true, RequeueAfter: rq}, nil
+	r.Log.Info("Reconciliation completed", "requeueAfter", rq.String())
+	return reconcile.Result{Requeue: true, RequeueAfter: utils.Jitter(rq, r.RequeueJitter)}, nil
+}
+
+// dnsNames returns the names the certificate is issued for: the webhook server one,
+// and the metrics one when served over TLS.
+func (r TLSReconciler) dnsNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", webhookServiceName, r.Namespace),
+		fmt.Sprintf("%s.%s.svc", metricsServiceName, r.Namespace),
+	}
+}
+
+func (r TLSReconciler) hasDNSNames(c *x509.Certificate) bool {
+	for _, name := range r.dnsNames() {
+		if c.VerifyHostname(name) != nil {
+			return false
+		}
+	}
+
+	return true
 }
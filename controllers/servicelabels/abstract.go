@@ -42,7 +42,7 @@ func (r *abstractServiceLabelsReconciler) Reconcile(ctx context.Context, request
 		case *NonTenantObject, *NoServicesMetadata:
 			return reconcile.Result{}, nil
 		default:
-			r.log.Error(err, fmt.Sprintf("Cannot sync %t labels", r.obj))
+			r.log.Error(err, "Cannot sync labels", "kind", fmt.Sprintf("%T", r.obj), "namespace", request.Namespace, "name", request.Name)
 			return reconcile.Result{}, err
 		}
 	}
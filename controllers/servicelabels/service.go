@@ -7,12 +7,14 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
 type ServicesLabelsReconciler struct {
 	abstractServiceLabelsReconciler
 
-	Log logr.Logger
+	Log     logr.Logger
+	Options controller.Options
 }
 
 func (r *ServicesLabelsReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -23,5 +25,6 @@ func (r *ServicesLabelsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(r.abstractServiceLabelsReconciler.obj, r.abstractServiceLabelsReconciler.forOptionPerInstanceName()).
+		WithOptions(r.Options).
 		Complete(r)
 }
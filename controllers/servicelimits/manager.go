@@ -0,0 +1,135 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package servicelimits
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// Manager reports in the status of the Tenants limiting their Services the LoadBalancers and the node ports
+// counted across their Namespaces, as enforced upon the Services admission.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("servicelimits").
+		For(&capsulev1beta1.Tenant{}).
+		Watches(&source.Kind{Type: &corev1.Service{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			tntList := &capsulev1beta1.TenantList{}
+			if err := r.List(context.Background(), tntList, client.MatchingFieldsSelector{
+				Selector: fields.OneTermEqualSelector(".status.namespaces", object.GetNamespace()),
+			}); err != nil {
+				r.Log.Error(err, "Cannot list the Tenants", "namespace", object.GetNamespace())
+
+				return nil
+			}
+
+			if len(tntList.Items) == 0 {
+				return nil
+			}
+
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: tntList.Items[0].GetName()}}}
+		}), builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldSvc, ok := e.ObjectOld.(*corev1.Service)
+				if !ok {
+					return false
+				}
+
+				svc, ok := e.ObjectNew.(*corev1.Service)
+				if !ok {
+					return false
+				}
+
+				var previous, current capsulev1beta1.ServiceUsage
+
+				previous.Add(oldSvc)
+				current.Add(svc)
+
+				return previous != current
+			},
+		})).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		log.Error(err, "Error reading the object")
+
+		return
+	}
+
+	var usage *capsulev1beta1.ServiceUsage
+
+	if tnt.ServiceLimits() != nil {
+		usage = &capsulev1beta1.ServiceUsage{}
+
+		for _, ns := range tnt.Status.Namespaces {
+			svcList := &corev1.ServiceList{}
+			if err = r.List(ctx, svcList, client.InNamespace(ns)); err != nil {
+				log.Error(err, "Cannot list the Tenant Services", "namespace", ns)
+
+				return
+			}
+
+			for i := range svcList.Items {
+				usage.Add(&svcList.Items[i])
+			}
+		}
+	}
+
+	if err = r.updateStatus(ctx, tnt, usage); err != nil {
+		log.Error(err, "Cannot update the Tenant Services usage")
+	}
+
+	return
+}
+
+func (r *Manager) updateStatus(ctx context.Context, tnt *capsulev1beta1.Tenant, usage *capsulev1beta1.ServiceUsage) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), found); err != nil {
+			return
+		}
+
+		if equality.Semantic.DeepEqual(found.Status.Services, usage) {
+			return nil
+		}
+
+		found.Status.Services = usage
+
+		return r.Client.Status().Update(ctx, found)
+	})
+}
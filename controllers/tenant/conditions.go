@@ -0,0 +1,39 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// updateConditions reports the outcome of the reconciliation of the Tenant in its conditions: these are left as they
+// are when the Tenant spec has been changed meanwhile, since a new reconciliation is pending.
+func (r *Manager) updateConditions(ctx context.Context, tnt *capsulev1beta1.Tenant, reconcileErr error) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), found); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		if found.GetGeneration() != tnt.GetGeneration() {
+			return nil
+		}
+
+		updated := found.DeepCopy()
+		updated.SetReadyCondition(reconcileErr)
+		updated.SetCordonedCondition()
+
+		if equality.Semantic.DeepEqual(found.Status.Conditions, updated.Status.Conditions) {
+			return nil
+		}
+
+		return r.Client.Status().Update(ctx, updated)
+	})
+}
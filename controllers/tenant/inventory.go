@@ -0,0 +1,264 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+type InventoryState string
+
+const (
+	// InventoryStateSynced reports the object as last asserted by Capsule.
+	InventoryStateSynced InventoryState = "Synced"
+	// InventoryStateDrifted reports the object changed after the last assertion, pending the next reconciliation:
+	// the Namespaces and the ResourceQuotas are never reported as drifted, since their status is continuously updated.
+	InventoryStateDrifted InventoryState = "Drifted"
+	// InventoryStateFailed reports the last assertion of the object failed.
+	InventoryStateFailed InventoryState = "Failed"
+	// InventoryStateUnknown reports the object has not been asserted since the replica started.
+	InventoryStateUnknown InventoryState = "Unknown"
+)
+
+// InventoryItem is an object managed by Capsule for a Tenant, as served by the inventory endpoint.
+type InventoryItem struct {
+	Kind         string         `json:"kind"`
+	Namespace    string         `json:"namespace,omitempty"`
+	Name         string         `json:"name"`
+	State        InventoryState `json:"state"`
+	Message      string         `json:"message,omitempty"`
+	LastSyncTime *metav1.Time   `json:"lastSyncTime,omitempty"`
+}
+
+type inventoryResult struct {
+	resourceVersion string
+	err             error
+	time            metav1.Time
+}
+
+// Inventory keeps track of the last assertion of the objects managed by the Tenant controller, serving the ones
+// of a Tenant along with their sync state: the controller runs on the leader only, so the other replicas don't serve
+// the inventory.
+type Inventory struct {
+	Client client.Client
+	Log    logr.Logger
+	// Elected is closed once the replica is elected as leader, as the channel returned by the manager.
+	Elected <-chan struct{}
+
+	mutex   sync.Mutex
+	results map[string]map[string]inventoryResult
+}
+
+func inventoryKind(obj client.Object) string {
+	switch obj.(type) {
+	case *corev1.Namespace:
+		return "Namespace"
+	case *corev1.LimitRange:
+		return "LimitRange"
+	case *corev1.ResourceQuota:
+		return "ResourceQuota"
+	case *networkingv1.NetworkPolicy:
+		return "NetworkPolicy"
+	case *rbacv1.RoleBinding:
+		return "RoleBinding"
+	default:
+		return ""
+	}
+}
+
+func inventoryKey(kind string, obj client.Object) string {
+	return kind + "/" + types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+}
+
+// track records the result of the assertion of the given object.
+func (i *Inventory) track(tenant string, obj client.Object, err error) {
+	if i == nil {
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.results == nil {
+		i.results = make(map[string]map[string]inventoryResult)
+	}
+
+	if _, ok := i.results[tenant]; !ok {
+		i.results[tenant] = make(map[string]inventoryResult)
+	}
+
+	kind := inventoryKind(obj)
+
+	result := inventoryResult{
+		err:  err,
+		time: metav1.Now(),
+	}
+	// the resource version of the objects having a status doesn't track the changes to the desired state
+	if kind != "Namespace" && kind != "ResourceQuota" {
+		result.resourceVersion = obj.GetResourceVersion()
+	}
+
+	i.results[tenant][inventoryKey(kind, obj)] = result
+}
+
+// forget drops the results of a deleted Tenant.
+func (i *Inventory) forget(tenant string) {
+	if i == nil {
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	delete(i.results, tenant)
+}
+
+// Items lists the objects managed by Capsule for the given Tenant, reporting their sync state.
+func (i *Inventory) Items(ctx context.Context, tenant string) ([]InventoryItem, error) {
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return nil, err
+	}
+
+	lists := []client.ObjectList{
+		&corev1.NamespaceList{},
+		&corev1.LimitRangeList{},
+		&corev1.ResourceQuotaList{},
+		&networkingv1.NetworkPolicyList{},
+		&rbacv1.RoleBindingList{},
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	results := i.results[tenant]
+	listed := make(map[string]struct{})
+
+	var items []InventoryItem
+
+	for _, list := range lists {
+		if err = i.Client.List(ctx, list, client.MatchingLabels{tenantLabel: tenant}); err != nil {
+			return nil, err
+		}
+
+		if err = meta.EachListItem(list, func(o runtime.Object) error {
+			obj, ok := o.(client.Object)
+			if !ok {
+				return nil
+			}
+
+			kind := inventoryKind(obj)
+			key := inventoryKey(kind, obj)
+			listed[key] = struct{}{}
+
+			item := InventoryItem{
+				Kind:      kind,
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				State:     InventoryStateUnknown,
+			}
+
+			if result, ok := results[key]; ok {
+				item.LastSyncTime = result.time.DeepCopy()
+
+				switch {
+				case result.err != nil:
+					item.State, item.Message = InventoryStateFailed, result.err.Error()
+				case len(result.resourceVersion) > 0 && result.resourceVersion != obj.GetResourceVersion():
+					item.State = InventoryStateDrifted
+				default:
+					item.State = InventoryStateSynced
+				}
+			}
+
+			items = append(items, item)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	// the objects no more existing, as the pruned ones, are forgotten
+	for key := range results {
+		if _, ok := listed[key]; !ok {
+			delete(results, key)
+		}
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Kind != items[b].Kind {
+			return items[a].Kind < items[b].Kind
+		}
+		if items[a].Namespace != items[b].Namespace {
+			return items[a].Namespace < items[b].Namespace
+		}
+		return items[a].Name < items[b].Name
+	})
+
+	return items, nil
+}
+
+// ServeHTTP serves as JSON the inventory of the Tenant set with the tenant query parameter, on the leader only.
+func (i *Inventory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-i.Elected:
+	default:
+		http.Error(w, "the inventory is served by the leader replica only", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	name := r.URL.Query().Get("tenant")
+	if len(name) == 0 {
+		http.Error(w, "missing tenant query parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := i.Client.Get(r.Context(), types.NamespacedName{Name: name}, &capsulev1beta1.Tenant{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "tenant not found", http.StatusNotFound)
+
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	items, err := i.Items(r.Context(), name)
+	if err != nil {
+		i.Log.Error(err, "Cannot list the Tenant inventory", "tenant", name)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(struct {
+		Tenant string          `json:"tenant"`
+		Items  []InventoryItem `json:"items"`
+	}{Tenant: name, Items: items}); err != nil {
+		i.Log.Error(err, "Cannot export the Tenant inventory", "tenant", name)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestInventory_Items(t *testing.T) {
+	labels := map[string]string{"capsule.clastix.io/tenant": "oil"}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-production", Labels: labels}}
+	np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil-0", Namespace: "oil-production", Labels: labels}}
+	lr := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil-0", Namespace: "oil-production", Labels: labels}}
+	rq := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil-0", Namespace: "oil-production", Labels: labels}}
+	other := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "capsule-gas-0", Namespace: "gas-production", Labels: map[string]string{"capsule.clastix.io/tenant": "gas"}}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ns, np, lr, rq, other).Build()
+
+	inventory := &Inventory{Client: c}
+
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(np), np))
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(lr), lr))
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(rq), rq))
+
+	inventory.track("oil", np, nil)
+	inventory.track("oil", rq, fmt.Errorf("quota exceeded"))
+	inventory.track("oil", lr, nil)
+	// the LimitRange is edited after the assertion
+	lr.Spec.Limits = []corev1.LimitRangeItem{{Type: corev1.LimitTypeContainer}}
+	assert.NoError(t, c.Update(context.Background(), lr))
+
+	items, err := inventory.Items(context.Background(), "oil")
+	assert.NoError(t, err)
+
+	states := make(map[string]InventoryState)
+	for _, item := range items {
+		states[item.Kind] = item.State
+	}
+
+	assert.Equal(t, map[string]InventoryState{
+		"Namespace":     InventoryStateUnknown,
+		"NetworkPolicy": InventoryStateSynced,
+		"LimitRange":    InventoryStateDrifted,
+		"ResourceQuota": InventoryStateFailed,
+	}, states)
+	assert.Equal(t, "LimitRange", items[0].Kind)
+}
+
+func TestInventory_ServeHTTP(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil"}}
+
+	s := runtime.NewScheme()
+	assert.NoError(t, scheme.AddToScheme(s))
+	assert.NoError(t, capsulev1beta1.AddToScheme(s))
+
+	elected := make(chan struct{})
+	inventory := &Inventory{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(tnt).Build(), Elected: elected}
+	// the followers don't serve the inventory
+	recorder := httptest.NewRecorder()
+	inventory.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/inventory?tenant=oil", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	close(elected)
+
+	recorder = httptest.NewRecorder()
+	inventory.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/inventory?tenant=oil", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -8,34 +8,30 @@ import (
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
-// Ensuring all the LimitRange are applied to each Namespace handled by the Tenant.
+// Ensuring all the LimitRange are applied to each Namespace handled by the Tenant,
+// according to the overrides and the profile selected by the Namespace.
 func (r *Manager) syncLimitRanges(tenant *capsulev1beta1.Tenant) error {
-	// getting requested LimitRange keys
-	keys := make([]string, 0, len(tenant.Spec.LimitRanges.Items))
-
-	for i := range tenant.Spec.LimitRanges.Items {
-		keys = append(keys, strconv.Itoa(i))
-	}
-
 	group := new(errgroup.Group)
 
 	for _, ns := range tenant.Status.Namespaces {
 		namespace := ns
 
 		group.Go(func() error {
-			return r.syncLimitRange(tenant, namespace, keys)
+			return r.syncLimitRange(tenant, namespace)
 		})
 	}
 
 	return group.Wait()
 }
 
-func (r *Manager) syncLimitRange(tenant *capsulev1beta1.Tenant, namespace string, keys []string) (err error) {
+func (r *Manager) syncLimitRange(tenant *capsulev1beta1.Tenant, namespace string) (err error) {
 	// getting LimitRange labels for the mutateFn
 	var tenantLabel, limitRangeLabel string
 
@@ -46,31 +42,51 @@ func (r *Manager) syncLimitRange(tenant *capsulev1beta1.Tenant, namespace string
 		return
 	}
 
+	ns := &corev1.Namespace{}
+	if err = r.Client.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+		return
+	}
+
+	var items []corev1.LimitRangeSpec
+	if items, err = tenant.LimitRangesFor(ns); err != nil {
+		return
+	}
+
+	// getting requested LimitRange keys
+	keys := make([]string, 0, len(items))
+
+	for i := range items {
+		keys = append(keys, strconv.Itoa(i))
+	}
+
 	if err = r.pruningResources(namespace, keys, &corev1.LimitRange{}); err != nil {
 		return
 	}
 
-	for i, spec := range tenant.Spec.LimitRanges.Items {
+	for i, spec := range items {
 		target := &corev1.LimitRange{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      fmt.Sprintf("capsule-%s-%d", tenant.Name, i),
 				Namespace: namespace,
+				Labels: map[string]string{
+					tenantLabel:     tenant.Name,
+					limitRangeLabel: strconv.Itoa(i),
+				},
 			},
+			Spec: spec,
 		}
 
+		stampMetadata(tenant, target)
+
 		var res controllerutil.OperationResult
-		res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
-			target.ObjectMeta.Labels = map[string]string{
-				tenantLabel:     tenant.Name,
-				limitRangeLabel: strconv.Itoa(i),
-			}
-			target.Spec = spec
-			return controllerutil.SetControllerReference(tenant, target, r.Scheme)
-		})
+		if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err == nil {
+			res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
+		}
 
 		r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring LimitRange %s", target.GetName()), err)
+		r.Inventory.track(tenant.Name, target, err)
 
-		r.Log.Info("LimitRange sync result: "+string(res), "name", target.Name, "namespace", target.Namespace)
+		r.Log.Info("LimitRange synced", "result", res, "name", target.Name, "namespace", target.Namespace)
 		if err != nil {
 			return
 		}
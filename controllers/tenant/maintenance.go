@@ -0,0 +1,192 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/utils"
+)
+
+// maintenance collects the disruptive changes deferred by a reconciliation, when the Tenant maintenance windows are
+// closed: when open, the changes are applied straight away.
+type maintenance struct {
+	open bool
+
+	mutex   sync.Mutex
+	pending []capsulev1beta1.PendingChange
+}
+
+func (m *maintenance) deferred() bool {
+	return m != nil && !m.open
+}
+
+func (m *maintenance) add(kind string, obj client.Object, reason string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.pending = append(m.pending, capsulev1beta1.PendingChange{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Reason:    reason,
+	})
+}
+
+// changes returns the pending changes sorted by kind, namespace and name.
+func (m *maintenance) changes() []capsulev1beta1.PendingChange {
+	if m == nil {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sort.SliceStable(m.pending, func(a, b int) bool {
+		if m.pending[a].Kind != m.pending[b].Kind {
+			return m.pending[a].Kind < m.pending[b].Kind
+		}
+		if m.pending[a].Namespace != m.pending[b].Namespace {
+			return m.pending[a].Namespace < m.pending[b].Namespace
+		}
+		return m.pending[a].Name < m.pending[b].Name
+	})
+
+	return m.pending
+}
+
+// dryRunApply returns the existing object, nil if missing, and the one resulting from the server-side apply of the
+// desired one, without persisting it: the comparison of the two isn't affected by the API server defaults.
+func (r *Manager) dryRunApply(ctx context.Context, desired client.Object) (existing, applied client.Object, err error) {
+	gvk, err := apiutil.GVKForObject(desired, r.Scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ok bool
+
+	if existing, ok = desired.DeepCopyObject().(client.Object); !ok {
+		return nil, nil, nil
+	}
+
+	if err = r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, err
+		}
+
+		existing = nil
+	}
+
+	if applied, ok = desired.DeepCopyObject().(client.Object); !ok {
+		return nil, nil, nil
+	}
+
+	applied.GetObjectKind().SetGroupVersionKind(gvk)
+	applied.SetResourceVersion("")
+	applied.SetManagedFields(nil)
+
+	if err = r.Patch(ctx, applied, client.Apply, client.FieldOwner(utils.FieldManager), client.ForceOwnership, client.DryRunAll); err != nil {
+		return nil, nil, err
+	}
+
+	return existing, applied, nil
+}
+
+// resourceQuotaDisruption returns why the apply of the ResourceQuota is disruptive, lowering or adding a hard limit
+// of an existing one, empty if it isn't.
+func (r *Manager) resourceQuotaDisruption(ctx context.Context, desired *corev1.ResourceQuota) (string, error) {
+	existing, applied, err := r.dryRunApply(ctx, desired)
+	if err != nil || existing == nil || applied == nil {
+		return "", err
+	}
+
+	if shrunk := shrunkResources(existing.(*corev1.ResourceQuota).Spec.Hard, applied.(*corev1.ResourceQuota).Spec.Hard); len(shrunk) > 0 {
+		return "lowers the hard limits of " + joinResourceNames(shrunk), nil
+	}
+
+	return "", nil
+}
+
+// networkPolicyDisruption returns why the apply of the NetworkPolicy is disruptive, changing the rules of an existing
+// one, or adding a new one to a Namespace already holding the Tenant ones, empty if it isn't.
+func (r *Manager) networkPolicyDisruption(ctx context.Context, desired *networkingv1.NetworkPolicy, namespaceHasPolicies bool) (string, error) {
+	existing, applied, err := r.dryRunApply(ctx, desired)
+	if err != nil || applied == nil {
+		return "", err
+	}
+
+	if existing == nil {
+		if namespaceHasPolicies {
+			return "adds a NetworkPolicy to the Namespace", nil
+		}
+
+		return "", nil
+	}
+
+	if !equality.Semantic.DeepEqual(existing.(*networkingv1.NetworkPolicy).Spec, applied.(*networkingv1.NetworkPolicy).Spec) {
+		return "changes the NetworkPolicy rules", nil
+	}
+
+	return "", nil
+}
+
+// shrunkResources returns the names of the resources whose hard limit is lowered, or added, by the desired one,
+// sorted by name.
+func shrunkResources(existing, desired corev1.ResourceList) (names []corev1.ResourceName) {
+	for name, quantity := range desired {
+		if current, ok := existing[name]; !ok || quantity.Cmp(current) < 0 {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func joinResourceNames(names []corev1.ResourceName) (joined string) {
+	for i, name := range names {
+		if i > 0 {
+			joined += ", "
+		}
+
+		joined += string(name)
+	}
+
+	return joined
+}
+
+// updatePendingChanges reports the deferred changes in the Tenant status, along with the time the next maintenance
+// window opens at.
+func (r *Manager) updatePendingChanges(ctx context.Context, tnt *capsulev1beta1.Tenant, changes []capsulev1beta1.PendingChange, next *metav1.Time) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, client.ObjectKeyFromObject(tnt), found); err != nil {
+			return
+		}
+
+		if equality.Semantic.DeepEqual(found.Status.PendingChanges, changes) && equality.Semantic.DeepEqual(found.Status.NextMaintenanceWindow, next) {
+			return nil
+		}
+
+		found.Status.PendingChanges = changes
+		found.Status.NextMaintenanceWindow = next
+
+		return r.Client.Status().Update(ctx, found)
+	})
+}
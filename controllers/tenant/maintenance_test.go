@@ -0,0 +1,53 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestShrunkResources(t *testing.T) {
+	existing := corev1.ResourceList{
+		corev1.ResourceLimitsCPU:    resource.MustParse("8"),
+		corev1.ResourceLimitsMemory: resource.MustParse("16Gi"),
+		corev1.ResourcePods:         resource.MustParse("10"),
+	}
+
+	assert.Empty(t, shrunkResources(existing, existing))
+	assert.Empty(t, shrunkResources(existing, corev1.ResourceList{
+		corev1.ResourceLimitsCPU: resource.MustParse("8000m"),
+		corev1.ResourcePods:      resource.MustParse("20"),
+	}))
+	assert.Equal(t, []corev1.ResourceName{corev1.ResourceLimitsMemory, corev1.ResourceRequestsStorage}, shrunkResources(existing, corev1.ResourceList{
+		corev1.ResourceLimitsMemory:    resource.MustParse("8Gi"),
+		corev1.ResourceRequestsStorage: resource.MustParse("100Gi"),
+	}))
+}
+
+func TestMaintenance(t *testing.T) {
+	var none *maintenance
+	assert.False(t, none.deferred())
+	assert.Empty(t, none.changes())
+
+	closed := &maintenance{}
+	assert.True(t, closed.deferred())
+
+	closed.add("ResourceQuota", &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil-0", Namespace: "oil-production"}}, "lowers the hard limits of pods")
+	closed.add("NetworkPolicy", &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil-1", Namespace: "oil-production"}}, "changes the NetworkPolicy rules")
+
+	assert.Equal(t, []capsulev1beta1.PendingChange{
+		{Kind: "NetworkPolicy", Namespace: "oil-production", Name: "capsule-oil-1", Reason: "changes the NetworkPolicy rules"},
+		{Kind: "ResourceQuota", Namespace: "oil-production", Name: "capsule-oil-0", Reason: "lowers the hard limits of pods"},
+	}, closed.changes())
+
+	assert.False(t, (&maintenance{open: true}).deferred())
+}
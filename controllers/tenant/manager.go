@@ -2,20 +2,27 @@ package tenant
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
 type Manager struct {
@@ -23,6 +30,19 @@ type Manager struct {
 	Log      logr.Logger
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	Options  controller.Options
+	// Configuration provides the protected Namespaces and the Tenant prefix the declared Namespaces are subject to.
+	Configuration configuration.Configuration
+	// ResyncPeriod is the interval a Tenant is reconciled at even without watch events,
+	// re-asserting the generated objects: zero disables the periodic reconciliation.
+	ResyncPeriod time.Duration
+	// ResyncJitter is the fraction of the ResyncPeriod randomly subtracted from it, so that the Tenants are not
+	// reconciled in lockstep.
+	ResyncJitter float64
+	// Inventory tracks the objects asserted for each Tenant, served by the inventory endpoint.
+	Inventory *Inventory
+
+	maintenance *maintenance
 }
 
 func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
@@ -33,28 +53,45 @@ func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.LimitRange{}).
 		Owns(&corev1.ResourceQuota{}).
 		Owns(&rbacv1.RoleBinding{}).
+		Watches(&source.Kind{Type: &capsulev1beta1.Tenant{}}, handler.EnqueueRequestsFromMapFunc(r.peersOf)).
+		WithOptions(r.Options).
 		Complete(r)
 }
 
 func (r Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
-	r.Log = r.Log.WithValues("Request.Name", request.Name)
+	r.Log = r.Log.WithValues("tenant", request.Name)
 
 	// Fetch the Tenant instance
 	instance := &capsulev1beta1.Tenant{}
 	if err = r.Get(ctx, request.NamespacedName, instance); err != nil {
 		if errors.IsNotFound(err) {
 			r.Log.Info("Request object not found, could have been deleted after reconcile request")
+			r.Inventory.forget(request.Name)
 			return reconcile.Result{}, nil
 		}
 		r.Log.Error(err, "Error reading the object")
 		return
 	}
+	// Reporting the outcome of the reconciliation in the Tenant conditions
+	defer func() {
+		if conditionsErr := r.updateConditions(ctx, instance, err); conditionsErr != nil {
+			r.Log.Error(conditionsErr, "Cannot update the Tenant conditions")
+		}
+	}()
+	// Deferring the disruptive changes when the maintenance windows are closed
+	r.maintenance = &maintenance{open: instance.InMaintenanceWindow(time.Now())}
 	// Ensuring the Tenant Status
 	if err = r.updateTenantStatus(instance); err != nil {
 		r.Log.Error(err, "Cannot update Tenant status")
 		return
 	}
 
+	r.Log.Info("Starting processing of declared Namespaces", "items", len(instance.Spec.Namespaces))
+	if err = r.syncDeclaredNamespaces(instance); err != nil {
+		r.Log.Error(err, "Cannot sync declared Namespace items")
+		return
+	}
+
 	// Ensuring all namespaces are collected
 	r.Log.Info("Ensuring all Namespaces are collected")
 	if err = r.collectNamespaces(instance); err != nil {
@@ -86,6 +123,12 @@ func (r Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ct
 		return
 	}
 
+	r.Log.Info("Starting processing of object counts", "items", len(instance.Spec.ResourceQuota.ObjectCounts))
+	if err = r.syncObjectCounts(instance); err != nil {
+		r.Log.Error(err, "Cannot sync object counts")
+		return
+	}
+
 	r.Log.Info("Ensuring additional RoleBindings for owner")
 	if err = r.syncAdditionalRoleBindings(instance); err != nil {
 		r.Log.Error(err, "Cannot sync additional RoleBindings items")
@@ -104,8 +147,26 @@ func (r Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ct
 		return
 	}
 
+	result.RequeueAfter = utils.Jitter(r.ResyncPeriod, r.ResyncJitter)
+
+	r.Log.Info("Ensuring pending changes")
+	var next *metav1.Time
+	if changes := r.maintenance.changes(); len(changes) > 0 {
+		if start := instance.NextMaintenanceWindow(time.Now()); !start.IsZero() {
+			next = &metav1.Time{Time: start}
+			// the deferred changes are applied as soon as the next maintenance window opens
+			if until := time.Until(start) + time.Second; result.RequeueAfter == 0 || until < result.RequeueAfter {
+				result.RequeueAfter = until
+			}
+		}
+	}
+	if err = r.updatePendingChanges(ctx, instance, r.maintenance.changes(), next); err != nil {
+		r.Log.Error(err, "Cannot update the Tenant pending changes")
+		return
+	}
+
 	r.Log.Info("Tenant reconciling completed")
-	return ctrl.Result{}, err
+	return result, err
 }
 
 func (r *Manager) updateTenantStatus(tnt *capsulev1beta1.Tenant) error {
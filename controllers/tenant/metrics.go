@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var driftsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capsule_tenant_drifts_total",
+	Help: "The objects generated by Capsule in the Tenant Namespaces updated to re-assert their desired state, as upon manual edits or Tenant changes.",
+}, []string{"tenant"})
+
+func init() {
+	metrics.Registry.MustRegister(driftsTotal)
+}
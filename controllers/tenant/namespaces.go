@@ -10,6 +10,8 @@ import (
 
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -17,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
 )
 
 // Ensuring all annotations are applied to each Namespace handled by the Tenant.
@@ -42,8 +45,10 @@ func (r *Manager) syncNamespaces(tenant *capsulev1beta1.Tenant) (err error) {
 func (r *Manager) syncNamespaceMetadata(namespace string, tnt *capsulev1beta1.Tenant) (err error) {
 	var res controllerutil.OperationResult
 
+	var ns *corev1.Namespace
+
 	err = retry.RetryOnConflict(retry.DefaultBackoff, func() (conflictErr error) {
-		ns := &corev1.Namespace{}
+		ns = &corev1.Namespace{}
 		if conflictErr = r.Client.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
 			return
 		}
@@ -143,10 +148,154 @@ func (r *Manager) syncNamespaceMetadata(namespace string, tnt *capsulev1beta1.Te
 	})
 
 	r.emitEvent(tnt, namespace, res, "Ensuring Namespace metadata", err)
+	r.Inventory.track(tnt.Name, ns, err)
+
+	return
+}
+
+// Ensuring the Namespaces declared by the Tenant exist and are bound to it.
+func (r *Manager) syncDeclaredNamespaces(tenant *capsulev1beta1.Tenant) (err error) {
+	group := new(errgroup.Group)
+
+	bound := make(map[string]struct{}, len(tenant.Status.Namespaces))
+	for _, namespace := range tenant.Status.Namespaces {
+		bound[namespace] = struct{}{}
+	}
+	// the declared Namespaces are accounted to the namespace quota as the ones created by the owners
+	free := -1
+	if options := tenant.Spec.NamespaceOptions; options != nil && options.Quota != nil {
+		free = int(*options.Quota) - len(tenant.Status.Namespaces)
+	}
+
+	for _, item := range tenant.Spec.Namespaces {
+		declared := item
+
+		if _, ok := bound[declared.Name]; !ok {
+			if free == 0 {
+				r.Recorder.Eventf(tenant, corev1.EventTypeWarning, "DeclaredNamespaceQuotaExceeded", "Namespace %s cannot be bound, quota exceeded for the current Tenant", declared.Name)
+
+				continue
+			}
+
+			if free > 0 {
+				free--
+			}
+		}
+
+		group.Go(func() error {
+			return r.syncDeclaredNamespace(declared, tenant)
+		})
+	}
+
+	if err = group.Wait(); err != nil {
+		r.Log.Error(err, "Cannot sync declared Namespaces")
+
+		err = fmt.Errorf("cannot sync declared Namespaces: %s", err.Error())
+	}
+	return
+}
+
+func (r *Manager) syncDeclaredNamespace(declared capsulev1beta1.DeclaredNamespaceSpec, tnt *capsulev1beta1.Tenant) (err error) {
+	ns := &corev1.Namespace{}
+	if err = r.Client.Get(context.TODO(), types.NamespacedName{Name: declared.Name}, ns); err == nil {
+		owner := metav1.GetControllerOf(ns)
+		// a Namespace bound to a different Tenant, or controlled by someone else, is never claimed
+		if owner != nil && owner.UID != tnt.GetUID() {
+			r.Recorder.Eventf(tnt, corev1.EventTypeWarning, "DeclaredNamespaceConflict", "Namespace %s is already controlled by %s %s", declared.Name, owner.Kind, owner.Name)
+
+			return nil
+		}
+		// neither is a Namespace not created by Capsule, since it would be deleted along with the Tenant, unless
+		// the cluster administrators opted it in
+		if owner == nil && ns.GetAnnotations()[capsulev1beta1.DeclaredNamespaceAdoptAnnotation] != tnt.GetName() {
+			r.Recorder.Eventf(tnt, corev1.EventTypeWarning, "DeclaredNamespaceConflict", "Namespace %s already exists, annotate it with %s=%s to bind it to the Tenant", declared.Name, capsulev1beta1.DeclaredNamespaceAdoptAnnotation, tnt.GetName())
+
+			return nil
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return
+	}
+
+	if metav1.GetControllerOf(ns) == nil {
+		var reason string
+		if reason, err = declaredNamespaceViolation(r.Configuration, tnt, declared.Name); err != nil {
+			return
+		}
+
+		if len(reason) > 0 {
+			r.Recorder.Eventf(tnt, corev1.EventTypeWarning, "DeclaredNamespaceForbidden", "Namespace %s cannot be bound: %s", declared.Name, reason)
+
+			return nil
+		}
+	}
+
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	var res controllerutil.OperationResult
+
+	ns = &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: declared.Name,
+		},
+	}
+	res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, ns, func() error {
+		labels, annotations := ns.GetLabels(), ns.GetAnnotations()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+
+		labels[capsuleLabel] = tnt.GetName()
+
+		if md := declared.AdditionalMetadata; md != nil {
+			for k, v := range md.Labels {
+				labels[k] = v
+			}
+			for k, v := range md.Annotations {
+				annotations[k] = v
+			}
+		}
+
+		ns.SetLabels(labels)
+		ns.SetAnnotations(annotations)
+
+		return controllerutil.SetControllerReference(tnt, ns, r.Scheme)
+	})
+
+	r.emitEvent(tnt, declared.Name, res, "Ensuring declared Namespace", err)
+	r.Inventory.track(tnt.Name, ns, err)
 
 	return
 }
 
+// declaredNamespaceViolation returns the reason the declared Namespace cannot be bound to the Tenant, if any, according
+// to the rules applied to the Namespaces created by the owners: the protected Namespaces and the Tenant prefix.
+func declaredNamespaceViolation(cfg configuration.Configuration, tnt *capsulev1beta1.Tenant, name string) (string, error) {
+	rules, err := cfg.ProtectedNamespaces()
+	if err != nil {
+		return "", fmt.Errorf("cannot compile the protected Namespaces: %w", err)
+	}
+
+	for _, rule := range rules {
+		if rule.Protects(name) {
+			return fmt.Sprintf("the name matches the protected Namespaces regexp %s", rule.Regexp.String()), nil
+		}
+	}
+
+	forcePrefix := cfg.ForceTenantPrefix()
+	if tnt.Spec.ForceTenantPrefix != nil {
+		forcePrefix = *tnt.Spec.ForceTenantPrefix
+	}
+
+	if prefix := tnt.GetName() + "-"; forcePrefix && !strings.HasPrefix(name, prefix) {
+		return fmt.Sprintf("the name doesn't match the Tenant prefix %s", prefix), nil
+	}
+
+	return "", nil
+}
+
 func (r *Manager) ensureNamespaceCount(tenant *capsulev1beta1.Tenant) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		tenant.Status.Size = uint(len(tenant.Status.Namespaces))
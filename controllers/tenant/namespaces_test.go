@@ -0,0 +1,50 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+func TestDeclaredNamespaceViolation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, capsulev1alpha1.AddToScheme(scheme))
+
+	config := &capsulev1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: capsulev1alpha1.CapsuleConfigurationSpec{
+			ProtectedNamespaces: []capsulev1alpha1.ProtectedNamespaceSpec{{Regex: "^kube-.*$"}},
+			ForceTenantPrefix:   true,
+		},
+	}
+	cfg := configuration.NewCapsuleConfiguration(fake.NewClientBuilder().WithScheme(scheme).WithObjects(config).Build(), "default")
+
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil"}}
+
+	for name, allowed := range map[string]bool{"oil-production": true, "kube-system": false, "production": false} {
+		reason, err := declaredNamespaceViolation(cfg, tnt, name)
+		assert.NoError(t, err)
+		assert.Equal(t, allowed, len(reason) == 0, name)
+	}
+	// the Tenant setting overrides the configuration one
+	tnt.Spec.ForceTenantPrefix = pointer.BoolPtr(false)
+	reason, err := declaredNamespaceViolation(cfg, tnt, "production")
+	assert.NoError(t, err)
+	assert.Empty(t, reason)
+	// the invalid rules fail closed
+	config.Spec.ProtectedNamespaces = []capsulev1alpha1.ProtectedNamespaceSpec{{Regex: "("}}
+	cfg = configuration.NewCapsuleConfiguration(fake.NewClientBuilder().WithScheme(scheme).WithObjects(config).Build(), "default")
+	_, err = declaredNamespaceViolation(cfg, tnt, "oil-production")
+	assert.Error(t, err)
+}
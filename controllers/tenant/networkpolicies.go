@@ -8,9 +8,11 @@ import (
 	"golang.org/x/sync/errgroup"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
 // Ensuring all the NetworkPolicies are applied to each Namespace handled by the Tenant.
@@ -21,6 +23,19 @@ func (r *Manager) syncNetworkPolicies(tenant *capsulev1beta1.Tenant) error {
 	for i := range tenant.Spec.NetworkPolicies.Items {
 		keys = append(keys, strconv.Itoa(i))
 	}
+	// the peerings open the traffic isolated by the Tenant NetworkPolicies, if any
+	var peerings []peering
+
+	if ingress, egress := isolatedDirections(tenant.Spec.NetworkPolicies.Items); ingress || egress {
+		var err error
+		if peerings, err = r.peerings(context.TODO(), tenant); err != nil {
+			return err
+		}
+
+		for _, p := range peerings {
+			keys = append(keys, p.key)
+		}
+	}
 
 	group := new(errgroup.Group)
 
@@ -28,14 +43,14 @@ func (r *Manager) syncNetworkPolicies(tenant *capsulev1beta1.Tenant) error {
 		namespace := ns
 
 		group.Go(func() error {
-			return r.syncNetworkPolicy(tenant, namespace, keys)
+			return r.syncNetworkPolicy(tenant, namespace, keys, peerings)
 		})
 	}
 
 	return group.Wait()
 }
 
-func (r *Manager) syncNetworkPolicy(tenant *capsulev1beta1.Tenant, namespace string, keys []string) (err error) {
+func (r *Manager) syncNetworkPolicy(tenant *capsulev1beta1.Tenant, namespace string, keys []string, peerings []peering) (err error) {
 	if err = r.pruningResources(namespace, keys, &networkingv1.NetworkPolicy{}); err != nil {
 		return
 	}
@@ -49,29 +64,92 @@ func (r *Manager) syncNetworkPolicy(tenant *capsulev1beta1.Tenant, namespace str
 	if networkPolicyLabel, err = capsulev1beta1.GetTypeLabel(&networkingv1.NetworkPolicy{}); err != nil {
 		return
 	}
+	// adding a NetworkPolicy to a Namespace already holding the Tenant ones isolates the Pods it selects
+	var namespaceHasPolicies bool
+
+	if r.maintenance.deferred() {
+		list := &networkingv1.NetworkPolicyList{}
+		if err = r.List(context.TODO(), list, client.InNamespace(namespace), client.MatchingLabels{tenantLabel: tenant.Name}); err != nil {
+			return
+		}
+
+		namespaceHasPolicies = len(list.Items) > 0
+	}
 
 	for i, spec := range tenant.Spec.NetworkPolicies.Items {
 		target := &networkingv1.NetworkPolicy{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      fmt.Sprintf("capsule-%s-%d", tenant.Name, i),
 				Namespace: namespace,
+				Labels: map[string]string{
+					tenantLabel:        tenant.Name,
+					networkPolicyLabel: strconv.Itoa(i),
+				},
 			},
+			Spec: spec,
+		}
+
+		stampMetadata(tenant, target)
+
+		if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err != nil {
+			return
+		}
+
+		if r.maintenance.deferred() {
+			var reason string
+			if reason, err = r.networkPolicyDisruption(context.TODO(), target, namespaceHasPolicies); err != nil {
+				return
+			}
+
+			if len(reason) > 0 {
+				r.Log.Info("Network Policy change deferred to the next maintenance window", "name", target.Name, "namespace", target.Namespace, "reason", reason)
+				r.maintenance.add("NetworkPolicy", target, reason)
+
+				continue
+			}
 		}
 
 		var res controllerutil.OperationResult
-		res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
-			target.SetLabels(map[string]string{
-				tenantLabel:        tenant.Name,
-				networkPolicyLabel: strconv.Itoa(i),
-			})
-			target.Spec = spec
-
-			return controllerutil.SetControllerReference(tenant, target, r.Scheme)
-		})
+		res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
 
 		r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring NetworkPolicy %s", target.GetName()), err)
+		r.Inventory.track(tenant.Name, target, err)
+
+		r.Log.Info("Network Policy synced", "result", res, "name", target.Name, "namespace", target.Namespace)
+
+		if err != nil {
+			return
+		}
+	}
+
+	ingress, egress := isolatedDirections(tenant.Spec.NetworkPolicies.Items)
+	// the peering NetworkPolicies only open the traffic, they're applied at any time
+	for _, p := range peerings {
+		target := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("capsule-%s-peer-%s", tenant.Name, p.peer),
+				Namespace: namespace,
+				Labels: map[string]string{
+					tenantLabel:        tenant.Name,
+					networkPolicyLabel: p.key,
+				},
+			},
+			Spec: peeringPolicySpec(tenantLabel, p, ingress, egress),
+		}
+
+		stampMetadata(tenant, target)
+
+		if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err != nil {
+			return
+		}
+
+		var res controllerutil.OperationResult
+		res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
+
+		r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring peering NetworkPolicy %s", target.GetName()), err)
+		r.Inventory.track(tenant.Name, target, err)
 
-		r.Log.Info("Network Policy sync result: "+string(res), "name", target.Name, "namespace", target.Namespace)
+		r.Log.Info("Peering Network Policy synced", "result", res, "name", target.Name, "namespace", target.Namespace)
 
 		if err != nil {
 			return
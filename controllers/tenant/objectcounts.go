@@ -0,0 +1,126 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/utils"
+)
+
+// The object counts of the Tenant are enforced by a ResourceQuota in each Tenant Namespace, whose hard counts are the
+// Namespace usage plus the room left across the whole Tenant: the ResourceQuotas are recalculated as their usage
+// changes, while the admission of the counted objects checks the Tenant counts at the margin, as upon concurrent
+// creations in different Namespaces.
+// The ResourceQuotas are pruned along with the ResourceQuota items when the object counts are removed.
+func (r *Manager) syncObjectCounts(tenant *capsulev1beta1.Tenant) (err error) {
+	if len(tenant.Spec.ResourceQuota.ObjectCounts) == 0 {
+		return nil
+	}
+
+	var tenantLabel, typeLabel string
+
+	if tenantLabel, err = capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{}); err != nil {
+		return err
+	}
+
+	if typeLabel, err = capsulev1beta1.GetTypeLabel(&corev1.ResourceQuota{}); err != nil {
+		return err
+	}
+
+	list := &corev1.ResourceQuotaList{}
+	if err = r.List(context.TODO(), list, client.MatchingLabels{tenantLabel: tenant.Name, typeLabel: capsulev1beta1.ObjectCountsQuotaType}); err != nil {
+		return err
+	}
+
+	used := make(map[string]corev1.ResourceList, len(list.Items))
+	for _, item := range list.Items {
+		used[item.GetNamespace()] = item.Status.Used
+	}
+
+	hard := objectCountsHard(tenant.Spec.ResourceQuota.ObjectCounts, used, tenant.Status.Namespaces)
+
+	group := new(errgroup.Group)
+
+	for _, ns := range tenant.Status.Namespaces {
+		namespace := ns
+
+		group.Go(func() (err error) {
+			target := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("capsule-%s-%s", tenant.Name, capsulev1beta1.ObjectCountsQuotaType),
+					Namespace: namespace,
+					Labels: map[string]string{
+						tenantLabel: tenant.Name,
+						typeLabel:   capsulev1beta1.ObjectCountsQuotaType,
+					},
+				},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: hard[namespace],
+				},
+			}
+
+			stampMetadata(tenant, target)
+
+			if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err != nil {
+				return
+			}
+
+			var res controllerutil.OperationResult
+			res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
+
+			r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring ResourceQuota %s", target.GetName()), err)
+			r.Inventory.track(tenant.Name, target, err)
+
+			r.Log.Info("Object counts Resource Quota synced", "result", res, "name", target.Name, "namespace", target.Namespace)
+
+			return
+		})
+	}
+
+	return group.Wait()
+}
+
+// objectCountsHard returns the hard counts of the ResourceQuota of each Tenant Namespace, given the limits of the
+// Tenant and the usage of each Namespace: each Namespace can take up the room left across the whole Tenant, if any.
+func objectCountsHard(limits corev1.ResourceList, used map[string]corev1.ResourceList, namespaces []string) map[string]corev1.ResourceList {
+	hard := make(map[string]corev1.ResourceList, len(namespaces))
+
+	for _, ns := range namespaces {
+		hard[ns] = corev1.ResourceList{}
+	}
+
+	for name, limit := range limits {
+		var total resource.Quantity
+
+		for _, ns := range namespaces {
+			total.Add(used[ns][name])
+		}
+
+		left := limit.DeepCopy()
+		left.Sub(total)
+
+		if left.Sign() < 0 {
+			left = resource.Quantity{}
+		}
+
+		for _, ns := range namespaces {
+			quantity := used[ns][name].DeepCopy()
+			quantity.Add(left)
+
+			hard[ns][name] = quantity
+		}
+	}
+
+	return hard
+}
@@ -0,0 +1,40 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestObjectCountsHard(t *testing.T) {
+	deployments, secrets := corev1.ResourceName("count/deployments.apps"), corev1.ResourceName("count/secrets")
+
+	limits := corev1.ResourceList{
+		deployments: resource.MustParse("10"),
+		secrets:     resource.MustParse("5"),
+	}
+
+	used := map[string]corev1.ResourceList{
+		"oil-production":  {deployments: resource.MustParse("4"), secrets: resource.MustParse("4")},
+		"oil-development": {deployments: resource.MustParse("2"), secrets: resource.MustParse("3")},
+	}
+
+	hard := objectCountsHard(limits, used, []string{"oil-production", "oil-development", "oil-test"})
+
+	for ns, expected := range map[string]map[corev1.ResourceName]int64{
+		// 4 Deployments left, none of the Secrets since the Tenant is over the limit
+		"oil-production":  {deployments: 8, secrets: 4},
+		"oil-development": {deployments: 6, secrets: 3},
+		"oil-test":        {deployments: 4, secrets: 0},
+	} {
+		for name, value := range expected {
+			quantity := hard[ns][name]
+			assert.Equal(t, value, quantity.Value(), "%s %s", ns, name)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// peering is the traffic opened between the Tenant and a consenting peer Tenant.
+type peering struct {
+	// the key of the peering NetworkPolicy, as set in its type label
+	key  string
+	peer string
+	// the ports of the Tenant Pods the peer Tenant Pods can connect to
+	ingressPorts []networkingv1.NetworkPolicyPort
+	// the ports of the peer Tenant Pods the Tenant Pods can connect to
+	egressPorts []networkingv1.NetworkPolicyPort
+}
+
+// peerings returns the peerings of the Tenant with the peer Tenants declaring it as a peer as well.
+func (r *Manager) peerings(ctx context.Context, tnt *capsulev1beta1.Tenant) (peerings []peering, err error) {
+	for i, spec := range tnt.Spec.Peers {
+		peer := &capsulev1beta1.Tenant{}
+		if err = r.Get(ctx, types.NamespacedName{Name: spec.Tenant}, peer); err != nil {
+			if apierrors.IsNotFound(err) {
+				err = nil
+
+				continue
+			}
+
+			return nil, err
+		}
+
+		consent, ok := peer.Peer(tnt.GetName())
+		if !ok {
+			r.Log.Info("Peering not declared by the peer Tenant", "peer", spec.Tenant)
+
+			continue
+		}
+
+		peerings = append(peerings, peering{
+			key:          fmt.Sprintf("peer-%d", i),
+			peer:         spec.Tenant,
+			ingressPorts: spec.Ports,
+			egressPorts:  consent.Ports,
+		})
+	}
+
+	return peerings, nil
+}
+
+// isolatedDirections returns the directions the traffic of all the Pods is isolated in by the Tenant NetworkPolicies:
+// the peering NetworkPolicies open the traffic in these directions only, not to isolate the Pods in the other ones.
+func isolatedDirections(items []networkingv1.NetworkPolicySpec) (ingress, egress bool) {
+	for _, spec := range items {
+		if len(spec.PodSelector.MatchLabels) > 0 || len(spec.PodSelector.MatchExpressions) > 0 {
+			continue
+		}
+
+		policyTypes := spec.PolicyTypes
+		// the policy types default to Ingress, along with Egress when any egress rule is declared
+		if len(policyTypes) == 0 {
+			policyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+
+			if len(spec.Egress) > 0 {
+				policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+			}
+		}
+
+		for _, policyType := range policyTypes {
+			switch policyType {
+			case networkingv1.PolicyTypeIngress:
+				ingress = true
+			case networkingv1.PolicyTypeEgress:
+				egress = true
+			}
+		}
+	}
+
+	return ingress, egress
+}
+
+// peeringPolicySpec returns the spec of the NetworkPolicy opening the traffic with the peer Tenant Namespaces
+// in the given directions.
+func peeringPolicySpec(tenantLabel string, p peering, ingress, egress bool) networkingv1.NetworkPolicySpec {
+	peers := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{tenantLabel: p.peer}},
+	}}
+
+	spec := networkingv1.NetworkPolicySpec{}
+
+	if ingress {
+		spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+		spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: peers, Ports: p.ingressPorts}}
+	}
+
+	if egress {
+		spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		spec.Egress = []networkingv1.NetworkPolicyEgressRule{{To: peers, Ports: p.egressPorts}}
+	}
+
+	return spec
+}
+
+// peersOf returns the requests for the Tenants declared as peers by the given one, or declaring it as a peer.
+func (r *Manager) peersOf(object client.Object) (requests []reconcile.Request) {
+	tnt, ok := object.(*capsulev1beta1.Tenant)
+	if !ok {
+		return nil
+	}
+
+	names := make(map[string]struct{})
+
+	for _, peer := range tnt.Spec.Peers {
+		names[peer.Tenant] = struct{}{}
+	}
+
+	tntList := &capsulev1beta1.TenantList{}
+	if err := r.List(context.Background(), tntList); err != nil {
+		r.Log.Error(err, "Cannot list the Tenants")
+	}
+
+	for i := range tntList.Items {
+		if _, ok := tntList.Items[i].Peer(tnt.GetName()); ok {
+			names[tntList.Items[i].GetName()] = struct{}{}
+		}
+	}
+
+	delete(names, tnt.GetName())
+
+	for name := range names {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+
+	return requests
+}
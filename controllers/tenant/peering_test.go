@@ -0,0 +1,57 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestIsolatedDirections(t *testing.T) {
+	ingress, egress := isolatedDirections(nil)
+	assert.False(t, ingress)
+	assert.False(t, egress)
+
+	ingress, egress = isolatedDirections([]networkingv1.NetworkPolicySpec{
+		{PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}, PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+		{Ingress: []networkingv1.NetworkPolicyIngressRule{{}}},
+	})
+	assert.True(t, ingress)
+	assert.False(t, egress)
+
+	ingress, egress = isolatedDirections([]networkingv1.NetworkPolicySpec{
+		{Egress: []networkingv1.NetworkPolicyEgressRule{{}}},
+	})
+	assert.True(t, ingress)
+	assert.True(t, egress)
+}
+
+func TestPeeringPolicySpec(t *testing.T) {
+	port := intstr.FromInt(5432)
+
+	p := peering{
+		key:          "peer-0",
+		peer:         "gas",
+		ingressPorts: []networkingv1.NetworkPolicyPort{{Port: &port}},
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"capsule.clastix.io/tenant": "gas"}},
+	}}
+
+	assert.Equal(t, networkingv1.NetworkPolicySpec{
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers, Ports: p.ingressPorts}},
+	}, peeringPolicySpec("capsule.clastix.io/tenant", p, true, false))
+
+	assert.Equal(t, networkingv1.NetworkPolicySpec{
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers, Ports: p.ingressPorts}},
+		Egress:      []networkingv1.NetworkPolicyEgressRule{{To: peers}},
+	}, peeringPolicySpec("capsule.clastix.io/tenant", p, true, true))
+}
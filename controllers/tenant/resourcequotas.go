@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
 // When the Resource Budget assigned to a Tenant is Tenant-scoped we have to rely on the ResourceQuota resources to
@@ -77,14 +78,14 @@ func (r *Manager) syncResourceQuotas(tenant *capsulev1beta1.Tenant) (err error)
 				// For this case, we're going to block the Quota setting the Hard as the
 				// used one.
 				for name, hardQuota := range resourceQuota.Hard {
-					r.Log.Info("Desired hard " + name.String() + " quota is " + hardQuota.String())
+					r.Log.Info("Desired hard quota", "resource", name, "quantity", hardQuota.String())
 
 					// Getting the whole usage across all the Tenant Namespaces
 					var quantity resource.Quantity
 					for _, item := range list.Items {
 						quantity.Add(item.Status.Used[name])
 					}
-					r.Log.Info("Computed " + name.String() + " quota for the whole Tenant is " + quantity.String())
+					r.Log.Info("Computed quota for the whole Tenant", "resource", name, "quantity", quantity.String())
 
 					switch quantity.Cmp(resourceQuota.Hard[name]) {
 					case 0:
@@ -129,27 +130,21 @@ func (r *Manager) syncResourceQuotas(tenant *capsulev1beta1.Tenant) (err error)
 			return
 		}
 	}
-	// getting requested ResourceQuota keys
-	keys := make([]string, 0, len(tenant.Spec.ResourceQuota.Items))
-
-	for i := range tenant.Spec.ResourceQuota.Items {
-		keys = append(keys, strconv.Itoa(i))
-	}
-
+	// Replicating the ResourceQuota items across all the Tenant Namespaces
 	group := new(errgroup.Group)
 
 	for _, ns := range tenant.Status.Namespaces {
 		namespace := ns
 
 		group.Go(func() error {
-			return r.syncResourceQuota(tenant, namespace, keys)
+			return r.syncResourceQuota(tenant, namespace)
 		})
 	}
 
 	return group.Wait()
 }
 
-func (r *Manager) syncResourceQuota(tenant *capsulev1beta1.Tenant, namespace string, keys []string) (err error) {
+func (r *Manager) syncResourceQuota(tenant *capsulev1beta1.Tenant, namespace string) (err error) {
 	// getting ResourceQuota labels for the mutateFn
 	var tenantLabel, typeLabel string
 
@@ -160,42 +155,88 @@ func (r *Manager) syncResourceQuota(tenant *capsulev1beta1.Tenant, namespace str
 	if typeLabel, err = capsulev1beta1.GetTypeLabel(&corev1.ResourceQuota{}); err != nil {
 		return err
 	}
+
+	ns := &corev1.Namespace{}
+	if err = r.Client.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+		return err
+	}
+	// With the Namespace scope the items can be replaced by the overrides selecting the Namespace
+	var items []corev1.ResourceQuotaSpec
+	if items, err = tenant.ResourceQuotasFor(ns); err != nil {
+		return err
+	}
+	// getting requested ResourceQuota keys
+	keys := make([]string, 0, len(items))
+
+	for i := range items {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	// the object counts ResourceQuota is managed by syncObjectCounts
+	if len(tenant.Spec.ResourceQuota.ObjectCounts) > 0 {
+		keys = append(keys, capsulev1beta1.ObjectCountsQuotaType)
+	}
 	// Pruning resource of non-requested resources
 	if err = r.pruningResources(namespace, keys, &corev1.ResourceQuota{}); err != nil {
 		return err
 	}
 
-	for index, resQuota := range tenant.Spec.ResourceQuota.Items {
+	for index, resQuota := range items {
 		target := &corev1.ResourceQuota{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      fmt.Sprintf("capsule-%s-%d", tenant.Name, index),
 				Namespace: namespace,
+				Labels: map[string]string{
+					tenantLabel: tenant.Name,
+					typeLabel:   strconv.Itoa(index),
+				},
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Scopes:        resQuota.Scopes,
+				ScopeSelector: resQuota.ScopeSelector,
 			},
 		}
+		// In case of Namespace scope for the ResourceQuota we can easily apply the bare specification,
+		// otherwise the hard quota is left to the Tenant-scoped computation.
+		if tenant.Spec.ResourceQuota.Scope == capsulev1beta1.ResourceQuotaScopeNamespace {
+			target.Spec.Hard = resQuota.Hard
+		}
+		// The capacity hints are read by the capacity management tools from the ResourceQuotas annotations
+		if hints := tenant.Spec.CapacityHints; hints != nil && len(hints.ResourceQuotaAnnotations) > 0 {
+			annotations := make(map[string]string, len(hints.ResourceQuotaAnnotations))
+			for k, v := range hints.ResourceQuotaAnnotations {
+				annotations[k] = v
+			}
 
-		var res controllerutil.OperationResult
-		err = retry.RetryOnConflict(retry.DefaultBackoff, func() (retryErr error) {
-			res, retryErr = controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
-				target.SetLabels(map[string]string{
-					tenantLabel: tenant.Name,
-					typeLabel:   strconv.Itoa(index),
-				})
-				target.Spec.Scopes = resQuota.Scopes
-				target.Spec.ScopeSelector = resQuota.ScopeSelector
-				// In case of Namespace scope for the ResourceQuota we can easily apply the bare specification
-				if tenant.Spec.ResourceQuota.Scope == capsulev1beta1.ResourceQuotaScopeNamespace {
-					target.Spec.Hard = resQuota.Hard
-				}
+			target.SetAnnotations(annotations)
+		}
 
-				return controllerutil.SetControllerReference(tenant, target, r.Scheme)
-			})
+		stampMetadata(tenant, target)
 
-			return retryErr
-		})
+		if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err != nil {
+			return
+		}
+
+		if r.maintenance.deferred() && tenant.Spec.ResourceQuota.Scope == capsulev1beta1.ResourceQuotaScopeNamespace {
+			var reason string
+			if reason, err = r.resourceQuotaDisruption(context.TODO(), target); err != nil {
+				return
+			}
+
+			if len(reason) > 0 {
+				r.Log.Info("Resource Quota change deferred to the next maintenance window", "name", target.Name, "namespace", target.Namespace, "reason", reason)
+				r.maintenance.add("ResourceQuota", target, reason)
+
+				continue
+			}
+		}
+
+		var res controllerutil.OperationResult
+		res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
 
 		r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring ResourceQuota %s", target.GetName()), err)
+		r.Inventory.track(tenant.Name, target, err)
 
-		r.Log.Info("Resource Quota sync result: "+string(res), "name", target.Name, "namespace", target.Namespace)
+		r.Log.Info("Resource Quota synced", "result", res, "name", target.Name, "namespace", target.Namespace)
 
 		if err != nil {
 			return
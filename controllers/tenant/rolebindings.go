@@ -14,6 +14,7 @@ import (
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
 	"github.com/clastix/capsule/controllers/rbac"
+	"github.com/clastix/capsule/pkg/utils"
 )
 
 // Additional Role Bindings can be used in many ways: applying Pod Security Policies or giving
@@ -72,31 +73,33 @@ func (r *Manager) syncAdditionalRoleBinding(tenant *capsulev1beta1.Tenant, ns st
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      fmt.Sprintf("capsule-%s-%d-%s", tenant.Name, i, roleBinding.ClusterRoleName),
 				Namespace: ns,
+				Labels: map[string]string{
+					tenantLabel:      tenant.Name,
+					roleBindingLabel: roleBindingHashLabel,
+				},
 			},
-		}
-
-		var res controllerutil.OperationResult
-		res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() error {
-			target.ObjectMeta.Labels = map[string]string{
-				tenantLabel:      tenant.Name,
-				roleBindingLabel: roleBindingHashLabel,
-			}
-			target.RoleRef = rbacv1.RoleRef{
+			RoleRef: rbacv1.RoleRef{
 				APIGroup: "rbac.authorization.k8s.io",
 				Kind:     "ClusterRole",
 				Name:     roleBinding.ClusterRoleName,
-			}
-			target.Subjects = roleBinding.Subjects
+			},
+			Subjects: roleBinding.Subjects,
+		}
 
-			return controllerutil.SetControllerReference(tenant, target, r.Scheme)
-		})
+		stampMetadata(tenant, target)
+
+		var res controllerutil.OperationResult
+		if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err == nil {
+			res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
+		}
 
 		r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring additional RoleBinding %s", target.GetName()), err)
+		r.Inventory.track(tenant.Name, target, err)
 
 		if err != nil {
 			r.Log.Error(err, "Cannot sync Additional RoleBinding")
 		}
-		r.Log.Info(fmt.Sprintf("Additional RoleBindings sync result: %s", string(res)), "name", target.Name, "namespace", target.Namespace)
+		r.Log.Info("Additional RoleBindings synced", "result", res, "name", target.Name, "namespace", target.Namespace)
 		if err != nil {
 			return
 		}
@@ -157,20 +160,23 @@ func (r *Manager) ownerRoleBinding(tenant *capsulev1beta1.Tenant) error {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      namespacedName.Name,
 				Namespace: namespacedName.Namespace,
+				Labels:    newLabels,
 			},
+			Subjects: subjects,
+			RoleRef:  roleRef,
 		}
 
+		stampMetadata(tenant, target)
+
 		var res controllerutil.OperationResult
-		res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
-			target.ObjectMeta.Labels = newLabels
-			target.Subjects = subjects
-			target.RoleRef = roleRef
-			return controllerutil.SetControllerReference(tenant, target, r.Scheme)
-		})
+		if err = controllerutil.SetControllerReference(tenant, target, r.Scheme); err == nil {
+			res, err = utils.Apply(context.TODO(), r.Client, r.Scheme, target)
+		}
 
 		r.emitEvent(tenant, target.GetNamespace(), res, fmt.Sprintf("Ensuring Capsule RoleBinding %s", target.GetName()), err)
+		r.Inventory.track(tenant.Name, target, err)
 
-		r.Log.Info("Role Binding sync result: "+string(res), "name", target.Name, "namespace", target.Namespace)
+		r.Log.Info("Role Binding synced", "result", res, "name", target.Name, "namespace", target.Namespace)
 		if err != nil {
 			return err
 		}
@@ -39,7 +39,7 @@ func (r *Manager) pruningResources(ns string, keys []string, obj client.Object)
 		selector = selector.Add(*notIn)
 	}
 
-	r.Log.Info("Pruning objects with label selector " + selector.String())
+	r.Log.Info("Pruning objects", "selector", selector.String())
 
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		return r.DeleteAllOf(context.TODO(), obj, &client.DeleteAllOfOptions{
@@ -60,4 +60,38 @@ func (r *Manager) emitEvent(object runtime.Object, namespace string, res control
 	}
 
 	r.Recorder.AnnotatedEventf(object, map[string]string{"OperationResult": string(res)}, eventType, namespace, msg)
+	// an existing object diverging from the desired state has been restored
+	if tnt, ok := object.(client.Object); ok && res == controllerutil.OperationResultUpdated {
+		driftsTotal.WithLabelValues(tnt.GetName()).Inc()
+	}
+}
+
+// stampMetadata adds to the generated object the additional metadata required by the Tenant:
+// the Capsule labels have precedence, since these are used to select and prune the objects.
+func stampMetadata(tenant *capsulev1beta1.Tenant, obj client.Object) {
+	if tenant.Spec.GeneratedObjectsMetadata == nil {
+		return
+	}
+
+	l := make(map[string]string)
+	for k, v := range tenant.Spec.GeneratedObjectsMetadata.Labels {
+		l[k] = v
+	}
+
+	for k, v := range obj.GetLabels() {
+		l[k] = v
+	}
+
+	obj.SetLabels(l)
+
+	a := make(map[string]string)
+	for k, v := range tenant.Spec.GeneratedObjectsMetadata.Annotations {
+		a[k] = v
+	}
+
+	for k, v := range obj.GetAnnotations() {
+		a[k] = v
+	}
+
+	obj.SetAnnotations(a)
 }
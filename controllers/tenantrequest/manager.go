@@ -0,0 +1,193 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenantrequest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// Manager materializes the Tenants of the approved TenantRequests from their TenantClass: the Tenants outlive the
+// TenantRequests, which are cluster-scoped and namespaced respectively.
+type Manager struct {
+	client.Client
+	Log      logr.Logger
+	Options  controller.Options
+	Recorder record.EventRecorder
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tenantrequest").
+		For(&capsulev1beta1.TenantRequest{}).
+		Watches(&source.Kind{Type: &capsulev1beta1.TenantClass{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			list := &capsulev1beta1.TenantRequestList{}
+			if err := r.List(context.Background(), list); err != nil {
+				r.Log.Error(err, "Cannot list the TenantRequests of the TenantClass", "class", object.GetName())
+
+				return nil
+			}
+
+			var requests []reconcile.Request
+
+			for _, item := range list.Items {
+				if item.Spec.TenantClassName == object.GetName() && item.Status.Phase != capsulev1beta1.TenantRequestProvisioned {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: item.GetNamespace(), Name: item.GetName()}})
+				}
+			}
+
+			return requests
+		})).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("namespace", request.Namespace, "name", request.Name)
+
+	tntReq := &capsulev1beta1.TenantRequest{}
+	if err = r.Get(ctx, request.NamespacedName, tntReq); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+
+	if tntReq.Status.Phase == capsulev1beta1.TenantRequestProvisioned {
+		return ctrl.Result{}, nil
+	}
+
+	phase, message, err := r.materialize(ctx, tntReq)
+	if err != nil {
+		log.Error(err, "Cannot materialize the requested Tenant")
+		return
+	}
+
+	if phase != tntReq.Status.Phase {
+		switch phase {
+		case capsulev1beta1.TenantRequestFailed:
+			r.Recorder.Event(tntReq, corev1.EventTypeWarning, "TenantRequestFailed", message)
+		case capsulev1beta1.TenantRequestProvisioned:
+			r.Recorder.Event(tntReq, corev1.EventTypeNormal, "TenantProvisioned", message)
+		}
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		found := &capsulev1beta1.TenantRequest{}
+		if err = r.Get(ctx, request.NamespacedName, found); err != nil {
+			return
+		}
+
+		if found.Status.Phase == phase && found.Status.Message == message {
+			return nil
+		}
+
+		found.Status.Phase, found.Status.Message = phase, message
+
+		return r.Status().Update(ctx, found)
+	})
+	if err != nil {
+		log.Error(err, "Cannot update the TenantRequest status")
+	}
+
+	return
+}
+
+// materialize creates the Tenant of the approved TenantRequest, returning the resulting phase: the errors are
+// returned only when worth a retry, the invalid Tenants failing the TenantRequest instead.
+func (r *Manager) materialize(ctx context.Context, tntReq *capsulev1beta1.TenantRequest) (capsulev1beta1.TenantRequestPhase, string, error) {
+	if !tntReq.IsApproved() {
+		return capsulev1beta1.TenantRequestPending, "Waiting for the approval", nil
+	}
+
+	class := &capsulev1beta1.TenantClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: tntReq.Spec.TenantClassName}, class); err != nil {
+		if errors.IsNotFound(err) {
+			return capsulev1beta1.TenantRequestPending, fmt.Sprintf("Waiting for the TenantClass %s", tntReq.Spec.TenantClassName), nil
+		}
+
+		return "", "", err
+	}
+
+	tnt := render(tntReq, class)
+
+	if err := r.Create(ctx, tnt); err != nil {
+		switch {
+		case errors.IsAlreadyExists(err):
+			found := &capsulev1beta1.Tenant{}
+			if err = r.Get(ctx, types.NamespacedName{Name: tnt.GetName()}, found); err != nil {
+				return "", "", err
+			}
+
+			if found.GetAnnotations()[capsulev1beta1.TenantRequestAnnotation] != tnt.GetAnnotations()[capsulev1beta1.TenantRequestAnnotation] {
+				return capsulev1beta1.TenantRequestFailed, fmt.Sprintf("The Tenant %s already exists", tnt.GetName()), nil
+			}
+		case errors.IsInvalid(err), errors.IsForbidden(err), errors.IsBadRequest(err):
+			return capsulev1beta1.TenantRequestFailed, err.Error(), nil
+		default:
+			return "", "", err
+		}
+	}
+
+	return capsulev1beta1.TenantRequestProvisioned, fmt.Sprintf("The Tenant %s has been provisioned from the TenantClass %s", tnt.GetName(), class.GetName()), nil
+}
+
+// render returns the Tenant of the TenantRequest, with a copy of the restrictions of the TenantClass: the owners are
+// the requested ones, along with the additional owners of the class.
+func render(tntReq *capsulev1beta1.TenantRequest, class *capsulev1beta1.TenantClass) *capsulev1beta1.Tenant {
+	spec := class.Spec.DeepCopy()
+
+	owners := append(capsulev1beta1.OwnerListSpec{}, tntReq.Spec.Owners...)
+
+	for _, owner := range spec.AdditionalOwners {
+		if owners.FindOwner(owner.Name, owner.Kind).Name == "" {
+			owners = append(owners, owner)
+		}
+	}
+
+	return &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: tntReq.Spec.TenantName,
+			Labels: map[string]string{
+				capsulev1beta1.TenantClassLabel: class.GetName(),
+			},
+			Annotations: map[string]string{
+				capsulev1beta1.TenantRequestAnnotation: fmt.Sprintf("%s/%s", tntReq.GetNamespace(), tntReq.GetName()),
+			},
+		},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners:              owners,
+			NamespaceOptions:    spec.NamespaceOptions,
+			ServiceOptions:      spec.ServiceOptions,
+			StorageClasses:      spec.StorageClasses,
+			IngressOptions:      spec.IngressOptions,
+			ContainerRegistries: spec.ContainerRegistries,
+			NodeSelector:        spec.NodeSelector,
+			NetworkPolicies:     spec.NetworkPolicies,
+			LimitRanges:         spec.LimitRanges,
+			ResourceQuota:       spec.ResourceQuota,
+			PodOptions:          spec.PodOptions,
+			ImagePullPolicies:   spec.ImagePullPolicies,
+			PriorityClasses:     spec.PriorityClasses,
+		},
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenantrequest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestRender(t *testing.T) {
+	class := &capsulev1beta1.TenantClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gold"},
+		Spec: capsulev1beta1.TenantClassSpec{
+			AdditionalOwners: capsulev1beta1.OwnerListSpec{
+				{Kind: capsulev1beta1.GroupOwner, Name: "platform"},
+				{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+			},
+			NamespaceOptions: &capsulev1beta1.NamespaceOptions{Quota: pointer.Int32Ptr(3)},
+			NodeSelector:     map[string]string{"pool": "gold"},
+		},
+	}
+
+	tntReq := &capsulev1beta1.TenantRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "onboarding", Name: "oil"},
+		Spec: capsulev1beta1.TenantRequestSpec{
+			TenantName:      "oil",
+			TenantClassName: "gold",
+			Owners:          capsulev1beta1.OwnerListSpec{{Kind: capsulev1beta1.UserOwner, Name: "alice"}},
+		},
+	}
+
+	tnt := render(tntReq, class)
+	assert.Equal(t, "oil", tnt.GetName())
+	assert.Equal(t, "gold", tnt.GetLabels()[capsulev1beta1.TenantClassLabel])
+	assert.Equal(t, "onboarding/oil", tnt.GetAnnotations()[capsulev1beta1.TenantRequestAnnotation])
+	assert.ElementsMatch(t, capsulev1beta1.OwnerListSpec{
+		{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+		{Kind: capsulev1beta1.GroupOwner, Name: "platform"},
+	}, tnt.Spec.Owners)
+	assert.Equal(t, int32(3), *tnt.Spec.NamespaceOptions.Quota)
+	// the restrictions are copied, not shared with the class
+	tnt.Spec.NodeSelector["pool"] = "silver"
+	assert.Equal(t, "gold", class.Spec.NodeSelector["pool"])
+}
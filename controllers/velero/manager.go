@@ -0,0 +1,231 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// RestoreNameLabel is the label Velero puts on each restored object.
+const RestoreNameLabel = "velero.io/restore-name"
+
+var scheduleGVK = schema.GroupVersionKind{
+	Group:   "velero.io",
+	Version: "v1",
+	Kind:    "Schedule",
+}
+
+// Manager keeps a Velero Schedule for each Tenant declaring a backup, covering all the Tenant Namespaces,
+// and binds back to the Tenant the Namespaces restored by Velero, since the ownerReferences are not restored.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+	// The Namespace where Velero is installed and the Schedule objects are created.
+	Namespace string
+}
+
+func (r *Manager) newSchedule(tenantName string) *unstructured.Unstructured {
+	schedule := &unstructured.Unstructured{}
+	schedule.SetGroupVersionKind(scheduleGVK)
+	schedule.SetNamespace(r.Namespace)
+	schedule.SetName(fmt.Sprintf("capsule-%s", tenantName))
+
+	return schedule
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	schedule := &unstructured.Unstructured{}
+	schedule.SetGroupVersionKind(scheduleGVK)
+
+	capsuleLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("velero").
+		For(&capsulev1beta1.Tenant{}).
+		Owns(schedule).
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: object.GetLabels()[capsuleLabel]}}}
+		}), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			labels := object.GetLabels()
+
+			_, restored := labels[RestoreNameLabel]
+			_, ok := labels[capsuleLabel]
+
+			return restored && ok
+		}))).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+	// the objects of the Tenants being deleted are removed by the cleanup controller
+	if !tnt.GetDeletionTimestamp().IsZero() {
+		return
+	}
+
+	if tnt.Spec.Backup == nil {
+		return ctrl.Result{}, r.removeSchedule(ctx, log, tnt)
+	}
+
+	if err = r.bindRestoredNamespaces(ctx, log, tnt); err != nil {
+		log.Error(err, "Cannot bind restored Namespaces")
+		return
+	}
+	// An empty list of included Namespaces would make Velero backing up the whole cluster
+	if len(tnt.Status.Namespaces) == 0 {
+		return ctrl.Result{}, r.removeSchedule(ctx, log, tnt)
+	}
+
+	schedule := r.newSchedule(tnt.GetName())
+
+	var res controllerutil.OperationResult
+	res, err = controllerutil.CreateOrUpdate(ctx, r.Client, schedule, func() (err error) {
+		capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+		labels := schedule.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[capsuleLabel] = tnt.GetName()
+		schedule.SetLabels(labels)
+
+		if err = unstructured.SetNestedField(schedule.Object, scheduleSpec(tnt), "spec"); err != nil {
+			return
+		}
+
+		return controllerutil.SetControllerReference(tnt, schedule, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "Cannot sync Velero Schedule")
+		return
+	}
+
+	log.Info("Velero Schedule synced", "result", res, "name", schedule.GetName(), "namespace", schedule.GetNamespace())
+
+	return
+}
+
+func (r *Manager) removeSchedule(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant) (err error) {
+	schedule := r.newSchedule(tnt.GetName())
+
+	if err = r.Get(ctx, types.NamespacedName{Namespace: schedule.GetNamespace(), Name: schedule.GetName()}, schedule); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// removing only the Schedule objects managed by Capsule
+	if !metav1.IsControlledBy(schedule, tnt) {
+		return nil
+	}
+
+	log.Info("Removing Velero Schedule, no more requested")
+
+	return client.IgnoreNotFound(r.Delete(ctx, schedule))
+}
+
+// bindRestoredNamespaces sets back the Tenant as controller of the Namespaces restored by Velero,
+// allowing the Tenant controller to collect them again.
+func (r *Manager) bindRestoredNamespaces(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant) error {
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	list := &corev1.NamespaceList{}
+	if err := r.List(ctx, list, client.MatchingLabels{capsuleLabel: tnt.GetName()}, client.HasLabels{RestoreNameLabel}); err != nil {
+		return err
+	}
+
+	for _, item := range list.Items {
+		if metav1.GetControllerOf(&item) != nil {
+			continue
+		}
+
+		name := item.GetName()
+
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+			ns := &corev1.Namespace{}
+			if err = r.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+				return
+			}
+
+			if err = controllerutil.SetControllerReference(tnt, ns, r.Scheme); err != nil {
+				return
+			}
+
+			return r.Update(ctx, ns)
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Info("Restored Namespace bound to the Tenant", "namespace", name)
+	}
+
+	return nil
+}
+
+func scheduleSpec(tnt *capsulev1beta1.Tenant) map[string]interface{} {
+	capsuleLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	namespaces := append([]string{}, tnt.Status.Namespaces...)
+	sort.Strings(namespaces)
+
+	included := make([]interface{}, 0, len(namespaces))
+	for _, ns := range namespaces {
+		included = append(included, ns)
+	}
+
+	template := map[string]interface{}{
+		"includedNamespaces": included,
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				capsuleLabel: tnt.GetName(),
+			},
+		},
+	}
+
+	if ttl := tnt.Spec.Backup.TTL; ttl != nil {
+		template["ttl"] = ttl.Duration.String()
+	}
+
+	return map[string]interface{}{
+		"schedule": tnt.Spec.Backup.Schedule,
+		"template": template,
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package velero
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestScheduleSpec(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec: capsulev1beta1.TenantSpec{
+			Backup: &capsulev1beta1.BackupSpec{Schedule: "0 1 * * *"},
+		},
+		Status: capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production", "oil-development"}},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"schedule": "0 1 * * *",
+		"template": map[string]interface{}{
+			// the Namespaces are sorted to avoid useless updates
+			"includedNamespaces": []interface{}{"oil-development", "oil-production"},
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{"capsule.clastix.io/tenant": "oil"},
+			},
+		},
+	}, scheduleSpec(tnt))
+	assert.Equal(t, []string{"oil-production", "oil-development"}, tnt.Status.Namespaces)
+
+	tnt.Spec.Backup.TTL = &metav1.Duration{Duration: 72 * time.Hour}
+	assert.Equal(t, "72h0m0s", scheduleSpec(tnt)["template"].(map[string]interface{})["ttl"])
+}
+
+func TestBindRestoredNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "oil-uid"}}
+	labels := map[string]string{"capsule.clastix.io/tenant": "oil", RestoreNameLabel: "oil-20211015"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		tnt,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-production", Labels: labels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-development", Labels: map[string]string{"capsule.clastix.io/tenant": "oil"}}},
+	).Build()
+
+	assert.NoError(t, (&Manager{Client: c, Scheme: scheme}).bindRestoredNamespaces(context.Background(), logr.Discard(), tnt))
+
+	restored := &corev1.Namespace{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "oil-production"}, restored))
+	assert.True(t, metav1.IsControlledBy(restored, tnt))
+	// the Namespaces not restored by Velero are left untouched
+	other := &corev1.Namespace{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "oil-development"}, other))
+	assert.Nil(t, metav1.GetControllerOf(other))
+}
@@ -0,0 +1,259 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// SourceAnnotation references the TenantWebhookConfiguration a generated webhook configuration is rendered from.
+const SourceAnnotation = "capsule.clastix.io/webhook-configuration"
+
+// Manager registers the webhooks declared by the TenantWebhookConfiguration resources of the Tenants allowing them
+// in cluster-wide webhook configurations, owned by the Tenant and restricted to its Namespaces.
+type Manager struct {
+	client.Client
+	Log     logr.Logger
+	Options controller.Options
+	Scheme  *runtime.Scheme
+}
+
+func (r *Manager) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("webhookconfiguration").
+		For(&capsulev1beta1.Tenant{}).
+		Owns(&admissionregistrationv1.ValidatingWebhookConfiguration{}).
+		Owns(&admissionregistrationv1.MutatingWebhookConfiguration{}).
+		Watches(&source.Kind{Type: &capsulev1beta1.TenantWebhookConfiguration{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			tntList := &capsulev1beta1.TenantList{}
+			if err := r.List(context.Background(), tntList, client.MatchingFieldsSelector{
+				Selector: fields.OneTermEqualSelector(".status.namespaces", object.GetNamespace()),
+			}); err != nil {
+				r.Log.Error(err, "Cannot list the Tenant of the webhook configuration", "namespace", object.GetNamespace())
+
+				return nil
+			}
+
+			requests := make([]reconcile.Request, 0, len(tntList.Items))
+			for _, tnt := range tntList.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: tnt.GetName()}})
+			}
+
+			return requests
+		})).
+		WithOptions(r.Options).
+		Complete(r)
+}
+
+func (r *Manager) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("tenant", request.Name)
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = r.Get(ctx, request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return
+	}
+	// the objects of the Tenants being deleted are removed by the cleanup controller
+	if !tnt.GetDeletionTimestamp().IsZero() {
+		return
+	}
+
+	var sources []capsulev1beta1.TenantWebhookConfiguration
+
+	if tnt.Spec.WebhookConfigurations != nil && tnt.Spec.WebhookConfigurations.Allowed {
+		for _, ns := range tnt.Status.Namespaces {
+			list := &capsulev1beta1.TenantWebhookConfigurationList{}
+			if err = r.List(ctx, list, client.InNamespace(ns)); err != nil {
+				log.Error(err, "Cannot list the TenantWebhookConfigurations", "namespace", ns)
+				return
+			}
+
+			sources = append(sources, list.Items...)
+		}
+	}
+
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return
+	}
+
+	validating, mutating := make(map[string]bool), make(map[string]bool)
+
+	for i := range sources {
+		source := &sources[i]
+
+		if err = r.syncValidating(ctx, log, tnt, tenantLabel, source); err != nil {
+			log.Error(err, "Cannot sync ValidatingWebhookConfiguration", "source", client.ObjectKeyFromObject(source).String())
+			return
+		}
+		validating[source.GeneratedName()] = len(source.Spec.ValidatingWebhooks) > 0
+
+		if err = r.syncMutating(ctx, log, tnt, tenantLabel, source); err != nil {
+			log.Error(err, "Cannot sync MutatingWebhookConfiguration", "source", client.ObjectKeyFromObject(source).String())
+			return
+		}
+		mutating[source.GeneratedName()] = len(source.Spec.MutatingWebhooks) > 0
+	}
+
+	return ctrl.Result{}, r.prune(ctx, log, tnt, tenantLabel, validating, mutating)
+}
+
+func (r *Manager) syncValidating(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, tenantLabel string, source *capsulev1beta1.TenantWebhookConfiguration) error {
+	if len(source.Spec.ValidatingWebhooks) == 0 {
+		return nil
+	}
+
+	cfg := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	cfg.SetName(source.GeneratedName())
+
+	res, err := controllerutil.CreateOrUpdate(ctx, r.Client, cfg, func() error {
+		if err := controlled(cfg, tnt); err != nil {
+			return err
+		}
+
+		r.setMetadata(cfg, tnt, tenantLabel, source)
+
+		cfg.Webhooks = make([]admissionregistrationv1.ValidatingWebhook, 0, len(source.Spec.ValidatingWebhooks))
+
+		for _, webhook := range source.Spec.ValidatingWebhooks {
+			webhook = *webhook.DeepCopy()
+
+			if !restrict(tenantLabel, tnt.GetName(), source.GetNamespace(), &webhook.NamespaceSelector, &webhook.ObjectSelector, webhook.Rules, &webhook.ClientConfig) {
+				log.Info("Skipping the webhook not backed by a Service of the Tenant", "source", client.ObjectKeyFromObject(source).String(), "webhook", webhook.Name)
+
+				continue
+			}
+
+			cfg.Webhooks = append(cfg.Webhooks, webhook)
+		}
+
+		return controllerutil.SetControllerReference(tnt, cfg, r.Scheme)
+	})
+
+	log.Info("ValidatingWebhookConfiguration synced", "result", res, "name", cfg.GetName())
+
+	return err
+}
+
+func (r *Manager) syncMutating(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, tenantLabel string, source *capsulev1beta1.TenantWebhookConfiguration) error {
+	if len(source.Spec.MutatingWebhooks) == 0 {
+		return nil
+	}
+
+	cfg := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	cfg.SetName(source.GeneratedName())
+
+	res, err := controllerutil.CreateOrUpdate(ctx, r.Client, cfg, func() error {
+		if err := controlled(cfg, tnt); err != nil {
+			return err
+		}
+
+		r.setMetadata(cfg, tnt, tenantLabel, source)
+
+		cfg.Webhooks = make([]admissionregistrationv1.MutatingWebhook, 0, len(source.Spec.MutatingWebhooks))
+
+		for _, webhook := range source.Spec.MutatingWebhooks {
+			webhook = *webhook.DeepCopy()
+
+			if !restrict(tenantLabel, tnt.GetName(), source.GetNamespace(), &webhook.NamespaceSelector, &webhook.ObjectSelector, webhook.Rules, &webhook.ClientConfig) {
+				log.Info("Skipping the webhook not backed by a Service of the Tenant", "source", client.ObjectKeyFromObject(source).String(), "webhook", webhook.Name)
+
+				continue
+			}
+
+			cfg.Webhooks = append(cfg.Webhooks, webhook)
+		}
+
+		return controllerutil.SetControllerReference(tnt, cfg, r.Scheme)
+	})
+
+	log.Info("MutatingWebhookConfiguration synced", "result", res, "name", cfg.GetName())
+
+	return err
+}
+
+// prune deletes the webhook configurations controlled by the Tenant no more declared, or declaring no webhooks.
+func (r *Manager) prune(ctx context.Context, log logr.Logger, tnt *capsulev1beta1.Tenant, tenantLabel string, validating, mutating map[string]bool) error {
+	selector := client.MatchingLabels{tenantLabel: tnt.GetName()}
+
+	validatingList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := r.List(ctx, validatingList, selector); err != nil {
+		return err
+	}
+
+	for i := range validatingList.Items {
+		if item := &validatingList.Items[i]; !validating[item.GetName()] && metav1.IsControlledBy(item, tnt) {
+			log.Info("Pruning ValidatingWebhookConfiguration", "name", item.GetName())
+
+			if err := r.Delete(ctx, item); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	mutatingList := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := r.List(ctx, mutatingList, selector); err != nil {
+		return err
+	}
+
+	for i := range mutatingList.Items {
+		if item := &mutatingList.Items[i]; !mutating[item.GetName()] && metav1.IsControlledBy(item, tnt) {
+			log.Info("Pruning MutatingWebhookConfiguration", "name", item.GetName())
+
+			if err := r.Delete(ctx, item); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Manager) setMetadata(obj client.Object, tnt *capsulev1beta1.Tenant, tenantLabel string, source *capsulev1beta1.TenantWebhookConfiguration) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[tenantLabel] = tnt.GetName()
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[SourceAnnotation] = client.ObjectKeyFromObject(source).String()
+	obj.SetAnnotations(annotations)
+}
+
+// controlled ensures the given webhook configuration, when already existing, is controlled by the Tenant, so that
+// the ones created by other parties are never overwritten.
+func controlled(obj client.Object, tnt *capsulev1beta1.Tenant) error {
+	if len(obj.GetUID()) == 0 || metav1.IsControlledBy(obj, tnt) {
+		return nil
+	}
+
+	return fmt.Errorf("the webhook configuration %s is not controlled by the Tenant %s", obj.GetName(), tnt.GetName())
+}
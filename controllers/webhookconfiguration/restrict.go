@@ -0,0 +1,47 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfiguration
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// restrict confines a Tenant webhook to the Namespaces of the Tenant: the namespaced resources only are intercepted,
+// in the Namespaces labelled with the Tenant, skipping the objects generated by Capsule, labelled as well.
+// The webhook must be served by a Service of the declaring Namespace, the ones with a URL are reported as invalid.
+func restrict(tenantLabel, tenant, namespace string, namespaceSelector, objectSelector **metav1.LabelSelector, rules []admissionregistrationv1.RuleWithOperations, clientConfig *admissionregistrationv1.WebhookClientConfig) bool {
+	if clientConfig.URL != nil || clientConfig.Service == nil {
+		return false
+	}
+
+	clientConfig.Service.Namespace = namespace
+
+	if *namespaceSelector == nil {
+		*namespaceSelector = &metav1.LabelSelector{}
+	}
+
+	(*namespaceSelector).MatchExpressions = append((*namespaceSelector).MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      tenantLabel,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{tenant},
+	})
+
+	if *objectSelector == nil {
+		*objectSelector = &metav1.LabelSelector{}
+	}
+
+	(*objectSelector).MatchExpressions = append((*objectSelector).MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      tenantLabel,
+		Operator: metav1.LabelSelectorOpDoesNotExist,
+	})
+
+	scope := admissionregistrationv1.NamespacedScope
+
+	for i := range rules {
+		rules[i].Scope = &scope
+	}
+
+	return true
+}
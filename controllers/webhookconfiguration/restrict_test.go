@@ -0,0 +1,39 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfiguration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestRestrict(t *testing.T) {
+	clusterScope := admissionregistrationv1.ClusterScope
+
+	webhook := admissionregistrationv1.ValidatingWebhook{
+		ClientConfig: admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{Namespace: "gas-production", Name: "operator"},
+		},
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "production"}},
+		Rules:             []admissionregistrationv1.RuleWithOperations{{}, {Rule: admissionregistrationv1.Rule{Scope: &clusterScope}}},
+	}
+
+	if assert.True(t, restrict("capsule.clastix.io/tenant", "oil", "oil-production", &webhook.NamespaceSelector, &webhook.ObjectSelector, webhook.Rules, &webhook.ClientConfig)) {
+		assert.Equal(t, "oil-production", webhook.ClientConfig.Service.Namespace)
+		assert.Equal(t, map[string]string{"environment": "production"}, webhook.NamespaceSelector.MatchLabels)
+		assert.Equal(t, []metav1.LabelSelectorRequirement{{Key: "capsule.clastix.io/tenant", Operator: metav1.LabelSelectorOpIn, Values: []string{"oil"}}}, webhook.NamespaceSelector.MatchExpressions)
+		assert.Equal(t, []metav1.LabelSelectorRequirement{{Key: "capsule.clastix.io/tenant", Operator: metav1.LabelSelectorOpDoesNotExist}}, webhook.ObjectSelector.MatchExpressions)
+
+		for _, rule := range webhook.Rules {
+			assert.Equal(t, admissionregistrationv1.NamespacedScope, *rule.Scope)
+		}
+	}
+
+	webhook.ClientConfig = admissionregistrationv1.WebhookClientConfig{URL: pointer.StringPtr("https://example.com")}
+	assert.False(t, restrict("capsule.clastix.io/tenant", "oil", "oil-production", &webhook.NamespaceSelector, &webhook.ObjectSelector, webhook.Rules, &webhook.ClientConfig))
+}
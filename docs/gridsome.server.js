@@ -262,6 +262,98 @@ module.exports = function (api) {
                   label: 'Denying specific user-defined labels or annotations on Nodes',
                   path: '/docs/operator/use-cases/deny-specific-user-defined-labels-or-annotations-on-nodes'
                 },
+                {
+                  label: 'Kyverno Policies',
+                  path: '/docs/operator/use-cases/kyverno-policies'
+                },
+                {
+                  label: 'Export and Import Tenants',
+                  path: '/docs/operator/use-cases/tenant-export-import'
+                },
+                {
+                  label: 'Multi-cluster Federation',
+                  path: '/docs/operator/use-cases/multi-cluster-federation'
+                },
+                {
+                  label: 'Cost Allocation and Chargeback',
+                  path: '/docs/operator/use-cases/chargeback'
+                },
+                {
+                  label: 'API Priority and Fairness',
+                  path: '/docs/operator/use-cases/api-priority-and-fairness'
+                },
+                {
+                  label: 'Limit CronJobs',
+                  path: '/docs/operator/use-cases/cronjob-limits'
+                },
+                {
+                  label: 'Declarative Namespaces',
+                  path: '/docs/operator/use-cases/declarative-namespaces'
+                },
+                {
+                  label: 'Access bundles',
+                  path: '/docs/operator/use-cases/access-bundles'
+                },
+                {
+                  label: 'Register Tenant webhooks',
+                  path: '/docs/operator/use-cases/tenant-webhooks'
+                },
+                {
+                  label: 'Sub-tenants',
+                  path: '/docs/operator/use-cases/sub-tenants'
+                },
+                {
+                  label: 'Tenant requests',
+                  path: '/docs/operator/use-cases/tenant-requests'
+                },
+                {
+                  label: 'Workload labels',
+                  path: '/docs/operator/use-cases/workload-labels'
+                },
+                {
+                  label: 'Secret restrictions',
+                  path: '/docs/operator/use-cases/secret-restrictions'
+                },
+                {
+                  label: 'External secrets',
+                  path: '/docs/operator/use-cases/external-secrets'
+                },
+                {
+                  label: 'Tenant mapping',
+                  path: '/docs/operator/use-cases/tenant-mapping'
+                },
+                {
+                  label: 'Pod DNS',
+                  path: '/docs/operator/use-cases/pod-dns'
+                },
+                {
+                  label: 'Maintenance windows',
+                  path: '/docs/operator/use-cases/maintenance-windows'
+                },
+                {
+                  label: 'Restrictions rollout',
+                  path: '/docs/operator/use-cases/restrictions-rollout'
+                },
+                {
+                  label: 'Tenant exceptions',
+                  path: '/docs/operator/use-cases/tenant-exceptions'
+                },
+                {
+                  label: 'Tenant peering',
+                  path: '/docs/operator/use-cases/tenant-peering'
+                },
+                {
+                  label: 'Capacity hints',
+                  path: '/docs/operator/use-cases/capacity-hints'
+                },
+                {
+                  label: 'Batch queueing',
+                  path: '/docs/operator/use-cases/batch-queueing'
+                },
+                {
+                  label: 'Admission warnings',
+                  path: '/docs/operator/use-cases/admission-warnings'
+                },
               ]
             },
           ]
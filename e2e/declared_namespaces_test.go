@@ -0,0 +1,66 @@
+//+build e2e
+
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+var _ = Describe("creating a Tenant with declared Namespaces", func() {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-declared-namespaces",
+		},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{
+				{
+					Name: "alice",
+					Kind: "User",
+				},
+			},
+			Namespaces: []capsulev1beta1.DeclaredNamespaceSpec{
+				{
+					Name: "declared-production",
+					AdditionalMetadata: &capsulev1beta1.AdditionalMetadataSpec{
+						Labels: map[string]string{
+							"environment": "production",
+						},
+					},
+				},
+				{
+					Name: "declared-development",
+				},
+			},
+		},
+	}
+
+	JustBeforeEach(func() {
+		EventuallyCreation(func() error {
+			tnt.ResourceVersion = ""
+			return k8sClient.Create(context.TODO(), tnt)
+		}).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+
+	It("should create the Namespaces and bind them to the Tenant", func() {
+		TenantNamespaceList(tnt, defaultTimeoutInterval).Should(ContainElements("declared-production", "declared-development"))
+
+		ns := &corev1.Namespace{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "declared-production"}, ns)).Should(Succeed())
+		Expect(ns.GetLabels()).Should(HaveKeyWithValue("environment", "production"))
+		Expect(ns.GetLabels()).Should(HaveKeyWithValue("capsule.clastix.io/tenant", tnt.GetName()))
+	})
+})
@@ -0,0 +1,93 @@
+//+build e2e
+
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+var _ = Describe("creating a Tenant with metadata for the generated objects", func() {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-generated-metadata",
+		},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{
+				{
+					Name: "alice",
+					Kind: "User",
+				},
+			},
+			ResourceQuota: capsulev1beta1.ResourceQuotaSpec{
+				Items: []corev1.ResourceQuotaSpec{
+					{
+						Hard: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourcePods: resource.MustParse("10"),
+						},
+					},
+				},
+			},
+			GeneratedObjectsMetadata: &capsulev1beta1.AdditionalMetadataSpec{
+				Labels: map[string]string{
+					"cost-center": "oil",
+				},
+				Annotations: map[string]string{
+					"argocd.argoproj.io/compare-options": "IgnoreExtraneous",
+				},
+			},
+		},
+	}
+
+	JustBeforeEach(func() {
+		EventuallyCreation(func() error {
+			tnt.ResourceVersion = ""
+			return k8sClient.Create(context.TODO(), tnt)
+		}).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+
+	It("should stamp the metadata and restore it once stripped", func() {
+		ns := NewNamespace("generated-metadata")
+		NamespaceCreation(ns, tnt.Spec.Owners[0], defaultTimeoutInterval).Should(Succeed())
+		TenantNamespaceList(tnt, defaultTimeoutInterval).Should(ContainElement(ns.GetName()))
+
+		key := types.NamespacedName{Name: fmt.Sprintf("capsule-%s-0", tnt.GetName()), Namespace: ns.GetName()}
+		rq := &corev1.ResourceQuota{}
+
+		stamped := func() bool {
+			if err := k8sClient.Get(context.TODO(), key, rq); err != nil {
+				return false
+			}
+
+			return rq.GetLabels()["cost-center"] == "oil" && rq.GetAnnotations()["argocd.argoproj.io/compare-options"] == "IgnoreExtraneous"
+		}
+
+		Eventually(stamped, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+
+		Eventually(func() error {
+			if err := k8sClient.Get(context.TODO(), key, rq); err != nil {
+				return err
+			}
+			delete(rq.Labels, "cost-center")
+
+			return k8sClient.Update(context.TODO(), rq)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		Eventually(stamped, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+	})
+})
@@ -0,0 +1,133 @@
+//+build e2e
+
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+var _ = Describe("creating Namespaces selecting a LimitRange profile", func() {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-limit-range-profiles",
+		},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{
+				{
+					Name: "alice",
+					Kind: "User",
+				},
+			},
+			LimitRanges: capsulev1beta1.LimitRangesSpec{
+				Items: []corev1.LimitRangeSpec{
+					{
+						Limits: []corev1.LimitRangeItem{
+							{
+								Type: corev1.LimitTypeContainer,
+								Max: map[corev1.ResourceName]resource.Quantity{
+									corev1.ResourceCPU: resource.MustParse("1"),
+								},
+							},
+						},
+					},
+				},
+				Profiles: []capsulev1beta1.LimitRangeProfile{
+					{
+						Name: "large",
+						Items: []corev1.LimitRangeSpec{
+							{
+								Limits: []corev1.LimitRangeItem{
+									{
+										Type: corev1.LimitTypeContainer,
+										Max: map[corev1.ResourceName]resource.Quantity{
+											corev1.ResourceCPU: resource.MustParse("4"),
+										},
+										MaxLimitRequestRatio: map[corev1.ResourceName]resource.Quantity{
+											corev1.ResourceCPU: resource.MustParse("2"),
+										},
+									},
+								},
+							},
+							{
+								Limits: []corev1.LimitRangeItem{
+									{
+										Type: corev1.LimitTypePersistentVolumeClaim,
+										Max: map[corev1.ResourceName]resource.Quantity{
+											corev1.ResourceStorage: resource.MustParse("100Gi"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	JustBeforeEach(func() {
+		EventuallyCreation(func() error {
+			tnt.ResourceVersion = ""
+			return k8sClient.Create(context.TODO(), tnt)
+		}).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+
+	limitRangeSpec := func(namespace string, i int) func() (corev1.LimitRangeSpec, error) {
+		return func() (corev1.LimitRangeSpec, error) {
+			lr := &corev1.LimitRange{}
+			err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-%d", tnt.GetName(), i), Namespace: namespace}, lr)
+
+			return lr.Spec, err
+		}
+	}
+
+	It("should apply the default items without the profile label", func() {
+		ns := NewNamespace("lr-profile-default")
+		NamespaceCreation(ns, tnt.Spec.Owners[0], defaultTimeoutInterval).Should(Succeed())
+		TenantNamespaceList(tnt, defaultTimeoutInterval).Should(ContainElement(ns.GetName()))
+
+		Eventually(limitRangeSpec(ns.GetName(), 0), defaultTimeoutInterval, defaultPollInterval).Should(Equal(tnt.Spec.LimitRanges.Items[0]))
+	})
+
+	It("should apply the selected profile and switch back to the default items", func() {
+		ns := NewNamespace("lr-profile-large")
+		ns.SetLabels(map[string]string{capsulev1beta1.LimitRangeProfileLabel: "large"})
+		NamespaceCreation(ns, tnt.Spec.Owners[0], defaultTimeoutInterval).Should(Succeed())
+		TenantNamespaceList(tnt, defaultTimeoutInterval).Should(ContainElement(ns.GetName()))
+
+		for i, s := range tnt.Spec.LimitRanges.Profiles[0].Items {
+			Eventually(limitRangeSpec(ns.GetName(), i), defaultTimeoutInterval, defaultPollInterval).Should(Equal(s))
+		}
+
+		Eventually(func() error {
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns); err != nil {
+				return err
+			}
+			delete(ns.Labels, capsulev1beta1.LimitRangeProfileLabel)
+
+			return k8sClient.Update(context.TODO(), ns)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		Eventually(limitRangeSpec(ns.GetName(), 0), defaultTimeoutInterval, defaultPollInterval).Should(Equal(tnt.Spec.LimitRanges.Items[0]))
+		Eventually(func() error {
+			_, err := limitRangeSpec(ns.GetName(), 1)()
+			return err
+		}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+	})
+})
@@ -13,11 +13,13 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/webhook/managed"
 )
 
 var _ = Describe("when Tenant owner interacts with the webhooks", func() {
@@ -140,6 +142,39 @@ var _ = Describe("when Tenant owner interacts with the webhooks", func() {
 			cs := ownerClient(tnt.Spec.Owners[0])
 			Expect(cs.NetworkingV1().NetworkPolicies(ns.GetName()).Delete(context.TODO(), rq.Name, metav1.DeleteOptions{})).ShouldNot(Succeed())
 		})
+		By("blocking Capsule Role Binding", func() {
+			ns := NewNamespace("role-binding-disallow")
+			NamespaceCreation(ns, tnt.Spec.Owners[0], defaultTimeoutInterval).Should(Succeed())
+			TenantNamespaceList(tnt, defaultTimeoutInterval).Should(ContainElement(ns.GetName()))
+
+			rb := &rbacv1.RoleBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), types.NamespacedName{Name: "namespace-deleter", Namespace: ns.GetName()}, rb)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+			cs := ownerClient(tnt.Spec.Owners[0])
+			Expect(cs.RbacV1().RoleBindings(ns.GetName()).Delete(context.TODO(), rb.Name, metav1.DeleteOptions{})).ShouldNot(Succeed())
+		})
+	})
+
+	It("should allow deleting the Capsule objects opted out", func() {
+		ns := NewNamespace("network-policy-editable")
+		NamespaceCreation(ns, tnt.Spec.Owners[0], defaultTimeoutInterval).Should(Succeed())
+		TenantNamespaceList(tnt, defaultTimeoutInterval).Should(ContainElement(ns.GetName()))
+
+		np := &networkingv1.NetworkPolicy{}
+		Eventually(func() error {
+			n := fmt.Sprintf("capsule-%s-0", tnt.GetName())
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: n, Namespace: ns.GetName()}, np); err != nil {
+				return err
+			}
+			np.SetAnnotations(map[string]string{managed.EditableAnnotation: "true"})
+
+			return k8sClient.Update(context.TODO(), np)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		cs := ownerClient(tnt.Spec.Owners[0])
+		Expect(cs.NetworkingV1().NetworkPolicies(ns.GetName()).Delete(context.TODO(), np.Name, metav1.DeleteOptions{})).Should(Succeed())
 	})
 
 	It("should allow", func() {
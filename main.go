@@ -6,47 +6,75 @@ package main
 import (
 	goflag "flag"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
 	"os"
 	goRuntime "runtime"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-
-	"github.com/clastix/capsule/pkg/webhook/node"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	accessbundlecontroller "github.com/clastix/capsule/controllers/accessbundle"
+	chargebackcontroller "github.com/clastix/capsule/controllers/chargeback"
+	cleanupcontroller "github.com/clastix/capsule/controllers/cleanup"
 	configcontroller "github.com/clastix/capsule/controllers/config"
+	federationcontroller "github.com/clastix/capsule/controllers/federation"
+	flowcontrolcontroller "github.com/clastix/capsule/controllers/flowcontrol"
+	impactcontroller "github.com/clastix/capsule/controllers/impact"
+	kueuecontroller "github.com/clastix/capsule/controllers/kueue"
+	kyvernocontroller "github.com/clastix/capsule/controllers/kyverno"
+	mappingcontroller "github.com/clastix/capsule/controllers/mapping"
+	namespaceratecontroller "github.com/clastix/capsule/controllers/namespacerate"
+	podgccontroller "github.com/clastix/capsule/controllers/podgc"
+	pvcontroller "github.com/clastix/capsule/controllers/pv"
 	rbaccontroller "github.com/clastix/capsule/controllers/rbac"
 	secretcontroller "github.com/clastix/capsule/controllers/secret"
 	servicelabelscontroller "github.com/clastix/capsule/controllers/servicelabels"
+	servicelimitscontroller "github.com/clastix/capsule/controllers/servicelimits"
 	tenantcontroller "github.com/clastix/capsule/controllers/tenant"
+	tenantrequestcontroller "github.com/clastix/capsule/controllers/tenantrequest"
+	velerocontroller "github.com/clastix/capsule/controllers/velero"
+	webhookconfigurationcontroller "github.com/clastix/capsule/controllers/webhookconfiguration"
 	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/health"
 	"github.com/clastix/capsule/pkg/indexer"
+	"github.com/clastix/capsule/pkg/logging"
+	"github.com/clastix/capsule/pkg/lookup"
+	capsuleserver "github.com/clastix/capsule/pkg/server"
+	capsuleutils "github.com/clastix/capsule/pkg/utils"
 	"github.com/clastix/capsule/pkg/webhook"
-	"github.com/clastix/capsule/pkg/webhook/ingress"
-	namespacewebhook "github.com/clastix/capsule/pkg/webhook/namespace"
-	"github.com/clastix/capsule/pkg/webhook/networkpolicy"
-	"github.com/clastix/capsule/pkg/webhook/ownerreference"
-	"github.com/clastix/capsule/pkg/webhook/pod"
-	"github.com/clastix/capsule/pkg/webhook/pvc"
-	"github.com/clastix/capsule/pkg/webhook/route"
-	"github.com/clastix/capsule/pkg/webhook/service"
-	"github.com/clastix/capsule/pkg/webhook/tenant"
+	"github.com/clastix/capsule/pkg/webhook/denials"
 	"github.com/clastix/capsule/pkg/webhook/utils"
+	"github.com/clastix/capsule/pkg/webhook/webhooks"
 	// +kubebuilder:scaffold:imports
 )
 
+const (
+	webhookPort         = 9443
+	webhookInternalPort = 9444
+	webhookCertDir      = "/tmp/k8s-webhook-server/serving-certs"
+	metricsInternalAddr = "127.0.0.1:18080"
+)
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -70,18 +98,66 @@ func printVersion() {
 }
 
 func main() {
-	var metricsAddr string
+	var metricsAddr, metricsCertDir string
+	var metricsSecure bool
+	var leaseDuration, renewDeadline, retryPeriod, shutdownDelay time.Duration
 	var enableLeaderElection bool
 	var version bool
+	var enableKyvernoPolicies, enableVeleroBackups, enableFederation, enableChargeback, enableAPIPriorityAndFairness, enableAccessBundles, enablePodGarbageCollection, enableTenantMapping, enableKueueQueues, enableRestrictionsImpact bool
+	var veleroNamespace, accessBundleServer string
+	var federationSyncPeriod, chargebackPeriod, usageHistoryPeriod time.Duration
+	var tenantMaxConcurrentReconciles, secretMaxConcurrentReconciles, admissionDenialsHistory, usageHistorySize int
+	var persistAdmissionDenials bool
+	var rateLimiterOptions capsuleutils.RateLimiterOptions
+	var tenantLookupMaxStaleness, tenantResyncPeriod time.Duration
+	var certificateRenewalThreshold float64
+	var tlsOptions capsuleserver.TLSOptions
 	var namespace, configurationName string
 	var goFlagSet goflag.FlagSet
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.BoolVar(&metricsSecure, "metrics-secure", false, "Serve the metric endpoint over TLS, using the certificate issued by the Capsule CA unless a different one is provided")
+	flag.StringVar(&metricsCertDir, "metrics-cert-dir", webhookCertDir, "The directory containing the tls.crt and tls.key files used to serve the metric endpoint over TLS")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second, "The duration the non-leader replicas wait before forcing to acquire the leadership")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second, "The duration the leader retries refreshing the leadership before giving it up")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second, "The duration the replicas wait between the leader election actions")
+	flag.DurationVar(&shutdownDelay, "shutdown-delay", 0, "The duration a terminating replica keeps serving the admission requests while reported as not ready, allowing the webhook Service Endpoints to be updated")
 	flag.BoolVar(&version, "version", false, "Print the Capsule version and exit")
 	flag.StringVar(&configurationName, "configuration-name", "default", "The CapsuleConfiguration resource name to use")
+	flag.BoolVar(&enableKyvernoPolicies, "enable-kyverno-policies", false, "Emit a Kyverno ClusterPolicy for the Tenants opting in, requires Kyverno to be installed")
+	flag.BoolVar(&enableVeleroBackups, "enable-velero-backups", false, "Manage a Velero Schedule for the Tenants declaring a backup, requires Velero to be installed")
+	flag.StringVar(&veleroNamespace, "velero-namespace", "velero", "The Namespace where Velero is installed")
+	flag.BoolVar(&enableFederation, "enable-federation", false, "Run as federation hub, replicating the Tenants to the member clusters declared in the Capsule Namespace")
+	flag.DurationVar(&federationSyncPeriod, "federation-sync-period", time.Minute, "How often the Tenants are replicated to the member clusters")
+	flag.BoolVar(&enableChargeback, "enable-chargeback", false, "Collect the resources requested and used by each Tenant, exporting them at the /chargeback metrics endpoint")
+	flag.DurationVar(&chargebackPeriod, "chargeback-period", 5*time.Minute, "How often the Tenant resources usage is collected")
+	flag.IntVar(&usageHistorySize, "usage-history-size", 0, "The number of usage snapshots, as namespaces, Pods and CPU and memory requests, kept in the status of each Tenant, zero disables it")
+	flag.DurationVar(&usageHistoryPeriod, "usage-history-period", time.Hour, "How often the Tenant usage snapshots are recorded")
+	flag.BoolVar(&enableAPIPriorityAndFairness, "enable-api-priority-and-fairness", false, "Manage a FlowSchema for the Tenants opting in, requires the flowcontrol.apiserver.k8s.io/v1beta1 API")
+	flag.BoolVar(&enableAccessBundles, "enable-access-bundles", false, "Publish a kubeconfig Secret for the owners of the Tenants declaring an access bundle, minting their credentials through CertificateSigningRequests and TokenRequests")
+	flag.StringVar(&accessBundleServer, "access-bundle-server", "", "The API server URL set in the published kubeconfig files, if omitted the one used by Capsule")
+	flag.BoolVar(&enablePodGarbageCollection, "enable-pod-garbage-collection", false, "Delete the finished Pods of the Tenants declaring a maximum age, caching the Pods of the cluster")
+	flag.BoolVar(&enableTenantMapping, "enable-tenant-mapping", false, "Publish the Namespaces, IngressClasses, StorageClasses and Nodes selected by each Tenant in the capsule-tenant-mapping ConfigMap of the Capsule Namespace, caching the Nodes and the classes of the cluster")
+	flag.BoolVar(&enableKueueQueues, "enable-kueue-queues", false, "Manage a Kueue ClusterQueue, and the LocalQueues of its Namespaces, for the Tenants declaring the batch queueing, requires Kueue to be installed")
+	flag.BoolVar(&enableRestrictionsImpact, "enable-restrictions-impact", false, "Report the Pods violating the Pod restrictions of each Tenant in its status, and delay the enforcement of the changed restrictions for the Tenants declaring the WhenCompliant rollout, caching the Pods of the cluster")
+	flag.IntVar(&tenantMaxConcurrentReconciles, "tenant-max-concurrent-reconciles", 1, "The maximum number of Tenants reconciled in parallel, along with their Namespaces, ResourceQuotas, LimitRanges, NetworkPolicies and RoleBindings")
+	flag.DurationVar(&tenantResyncPeriod, "tenant-resync-period", 0, "The interval the Tenants are reconciled at even without watch events, re-asserting the generated objects drifted by manual edits or missed events, zero disables it")
+	flag.IntVar(&secretMaxConcurrentReconciles, "secret-max-concurrent-reconciles", 1, "The maximum number of CA and TLS Secrets reconciliations running in parallel")
+	flag.DurationVar(&rateLimiterOptions.BaseDelay, "rate-limiter-base-delay", 5*time.Millisecond, "The initial delay before retrying a failed reconciliation, exponentially increased at each failure")
+	flag.DurationVar(&rateLimiterOptions.MaxDelay, "rate-limiter-max-delay", 1000*time.Second, "The maximum delay before retrying a failed reconciliation")
+	flag.Float64Var(&rateLimiterOptions.Jitter, "requeue-jitter", 0.1, "The fraction of the delays before retrying a failed reconciliation, and of the periodic reconciliations and certificate renewals, randomly subtracted from them so that the items scheduled together are not reconciled in lockstep, 0 disables it")
+	flag.Float64Var(&rateLimiterOptions.QPS, "rate-limiter-qps", 10, "The overall number of reconciliations enqueued per second by each controller")
+	flag.IntVar(&rateLimiterOptions.Burst, "rate-limiter-burst", 100, "The maximum burst of reconciliations enqueued by each controller")
+	flag.Float64Var(&certificateRenewalThreshold, "certificate-renewal-threshold", 2.0/3.0, "The fraction of the CA and webhook certificates lifetime after which they're renewed, a value out of the (0, 1) range renews them at the expiration")
+	flag.StringVar(&tlsOptions.MinVersion, "tls-min-version", "1.2", "The minimum TLS version accepted by the webhook and metrics servers, one of 1.0, 1.1, 1.2 or 1.3")
+	flag.StringSliceVar(&tlsOptions.CipherSuites, "tls-cipher-suites", nil, "Comma-separated list of the cipher suites accepted by the webhook and metrics servers using the IANA names, if omitted the Go default ones are used")
+	flag.StringVar(&tlsOptions.ClientCAFile, "webhook-client-ca-file", "", "The PEM bundle used to verify the client certificate presented by the API server to the webhook server, if omitted no client certificate is required")
+	flag.IntVar(&admissionDenialsHistory, "admission-denials-history", 20, "The number of the last admission denials kept in memory for each Tenant, served at the /denials metrics endpoint, 0 disables it")
+	flag.BoolVar(&persistAdmissionDenials, "persist-admission-denials", false, "Persist the last admission denials of each Tenant in the capsule-admission-denials ConfigMap of the denied requests Namespaces, readable by the Tenant owners")
+	flag.DurationVar(&tenantLookupMaxStaleness, "tenant-lookup-max-staleness", 30*time.Second, "The maximum staleness of the in-memory Tenant index serving the webhooks lookups, 0 disables it")
 
 	opts := zap.Options{
 		EncoderConfigOptions: append([]zap.EncoderConfigOption{}, func(config *zapcore.EncoderConfig) {
@@ -93,7 +169,18 @@ func main() {
 	flag.CommandLine.AddGoFlagSet(&goFlagSet)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	flagLevel := opts.Level
+	if flagLevel == nil {
+		flagLevel = zapcore.InfoLevel
+		if opts.Development {
+			flagLevel = zapcore.DebugLevel
+		}
+	}
+	// the zap logger emits all the verbosity levels, filtered by the ones adjustable through the CapsuleConfiguration
+	logLevels := logging.NewLevels(flagLevel)
+	opts.Level = zapcore.Level(-logging.MaxVerbosity)
+
+	ctrl.SetLogger(logging.New(zap.New(zap.UseFlagOptions(&opts)), logLevels))
 
 	printVersion()
 	if version {
@@ -110,13 +197,51 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := tlsOptions.Validate(); err != nil {
+		setupLog.Error(err, "invalid TLS options")
+		os.Exit(1)
+	}
+
+	cacheSelectors, err := indexer.CacheSelectors(namespace)
+	if err != nil {
+		setupLog.Error(err, "unable to build the cache selectors")
+		os.Exit(1)
+	}
+
+	webhookMux := http.NewServeMux()
+	// the controller-runtime webhook server doesn't allow restricting the cipher suites: it's kept bound
+	// to the loopback interface, taking care of the handlers registration, while the admission requests
+	// are served by the hardened TLS server added below.
+	webhookServer := &ctrlwebhook.Server{
+		Host:       "127.0.0.1",
+		Port:       webhookInternalPort,
+		CertDir:    webhookCertDir,
+		CertName:   "tls.crt",
+		KeyName:    "tls.key",
+		WebhookMux: webhookMux,
+	}
+
+	// when served over TLS, the metrics are proxied from the controller-runtime listener bound to the loopback interface
+	metricsBindAddress := metricsAddr
+	if metricsSecure {
+		metricsBindAddress = metricsInternalAddr
+	}
+
+	// the Lease lock is safely acquired since the previous releases hold both the ConfigMap and the Lease ones,
+	// while stepping down on termination the new leader is elected without waiting for the lease expiration.
 	manager, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		MetricsBindAddress:     metricsAddr,
-		Port:                   9443,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "42c733ea.clastix.capsule.io",
-		HealthProbeBindAddress: ":10080",
+		Scheme:                        scheme,
+		NewCache:                      cache.BuilderWithOptions(cache.Options{SelectorsByObject: cacheSelectors}),
+		MetricsBindAddress:            metricsBindAddress,
+		WebhookServer:                 webhookServer,
+		LeaderElection:                enableLeaderElection,
+		LeaderElectionID:              "42c733ea.clastix.capsule.io",
+		LeaderElectionResourceLock:    resourcelock.LeasesResourceLock,
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &leaseDuration,
+		RenewDeadline:                 &renewDeadline,
+		RetryPeriod:                   &retryPeriod,
+		HealthProbeBindAddress:        ":10080",
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -129,26 +254,75 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = manager.Add(&capsuleserver.SecureServer{
+		Log:         ctrl.Log.WithName("webhooks"),
+		BindAddress: fmt.Sprintf(":%d", webhookPort),
+		CertDir:     webhookCertDir,
+		CertName:    "tls.crt",
+		KeyName:     "tls.key",
+		TLS:         tlsOptions,
+		Handler:     webhookMux,
+	}); err != nil {
+		setupLog.Error(err, "unable to create the webhook server")
+		os.Exit(1)
+	}
+
+	if metricsSecure {
+		// the scrapers are not expected to present a client certificate
+		metricsTLSOptions := tlsOptions
+		metricsTLSOptions.ClientCAFile = ""
+
+		if err = manager.Add(&capsuleserver.SecureServer{
+			Log:         ctrl.Log.WithName("metrics"),
+			BindAddress: metricsAddr,
+			CertDir:     metricsCertDir,
+			CertName:    "tls.crt",
+			KeyName:     "tls.key",
+			TLS:         metricsTLSOptions,
+			Handler:     httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: metricsInternalAddr}),
+		}); err != nil {
+			setupLog.Error(err, "unable to create the metrics server")
+			os.Exit(1)
+		}
+	}
+
 	_ = manager.AddReadyzCheck("ping", healthz.Ping)
 	_ = manager.AddHealthzCheck("ping", healthz.Ping)
-
-	ctx := ctrl.SetupSignalHandler()
+	// all the replicas serve the admission traffic, while the reconcilers are leader elected:
+	// a replica is ready only once able to take admission decisions.
+	_ = manager.AddReadyzCheck("webhook-certificate", health.WebhookCertificate(manager.GetWebhookServer()))
+	_ = manager.AddReadyzCheck("cache-sync", health.CacheSynced(manager.GetCache()))
+	// detecting half-broken states, as a serving certificate or webhooks not matching the Capsule CA
+	caSecret := types.NamespacedName{Namespace: namespace, Name: secretcontroller.CASecretName}
+	_ = manager.AddReadyzCheck("serving-certificate-trusted", health.ServingCertificateTrusted(manager.GetClient(), manager.GetWebhookServer(), caSecret))
+	_ = manager.AddReadyzCheck("ca-bundle", health.CABundle(manager.GetClient(), caSecret, secretcontroller.MutatingWebhookConfigurationName, secretcontroller.ValidatingWebhookConfigurationName))
+	// readiness only, as restarting the replica does not install the missing CRDs
+	_ = manager.AddReadyzCheck("crds", health.CustomResourceDefinitions(manager.GetClient(), "tenants.capsule.clastix.io", "capsuleconfigurations.capsule.clastix.io"))
+
+	ctx, shuttingDown := health.GracefulShutdown(ctrl.SetupSignalHandler(), shutdownDelay)
+	_ = manager.AddReadyzCheck("shutdown", shuttingDown)
 
 	if err = (&secretcontroller.CAReconciler{
-		Client:    manager.GetClient(),
-		Log:       ctrl.Log.WithName("controllers").WithName("CA"),
-		Scheme:    manager.GetScheme(),
-		Namespace: namespace,
+		Client:           manager.GetClient(),
+		Log:              ctrl.Log.WithName("controllers").WithName("CA"),
+		Scheme:           manager.GetScheme(),
+		Namespace:        namespace,
+		Options:          capsuleutils.ControllerOptions(secretMaxConcurrentReconciles, rateLimiterOptions),
+		RenewalThreshold: certificateRenewalThreshold,
+		RequeueJitter:    rateLimiterOptions.Jitter,
 	}).SetupWithManager(manager); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
 		os.Exit(1)
 	}
 
 	if err = (&secretcontroller.TLSReconciler{
-		Client:    manager.GetClient(),
-		Log:       ctrl.Log.WithName("controllers").WithName("Tls"),
-		Scheme:    manager.GetScheme(),
-		Namespace: namespace,
+		Client:           manager.GetClient(),
+		Log:              ctrl.Log.WithName("controllers").WithName("Tls"),
+		Scheme:           manager.GetScheme(),
+		Namespace:        namespace,
+		Options:          capsuleutils.ControllerOptions(secretMaxConcurrentReconciles, rateLimiterOptions),
+		RenewalThreshold: certificateRenewalThreshold,
+		RequeueJitter:    rateLimiterOptions.Jitter,
 	}).SetupWithManager(manager); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
 		os.Exit(1)
@@ -158,33 +332,62 @@ func main() {
 
 	cfg := configuration.NewCapsuleConfiguration(manager.GetClient(), configurationName)
 
-	// webhooks: the order matters, don't change it and just append
-	webhooksList := append(
-		make([]webhook.Webhook, 0),
-		route.Pod(pod.ImagePullPolicy(), pod.ContainerRegistry(), pod.PriorityClass()),
-		route.Namespace(utils.InCapsuleGroups(cfg, namespacewebhook.QuotaHandler(), namespacewebhook.FreezeHandler(cfg), namespacewebhook.PrefixHandler(cfg), namespacewebhook.UserMetadataHandler())),
-		route.Ingress(ingress.Class(cfg), ingress.Hostnames(cfg), ingress.Collision(cfg), ingress.Wildcard()),
-		route.PVC(pvc.Handler()),
-		route.Service(service.Handler()),
-		route.NetworkPolicy(utils.InCapsuleGroups(cfg, networkpolicy.Handler())),
-		route.Tenant(tenant.NameHandler(), tenant.RoleBindingRegexHandler(), tenant.IngressClassRegexHandler(), tenant.StorageClassRegexHandler(), tenant.ContainerRegistryRegexHandler(), tenant.HostnameRegexHandler(), tenant.FreezedEmitter(), tenant.ServiceAccountNameHandler()),
-		route.OwnerReference(utils.InCapsuleGroups(cfg, ownerreference.Handler(cfg))),
-		route.Cordoning(tenant.CordoningHandler(cfg)),
-		route.Node(utils.InCapsuleGroups(cfg, node.UserMetadataHandler(cfg, kubeVersion))),
-	)
+	webhooksList := webhooks.List(cfg, kubeVersion, manager.GetAPIReader())
 
 	nodeWebhookSupported, _ := utils.NodeWebhookSupported(kubeVersion)
 	if !nodeWebhookSupported {
 		setupLog.Info("Disabling node labels verification webhook as current Kubernetes version doesn't have fix for CVE-2021-25735")
 	}
 
-	if err = webhook.Register(manager, webhooksList...); err != nil {
+	var tenantIndex *lookup.TenantIndex
+	if tenantLookupMaxStaleness > 0 {
+		tenantIndex = &lookup.TenantIndex{
+			Log:          ctrl.Log.WithName("lookup").WithName("Tenant"),
+			MaxStaleness: tenantLookupMaxStaleness,
+		}
+		if err = manager.Add(tenantIndex); err != nil {
+			setupLog.Error(err, "unable to create the Tenant lookup index")
+			os.Exit(1)
+		}
+	}
+
+	var denialsHistory *denials.History
+	if admissionDenialsHistory > 0 {
+		denialsHistory = &denials.History{
+			Client:  manager.GetClient(),
+			Reader:  manager.GetAPIReader(),
+			Log:     ctrl.Log.WithName("webhooks").WithName("Denials"),
+			Size:    admissionDenialsHistory,
+			Persist: persistAdmissionDenials,
+		}
+		if err = manager.Add(denialsHistory); err != nil {
+			setupLog.Error(err, "unable to create the admission denials history")
+			os.Exit(1)
+		}
+		if err = manager.AddMetricsExtraHandler("/denials", capsuleserver.Authenticated(manager.GetClient(), denialsHistory)); err != nil {
+			setupLog.Error(err, "unable to register admission denials endpoint")
+			os.Exit(1)
+		}
+	}
+
+	if err = webhook.Register(manager, webhookCertDir, ctrl.Log.WithName("webhooks").WithName("Router"), cfg, tenantIndex, denialsHistory, webhooksList...); err != nil {
 		setupLog.Error(err, "unable to setup webhooks")
 		os.Exit(1)
 	}
 
+	replay, err := webhook.NewReplay(manager, ctrl.Log.WithName("webhooks").WithName("Replay"), cfg, tenantIndex, secretcontroller.MutatingWebhookConfigurationName, secretcontroller.ValidatingWebhookConfigurationName, webhooksList...)
+	if err != nil {
+		setupLog.Error(err, "unable to create the admission replay")
+		os.Exit(1)
+	}
+	if err = manager.AddMetricsExtraHandler("/replay", capsuleserver.Authenticated(manager.GetClient(), replay)); err != nil {
+		setupLog.Error(err, "unable to register admission replay endpoint")
+		os.Exit(1)
+	}
+
 	rbacManager := &rbaccontroller.Manager{
 		Log:           ctrl.Log.WithName("controllers").WithName("Rbac"),
+		Options:       capsuleutils.ControllerOptions(1, rateLimiterOptions),
 		Configuration: cfg,
 	}
 	if err = manager.Add(rbacManager); err != nil {
@@ -197,19 +400,22 @@ func main() {
 	}
 
 	if err = (&servicelabelscontroller.ServicesLabelsReconciler{
-		Log: ctrl.Log.WithName("controllers").WithName("ServiceLabels"),
+		Log:     ctrl.Log.WithName("controllers").WithName("ServiceLabels"),
+		Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
 	}).SetupWithManager(manager); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceLabels")
 		os.Exit(1)
 	}
 	if err = (&servicelabelscontroller.EndpointsLabelsReconciler{
-		Log: ctrl.Log.WithName("controllers").WithName("EndpointLabels"),
+		Log:     ctrl.Log.WithName("controllers").WithName("EndpointLabels"),
+		Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
 	}).SetupWithManager(manager); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EndpointLabels")
 		os.Exit(1)
 	}
 	if err = (&servicelabelscontroller.EndpointSlicesLabelsReconciler{
 		Log:          ctrl.Log.WithName("controllers").WithName("EndpointSliceLabels"),
+		Options:      capsuleutils.ControllerOptions(1, rateLimiterOptions),
 		VersionMinor: kubeVersion.Minor(),
 		VersionMajor: kubeVersion.Major(),
 	}).SetupWithManager(manager); err != nil {
@@ -217,7 +423,9 @@ func main() {
 	}
 
 	if err = (&configcontroller.Manager{
-		Log: ctrl.Log.WithName("controllers").WithName("CapsuleConfiguration"),
+		Log:     ctrl.Log.WithName("controllers").WithName("CapsuleConfiguration"),
+		Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+		Levels:  logLevels,
 	}).SetupWithManager(manager, configurationName); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CapsuleConfiguration")
 		os.Exit(1)
@@ -236,15 +444,228 @@ func main() {
 	}
 
 	if len(ca.Data) > 0 {
+		inventory := &tenantcontroller.Inventory{
+			Client:  manager.GetClient(),
+			Log:     ctrl.Log.WithName("controllers").WithName("Inventory"),
+			Elected: manager.Elected(),
+		}
+		if err = manager.AddMetricsExtraHandler("/inventory", capsuleserver.Authenticated(manager.GetClient(), inventory)); err != nil {
+			setupLog.Error(err, "unable to register inventory endpoint")
+			os.Exit(1)
+		}
 		if err = (&tenantcontroller.Manager{
-			Client:   manager.GetClient(),
-			Log:      ctrl.Log.WithName("controllers").WithName("Tenant"),
-			Scheme:   manager.GetScheme(),
-			Recorder: manager.GetEventRecorderFor("tenant-controller"),
+			Client:        manager.GetClient(),
+			Log:           ctrl.Log.WithName("controllers").WithName("Tenant"),
+			Scheme:        manager.GetScheme(),
+			Recorder:      manager.GetEventRecorderFor("tenant-controller"),
+			Options:       capsuleutils.ControllerOptions(tenantMaxConcurrentReconciles, rateLimiterOptions),
+			Configuration: cfg,
+			ResyncPeriod:  tenantResyncPeriod,
+			ResyncJitter:  rateLimiterOptions.Jitter,
+			Inventory:     inventory,
 		}).SetupWithManager(manager); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "Tenant")
 			os.Exit(1)
 		}
+		if err = (&pvcontroller.Manager{
+			Client:  manager.GetClient(),
+			Log:     ctrl.Log.WithName("controllers").WithName("PersistentVolume"),
+			Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+		}).SetupWithManager(manager); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PersistentVolume")
+			os.Exit(1)
+		}
+		if err = (&webhookconfigurationcontroller.Manager{
+			Client:  manager.GetClient(),
+			Log:     ctrl.Log.WithName("controllers").WithName("WebhookConfiguration"),
+			Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+			Scheme:  manager.GetScheme(),
+		}).SetupWithManager(manager); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WebhookConfiguration")
+			os.Exit(1)
+		}
+		if err = (&tenantrequestcontroller.Manager{
+			Client:   manager.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("TenantRequest"),
+			Options:  capsuleutils.ControllerOptions(1, rateLimiterOptions),
+			Recorder: manager.GetEventRecorderFor("tenantrequest-controller"),
+		}).SetupWithManager(manager); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TenantRequest")
+			os.Exit(1)
+		}
+		if err = (&servicelimitscontroller.Manager{
+			Client:  manager.GetClient(),
+			Log:     ctrl.Log.WithName("controllers").WithName("ServiceLimits"),
+			Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+		}).SetupWithManager(manager); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ServiceLimits")
+			os.Exit(1)
+		}
+		if err = (&namespaceratecontroller.Manager{
+			Client:  manager.GetClient(),
+			Log:     ctrl.Log.WithName("controllers").WithName("NamespaceRate"),
+			Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+		}).SetupWithManager(manager); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NamespaceRate")
+			os.Exit(1)
+		}
+		if err = (&cleanupcontroller.Manager{
+			Client:   manager.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("Cleanup"),
+			Options:  capsuleutils.ControllerOptions(1, rateLimiterOptions),
+			Recorder: manager.GetEventRecorderFor("cleanup-controller"),
+		}).SetupWithManager(manager); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Cleanup")
+			os.Exit(1)
+		}
+		if enableKyvernoPolicies {
+			if err = (&kyvernocontroller.Manager{
+				Client:  manager.GetClient(),
+				Log:     ctrl.Log.WithName("controllers").WithName("Kyverno"),
+				Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Scheme:  manager.GetScheme(),
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Kyverno")
+				os.Exit(1)
+			}
+		}
+		if enableVeleroBackups {
+			if err = (&velerocontroller.Manager{
+				Client:    manager.GetClient(),
+				Log:       ctrl.Log.WithName("controllers").WithName("Velero"),
+				Options:   capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Scheme:    manager.GetScheme(),
+				Namespace: veleroNamespace,
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Velero")
+				os.Exit(1)
+			}
+		}
+		if enableKueueQueues {
+			if err = (&kueuecontroller.Manager{
+				Client:  manager.GetClient(),
+				Log:     ctrl.Log.WithName("controllers").WithName("Kueue"),
+				Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Scheme:  manager.GetScheme(),
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Kueue")
+				os.Exit(1)
+			}
+		}
+		if enableFederation {
+			if err = (&federationcontroller.Manager{
+				Client:     manager.GetClient(),
+				Log:        ctrl.Log.WithName("controllers").WithName("Federation"),
+				Options:    capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Scheme:     manager.GetScheme(),
+				Namespace:  namespace,
+				SyncPeriod: federationSyncPeriod,
+				SyncJitter: rateLimiterOptions.Jitter,
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Federation")
+				os.Exit(1)
+			}
+		}
+		if enableChargeback {
+			collector := &chargebackcontroller.Collector{
+				Reader: manager.GetAPIReader(),
+				Log:    ctrl.Log.WithName("controllers").WithName("Chargeback"),
+				Period: chargebackPeriod,
+			}
+			if err = manager.Add(collector); err != nil {
+				setupLog.Error(err, "unable to create chargeback collector")
+				os.Exit(1)
+			}
+			if err = manager.AddMetricsExtraHandler("/chargeback", capsuleserver.Authenticated(manager.GetClient(), collector)); err != nil {
+				setupLog.Error(err, "unable to register chargeback export endpoint")
+				os.Exit(1)
+			}
+		}
+		if usageHistorySize > 0 {
+			if err = manager.Add(&chargebackcontroller.History{
+				Log:    ctrl.Log.WithName("controllers").WithName("UsageHistory"),
+				Period: usageHistoryPeriod,
+				Size:   usageHistorySize,
+			}); err != nil {
+				setupLog.Error(err, "unable to create usage history recorder")
+				os.Exit(1)
+			}
+		}
+		if enableAPIPriorityAndFairness {
+			if err = (&flowcontrolcontroller.Manager{
+				Client:  manager.GetClient(),
+				Log:     ctrl.Log.WithName("controllers").WithName("FlowControl"),
+				Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Scheme:  manager.GetScheme(),
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "FlowControl")
+				os.Exit(1)
+			}
+		}
+		if enableAccessBundles {
+			restConfig := ctrl.GetConfigOrDie()
+
+			caData := restConfig.CAData
+			if len(caData) == 0 && len(restConfig.CAFile) > 0 {
+				if caData, err = ioutil.ReadFile(restConfig.CAFile); err != nil {
+					setupLog.Error(err, "unable to read the API server certificate authority")
+					os.Exit(1)
+				}
+			}
+
+			server := accessBundleServer
+			if len(server) == 0 {
+				server = restConfig.Host
+			}
+
+			if err = (&accessbundlecontroller.Manager{
+				Client:        manager.GetClient(),
+				Log:           ctrl.Log.WithName("controllers").WithName("AccessBundle"),
+				Options:       capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Scheme:        manager.GetScheme(),
+				APIReader:     manager.GetAPIReader(),
+				Namespace:     namespace,
+				Clientset:     clientset,
+				Configuration: cfg,
+				Server:        server,
+				CAData:        caData,
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AccessBundle")
+				os.Exit(1)
+			}
+		}
+		if enablePodGarbageCollection {
+			if err = (&podgccontroller.Manager{
+				Client:  manager.GetClient(),
+				Log:     ctrl.Log.WithName("controllers").WithName("PodGC"),
+				Options: capsuleutils.ControllerOptions(1, rateLimiterOptions),
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "PodGC")
+				os.Exit(1)
+			}
+		}
+		if enableRestrictionsImpact {
+			if err = (&impactcontroller.Manager{
+				Client:   manager.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("Impact"),
+				Options:  capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Recorder: manager.GetEventRecorderFor("impact-controller"),
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Impact")
+				os.Exit(1)
+			}
+		}
+		if enableTenantMapping {
+			if err = (&mappingcontroller.Manager{
+				Client:    manager.GetClient(),
+				Log:       ctrl.Log.WithName("controllers").WithName("Mapping"),
+				Options:   capsuleutils.ControllerOptions(1, rateLimiterOptions),
+				Namespace: namespace,
+			}).SetupWithManager(manager); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Mapping")
+				os.Exit(1)
+			}
+		}
 		if err = (&capsulev1alpha1.Tenant{}).SetupWebhookWithManager(manager); err != nil {
 			setupLog.Error(err, "unable to create conversion webhook", "webhook", "Tenant")
 			os.Exit(1)
@@ -0,0 +1,193 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// replicatedLists are the resources Capsule replicates in each Namespace of a Tenant.
+func replicatedLists() []client.ObjectList {
+	return []client.ObjectList{
+		&networkingv1.NetworkPolicyList{},
+		&corev1.LimitRangeList{},
+		&corev1.ResourceQuotaList{},
+		&rbacv1.RoleBindingList{},
+	}
+}
+
+// Export collects the Tenant and all the Capsule managed state, such as the adopted Namespaces and the
+// replicated resources, into a List of objects cleaned up of any cluster specific field.
+func Export(ctx context.Context, c client.Client, scheme *runtime.Scheme, name string) (list *unstructured.UnstructuredList, err error) {
+	tnt := &capsulev1beta1.Tenant{}
+	if err = c.Get(ctx, types.NamespacedName{Name: name}, tnt); err != nil {
+		return nil, err
+	}
+
+	list = &unstructured.UnstructuredList{}
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+
+	if err = appendObject(list, scheme, tnt); err != nil {
+		return nil, err
+	}
+
+	tenantLabel, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	for _, namespace := range tnt.Status.Namespaces {
+		ns := &corev1.Namespace{}
+		if err = c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+			return nil, err
+		}
+
+		if err = appendObject(list, scheme, ns); err != nil {
+			return nil, err
+		}
+
+		for _, objectList := range replicatedLists() {
+			if err = c.List(ctx, objectList, client.InNamespace(namespace), client.MatchingLabels{tenantLabel: tnt.GetName()}); err != nil {
+				return nil, err
+			}
+
+			var items []runtime.Object
+			if items, err = meta.ExtractList(objectList); err != nil {
+				return nil, err
+			}
+
+			for _, item := range items {
+				if err = appendObject(list, scheme, item.(client.Object)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// Import creates the objects of an exported bundle: the Tenant is created first, then the Namespaces and
+// the replicated resources are bound to it, since the owner references of the source cluster are meaningless.
+func Import(ctx context.Context, c client.Client, scheme *runtime.Scheme, list *unstructured.UnstructuredList) (err error) {
+	var tnt *capsulev1beta1.Tenant
+
+	tenantGVK := capsulev1beta1.GroupVersion.WithKind("Tenant")
+
+	for i := range list.Items {
+		if list.Items[i].GroupVersionKind() != tenantGVK {
+			continue
+		}
+
+		if tnt != nil {
+			return fmt.Errorf("the bundle contains more than a single Tenant")
+		}
+
+		tnt = &capsulev1beta1.Tenant{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, tnt); err != nil {
+			return err
+		}
+	}
+
+	if tnt == nil {
+		return fmt.Errorf("the bundle doesn't contain any Tenant")
+	}
+
+	if err = c.Create(ctx, tnt); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		obj := list.Items[i].DeepCopy()
+
+		if obj.GroupVersionKind() == tenantGVK {
+			continue
+		}
+
+		if err = controllerutil.SetControllerReference(tnt, obj, scheme); err != nil {
+			return err
+		}
+
+		if err = c.Create(ctx, obj); err != nil {
+			// the replicated resources could have been already created by the Tenant controller
+			if apierrors.IsAlreadyExists(err) && obj.GetKind() != "Namespace" {
+				continue
+			}
+
+			return fmt.Errorf("cannot create %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// Marshal returns the YAML representation of the bundle.
+func Marshal(list *unstructured.UnstructuredList) ([]byte, error) {
+	return yaml.Marshal(list.UnstructuredContent())
+}
+
+// Unmarshal decodes a YAML or JSON bundle.
+func Unmarshal(data []byte) (list *unstructured.UnstructuredList, err error) {
+	var content map[string]interface{}
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	list = &unstructured.UnstructuredList{}
+	list.SetUnstructuredContent(content)
+
+	return list, nil
+}
+
+func appendObject(list *unstructured.UnstructuredList, scheme *runtime.Scheme, obj client.Object) (err error) {
+	var gvk schema.GroupVersionKind
+	if gvk, err = apiutil.GVKForObject(obj, scheme); err != nil {
+		return
+	}
+
+	var content map[string]interface{}
+	if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+		return
+	}
+
+	item := unstructured.Unstructured{Object: content}
+	item.SetGroupVersionKind(gvk)
+	clean(&item)
+
+	list.Items = append(list.Items, item)
+
+	return
+}
+
+// clean removes all the fields bound to the source cluster, as the identifiers, the owner references and the status.
+func clean(obj *unstructured.Unstructured) {
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetSelfLink("")
+	obj.SetManagedFields(nil)
+	obj.SetOwnerReferences(nil)
+	obj.SetFinalizers(nil)
+	unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	if obj.GetKind() == "Namespace" {
+		unstructured.RemoveNestedField(obj.Object, "spec")
+	}
+}
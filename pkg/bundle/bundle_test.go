@@ -0,0 +1,84 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestExportImport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	controller := true
+
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "source-uid", ResourceVersion: "42"},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{{Kind: "User", Name: "alice"}},
+		},
+		Status: capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production"}, Size: 1, State: capsulev1beta1.TenantStateActive},
+	}
+	ownerReferences := []metav1.OwnerReference{{APIVersion: "capsule.clastix.io/v1beta1", Kind: "Tenant", Name: "oil", UID: "source-uid", Controller: &controller}}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil-production", Labels: map[string]string{"capsule.clastix.io/tenant": "oil"}, OwnerReferences: ownerReferences},
+	}
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "capsule-oil-0", Namespace: "oil-production", Labels: map[string]string{"capsule.clastix.io/tenant": "oil"}, OwnerReferences: ownerReferences},
+	}
+	unmanaged := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-defined", Namespace: "oil-production"},
+	}
+
+	source := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tnt, ns, np, unmanaged).Build()
+
+	list, err := Export(context.Background(), source, scheme, "oil")
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 3)
+
+	for _, item := range list.Items {
+		assert.Empty(t, item.GetUID())
+		assert.Empty(t, item.GetResourceVersion())
+		assert.Empty(t, item.GetOwnerReferences())
+		_, ok := item.Object["status"]
+		assert.False(t, ok)
+	}
+
+	data, err := Marshal(list)
+	assert.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Len(t, decoded.Items, 3)
+
+	destination := fake.NewClientBuilder().WithScheme(scheme).Build()
+	assert.NoError(t, Import(context.Background(), destination, scheme, decoded))
+
+	imported := &capsulev1beta1.Tenant{}
+	assert.NoError(t, destination.Get(context.Background(), types.NamespacedName{Name: "oil"}, imported))
+	assert.Equal(t, tnt.Spec.Owners, imported.Spec.Owners)
+
+	importedNs := &corev1.Namespace{}
+	assert.NoError(t, destination.Get(context.Background(), types.NamespacedName{Name: "oil-production"}, importedNs))
+	assert.True(t, metav1.IsControlledBy(importedNs, imported))
+
+	importedNp := &networkingv1.NetworkPolicy{}
+	assert.NoError(t, destination.Get(context.Background(), types.NamespacedName{Namespace: "oil-production", Name: "capsule-oil-0"}, importedNp))
+	assert.True(t, metav1.IsControlledBy(importedNp, imported))
+
+	assert.Error(t, Import(context.Background(), destination, scheme, decoded))
+}
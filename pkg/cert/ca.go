@@ -20,6 +20,7 @@ type CA interface {
 	CAPrivateKeyPem() (b *bytes.Buffer, err error)
 	ExpiresIn(now time.Time) (time.Duration, error)
 	ValidateCert(certificate *x509.Certificate) error
+	RenewAt(threshold float64) time.Time
 }
 
 type CapsuleCA struct {
@@ -56,6 +57,10 @@ func (c CapsuleCA) ExpiresIn(now time.Time) (time.Duration, error) {
 	return time.Duration(c.certificate.NotAfter.Unix()-now.Unix()) * time.Second, nil
 }
 
+func (c CapsuleCA) RenewAt(threshold float64) time.Time {
+	return RenewAt(c.certificate, threshold)
+}
+
 func (c CapsuleCA) CACertificatePem() (b *bytes.Buffer, err error) {
 	var crtBytes []byte
 	crtBytes, err = x509.CreateCertificate(rand.Reader, c.certificate, c.certificate, &c.key.PublicKey, c.key)
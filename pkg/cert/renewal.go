@@ -0,0 +1,58 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+)
+
+// RenewAt returns the time when the given certificate should be renewed, once the threshold
+// fraction of its lifetime has elapsed: a threshold out of the (0, 1) range means at its expiration.
+func RenewAt(certificate *x509.Certificate, threshold float64) time.Time {
+	if threshold <= 0 || threshold >= 1 {
+		return certificate.NotAfter
+	}
+
+	lifetime := certificate.NotAfter.Sub(certificate.NotBefore)
+
+	return certificate.NotBefore.Add(time.Duration(float64(lifetime) * threshold))
+}
+
+// MergeBundle returns a CA bundle made of the current CA certificate, followed by the certificates of the
+// existing bundle that are still valid and issued for a different key: this allows the API server to trust
+// the serving certificates signed by the previous CA until they are rolled.
+func MergeBundle(current, existing []byte, now time.Time) []byte {
+	bundle := new(bytes.Buffer)
+	bundle.Write(current)
+
+	var currentKey []byte
+	if block, _ := pem.Decode(current); block != nil {
+		if certificate, err := x509.ParseCertificate(block.Bytes); err == nil {
+			currentKey = certificate.RawSubjectPublicKeyInfo
+		}
+	}
+
+	for rest := existing; len(rest) > 0; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.After(certificate.NotAfter) || bytes.Equal(certificate.RawSubjectPublicKeyInfo, currentKey) {
+			continue
+		}
+
+		_ = pem.Encode(bundle, block)
+	}
+
+	return bundle.Bytes()
+}
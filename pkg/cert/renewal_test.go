@@ -0,0 +1,67 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"bytes"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenewAt(t *testing.T) {
+	ca, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	notBefore := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ca.certificate.NotBefore = notBefore
+	ca.certificate.NotAfter = notBefore.Add(90 * time.Hour)
+
+	assert.Equal(t, notBefore.Add(60*time.Hour), ca.RenewAt(2.0/3.0))
+	assert.Equal(t, ca.certificate.NotAfter, ca.RenewAt(0))
+	assert.Equal(t, ca.certificate.NotAfter, ca.RenewAt(1))
+}
+
+func TestMergeBundle(t *testing.T) {
+	previous, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	current, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	previousPem, err := previous.CACertificatePem()
+	assert.Nil(t, err)
+
+	currentPem, err := current.CACertificatePem()
+	assert.Nil(t, err)
+
+	count := func(bundle []byte) (n int) {
+		for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+			n++
+		}
+
+		return
+	}
+
+	tc := map[string]struct {
+		existing []byte
+		now      time.Time
+		expected int
+	}{
+		"empty":    {nil, time.Now(), 1},
+		"same":     {currentPem.Bytes(), time.Now(), 1},
+		"previous": {previousPem.Bytes(), time.Now(), 2},
+		"expired":  {previousPem.Bytes(), time.Now().AddDate(11, 0, 0), 1},
+	}
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			bundle := MergeBundle(currentPem.Bytes(), c.existing, c.now)
+
+			assert.True(t, bytes.HasPrefix(bundle, currentPem.Bytes()))
+			assert.Equal(t, c.expected, count(bundle))
+		})
+	}
+}
@@ -58,6 +58,25 @@ func (c capsuleConfiguration) ProtectedNamespaceRegexp() (*regexp.Regexp, error)
 	return r, nil
 }
 
+// ProtectedNamespaces returns the protected Namespaces rules, including the protectedNamespaceRegex one.
+func (c capsuleConfiguration) ProtectedNamespaces() ([]ProtectedNamespaceRule, error) {
+	rules, err := compileProtectedNamespaces(c.retrievalFn().Spec.ProtectedNamespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := c.ProtectedNamespaceRegexp()
+	if err != nil {
+		return nil, err
+	}
+
+	if exp != nil {
+		rules = append(rules, ProtectedNamespaceRule{Regexp: exp})
+	}
+
+	return rules, nil
+}
+
 func (c capsuleConfiguration) ForceTenantPrefix() bool {
 	return c.retrievalFn().Spec.ForceTenantPrefix
 }
@@ -66,6 +85,26 @@ func (c capsuleConfiguration) UserGroups() []string {
 	return c.retrievalFn().Spec.UserGroups
 }
 
+func (c capsuleConfiguration) Exemptions() *capsulev1alpha1.ExemptionsSpec {
+	return c.retrievalFn().Spec.Exemptions
+}
+
+func (c capsuleConfiguration) ProtectedNodeTaints() []string {
+	return c.retrievalFn().Spec.ProtectedNodeTaints
+}
+
+func (c capsuleConfiguration) TenantDefaults() *capsulev1alpha1.TenantDefaultsSpec {
+	return c.retrievalFn().Spec.TenantDefaults
+}
+
+func (c capsuleConfiguration) TenantRequestApproverGroups() []string {
+	return c.retrievalFn().Spec.TenantRequestApproverGroups
+}
+
+func (c capsuleConfiguration) Logging() *capsulev1alpha1.LoggingSpec {
+	return c.retrievalFn().Spec.Logging
+}
+
 func (c capsuleConfiguration) hasForbiddenNodeLabelsAnnotations() bool {
 	if _, ok := c.retrievalFn().Annotations[capsulev1alpha1.ForbiddenNodeLabelsAnnotation]; ok {
 		return true
@@ -6,13 +6,20 @@ package configuration
 import (
 	"regexp"
 
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
 )
 
 type Configuration interface {
 	ProtectedNamespaceRegexp() (*regexp.Regexp, error)
+	ProtectedNamespaces() ([]ProtectedNamespaceRule, error)
 	ForceTenantPrefix() bool
 	UserGroups() []string
+	Exemptions() *capsulev1alpha1.ExemptionsSpec
 	ForbiddenUserNodeLabels() *capsulev1beta1.ForbiddenListSpec
 	ForbiddenUserNodeAnnotations() *capsulev1beta1.ForbiddenListSpec
+	ProtectedNodeTaints() []string
+	TenantDefaults() *capsulev1alpha1.TenantDefaultsSpec
+	TenantRequestApproverGroups() []string
+	Logging() *capsulev1alpha1.LoggingSpec
 }
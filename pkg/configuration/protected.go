@@ -0,0 +1,59 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+// ProtectedNamespaceRule is the compiled form of a protected Namespaces rule.
+type ProtectedNamespaceRule struct {
+	Regexp     *regexp.Regexp
+	Exceptions []*regexp.Regexp
+}
+
+// Protects returns true if the given Namespace name matches the rule, and none of its exceptions.
+func (r ProtectedNamespaceRule) Protects(name string) bool {
+	if !r.Regexp.MatchString(name) {
+		return false
+	}
+
+	for _, exception := range r.Exceptions {
+		if exception.MatchString(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compileProtectedNamespaces(specs []capsulev1alpha1.ProtectedNamespaceSpec) ([]ProtectedNamespaceRule, error) {
+	rules := make([]ProtectedNamespaceRule, 0, len(specs))
+
+	for _, spec := range specs {
+		exp, err := regexp.Compile(spec.Regex)
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot compile the protected namespace rule regexp")
+		}
+
+		rule := ProtectedNamespaceRule{Regexp: exp}
+
+		for _, exception := range spec.Exceptions {
+			exp, err = regexp.Compile(exception)
+			if err != nil {
+				return nil, errors.Wrap(err, "Cannot compile the protected namespace rule exception regexp")
+			}
+
+			rule.Exceptions = append(rule.Exceptions, exp)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+func TestProtectedNamespaceRule(t *testing.T) {
+	rules, err := compileProtectedNamespaces([]capsulev1alpha1.ProtectedNamespaceSpec{
+		{Regex: `^kube-.*`},
+		{Regex: `^platform-.*`, Exceptions: []string{`^platform-sandbox$`}},
+	})
+	assert.Nil(t, err)
+
+	protected := func(name string) bool {
+		for _, rule := range rules {
+			if rule.Protects(name) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	assert.True(t, protected("kube-system"))
+	assert.True(t, protected("platform-monitoring"))
+	assert.False(t, protected("platform-sandbox"))
+	assert.False(t, protected("oil-production"))
+
+	_, err = compileProtectedNamespaces([]capsulev1alpha1.ProtectedNamespaceSpec{{Regex: `^kube-.*`, Exceptions: []string{`(`}}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,297 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package envtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"strconv"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/config"
+	configcontroller "github.com/clastix/capsule/controllers/config"
+	rbaccontroller "github.com/clastix/capsule/controllers/rbac"
+	tenantcontroller "github.com/clastix/capsule/controllers/tenant"
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/indexer"
+	capsuleutils "github.com/clastix/capsule/pkg/utils"
+	"github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/webhooks"
+)
+
+// ConfigurationName is the name of the CapsuleConfiguration created by the Environment.
+const ConfigurationName = "default"
+
+// Environment runs the Capsule webhooks and controllers against a control plane started by envtest, allowing the
+// distributions embedding Capsule to test their Tenant profiles against the actual admission logic.
+// The etcd and kube-apiserver binaries are located as for any envtest suite, such as through the KUBEBUILDER_ASSETS
+// environment variable.
+type Environment struct {
+	// Configuration is the spec of the CapsuleConfiguration created upon start. Optional, defaults to the
+	// capsule.clastix.io user group.
+	Configuration *capsulev1alpha1.CapsuleConfigurationSpec
+	// ControlPlane customizes the envtest one, such as its binary assets directory. Optional.
+	ControlPlane *envtest.Environment
+
+	// Config, Scheme and Client give access to the control plane as cluster administrator once started.
+	Config *rest.Config
+	Scheme *runtime.Scheme
+	Client client.Client
+
+	cancel context.CancelFunc
+	errCh  chan error
+}
+
+// Start starts the control plane, installs the Capsule CustomResourceDefinitions and webhooks, then runs the
+// Capsule manager until the webhooks are served.
+func (e *Environment) Start() (err error) {
+	if e.Scheme == nil {
+		e.Scheme = runtime.NewScheme()
+		for _, addToScheme := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, apiextensionsv1.AddToScheme, capsulev1alpha1.AddToScheme, capsulev1beta1.AddToScheme} {
+			if err = addToScheme(e.Scheme); err != nil {
+				return err
+			}
+		}
+	}
+
+	crds, mutating, validating, err := manifests()
+	if err != nil {
+		return err
+	}
+
+	if e.ControlPlane == nil {
+		e.ControlPlane = &envtest.Environment{}
+	}
+	e.ControlPlane.CRDInstallOptions.Scheme = e.Scheme
+	e.ControlPlane.CRDInstallOptions.CRDs = append(e.ControlPlane.CRDInstallOptions.CRDs, crds...)
+	e.ControlPlane.WebhookInstallOptions.MutatingWebhooks = append(e.ControlPlane.WebhookInstallOptions.MutatingWebhooks, mutating...)
+	e.ControlPlane.WebhookInstallOptions.ValidatingWebhooks = append(e.ControlPlane.WebhookInstallOptions.ValidatingWebhooks, validating...)
+
+	if e.Config, err = e.ControlPlane.Start(); err != nil {
+		return fmt.Errorf("cannot start the control plane: %w", err)
+	}
+
+	if e.Client, err = client.New(e.Config, client.Options{Scheme: e.Scheme}); err != nil {
+		return err
+	}
+
+	spec := capsulev1alpha1.CapsuleConfigurationSpec{UserGroups: []string{capsulev1beta1.GroupVersion.Group}}
+	if e.Configuration != nil {
+		spec = *e.Configuration
+	}
+
+	if err = e.Client.Create(context.Background(), &capsulev1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigurationName},
+		Spec:       spec,
+	}); err != nil {
+		return fmt.Errorf("cannot create the CapsuleConfiguration: %w", err)
+	}
+
+	kubeVersion, err := serverVersion(e.Config)
+	if err != nil {
+		return err
+	}
+
+	webhookOptions := e.ControlPlane.WebhookInstallOptions
+
+	manager, err := ctrl.NewManager(e.Config, ctrl.Options{
+		Scheme:             e.Scheme,
+		MetricsBindAddress: "0",
+		Host:               webhookOptions.LocalServingHost,
+		Port:               webhookOptions.LocalServingPort,
+		CertDir:            webhookOptions.LocalServingCertDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	var ctx context.Context
+	ctx, e.cancel = context.WithCancel(context.Background())
+
+	if err = e.setup(ctx, manager, kubeVersion); err != nil {
+		return err
+	}
+
+	e.errCh = make(chan error, 1)
+	go func() {
+		e.errCh <- manager.Start(ctx)
+	}()
+
+	if !manager.GetCache().WaitForCacheSync(ctx) {
+		return fmt.Errorf("cannot sync the Capsule manager cache")
+	}
+
+	address := net.JoinHostPort(webhookOptions.LocalServingHost, strconv.Itoa(webhookOptions.LocalServingPort))
+
+	return wait.PollImmediate(100*time.Millisecond, 30*time.Second, func() (bool, error) {
+		//nolint:gosec
+		conn, err := tls.Dial("tcp", address, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return false, nil
+		}
+
+		return true, conn.Close()
+	})
+}
+
+// setup registers the Capsule webhooks and controllers, as the manager does.
+func (e *Environment) setup(ctx context.Context, manager ctrl.Manager, kubeVersion *version.Version) (err error) {
+	log := ctrl.Log.WithName("envtest")
+
+	cfg := configuration.NewCapsuleConfiguration(manager.GetClient(), ConfigurationName)
+
+	if err = webhook.Register(manager, e.ControlPlane.WebhookInstallOptions.LocalServingCertDir, log.WithName("Router"), cfg, nil, nil, webhooks.List(cfg, kubeVersion, manager.GetAPIReader())...); err != nil {
+		return err
+	}
+
+	if err = (&capsulev1alpha1.Tenant{}).SetupWebhookWithManager(manager); err != nil {
+		return err
+	}
+
+	rbacManager := &rbaccontroller.Manager{
+		Log:           log.WithName("Rbac"),
+		Configuration: cfg,
+	}
+	if err = manager.Add(rbacManager); err != nil {
+		return err
+	}
+	if err = rbacManager.SetupWithManager(manager, ConfigurationName); err != nil {
+		return err
+	}
+
+	if err = (&configcontroller.Manager{
+		Log: log.WithName("CapsuleConfiguration"),
+	}).SetupWithManager(manager, ConfigurationName); err != nil {
+		return err
+	}
+
+	if err = (&tenantcontroller.Manager{
+		Client:        manager.GetClient(),
+		Log:           log.WithName("Tenant"),
+		Scheme:        manager.GetScheme(),
+		Recorder:      manager.GetEventRecorderFor("tenant-controller"),
+		Configuration: cfg,
+		Options: capsuleutils.ControllerOptions(1, capsuleutils.RateLimiterOptions{
+			BaseDelay: 5 * time.Millisecond,
+			MaxDelay:  10 * time.Second,
+			QPS:       10,
+			Burst:     100,
+		}),
+	}).SetupWithManager(manager); err != nil {
+		return err
+	}
+
+	return indexer.AddToManager(ctx, log, manager)
+}
+
+// Stop stops the Capsule manager and the control plane.
+func (e *Environment) Stop() error {
+	if e.cancel != nil {
+		e.cancel()
+
+		if err := <-e.errCh; err != nil {
+			return err
+		}
+	}
+
+	return e.ControlPlane.Stop()
+}
+
+// OwnerClient returns a client impersonating the given Tenant owner as a member of the Capsule user groups,
+// so that its requests go through the Capsule admission logic.
+func (e *Environment) OwnerClient(owner capsulev1beta1.OwnerSpec) (client.Client, error) {
+	groups := []string{capsulev1beta1.GroupVersion.Group}
+	if e.Configuration != nil {
+		groups = append([]string{}, e.Configuration.UserGroups...)
+	}
+	if owner.Kind == capsulev1beta1.GroupOwner {
+		groups = append(groups, owner.Name)
+	}
+
+	c := rest.CopyConfig(e.Config)
+	c.Impersonate = rest.ImpersonationConfig{
+		UserName: owner.Name,
+		Groups:   groups,
+	}
+
+	return client.New(c, client.Options{Scheme: e.Scheme})
+}
+
+func serverVersion(config *rest.Config) (*version.Version, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	return version.ParseGeneric(v.String())
+}
+
+// manifests decodes the embedded CustomResourceDefinitions and webhook configurations.
+func manifests() (crds, mutating, validating []client.Object, err error) {
+	err = fs.WalkDir(config.Manifests, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		b, err := config.Manifests.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+
+		for {
+			obj := &unstructured.Unstructured{}
+			if err = decoder.Decode(&obj.Object); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+
+				return fmt.Errorf("cannot decode %s: %w", path, err)
+			}
+
+			switch obj.GetKind() {
+			case "CustomResourceDefinition":
+				crd := &apiextensionsv1.CustomResourceDefinition{}
+				if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+					return err
+				}
+
+				crds = append(crds, crd)
+			case "MutatingWebhookConfiguration":
+				mutating = append(mutating, obj)
+			case "ValidatingWebhookConfiguration":
+				validating = append(validating, obj)
+			}
+		}
+	})
+
+	return crds, mutating, validating, err
+}
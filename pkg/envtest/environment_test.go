@@ -0,0 +1,44 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package envtest_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/pkg/envtest"
+)
+
+// The Tenant profile of a distribution allows a single Namespace: the second one created by the owner is denied.
+func TestEnvironment(t *testing.T) {
+	if len(os.Getenv("KUBEBUILDER_ASSETS")) == 0 {
+		t.Skip("KUBEBUILDER_ASSETS is not set, skipping the envtest control plane")
+	}
+
+	env := &envtest.Environment{}
+	if !assert.NoError(t, env.Start()) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, env.Stop())
+	}()
+
+	tnt := envtest.NewTenant("oil").WithUser("alice").WithNamespaceQuota(1).Build()
+	if !assert.NoError(t, env.Client.Create(context.Background(), tnt)) {
+		return
+	}
+
+	alice, err := env.OwnerClient(tnt.Spec.Owners[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, alice.Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-production"}}))
+	assert.Error(t, alice.Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-development"}}))
+}
@@ -0,0 +1,121 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package envtest
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// TenantBuilder builds the Tenants of the test cases, as in:
+//
+//	tnt := envtest.NewTenant("oil").WithUser("alice").WithNamespaceQuota(3).Build()
+type TenantBuilder struct {
+	tenant *capsulev1beta1.Tenant
+}
+
+// NewTenant returns a builder of a Tenant with the given name.
+func NewTenant(name string) *TenantBuilder {
+	return &TenantBuilder{
+		tenant: &capsulev1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		},
+	}
+}
+
+func (b *TenantBuilder) withOwner(kind capsulev1beta1.OwnerKind, name string) *TenantBuilder {
+	b.tenant.Spec.Owners = append(b.tenant.Spec.Owners, capsulev1beta1.OwnerSpec{Kind: kind, Name: name})
+
+	return b
+}
+
+// WithUser adds a User owner.
+func (b *TenantBuilder) WithUser(name string) *TenantBuilder {
+	return b.withOwner(capsulev1beta1.UserOwner, name)
+}
+
+// WithGroup adds a Group owner.
+func (b *TenantBuilder) WithGroup(name string) *TenantBuilder {
+	return b.withOwner(capsulev1beta1.GroupOwner, name)
+}
+
+// WithServiceAccount adds a ServiceAccount owner, in the system:serviceaccount:<namespace>:<name> form.
+func (b *TenantBuilder) WithServiceAccount(name string) *TenantBuilder {
+	return b.withOwner(capsulev1beta1.ServiceAccountOwner, name)
+}
+
+// WithNamespaceQuota sets the maximum number of Namespaces of the Tenant.
+func (b *TenantBuilder) WithNamespaceQuota(quota int32) *TenantBuilder {
+	if b.tenant.Spec.NamespaceOptions == nil {
+		b.tenant.Spec.NamespaceOptions = &capsulev1beta1.NamespaceOptions{}
+	}
+	b.tenant.Spec.NamespaceOptions.Quota = &quota
+
+	return b
+}
+
+// WithNodeSelector sets the node selector of the Tenant Namespaces.
+func (b *TenantBuilder) WithNodeSelector(selector map[string]string) *TenantBuilder {
+	b.tenant.Spec.NodeSelector = selector
+
+	return b
+}
+
+// WithStorageClasses sets the StorageClasses allowed to the Tenant.
+func (b *TenantBuilder) WithStorageClasses(allowed ...string) *TenantBuilder {
+	b.tenant.Spec.StorageClasses = &capsulev1beta1.AllowedListSpec{Exact: allowed}
+
+	return b
+}
+
+// WithIngressClasses sets the IngressClasses allowed to the Tenant.
+func (b *TenantBuilder) WithIngressClasses(allowed ...string) *TenantBuilder {
+	b.tenant.Spec.IngressOptions.AllowedClasses = &capsulev1beta1.AllowedListSpec{Exact: allowed}
+
+	return b
+}
+
+// WithContainerRegistries sets the container registries allowed to the Tenant.
+func (b *TenantBuilder) WithContainerRegistries(allowed ...string) *TenantBuilder {
+	b.tenant.Spec.ContainerRegistries = &capsulev1beta1.AllowedListSpec{Exact: allowed}
+
+	return b
+}
+
+// WithResourceQuota adds a ResourceQuota item, with the given scope.
+func (b *TenantBuilder) WithResourceQuota(scope capsulev1beta1.ResourceQuotaScope, hard corev1.ResourceList) *TenantBuilder {
+	b.tenant.Spec.ResourceQuota.Scope = scope
+	b.tenant.Spec.ResourceQuota.Items = append(b.tenant.Spec.ResourceQuota.Items, corev1.ResourceQuotaSpec{Hard: hard})
+
+	return b
+}
+
+// WithLimitRange adds a LimitRange item.
+func (b *TenantBuilder) WithLimitRange(spec corev1.LimitRangeSpec) *TenantBuilder {
+	b.tenant.Spec.LimitRanges.Items = append(b.tenant.Spec.LimitRanges.Items, spec)
+
+	return b
+}
+
+// WithNetworkPolicy adds a NetworkPolicy item.
+func (b *TenantBuilder) WithNetworkPolicy(spec networkingv1.NetworkPolicySpec) *TenantBuilder {
+	b.tenant.Spec.NetworkPolicies.Items = append(b.tenant.Spec.NetworkPolicies.Items, spec)
+
+	return b
+}
+
+// WithSpec applies any further change to the Tenant spec.
+func (b *TenantBuilder) WithSpec(fn func(spec *capsulev1beta1.TenantSpec)) *TenantBuilder {
+	fn(&b.tenant.Spec)
+
+	return b
+}
+
+// Build returns a copy of the built Tenant.
+func (b *TenantBuilder) Build() *capsulev1beta1.Tenant {
+	return b.tenant.DeepCopy()
+}
@@ -0,0 +1,51 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package envtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestTenantBuilder(t *testing.T) {
+	b := NewTenant("oil").
+		WithUser("alice").
+		WithGroup("oil-admins").
+		WithNamespaceQuota(3).
+		WithStorageClasses("ceph-rbd").
+		WithResourceQuota(capsulev1beta1.ResourceQuotaScopeTenant, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")})
+
+	tnt := b.Build()
+	assert.Equal(t, "oil", tnt.GetName())
+	assert.Equal(t, capsulev1beta1.OwnerListSpec{
+		{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+		{Kind: capsulev1beta1.GroupOwner, Name: "oil-admins"},
+	}, tnt.Spec.Owners)
+	assert.Equal(t, int32(3), *tnt.Spec.NamespaceOptions.Quota)
+	assert.Equal(t, []string{"ceph-rbd"}, tnt.Spec.StorageClasses.Exact)
+	assert.Equal(t, capsulev1beta1.ResourceQuotaScopeTenant, tnt.Spec.ResourceQuota.Scope)
+	assert.Len(t, tnt.Spec.ResourceQuota.Items, 1)
+	// the built Tenants are not affected by the further changes
+	b.WithUser("bob")
+	assert.Len(t, tnt.Spec.Owners, 2)
+}
+
+func TestManifests(t *testing.T) {
+	crds, mutating, validating, err := manifests()
+	assert.NoError(t, err)
+
+	var names []string
+	for _, crd := range crds {
+		names = append(names, crd.GetName())
+	}
+
+	assert.ElementsMatch(t, []string{"tenants.capsule.clastix.io", "capsuleconfigurations.capsule.clastix.io", "tenantwebhookconfigurations.capsule.clastix.io", "tenantclasses.capsule.clastix.io", "tenantrequests.capsule.clastix.io", "tenantexceptions.capsule.clastix.io"}, names)
+	assert.Len(t, mutating, 1)
+	assert.Len(t, validating, 1)
+}
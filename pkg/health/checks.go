@@ -0,0 +1,66 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const cacheSyncTimeout = time.Second
+
+// WebhookCertificate ensures the webhook server has a valid serving certificate to load,
+// preventing a replica from receiving admission traffic before the TLS Secret has been mounted.
+func WebhookCertificate(server *webhook.Server) healthz.Checker {
+	return func(*http.Request) error {
+		crt, err := servingCertificate(server)
+		if err != nil {
+			return err
+		}
+
+		if now := time.Now(); now.Before(crt.NotBefore) || now.After(crt.NotAfter) {
+			return fmt.Errorf("the webhook serving certificate is not valid at %s", now.Format(time.RFC3339))
+		}
+
+		return nil
+	}
+}
+
+func servingCertificate(server *webhook.Server) (*x509.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(server.CertDir, server.CertName), filepath.Join(server.CertDir, server.KeyName))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the webhook serving certificate: %w", err)
+	}
+
+	crt, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the webhook serving certificate: %w", err)
+	}
+
+	return crt, nil
+}
+
+// CacheSynced ensures the informers used by the webhook handlers to retrieve the Tenants and the
+// CapsuleConfiguration have been synced, so admission decisions are not taken on a partial state.
+func CacheSynced(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+
+		if !c.WaitForCacheSync(ctx) {
+			return fmt.Errorf("the informer caches are not synced yet")
+		}
+
+		return nil
+	}
+}
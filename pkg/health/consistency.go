@@ -0,0 +1,131 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const caCertificateKey = "tls.crt"
+
+// ServingCertificateTrusted ensures the serving certificate loaded by the webhook server
+// has been signed by the CA stored in the given Secret.
+func ServingCertificateTrusted(c client.Reader, server *webhook.Server, caSecret types.NamespacedName) healthz.Checker {
+	return func(req *http.Request) error {
+		_, ca, err := caCertificate(req.Context(), c, caSecret)
+		if err != nil {
+			return err
+		}
+
+		serving, err := servingCertificate(server)
+		if err != nil {
+			return err
+		}
+
+		pool := x509.NewCertPool()
+		pool.AddCert(ca)
+
+		if _, err = serving.Verify(x509.VerifyOptions{Roots: pool, CurrentTime: time.Now()}); err != nil {
+			return fmt.Errorf("the webhook serving certificate is not signed by the Capsule CA: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// CABundle ensures the CA stored in the given Secret is trusted by all the webhooks
+// of the given Mutating and Validating webhook configurations referring to a Service.
+func CABundle(c client.Reader, caSecret types.NamespacedName, mutating, validating string) healthz.Checker {
+	return func(req *http.Request) error {
+		caPem, _, err := caCertificate(req.Context(), c, caSecret)
+		if err != nil {
+			return err
+		}
+
+		mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err = c.Get(req.Context(), types.NamespacedName{Name: mutating}, mwc); err != nil {
+			return fmt.Errorf("cannot retrieve the MutatingWebhookConfiguration %s: %w", mutating, err)
+		}
+
+		for _, wh := range mwc.Webhooks {
+			if wh.ClientConfig.Service != nil && !bytes.Contains(wh.ClientConfig.CABundle, caPem) {
+				return fmt.Errorf("the CA bundle of the %s webhook doesn't contain the Capsule CA", wh.Name)
+			}
+		}
+
+		vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err = c.Get(req.Context(), types.NamespacedName{Name: validating}, vwc); err != nil {
+			return fmt.Errorf("cannot retrieve the ValidatingWebhookConfiguration %s: %w", validating, err)
+		}
+
+		for _, wh := range vwc.Webhooks {
+			if wh.ClientConfig.Service != nil && !bytes.Contains(wh.ClientConfig.CABundle, caPem) {
+				return fmt.Errorf("the CA bundle of the %s webhook doesn't contain the Capsule CA", wh.Name)
+			}
+		}
+
+		return nil
+	}
+}
+
+// CustomResourceDefinitions ensures the given CustomResourceDefinitions are installed and established.
+func CustomResourceDefinitions(c client.Reader, names ...string) healthz.Checker {
+	return func(req *http.Request) error {
+		for _, name := range names {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := c.Get(req.Context(), types.NamespacedName{Name: name}, crd); err != nil {
+				return fmt.Errorf("cannot retrieve the CustomResourceDefinition %s: %w", name, err)
+			}
+
+			var established bool
+
+			for _, condition := range crd.Status.Conditions {
+				if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+					established = true
+				}
+			}
+
+			if !established {
+				return fmt.Errorf("the CustomResourceDefinition %s is not established", name)
+			}
+		}
+
+		return nil
+	}
+}
+
+func caCertificate(ctx context.Context, c client.Reader, caSecret types.NamespacedName) ([]byte, *x509.Certificate, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, caSecret, secret); err != nil {
+		return nil, nil, fmt.Errorf("cannot retrieve the Capsule CA Secret: %w", err)
+	}
+
+	caPem := secret.Data[caCertificateKey]
+
+	block, _ := pem.Decode(caPem)
+	if block == nil {
+		return nil, nil, fmt.Errorf("the Capsule CA has not been generated yet")
+	}
+
+	ca, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse the Capsule CA: %w", err)
+	}
+
+	return caPem, ca, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// GracefulShutdown delays by the given duration the cancellation of the returned context once the parent one is done,
+// reporting the replica as not ready in the meanwhile: the Endpoints of the webhook Service are updated before the
+// webhook server stops, and the admission requests are served by the other replicas during a rolling upgrade.
+func GracefulShutdown(parent context.Context, delay time.Duration) (context.Context, healthz.Checker) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var draining int32
+
+	go func() {
+		<-parent.Done()
+
+		atomic.StoreInt32(&draining, 1)
+		time.Sleep(delay)
+
+		cancel()
+	}()
+
+	return ctx, func(*http.Request) error {
+		if atomic.LoadInt32(&draining) == 1 {
+			return fmt.Errorf("the replica is shutting down")
+		}
+
+		return nil
+	}
+}
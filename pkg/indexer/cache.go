@@ -0,0 +1,42 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package indexer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// CacheSelectors restricts the informers of the resources replicated by Capsule to the ones labelled with the
+// Tenant name, and the Secrets to the Capsule Namespace, reducing the memory footprint on clusters with
+// thousands of Namespaces: the other Secrets must be retrieved through the API reader of the manager, as the
+// access bundles and the Secret count of the Tenants do.
+func CacheSelectors(namespace string) (cache.SelectorsByObject, error) {
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := labels.NewRequirement(tenantLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantSelector := labels.NewSelector().Add(*exists)
+
+	return cache.SelectorsByObject{
+		&corev1.LimitRange{}:          {Label: tenantSelector},
+		&corev1.ResourceQuota{}:       {Label: tenantSelector},
+		&networkingv1.NetworkPolicy{}: {Label: tenantSelector},
+		&rbacv1.RoleBinding{}:         {Label: tenantSelector},
+		&corev1.Secret{}:              {Field: fields.OneTermEqualSelector("metadata.namespace", namespace)},
+	}, nil
+}
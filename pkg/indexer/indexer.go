@@ -41,7 +41,7 @@ func AddToManager(ctx context.Context, log logr.Logger, mgr manager.Manager) err
 		if err := mgr.GetFieldIndexer().IndexField(ctx, f.Object(), f.Field(), f.Func()); err != nil {
 			missingAPIError := &meta.NoKindMatchError{}
 			if errors.As(err, &missingAPIError) {
-				log.Info(fmt.Sprintf("skipping setup of Indexer %T for object %T", f, f.Object()), "error", err.Error())
+				log.Info("Skipping setup of the Indexer, the API is not available", "indexer", fmt.Sprintf("%T", f), "object", fmt.Sprintf("%T", f.Object()), "error", err.Error())
 
 				continue
 			}
@@ -0,0 +1,131 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+// MaxVerbosity is the highest verbosity of the Capsule logs.
+const MaxVerbosity = 10
+
+// Levels holds the verbosity of the Capsule logs, as the highest V level emitted: the one set by the flags is
+// overridden by the CapsuleConfiguration one, the named loggers and the Tenants ones.
+type Levels struct {
+	mu sync.RWMutex
+
+	flag    int
+	level   *int
+	loggers map[string]int
+	tenants map[string]int
+}
+
+// NewLevels returns the Levels enabling the logs enabled by the given zap level, as set by the --zap-log-level flag.
+func NewLevels(enabler zapcore.LevelEnabler) *Levels {
+	flag := -1
+
+	for v := 0; v <= MaxVerbosity && enabler.Enabled(zapcore.Level(-v)); v++ {
+		flag = v
+	}
+
+	return &Levels{flag: flag}
+}
+
+// ParseLevel returns the verbosity of the given level: error enables the error logs only, info and debug enable the
+// V(0) and V(1) logs, while a number enables the logs up to its V level.
+func ParseLevel(level capsulev1alpha1.LogLevel) (int, error) {
+	switch strings.ToLower(string(level)) {
+	case "error":
+		return -1, nil
+	case "info":
+		return 0, nil
+	case "debug":
+		return 1, nil
+	}
+
+	v, err := strconv.Atoi(string(level))
+	if err != nil || v < 0 || v > MaxVerbosity {
+		return 0, fmt.Errorf("invalid log level %s, expected one of error, info, debug or a verbosity up to %d", level, MaxVerbosity)
+	}
+
+	return v, nil
+}
+
+// Set replaces the configured levels with the given ones, resetting them to the flag one when nil:
+// the current levels are kept if any of the given ones is not valid.
+func (l *Levels) Set(spec *capsulev1alpha1.LoggingSpec) (err error) {
+	var level *int
+
+	loggers, tenants := map[string]int{}, map[string]int{}
+
+	if spec != nil {
+		if len(spec.Level) > 0 {
+			var v int
+			if v, err = ParseLevel(spec.Level); err != nil {
+				return
+			}
+
+			level = &v
+		}
+
+		for name, value := range spec.Loggers {
+			if loggers[name], err = ParseLevel(value); err != nil {
+				return fmt.Errorf("logger %s: %w", name, err)
+			}
+		}
+
+		for name, value := range spec.Tenants {
+			if tenants[name], err = ParseLevel(value); err != nil {
+				return fmt.Errorf("tenant %s: %w", name, err)
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.level, l.loggers, l.tenants = level, loggers, tenants
+
+	return nil
+}
+
+// Enabled reports if the log with the given V level is emitted by the named logger for the given Tenant, if any.
+func (l *Levels) Enabled(name, tenant string, verbosity int) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return verbosity <= l.verbosity(name, tenant)
+}
+
+func (l *Levels) verbosity(name, tenant string) int {
+	if v, ok := l.tenants[tenant]; ok && len(tenant) > 0 {
+		return v
+	}
+	// the most specific logger name wins, as controllers.Tenant over controllers
+	for current := name; len(current) > 0; {
+		if v, ok := l.loggers[current]; ok {
+			return v
+		}
+
+		i := strings.LastIndex(current, ".")
+		if i < 0 {
+			break
+		}
+
+		current = current[:i]
+	}
+
+	if l.level != nil {
+		return *l.level
+	}
+
+	return l.flag
+}
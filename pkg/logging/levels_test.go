@@ -0,0 +1,69 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+func TestNewLevels(t *testing.T) {
+	assert.Equal(t, -1, NewLevels(zapcore.ErrorLevel).flag)
+	assert.Equal(t, 0, NewLevels(zapcore.InfoLevel).flag)
+	assert.Equal(t, 1, NewLevels(zapcore.DebugLevel).flag)
+	assert.Equal(t, 4, NewLevels(zapcore.Level(-4)).flag)
+}
+
+func TestParseLevel(t *testing.T) {
+	for level, expected := range map[capsulev1alpha1.LogLevel]int{"error": -1, "info": 0, "debug": 1, "5": 5} {
+		v, err := ParseLevel(level)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, v)
+	}
+
+	for _, level := range []capsulev1alpha1.LogLevel{"warn", "-1", "11"} {
+		_, err := ParseLevel(level)
+		assert.Error(t, err)
+	}
+}
+
+func TestLevels(t *testing.T) {
+	levels := NewLevels(zapcore.InfoLevel)
+
+	assert.True(t, levels.Enabled("controllers.Tenant", "oil", 0))
+	assert.False(t, levels.Enabled("controllers.Tenant", "oil", 1))
+
+	assert.NoError(t, levels.Set(&capsulev1alpha1.LoggingSpec{
+		Level:   "error",
+		Loggers: map[string]capsulev1alpha1.LogLevel{"controllers": "info", "controllers.Tenant": "debug"},
+		Tenants: map[string]capsulev1alpha1.LogLevel{"oil": "4"},
+	}))
+
+	assert.False(t, levels.Enabled("webhooks.Router", "", 0))
+	assert.True(t, levels.Enabled("controllers.Velero", "gas", 0))
+	assert.False(t, levels.Enabled("controllers.Velero", "gas", 1))
+	assert.True(t, levels.Enabled("controllers.Tenant", "gas", 1))
+	assert.True(t, levels.Enabled("webhooks.Router", "oil", 4))
+	assert.False(t, levels.Enabled("webhooks.Router", "oil", 5))
+
+	assert.Error(t, levels.Set(&capsulev1alpha1.LoggingSpec{Tenants: map[string]capsulev1alpha1.LogLevel{"oil": "trace"}}))
+	assert.True(t, levels.Enabled("webhooks.Router", "oil", 4))
+
+	assert.NoError(t, levels.Set(nil))
+	assert.True(t, levels.Enabled("webhooks.Router", "oil", 0))
+	assert.False(t, levels.Enabled("webhooks.Router", "oil", 1))
+}
+
+func TestTenantValue(t *testing.T) {
+	tenant, ok := tenantValue([]interface{}{"namespace", "oil-dev", TenantKey, "oil"})
+	assert.True(t, ok)
+	assert.Equal(t, "oil", tenant)
+
+	_, ok = tenantValue([]interface{}{"namespace", "oil-dev", TenantKey})
+	assert.False(t, ok)
+}
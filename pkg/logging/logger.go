@@ -0,0 +1,105 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+// TenantKey is the key of the logged values referring to a Tenant, used to apply the Tenant levels.
+const TenantKey = "tenant"
+
+// logger filters the logs of the given sink, enabling all the V levels, according to the Levels
+// of its name and of the Tenant it refers to.
+type logger struct {
+	sink      logr.Logger
+	levels    *Levels
+	name      string
+	tenant    string
+	verbosity int
+}
+
+// New returns a logger emitting the logs of the given sink, enabling all the V levels up to MaxVerbosity,
+// according to the given levels.
+func New(sink logr.Logger, levels *Levels) logr.Logger {
+	// skipping the frame of the logger when reporting the caller
+	return &logger{sink: logr.WithCallDepth(sink, 1), levels: levels}
+}
+
+func (l *logger) Enabled() bool {
+	return l.levels.Enabled(l.name, l.tenant, l.verbosity) && l.sink.Enabled()
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	tenant := l.tenant
+	if value, ok := tenantValue(keysAndValues); ok {
+		tenant = value
+	}
+
+	if !l.levels.Enabled(l.name, tenant, l.verbosity) {
+		return
+	}
+
+	l.sink.Info(msg, keysAndValues...)
+}
+
+func (l *logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.sink.Error(err, msg, keysAndValues...)
+}
+
+func (l *logger) V(level int) logr.Logger {
+	c := *l
+	c.sink = l.sink.V(level)
+	c.verbosity += level
+
+	return &c
+}
+
+func (l *logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	c := *l
+	c.sink = l.sink.WithValues(keysAndValues...)
+
+	if value, ok := tenantValue(keysAndValues); ok {
+		c.tenant = value
+	}
+
+	return &c
+}
+
+func (l *logger) WithName(name string) logr.Logger {
+	c := *l
+	c.sink = l.sink.WithName(name)
+
+	if len(l.name) > 0 {
+		c.name = l.name + "." + name
+	} else {
+		c.name = name
+	}
+
+	return &c
+}
+
+func (l *logger) WithCallDepth(depth int) logr.Logger {
+	c := *l
+	c.sink = logr.WithCallDepth(l.sink, depth)
+
+	return &c
+}
+
+var _ logr.CallDepthLogger = &logger{}
+
+// tenantValue returns the value of the last Tenant key among the given ones, if any.
+func tenantValue(keysAndValues []interface{}) (tenant string, ok bool) {
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] != TenantKey {
+			continue
+		}
+
+		tenant, ok = fmt.Sprint(keysAndValues[i+1]), true
+	}
+
+	return
+}
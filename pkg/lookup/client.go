@@ -0,0 +1,51 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+const namespacesField = ".status.namespaces"
+
+// Client serves the Tenant lookups by Namespace from the TenantIndex, falling back to the
+// embedded Client for any other request or when the index is stale.
+type Client struct {
+	client.Client
+	Index *TenantIndex
+}
+
+func (c Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if tntList, ok := list.(*capsulev1beta1.TenantList); ok && c.Index != nil {
+		if namespace, ok := namespaceLookup(opts...); ok {
+			if items, fresh := c.Index.ForNamespace(namespace); fresh {
+				tntList.Items = items
+
+				return nil
+			}
+		}
+	}
+
+	return c.Client.List(ctx, list, opts...)
+}
+
+// namespaceLookup returns the Namespace if the given options are selecting the Tenants only by Namespace.
+func namespaceLookup(opts ...client.ListOption) (string, bool) {
+	options := &client.ListOptions{}
+	options.ApplyOptions(opts)
+
+	if options.FieldSelector == nil || options.LabelSelector != nil || len(options.Namespace) > 0 || options.Limit > 0 {
+		return "", false
+	}
+
+	if len(options.FieldSelector.Requirements()) != 1 {
+		return "", false
+	}
+
+	return options.FieldSelector.RequiresExactMatch(namespacesField)
+}
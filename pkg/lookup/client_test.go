@@ -0,0 +1,85 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestClient_List(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	oil := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Status:     capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production", "oil-development"}},
+	}
+	gas := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "gas"},
+		Status:     capsulev1beta1.TenantStatus{Namespaces: []string{"gas-production"}},
+	}
+
+	index := &TenantIndex{MaxStaleness: time.Minute}
+	index.set(oil)
+	index.set(gas)
+
+	byNamespace := func(ns string) client.ListOption {
+		return client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector(namespacesField, ns)}
+	}
+
+	c := Client{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(oil, gas).Build(), Index: index}
+
+	t.Run("stale index", func(t *testing.T) {
+		tntList := &capsulev1beta1.TenantList{}
+		assert.NoError(t, c.List(context.Background(), tntList))
+		assert.Len(t, tntList.Items, 2)
+
+		_, fresh := index.ForNamespace("oil-production")
+		assert.False(t, fresh)
+	})
+
+	index.lastSync = time.Now()
+
+	t.Run("namespace lookup", func(t *testing.T) {
+		tntList := &capsulev1beta1.TenantList{}
+		assert.NoError(t, c.List(context.Background(), tntList, byNamespace("oil-development")))
+		assert.Len(t, tntList.Items, 1)
+		assert.Equal(t, "oil", tntList.Items[0].GetName())
+	})
+
+	t.Run("unknown namespace", func(t *testing.T) {
+		tntList := &capsulev1beta1.TenantList{}
+		assert.NoError(t, c.List(context.Background(), tntList, byNamespace("default")))
+		assert.Empty(t, tntList.Items)
+	})
+
+	t.Run("moved namespace", func(t *testing.T) {
+		updated := oil.DeepCopy()
+		updated.Status.Namespaces = []string{"oil-production"}
+		index.set(updated)
+
+		tntList := &capsulev1beta1.TenantList{}
+		assert.NoError(t, c.List(context.Background(), tntList, byNamespace("oil-development")))
+		assert.Empty(t, tntList.Items)
+	})
+
+	t.Run("deleted tenant", func(t *testing.T) {
+		index.delete("gas")
+
+		tntList := &capsulev1beta1.TenantList{}
+		assert.NoError(t, c.List(context.Background(), tntList, byNamespace("gas-production")))
+		assert.Empty(t, tntList.Items)
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// TenantIndex is an in-memory index of the Tenants by Namespace, kept up to date by the Tenant informer
+// so that the admission decisions never block on the API server.
+// The index is rebuilt from the informer cache periodically, and it's considered stale
+// if not synced in the given MaxStaleness: in this case, the lookups fall back to the client.
+type TenantIndex struct {
+	Log logr.Logger
+	// The maximum time since the last sync for which the index can serve the lookups.
+	MaxStaleness time.Duration
+
+	cache      cache.Cache
+	mutex      sync.RWMutex
+	tenants    map[string]*capsulev1beta1.Tenant
+	namespaces map[string]string
+	lastSync   time.Time
+}
+
+// InjectCache injects the Cache, required to retrieve the Tenant informer
+func (i *TenantIndex) InjectCache(c cache.Cache) error {
+	i.cache = c
+
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface,
+// since each replica is serving the admission requests.
+func (i *TenantIndex) NeedLeaderElection() bool {
+	return false
+}
+
+func (i *TenantIndex) Start(ctx context.Context) error {
+	informer, err := i.cache.GetInformer(ctx, &capsulev1beta1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if tnt, ok := obj.(*capsulev1beta1.Tenant); ok {
+				i.set(tnt)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if tnt, ok := obj.(*capsulev1beta1.Tenant); ok {
+				i.set(tnt)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if tnt, ok := obj.(*capsulev1beta1.Tenant); ok {
+				i.delete(tnt.GetName())
+			}
+		},
+	})
+
+	if !i.cache.WaitForCacheSync(ctx) {
+		return nil
+	}
+
+	ticker := time.NewTicker(i.MaxStaleness / 2)
+	defer ticker.Stop()
+
+	for {
+		i.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ForNamespace returns the Tenants owning the given Namespace, along with a boolean reporting
+// if the index is fresh enough to serve the lookup.
+func (i *TenantIndex) ForNamespace(namespace string) ([]capsulev1beta1.Tenant, bool) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if i.lastSync.IsZero() || time.Since(i.lastSync) > i.MaxStaleness {
+		lookupsTotal.WithLabelValues("miss").Inc()
+
+		return nil, false
+	}
+
+	lookupsTotal.WithLabelValues("hit").Inc()
+
+	name, ok := i.namespaces[namespace]
+	if !ok {
+		return []capsulev1beta1.Tenant{}, true
+	}
+
+	return []capsulev1beta1.Tenant{*i.tenants[name].DeepCopy()}, true
+}
+
+// refresh rebuilds the whole index from the informer cache, healing any missed event.
+func (i *TenantIndex) refresh(ctx context.Context) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := i.cache.List(ctx, tntList); err != nil {
+		i.Log.Error(err, "Cannot list Tenants, the lookup index is not refreshed")
+
+		return
+	}
+
+	tenants := make(map[string]*capsulev1beta1.Tenant, len(tntList.Items))
+	namespaces := make(map[string]string)
+
+	for index := range tntList.Items {
+		tnt := tntList.Items[index]
+
+		tenants[tnt.GetName()] = &tnt
+
+		for _, ns := range tnt.Status.Namespaces {
+			namespaces[ns] = tnt.GetName()
+		}
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.tenants, i.namespaces, i.lastSync = tenants, namespaces, time.Now()
+}
+
+func (i *TenantIndex) set(tnt *capsulev1beta1.Tenant) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.unset(tnt.GetName())
+
+	if i.tenants == nil {
+		i.tenants, i.namespaces = make(map[string]*capsulev1beta1.Tenant), make(map[string]string)
+	}
+
+	i.tenants[tnt.GetName()] = tnt.DeepCopy()
+
+	for _, ns := range tnt.Status.Namespaces {
+		i.namespaces[ns] = tnt.GetName()
+	}
+}
+
+func (i *TenantIndex) delete(name string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.unset(name)
+}
+
+// unset removes the given Tenant from the index, the mutex must be held by the caller.
+func (i *TenantIndex) unset(name string) {
+	old, ok := i.tenants[name]
+	if !ok {
+		return
+	}
+
+	for _, ns := range old.Status.Namespaces {
+		if i.namespaces[ns] == name {
+			delete(i.namespaces, ns)
+		}
+	}
+
+	delete(i.tenants, name)
+}
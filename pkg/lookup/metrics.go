@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var lookupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capsule_tenant_lookups_total",
+	Help: "The Tenant lookups performed by the webhooks, served by the in-memory index (hit) or by the client (miss).",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(lookupsTotal)
+}
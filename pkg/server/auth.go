@@ -0,0 +1,92 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type userInfoKey struct{}
+
+// Authenticated serves the given handler only to the callers authenticated by their bearer token through the
+// TokenReview API, and authorized through the SubjectAccessReview API to perform the request, as the method on the
+// non-resource URL of its path: the identity of the caller is set in the request context, retrieved with UserInfoFrom.
+func Authenticated(c client.Client, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if len(token) == 0 || token == req.Header.Get("Authorization") {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+
+			return
+		}
+
+		review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+		if err := c.Create(req.Context(), review); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		if !review.Status.Authenticated {
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+
+			return
+		}
+
+		allowed, err := Authorized(req.Context(), c, review.Status.User, authorizationv1.SubjectAccessReviewSpec{
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: req.URL.Path, Verb: strings.ToLower(req.Method)},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		if !allowed {
+			http.Error(w, "forbidden", http.StatusForbidden)
+
+			return
+		}
+
+		handler.ServeHTTP(w, req.WithContext(WithUserInfo(req.Context(), review.Status.User)))
+	})
+}
+
+// Authorized reports if the given user is allowed to perform the request described by the attributes of the given
+// SubjectAccessReview spec.
+func Authorized(ctx context.Context, c client.Client, user authenticationv1.UserInfo, spec authorizationv1.SubjectAccessReviewSpec) (bool, error) {
+	spec.User, spec.Groups, spec.UID = user.Username, user.Groups, user.UID
+
+	if len(user.Extra) > 0 {
+		spec.Extra = make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for key, value := range user.Extra {
+			spec.Extra[key] = authorizationv1.ExtraValue(value)
+		}
+	}
+
+	review := &authorizationv1.SubjectAccessReview{Spec: spec}
+	if err := c.Create(ctx, review); err != nil {
+		return false, err
+	}
+
+	return review.Status.Allowed, nil
+}
+
+// WithUserInfo returns a copy of the given context holding the identity of the caller.
+func WithUserInfo(ctx context.Context, user authenticationv1.UserInfo) context.Context {
+	return context.WithValue(ctx, userInfoKey{}, user)
+}
+
+// UserInfoFrom returns the identity of the caller authenticated by Authenticated.
+func UserInfoFrom(ctx context.Context) (authenticationv1.UserInfo, bool) {
+	user, ok := ctx.Value(userInfoKey{}).(authenticationv1.UserInfo)
+
+	return user, ok
+}
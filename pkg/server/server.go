@@ -0,0 +1,72 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+)
+
+// SecureServer serves the given handler over TLS according to the hardening options, reloading
+// the serving certificate and key upon their rotation.
+type SecureServer struct {
+	Log         logr.Logger
+	BindAddress string
+	CertDir     string
+	CertName    string
+	KeyName     string
+	TLS         TLSOptions
+	Handler     http.Handler
+}
+
+// NeedLeaderElection returns false since every replica has to serve the traffic.
+func (s *SecureServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *SecureServer) Start(ctx context.Context) error {
+	watcher, err := certwatcher.New(filepath.Join(s.CertDir, s.CertName), filepath.Join(s.CertDir, s.KeyName))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			s.Log.Error(err, "certificate watcher error")
+		}
+	}()
+
+	cfg, err := s.TLS.Config(watcher.GetCertificate)
+	if err != nil {
+		return err
+	}
+
+	listener, err := tls.Listen("tcp", s.BindAddress, cfg)
+	if err != nil {
+		return err
+	}
+
+	s.Log.Info("serving TLS server", "address", s.BindAddress)
+
+	srv := &http.Server{Handler: s.Handler}
+
+	go func() {
+		<-ctx.Done()
+
+		if err := srv.Shutdown(context.Background()); err != nil {
+			s.Log.Error(err, "error shutting down the TLS server")
+		}
+	}()
+
+	if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
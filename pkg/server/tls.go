@@ -0,0 +1,102 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// TLSOptions contains the hardening options of the Capsule TLS listeners.
+type TLSOptions struct {
+	// MinVersion is the minimum TLS version accepted, as 1.2 or 1.3.
+	MinVersion string
+	// CipherSuites is the list of the accepted cipher suites, using the IANA names,
+	// leaving it empty uses the Go default ones. It's ignored by TLS 1.3.
+	CipherSuites []string
+	// ClientCAFile is the path of the PEM bundle used to verify the client certificates,
+	// leaving it empty doesn't require the clients to present a certificate.
+	ClientCAFile string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Validate returns an error if the options cannot be satisfied, allowing to fail fast at startup.
+func (o TLSOptions) Validate() error {
+	_, err := o.Config(nil)
+
+	return err
+}
+
+// Config returns the TLS configuration serving the certificates returned by the given function.
+func (o TLSOptions) Config(getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (*tls.Config, error) {
+	cfg := &tls.Config{
+		NextProtos:     []string{"h2"},
+		GetCertificate: getCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	if len(o.MinVersion) > 0 {
+		version, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid TLS minimum version %s, expects one of 1.0, 1.1, 1.2 or 1.3", o.MinVersion)
+		}
+
+		cfg.MinVersion = version
+	}
+
+	if len(o.CipherSuites) > 0 {
+		suites, err := cipherSuites(o.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.CipherSuites = suites
+	}
+
+	if len(o.ClientCAFile) > 0 {
+		pem, err := ioutil.ReadFile(o.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("the client CA bundle %s doesn't contain any certificate", o.ClientCAFile)
+		}
+
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+func cipherSuites(names []string) ([]uint16, error) {
+	available := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		available[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, ok := available[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure cipher suite %s", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
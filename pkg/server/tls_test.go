@@ -0,0 +1,36 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSOptionsConfig(t *testing.T) {
+	cfg, err := TLSOptions{}.Config(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+	assert.Nil(t, cfg.CipherSuites)
+	assert.Equal(t, tls.NoClientCert, cfg.ClientAuth)
+
+	cfg, err = TLSOptions{
+		MinVersion:   "1.3",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", " TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+	}.Config(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, cfg.CipherSuites)
+
+	_, err = TLSOptions{MinVersion: "1.4"}.Config(nil)
+	assert.NotNil(t, err)
+
+	_, err = TLSOptions{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}.Config(nil)
+	assert.NotNil(t, err)
+
+	_, err = TLSOptions{ClientCAFile: "/missing/ca.crt"}.Config(nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,58 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// FieldManager is the field manager used by Capsule to server-side apply the replicated resources.
+const FieldManager = "capsule"
+
+// Apply performs a server-side apply of the given object, forcing the ownership of the declared fields,
+// while the ones managed by other controllers are left untouched.
+// The object must contain only the desired fields, the returned operation result is computed
+// comparing the resource version prior and after the apply.
+func Apply(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj client.Object) (controllerutil.OperationResult, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return controllerutil.OperationResultNone, nil
+	}
+
+	var resourceVersion string
+
+	if err = c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err == nil {
+		resourceVersion = existing.GetResourceVersion()
+	} else if !apierrors.IsNotFound(err) {
+		return controllerutil.OperationResultNone, err
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	if err = c.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	switch {
+	case len(resourceVersion) == 0:
+		return controllerutil.OperationResultCreated, nil
+	case resourceVersion != obj.GetResourceVersion():
+		return controllerutil.OperationResultUpdated, nil
+	default:
+		return controllerutil.OperationResultNone, nil
+	}
+}
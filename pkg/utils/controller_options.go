@@ -0,0 +1,62 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"math/rand"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// RateLimiterOptions tunes the work queue rate limiter of the controllers: failing items are retried with an
+// exponential backoff between BaseDelay and MaxDelay, shortened by a random fraction up to Jitter so that the items
+// failing together are not retried in lockstep, while the overall retries are bound to QPS and Burst.
+type RateLimiterOptions struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Jitter    float64
+	QPS       float64
+	Burst     int
+}
+
+// ControllerOptions returns the options of a controller reconciling up to maxConcurrentReconciles items in parallel.
+func ControllerOptions(maxConcurrentReconciles int, opts RateLimiterOptions) controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RateLimiter: workqueue.NewMaxOfRateLimiter(
+			&jitteredRateLimiter{
+				RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(opts.BaseDelay, opts.MaxDelay),
+				jitter:      opts.Jitter,
+			},
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(opts.QPS), opts.Burst)},
+		),
+	}
+}
+
+type jitteredRateLimiter struct {
+	workqueue.RateLimiter
+	jitter float64
+}
+
+func (r *jitteredRateLimiter) When(item interface{}) time.Duration {
+	return Jitter(r.RateLimiter.When(item), r.jitter)
+}
+
+// Jitter returns the given duration shortened by a random fraction up to the given factor, spreading the requeues
+// of the items scheduled at the same time without delaying them past the given duration.
+func Jitter(d time.Duration, factor float64) time.Duration {
+	if factor <= 0 || d <= 0 {
+		return d
+	}
+
+	if factor > 1 {
+		factor = 1
+	}
+
+	//nolint:gosec
+	return d - time.Duration(rand.Float64()*factor*float64(d))
+}
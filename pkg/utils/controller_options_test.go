@@ -0,0 +1,41 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestJitter(t *testing.T) {
+	assert.Equal(t, time.Minute, Jitter(time.Minute, 0))
+	assert.Equal(t, time.Duration(0), Jitter(0, 0.5))
+
+	for i := 0; i < 100; i++ {
+		d := Jitter(time.Minute, 0.1)
+		assert.LessOrEqual(t, int64(d), int64(time.Minute))
+		assert.Greater(t, int64(d), int64(54*time.Second))
+
+		assert.GreaterOrEqual(t, int64(Jitter(time.Minute, 2)), int64(0))
+	}
+}
+
+func TestJitteredRateLimiter(t *testing.T) {
+	limiter := &jitteredRateLimiter{
+		RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(time.Second, 10*time.Second),
+		jitter:      0.5,
+	}
+
+	for i, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		d := limiter.When("item")
+		assert.LessOrEqual(t, int64(d), int64(max), "failure %d", i)
+		assert.Greater(t, int64(d), int64(max/2), "failure %d", i)
+	}
+
+	limiter.Forget("item")
+	assert.LessOrEqual(t, int64(limiter.When("item")), int64(time.Second))
+}
@@ -0,0 +1,83 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cronjob
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type defaults struct{}
+
+// Defaults lowers the history limits of the CronJobs to the Tenant maximum,
+// and sets the Tenant maximum activeDeadlineSeconds to the CronJobs not specifying it.
+func Defaults() capsulewebhook.Handler {
+	return &defaults{}
+}
+
+func (d *defaults) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return d.mutate(ctx, c, decoder, req)
+	}
+}
+
+func (d *defaults) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return d.mutate(ctx, c, decoder, req)
+	}
+}
+
+func (d *defaults) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (d *defaults) mutate(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request) *admission.Response {
+	cronJob, err := cronJobFromRequest(req, decoder)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tnt, err := utils.TenantForNamespace(ctx, c, cronJob.Object().GetNamespace())
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if tnt == nil || tnt.Spec.CronJobOptions == nil {
+		return nil
+	}
+
+	options := tnt.Spec.CronJobOptions
+
+	if max := options.MaxHistoryLimit; max != nil {
+		for _, limit := range cronJob.HistoryLimits() {
+			if *limit == nil || **limit > *max {
+				value := *max
+				*limit = &value
+			}
+		}
+	}
+
+	if max := options.MaxActiveDeadlineSeconds; max != nil && cronJob.JobSpec().ActiveDeadlineSeconds == nil {
+		value := *max
+		cronJob.JobSpec().ActiveDeadlineSeconds = &value
+	}
+
+	mutated, err := json.Marshal(cronJob.Object())
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	response := admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+
+	return &response
+}
@@ -0,0 +1,66 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cronjob
+
+import (
+	"fmt"
+	"strings"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+type scheduleForbidden struct {
+	schedule string
+	spec     capsulev1beta1.ForbiddenListSpec
+}
+
+func NewScheduleForbidden(schedule string, spec capsulev1beta1.ForbiddenListSpec) error {
+	return &scheduleForbidden{
+		schedule: schedule,
+		spec:     spec,
+	}
+}
+
+func (f scheduleForbidden) Error() (err string) {
+	err = fmt.Sprintf("CronJob schedule %s is forbidden for the current Tenant", f.schedule)
+
+	if len(f.spec.Exact) > 0 {
+		err += fmt.Sprintf(", the following ones are denied (%s)", strings.Join(f.spec.Exact, ", "))
+	}
+	if len(f.spec.Regex) > 0 {
+		err += fmt.Sprintf(", along with the ones matching the regex %s", f.spec.Regex)
+	}
+
+	return
+}
+
+type activeDeadlineSecondsExceeded struct {
+	value int64
+	max   int64
+}
+
+func NewActiveDeadlineSecondsExceeded(value, max int64) error {
+	return &activeDeadlineSecondsExceeded{
+		value: value,
+		max:   max,
+	}
+}
+
+func (a activeDeadlineSecondsExceeded) Error() string {
+	return fmt.Sprintf("CronJob activeDeadlineSeconds %d exceeds the maximum allowed by the current Tenant (%d)", a.value, a.max)
+}
+
+type concurrentJobsExceeded struct {
+	max int32
+}
+
+func NewConcurrentJobsExceeded(max int32) error {
+	return &concurrentJobsExceeded{
+		max: max,
+	}
+}
+
+func (c concurrentJobsExceeded) Error() string {
+	return fmt.Sprintf("Cannot start the Job, the maximum number of concurrent CronJob Jobs allowed by the current Tenant has been reached (%d)", c.max)
+}
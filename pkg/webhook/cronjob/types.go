@@ -0,0 +1,79 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cronjob
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// CronJob abstracts the CronJob versions served by the cluster, exposing the fields enforced by the Tenant.
+type CronJob interface {
+	Object() client.Object
+	Schedule() string
+	JobSpec() *batchv1.JobSpec
+	HistoryLimits() []**int32
+}
+
+type V1 struct {
+	*batchv1.CronJob
+}
+
+func (c V1) Object() client.Object {
+	return c.CronJob
+}
+
+func (c V1) Schedule() string {
+	return c.Spec.Schedule
+}
+
+func (c V1) JobSpec() *batchv1.JobSpec {
+	return &c.Spec.JobTemplate.Spec
+}
+
+func (c V1) HistoryLimits() []**int32 {
+	return []**int32{&c.Spec.SuccessfulJobsHistoryLimit, &c.Spec.FailedJobsHistoryLimit}
+}
+
+type V1Beta1 struct {
+	*batchv1beta1.CronJob
+}
+
+func (c V1Beta1) Object() client.Object {
+	return c.CronJob
+}
+
+func (c V1Beta1) Schedule() string {
+	return c.Spec.Schedule
+}
+
+func (c V1Beta1) JobSpec() *batchv1.JobSpec {
+	return &c.Spec.JobTemplate.Spec
+}
+
+func (c V1Beta1) HistoryLimits() []**int32 {
+	return []**int32{&c.Spec.SuccessfulJobsHistoryLimit, &c.Spec.FailedJobsHistoryLimit}
+}
+
+// cronJobFromRequest decodes the CronJob of the request according to its version, since the cluster serves the
+// batch/v1beta1 CronJobs along with the batch/v1 ones up to Kubernetes 1.25.
+func cronJobFromRequest(req admission.Request, decoder *admission.Decoder) (CronJob, error) {
+	if req.Kind.Version == "v1beta1" {
+		cronJob := &batchv1beta1.CronJob{}
+		if err := decoder.Decode(req, cronJob); err != nil {
+			return nil, err
+		}
+
+		return V1Beta1{CronJob: cronJob}, nil
+	}
+
+	cronJob := &batchv1.CronJob{}
+	if err := decoder.Decode(req, cronJob); err != nil {
+		return nil, err
+	}
+
+	return V1{CronJob: cronJob}, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cronjob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestCronJobFromRequest(t *testing.T) {
+	decoder, err := admission.NewDecoder(clientgoscheme.Scheme)
+	assert.NoError(t, err)
+
+	for _, version := range []string{"v1", "v1beta1"} {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Group: "batch", Version: version, Kind: "CronJob"},
+			Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/` + version + `","kind":"CronJob","metadata":{"name":"backup","namespace":"oil-production"},"spec":{"schedule":"0 * * * *","jobTemplate":{"spec":{"activeDeadlineSeconds":60}}}}`)},
+		}}
+
+		cronJob, err := cronJobFromRequest(req, decoder)
+		if !assert.NoError(t, err, version) {
+			continue
+		}
+
+		assert.Equal(t, "oil-production", cronJob.Object().GetNamespace())
+		assert.Equal(t, "0 * * * *", cronJob.Schedule())
+		assert.Equal(t, int64(60), *cronJob.JobSpec().ActiveDeadlineSeconds)
+
+		limits := cronJob.HistoryLimits()
+		value := int32(1)
+		*limits[1] = &value
+		assert.Nil(t, *limits[0])
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package cronjob
+
+import (
+	"context"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct {
+	reader client.Reader
+}
+
+// Handler validates the CronJobs against the Tenant options, and the Jobs they spawn against the Tenant concurrency
+// limit: the active Jobs are counted through the given reader, rather than starting a cluster-wide Job informer.
+func Handler(reader client.Reader) capsulewebhook.Handler {
+	return &handler{reader: reader}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		switch req.Kind.Kind {
+		case "CronJob":
+			return h.validateCronJob(ctx, c, decoder, recorder, req)
+		case "Job":
+			return h.validateJob(ctx, c, decoder, recorder, req)
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		if req.Kind.Kind == "CronJob" {
+			return h.validateCronJob(ctx, c, decoder, recorder, req)
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *handler) validateCronJob(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	cronJob, err := cronJobFromRequest(req, decoder)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tnt, err := utils.TenantForNamespace(ctx, c, cronJob.Object().GetNamespace())
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if tnt == nil || tnt.Spec.CronJobOptions == nil {
+		return nil
+	}
+
+	options := tnt.Spec.CronJobOptions
+
+	if forbidden := options.ForbiddenSchedules; forbidden != nil {
+		schedule := strings.Join(strings.Fields(cronJob.Schedule()), " ")
+
+		if forbidden.ExactMatch(schedule) || forbidden.RegexMatch(schedule) {
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "ForbiddenCronJobSchedule", "CronJob %s/%s schedule %s is forbidden for the current Tenant", req.Namespace, req.Name, schedule)
+
+			response := admission.Denied(NewScheduleForbidden(schedule, *forbidden).Error())
+
+			return &response
+		}
+	}
+
+	if max := options.MaxActiveDeadlineSeconds; max != nil {
+		if value := cronJob.JobSpec().ActiveDeadlineSeconds; value != nil && *value > *max {
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "CronJobActiveDeadlineSecondsExceeded", "CronJob %s/%s activeDeadlineSeconds %d exceeds the Tenant maximum", req.Namespace, req.Name, *value)
+
+			response := admission.Denied(NewActiveDeadlineSecondsExceeded(*value, *max).Error())
+
+			return &response
+		}
+	}
+
+	return nil
+}
+
+func (h *handler) validateJob(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	job := &batchv1.Job{}
+	if err := decoder.Decode(req, job); err != nil {
+		return utils.ErroredResponse(err)
+	}
+	// only the Jobs spawned by the CronJobs are subject to the concurrency limit
+	if !spawnedByCronJob(job) {
+		return nil
+	}
+
+	tnt, err := utils.TenantForNamespace(ctx, c, job.Namespace)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if tnt == nil || tnt.Spec.CronJobOptions == nil || tnt.Spec.CronJobOptions.MaxConcurrentJobs == nil {
+		return nil
+	}
+
+	max := *tnt.Spec.CronJobOptions.MaxConcurrentJobs
+
+	var active int32
+
+	for _, ns := range tnt.Status.Namespaces {
+		jobList := &batchv1.JobList{}
+		if err = h.reader.List(ctx, jobList, client.InNamespace(ns)); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		for i := range jobList.Items {
+			if spawnedByCronJob(&jobList.Items[i]) && !finished(&jobList.Items[i]) {
+				active++
+			}
+		}
+	}
+
+	if active >= max {
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "CronJobConcurrentJobsExceeded", "Job %s/%s cannot be started, the Tenant has already %d active CronJob Jobs", req.Namespace, job.GetName(), active)
+
+		response := admission.Denied(NewConcurrentJobsExceeded(max).Error())
+
+		return &response
+	}
+
+	return nil
+}
+
+func spawnedByCronJob(job *batchv1.Job) bool {
+	owner := metav1.GetControllerOf(job)
+
+	return owner != nil && owner.Kind == "CronJob"
+}
+
+func finished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
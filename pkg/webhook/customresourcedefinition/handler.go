@@ -0,0 +1,199 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package customresourcedefinition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct{}
+
+// Handler lets the Tenant owners install the CustomResourceDefinitions of the API groups allowed to their Tenants,
+// binding them to the Tenant with the Tenant label and an owner reference, so that they are deleted along with it.
+// The Tenant owners can update and delete only the CustomResourceDefinitions bound to their Tenants, and cannot
+// register a conversion webhook, as the API server would call any address they set.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := decoder.Decode(req, crd); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := c.List(ctx, tntList); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tnt := tenantForGroup(tntList.Items, req.UserInfo, crd.Spec.Group)
+		if tnt == nil {
+			response := admission.Denied(fmt.Sprintf("The API group %s is not allowed to any of your Tenants: please, reach out to the system administrators", crd.Spec.Group))
+
+			return &response
+		}
+
+		if response := h.validateConversion(crd); response != nil {
+			return response
+		}
+
+		return h.bind(tnt, crd, recorder)
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		tnt, response := h.boundTenant(ctx, c, decoder, req)
+		if response != nil {
+			return response
+		}
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := decoder.Decode(req, crd); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if response := h.validateConversion(crd); response != nil {
+			return response
+		}
+		// the binding to the Tenant is restored, whatever the changes
+		return h.bind(tnt, crd, recorder)
+	}
+}
+
+func (h *handler) OnDelete(c client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		_, response := h.boundTenant(ctx, c, decoder, req)
+
+		return response
+	}
+}
+
+// boundTenant returns the Tenant the stored CustomResourceDefinition is bound to, or the denial response when it
+// isn't bound to any Tenant owned by the requester.
+func (h *handler) boundTenant(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request) (*capsulev1beta1.Tenant, *admission.Response) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := decoder.DecodeRaw(req.OldObject, crd); err != nil {
+		return nil, utils.ErroredResponse(err)
+	}
+
+	ln, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return nil, utils.ErroredResponse(err)
+	}
+
+	denied := func() (*capsulev1beta1.Tenant, *admission.Response) {
+		response := admission.Denied(fmt.Sprintf("CustomResourceDefinition %s doesn't belong to any of your Tenants", crd.GetName()))
+
+		return nil, &response
+	}
+
+	tenantName, ok := crd.GetLabels()[ln]
+	if !ok {
+		return denied()
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err = c.Get(ctx, types.NamespacedName{Name: tenantName}, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return denied()
+		}
+
+		return nil, utils.ErroredResponse(err)
+	}
+
+	if !utils.IsTenantOwner(tnt.Spec.Owners, req.UserInfo) {
+		return denied()
+	}
+
+	return tnt, nil
+}
+
+// validateConversion denies the webhook conversion strategy, making the API server call the URL or the Service set
+// in the CustomResourceDefinition.
+func (h *handler) validateConversion(crd *apiextensionsv1.CustomResourceDefinition) *admission.Response {
+	if crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiextensionsv1.WebhookConverter {
+		return nil
+	}
+
+	response := admission.Denied(fmt.Sprintf("CustomResourceDefinition %s cannot use the %s conversion strategy: please, reach out to the system administrators", crd.GetName(), apiextensionsv1.WebhookConverter))
+
+	return &response
+}
+
+func (h *handler) bind(tnt *capsulev1beta1.Tenant, crd *apiextensionsv1.CustomResourceDefinition, recorder record.EventRecorder) *admission.Response {
+	ln, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	original, err := json.Marshal(crd.DeepCopy())
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	labels := crd.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[ln] = tnt.GetName()
+	crd.SetLabels(labels)
+
+	scheme := runtime.NewScheme()
+	_ = capsulev1beta1.AddToScheme(scheme)
+
+	if err = controllerutil.SetControllerReference(tnt, crd, scheme); err != nil {
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "Error", "CustomResourceDefinition %s cannot be bound to the Tenant", crd.GetName())
+
+		response := admission.Errored(http.StatusInternalServerError, err)
+
+		return &response
+	}
+
+	patched, err := json.Marshal(crd)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	response := admission.PatchResponseFromRaw(original, patched)
+
+	return &response
+}
+
+// tenantForGroup returns the first Tenant owned by the given user allowing the given API group, if any.
+func tenantForGroup(tenants []capsulev1beta1.Tenant, userInfo authenticationv1.UserInfo, group string) *capsulev1beta1.Tenant {
+	for i := range tenants {
+		tnt := tenants[i]
+
+		if tnt.Spec.CustomResourceDefinitions == nil || !tnt.Spec.CustomResourceDefinitions.AllowsGroup(group) {
+			continue
+		}
+
+		if utils.IsTenantOwner(tnt.Spec.Owners, userInfo) {
+			return tnt.DeepCopy()
+		}
+	}
+
+	return nil
+}
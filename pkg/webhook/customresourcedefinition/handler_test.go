@@ -0,0 +1,64 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package customresourcedefinition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestTenantForGroup(t *testing.T) {
+	tenant := func(name, owner string, groups ...string) capsulev1beta1.Tenant {
+		tnt := capsulev1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: capsulev1beta1.TenantSpec{
+				Owners: capsulev1beta1.OwnerListSpec{{Kind: capsulev1beta1.UserOwner, Name: owner}},
+			},
+		}
+		if len(groups) > 0 {
+			tnt.Spec.CustomResourceDefinitions = &capsulev1beta1.CustomResourceDefinitionsSpec{AllowedGroups: groups}
+		}
+
+		return tnt
+	}
+
+	tenants := []capsulev1beta1.Tenant{
+		tenant("solar", "alice"),
+		tenant("oil", "alice", "oil.acmecorp.com"),
+		tenant("gas", "bob", "gas.acmecorp.com"),
+	}
+	alice := authenticationv1.UserInfo{Username: "alice"}
+
+	if tnt := tenantForGroup(tenants, alice, "ci.oil.acmecorp.com"); assert.NotNil(t, tnt) {
+		assert.Equal(t, "oil", tnt.GetName())
+	}
+	// the groups of the Tenants not owned are not available
+	assert.Nil(t, tenantForGroup(tenants, alice, "gas.acmecorp.com"))
+	assert.Nil(t, tenantForGroup(tenants, alice, "acmecorp.com"))
+}
+
+func TestValidateConversion(t *testing.T) {
+	h := &handler{}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "pipelines.ci.oil.acmecorp.com"}}
+	assert.Nil(t, h.validateConversion(crd))
+
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+	assert.Nil(t, h.validateConversion(crd))
+
+	url := "https://169.254.169.254/latest"
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook:  &apiextensionsv1.WebhookConversion{ClientConfig: &apiextensionsv1.WebhookClientConfig{URL: &url}},
+	}
+	if response := h.validateConversion(crd); assert.NotNil(t, response) {
+		assert.False(t, response.Allowed)
+	}
+}
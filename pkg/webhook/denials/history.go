@@ -0,0 +1,359 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package denials
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/server"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap persisting the last denials of the requests of a Namespace.
+	ConfigMapName = "capsule-admission-denials"
+	// ConfigMapKey is the ConfigMap key holding the denials, as a JSON list sorted from the most recent one.
+	ConfigMapKey = "denials.json"
+
+	flushPeriod = 10 * time.Second
+)
+
+// Denial is an admission request denied by the Capsule webhooks.
+type Denial struct {
+	Time      metav1.Time `json:"time"`
+	User      string      `json:"user"`
+	Operation string      `json:"operation"`
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Webhook   string      `json:"webhook"`
+	Message   string      `json:"message"`
+}
+
+// History keeps the last admission denials of each Tenant in a bounded ring buffer, serving them as JSON to the
+// Tenant owners and optionally persisting them in a ConfigMap of each denied request Namespace, readable by the
+// Tenant owners. Each replica keeps the denials of the requests it served, merged with the persisted ones.
+type History struct {
+	Client client.Client
+	// Reader retrieves the persisted ConfigMaps, not cached by the manager.
+	Reader client.Reader
+	Log    logr.Logger
+	// The number of denials kept for each Tenant.
+	Size int
+	// Persist the denials in the capsule-admission-denials ConfigMap of the denied requests Namespaces.
+	Persist bool
+
+	mutex sync.Mutex
+	rings map[string]*ring
+	// the Namespaces whose ConfigMap is outdated, by Tenant
+	dirty map[string]map[string]struct{}
+}
+
+type ring struct {
+	items []Denial
+	next  int
+}
+
+func (r *ring) add(size int, denial Denial) {
+	if len(r.items) < size {
+		r.items = append(r.items, denial)
+
+		return
+	}
+
+	r.items[r.next] = denial
+	r.next = (r.next + 1) % size
+}
+
+// list returns the denials sorted from the most recent one.
+func (r *ring) list() []Denial {
+	denials := make([]Denial, 0, len(r.items))
+
+	for i := len(r.items) - 1; i >= 0; i-- {
+		denials = append(denials, r.items[(r.next+i)%len(r.items)])
+	}
+
+	return denials
+}
+
+// Record adds the denial to the ones of the given Tenant, evicting the oldest one once the history is full.
+func (h *History) Record(tenant string, denial Denial) {
+	if h == nil || h.Size <= 0 {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.rings == nil {
+		h.rings = make(map[string]*ring)
+		h.dirty = make(map[string]map[string]struct{})
+	}
+
+	r, ok := h.rings[tenant]
+	if !ok {
+		r = &ring{}
+		h.rings[tenant] = r
+	}
+
+	if len(r.items) == h.Size {
+		h.markDirty(tenant, r.items[r.next].Namespace)
+	}
+
+	r.add(h.Size, denial)
+	h.markDirty(tenant, denial.Namespace)
+}
+
+func (h *History) markDirty(tenant, namespace string) {
+	if !h.Persist || len(namespace) == 0 {
+		return
+	}
+
+	if _, ok := h.dirty[tenant]; !ok {
+		h.dirty[tenant] = make(map[string]struct{})
+	}
+
+	h.dirty[tenant][namespace] = struct{}{}
+}
+
+// List returns the denials of the given Tenant, sorted from the most recent one.
+func (h *History) List(tenant string) []Denial {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r, ok := h.rings[tenant]
+	if !ok {
+		return []Denial{}
+	}
+
+	return r.list()
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface,
+// since each replica is serving the admission requests.
+func (h *History) NeedLeaderElection() bool {
+	return false
+}
+
+// Start persists periodically the denials of the Namespaces having new ones, when enabled.
+func (h *History) Start(ctx context.Context) error {
+	if !h.Persist {
+		return nil
+	}
+
+	ticker := time.NewTicker(flushPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			h.flush(ctx)
+		}
+	}
+}
+
+func (h *History) flush(ctx context.Context) {
+	h.mutex.Lock()
+
+	pending := make(map[types.NamespacedName][]Denial)
+	// the time of the oldest denial kept for each Tenant once the history is full:
+	// the older persisted denials are evicted as well
+	cutoffs := make(map[string]time.Time)
+
+	for tenant, namespaces := range h.dirty {
+		var denials []Denial
+		if r, ok := h.rings[tenant]; ok {
+			denials = r.list()
+
+			if len(denials) == h.Size {
+				cutoffs[tenant] = denials[len(denials)-1].Time.Time
+			}
+		}
+
+		for namespace := range namespaces {
+			pending[types.NamespacedName{Namespace: namespace, Name: tenant}] = forNamespace(denials, namespace)
+		}
+	}
+
+	h.dirty = make(map[string]map[string]struct{})
+
+	h.mutex.Unlock()
+
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		h.Log.Error(err, "Cannot retrieve the Tenant label")
+
+		return
+	}
+
+	for key, denials := range pending {
+		if err = retry.OnError(retry.DefaultRetry, retriable, func() error {
+			return h.persist(ctx, key.Namespace, key.Name, tenantLabel, denials, cutoffs[key.Name])
+		}); err != nil {
+			h.Log.Error(err, "Cannot persist the admission denials", "namespace", key.Namespace)
+		}
+	}
+}
+
+func retriable(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+}
+
+// persist merges the given denials of the Namespace with the persisted ones, as the other replicas persist the
+// denials of the requests they served in the same ConfigMap: the update is rejected upon a concurrent one.
+func (h *History) persist(ctx context.Context, namespace, tenant, tenantLabel string, denials []Denial, cutoff time.Time) error {
+	cm := &corev1.ConfigMap{}
+	if err := h.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ConfigMapName}, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: namespace}}
+	}
+
+	var persisted []Denial
+	if data, ok := cm.Data[ConfigMapKey]; ok {
+		if err := json.Unmarshal([]byte(data), &persisted); err != nil {
+			h.Log.Error(err, "Discarding the malformed persisted admission denials", "namespace", namespace)
+		}
+	}
+
+	data, err := json.Marshal(merge(h.Size, cutoff, denials, persisted))
+	if err != nil {
+		return err
+	}
+
+	if cm.Labels == nil {
+		cm.Labels = make(map[string]string)
+	}
+	cm.Labels[tenantLabel] = tenant
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[ConfigMapKey] = string(data)
+
+	if len(cm.ResourceVersion) == 0 {
+		err = h.Client.Create(ctx, cm)
+	} else {
+		err = h.Client.Update(ctx, cm)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// merge returns the most recent denials among the given ones and the persisted ones, up to the given size and
+// newer than the given cutoff, when set.
+func merge(size int, cutoff time.Time, denials, persisted []Denial) []Denial {
+	// the persisted times are truncated to the seconds
+	key := func(denial Denial) Denial {
+		denial.Time = metav1.NewTime(denial.Time.UTC().Truncate(time.Second))
+
+		return denial
+	}
+
+	seen := make(map[Denial]struct{}, len(denials))
+
+	merged := make([]Denial, 0, len(denials)+len(persisted))
+
+	for _, denial := range denials {
+		seen[key(denial)] = struct{}{}
+		merged = append(merged, denial)
+	}
+
+	for _, denial := range persisted {
+		if _, ok := seen[key(denial)]; ok || denial.Time.Time.Before(cutoff.Truncate(time.Second)) {
+			continue
+		}
+
+		merged = append(merged, denial)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.After(merged[j].Time.Time)
+	})
+
+	if len(merged) > size {
+		merged = merged[:size]
+	}
+
+	return merged
+}
+
+// forNamespace filters the denials of the requests of the given Namespace.
+func forNamespace(denials []Denial, namespace string) []Denial {
+	filtered := make([]Denial, 0, len(denials))
+
+	for _, denial := range denials {
+		if denial.Namespace == namespace {
+			filtered = append(filtered, denial)
+		}
+	}
+
+	return filtered
+}
+
+// ServeHTTP serves as JSON the denials of the Tenant set with the tenant query parameter, to its owners only.
+func (h *History) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	caller, ok := server.UserInfoFrom(r.Context())
+	if !ok {
+		http.Error(w, "the caller is not authenticated", http.StatusUnauthorized)
+
+		return
+	}
+
+	name := r.URL.Query().Get("tenant")
+	if len(name) == 0 {
+		http.Error(w, "missing tenant query parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err := h.Client.Get(r.Context(), types.NamespacedName{Name: name}, tnt); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "tenant not found", http.StatusNotFound)
+
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+	// not disclosing the Tenants existence to the other callers
+	if !tnt.Spec.Owners.IsOwner(caller) {
+		http.Error(w, "tenant not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(struct {
+		Tenant  string   `json:"tenant"`
+		Denials []Denial `json:"denials"`
+	}{Tenant: name, Denials: h.List(name)}); err != nil {
+		h.Log.Error(err, "Cannot export the admission denials", "tenant", name)
+	}
+}
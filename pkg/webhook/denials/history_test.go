@@ -0,0 +1,117 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package denials
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/server"
+)
+
+func TestHistory_Record(t *testing.T) {
+	h := &History{Size: 3, Persist: true}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		h.Record("oil", Denial{Namespace: "oil-" + name, Name: name})
+	}
+	h.Record("gas", Denial{Namespace: "gas-production", Name: "e"})
+
+	var names []string
+	for _, denial := range h.List("oil") {
+		names = append(names, denial.Name)
+	}
+	// the oldest denial is evicted
+	assert.Equal(t, []string{"d", "c", "b"}, names)
+	assert.Len(t, h.List("gas"), 1)
+	assert.Empty(t, h.List("solar"))
+	// the Namespace of the evicted denial is persisted again
+	assert.Contains(t, h.dirty["oil"], "oil-a")
+	assert.Len(t, h.dirty["oil"], 4)
+}
+
+func TestForNamespace(t *testing.T) {
+	denials := []Denial{
+		{Namespace: "oil-production", Name: "a"},
+		{Namespace: "oil-development", Name: "b"},
+		{Namespace: "oil-production", Name: "c"},
+	}
+
+	assert.Equal(t, []Denial{denials[0], denials[2]}, forNamespace(denials, "oil-production"))
+	assert.Empty(t, forNamespace(denials, "oil-staging"))
+}
+
+func TestMerge(t *testing.T) {
+	now := time.Now()
+
+	at := func(name string, ago time.Duration) Denial {
+		return Denial{Time: metav1.NewTime(now.Add(-ago)), Namespace: "oil-production", Name: name}
+	}
+	// the persisted times are truncated to the seconds
+	persisted := func(denials ...Denial) (out []Denial) {
+		data, _ := json.Marshal(denials)
+		_ = json.Unmarshal(data, &out)
+
+		return out
+	}
+
+	local := []Denial{at("a", 0), at("c", 2*time.Second)}
+
+	names := func(denials []Denial) (names []string) {
+		for _, denial := range denials {
+			names = append(names, denial.Name)
+		}
+
+		return names
+	}
+	// the denials of the other replicas are kept, without duplicating the ones already persisted
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names(merge(5, time.Time{}, local, persisted(at("b", time.Second), at("c", 2*time.Second), at("d", time.Minute)))))
+	// up to the size of the history
+	assert.Equal(t, []string{"a", "b"}, names(merge(2, time.Time{}, local, persisted(at("b", time.Second), at("d", time.Minute)))))
+	// the persisted ones older than the evicted ones are evicted too
+	assert.Equal(t, []string{"a", "b", "c"}, names(merge(5, now.Add(-2*time.Second), local, persisted(at("b", time.Second), at("d", time.Minute)))))
+}
+
+func TestHistory_ServeHTTP(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{{Kind: capsulev1beta1.UserOwner, Name: "alice"}, {Kind: capsulev1beta1.GroupOwner, Name: "oil-admins"}},
+		},
+	}
+
+	h := &History{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tnt).Build(), Size: 3}
+	h.Record("oil", Denial{Namespace: "oil-production", Name: "nginx"})
+
+	serve := func(tenant string, user *authenticationv1.UserInfo) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/denials?tenant="+tenant, nil)
+		if user != nil {
+			req = req.WithContext(server.WithUserInfo(req.Context(), *user))
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("oil", nil).Code)
+	assert.Equal(t, http.StatusOK, serve("oil", &authenticationv1.UserInfo{Username: "alice"}).Code)
+	assert.Equal(t, http.StatusOK, serve("oil", &authenticationv1.UserInfo{Username: "joe", Groups: []string{"oil-admins"}}).Code)
+	assert.Equal(t, http.StatusNotFound, serve("oil", &authenticationv1.UserInfo{Username: "bob"}).Code)
+	assert.Equal(t, http.StatusNotFound, serve("gas", &authenticationv1.UserInfo{Username: "alice"}).Code)
+}
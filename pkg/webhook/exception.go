@@ -0,0 +1,103 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// exceptedBy returns the unexpired TenantException exempting the request from the webhook, if any.
+func (r *handlerRouter) exceptedBy(ctx context.Context, req admission.Request) (*capsulev1beta1.TenantException, bool) {
+	exceptionList := &capsulev1beta1.TenantExceptionList{}
+	if err := r.client.List(ctx, exceptionList); err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+
+	for i := range exceptionList.Items {
+		if exception := &exceptionList.Items[i]; !exception.IsExpired(now) && exceptionMatches(exception, r.path, req) {
+			return exception, true
+		}
+	}
+
+	return nil, false
+}
+
+// exceptionMatches returns true if the TenantException exempts the request from the webhook served at the given path.
+func exceptionMatches(exception *capsulev1beta1.TenantException, path string, req admission.Request) bool {
+	if exception.Spec.Namespace != req.Namespace {
+		return false
+	}
+
+	if len(exception.Spec.Kind) > 0 && exception.Spec.Kind != req.Kind.Kind {
+		return false
+	}
+
+	if len(exception.Spec.Name) > 0 && exception.Spec.Name != req.Name {
+		return false
+	}
+
+	var matched bool
+
+	for _, webhook := range exception.Spec.Webhooks {
+		if webhook == path {
+			matched = true
+
+			break
+		}
+	}
+
+	if !matched {
+		return false
+	}
+
+	if exception.Spec.Selector == nil {
+		return true
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(exception.Spec.Selector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(requestLabels(req)))
+}
+
+// requestLabels returns the labels of the object of the request, the deleted one upon the deletions.
+func requestLabels(req admission.Request) map[string]string {
+	raw := req.Object.Raw
+	if len(raw) == 0 {
+		raw = req.OldObject.Raw
+	}
+
+	object := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(raw, object); err != nil {
+		return nil
+	}
+
+	return object.GetLabels()
+}
+
+// exceptionWarning returns the warning returned along with the request exempted by the TenantException.
+func exceptionWarning(exception *capsulev1beta1.TenantException, response admission.Response) string {
+	var message string
+	if response.Result != nil {
+		// the denials carry their message in the reason
+		if message = response.Result.Message; len(message) == 0 {
+			message = string(response.Result.Reason)
+		}
+	}
+
+	return fmt.Sprintf("the request has been exempted by the TenantException %s until %s: %s", exception.GetName(), exception.Spec.ExpiresAt.UTC().Format(time.RFC3339), message)
+}
@@ -0,0 +1,61 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestExceptionMatches(t *testing.T) {
+	exception := &capsulev1beta1.TenantException{
+		ObjectMeta: metav1.ObjectMeta{Name: "hotfix"},
+		Spec: capsulev1beta1.TenantExceptionSpec{
+			Namespace: "oil-production",
+			Webhooks:  []string{"/pods"},
+			Kind:      "Pod",
+			Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "billing"}},
+			ExpiresAt: metav1.NewTime(time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)),
+			Reason:    "INC-42",
+		},
+	}
+
+	request := func(namespace, kind string, object string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: namespace,
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: kind},
+			Object:    runtime.RawExtension{Raw: []byte(object)},
+		}}
+	}
+
+	billing := `{"metadata":{"generateName":"billing-","labels":{"app":"billing"}}}`
+
+	for name, tc := range map[string]struct {
+		path    string
+		req     admission.Request
+		matches bool
+	}{
+		"matching":        {path: "/pods", req: request("oil-production", "Pod", billing), matches: true},
+		"other webhook":   {path: "/services", req: request("oil-production", "Pod", billing)},
+		"other namespace": {path: "/pods", req: request("oil-development", "Pod", billing)},
+		"other kind":      {path: "/pods", req: request("oil-production", "Service", billing)},
+		"other labels":    {path: "/pods", req: request("oil-production", "Pod", `{"metadata":{"labels":{"app":"nginx"}}}`)},
+	} {
+		assert.Equal(t, tc.matches, exceptionMatches(exception, tc.path, tc.req), name)
+	}
+
+	assert.False(t, exception.IsExpired(time.Date(2021, 9, 1, 11, 59, 0, 0, time.UTC)))
+	assert.True(t, exception.IsExpired(time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)))
+
+	denied := admission.Denied("Container image docker.io/nginx is forbidden")
+	assert.Equal(t, "the request has been exempted by the TenantException hotfix until 2021-09-01T12:00:00Z: Container image docker.io/nginx is forbidden", exceptionWarning(exception, denied))
+}
@@ -0,0 +1,52 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/utils"
+)
+
+var exemptionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capsule_webhook_exemptions_total",
+	Help: "The admission requests bypassing the Capsule webhooks, by webhook path and matching exemption.",
+}, []string{"webhook", "kind", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(exemptionsTotal)
+}
+
+// exemptedBy returns the kind and the name of the exemption matching the user performing the request, if any.
+func exemptedBy(req admission.Request, exemptions *capsulev1alpha1.ExemptionsSpec) (kind, name string, ok bool) {
+	if exemptions == nil {
+		return "", "", false
+	}
+
+	for _, user := range exemptions.Users {
+		if req.UserInfo.Username == user {
+			return "User", user, true
+		}
+	}
+
+	for _, sa := range exemptions.ServiceAccounts {
+		if req.UserInfo.Username == fmt.Sprintf("system:serviceaccount:%s", sa) {
+			return "ServiceAccount", sa, true
+		}
+	}
+
+	groupList := utils.NewUserGroupList(req.UserInfo.Groups)
+	for _, group := range exemptions.Groups {
+		if groupList.Find(group) {
+			return "Group", group, true
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,50 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+func TestExemptedBy(t *testing.T) {
+	exemptions := &capsulev1alpha1.ExemptionsSpec{
+		Users:           []string{"ci"},
+		Groups:          []string{"backup"},
+		ServiceAccounts: []string{"velero:velero"},
+	}
+
+	request := func(username string, groups ...string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups},
+		}}
+	}
+
+	for name, tc := range map[string]struct {
+		req  admission.Request
+		kind string
+		ok   bool
+	}{
+		"user":            {req: request("ci"), kind: "User", ok: true},
+		"group":           {req: request("bob", "system:authenticated", "backup"), kind: "Group", ok: true},
+		"service account": {req: request("system:serviceaccount:velero:velero"), kind: "ServiceAccount", ok: true},
+		"other namespace": {req: request("system:serviceaccount:oil-production:velero")},
+		"tenant owner":    {req: request("alice", "capsule.clastix.io")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			kind, _, ok := exemptedBy(tc.req, exemptions)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.kind, kind)
+		})
+	}
+
+	_, _, ok := exemptedBy(request("ci"), nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package externalsecret
+
+import (
+	"fmt"
+	"strings"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+type clusterSecretStoreForbidden struct {
+	name string
+	spec *capsulev1beta1.AllowedListSpec
+}
+
+func NewClusterSecretStoreForbidden(name string, spec *capsulev1beta1.AllowedListSpec) error {
+	return &clusterSecretStoreForbidden{
+		name: name,
+		spec: spec,
+	}
+}
+
+func (f clusterSecretStoreForbidden) Error() (err string) {
+	err = fmt.Sprintf("ClusterSecretStore %s is forbidden for the current Tenant", f.name)
+
+	if f.spec == nil {
+		return
+	}
+
+	if len(f.spec.Exact) > 0 {
+		err += fmt.Sprintf(", one of the following (%s)", strings.Join(f.spec.Exact, ", "))
+	}
+	if len(f.spec.Regex) > 0 {
+		err += fmt.Sprintf(", or matching the regex %s", f.spec.Regex)
+	}
+
+	return
+}
+
+type keyForbidden struct {
+	path   string
+	key    string
+	prefix string
+}
+
+func NewKeyForbidden(path, key, prefix string) error {
+	return &keyForbidden{
+		path:   path,
+		key:    key,
+		prefix: prefix,
+	}
+}
+
+func (f keyForbidden) Error() string {
+	return fmt.Sprintf("%s %s is forbidden for the current Tenant, the remote keys must start with %s", f.path, f.key, f.prefix)
+}
+
+type namespaceForbidden struct {
+	path      string
+	namespace string
+}
+
+func NewNamespaceForbidden(path, namespace string) error {
+	return &namespaceForbidden{
+		path:      path,
+		namespace: namespace,
+	}
+}
+
+func (f namespaceForbidden) Error() string {
+	return fmt.Sprintf("%s cannot reference the Namespace %s, the SecretStore can reference its own Namespace only", f.path, f.namespace)
+}
@@ -0,0 +1,229 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct{}
+
+// Handler validates the External Secrets Operator resources of the Tenant Namespaces: the ExternalSecrets can
+// reference only the allowed ClusterSecretStores and the remote keys starting with the Tenant prefix, while the
+// SecretStores cannot reference the credentials of other Namespaces. The shared operator cannot be used then to read
+// the secrets of the other Tenants.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, _ *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, recorder, req)
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, _ *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, recorder, req)
+	}
+}
+
+func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *handler) validate(ctx context.Context, c client.Client, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tnt, err := utils.TenantForNamespace(ctx, c, req.Namespace)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if tnt == nil {
+		return nil
+	}
+
+	var denied error
+
+	switch obj.GetKind() {
+	case "ExternalSecret":
+		denied = validateExternalSecret(obj, tnt)
+	case "SecretStore":
+		if refs := foreignNamespaces(obj, req.Namespace); len(refs) > 0 {
+			denied = NewNamespaceForbidden(refs[0].path, refs[0].value)
+		}
+	}
+
+	if denied == nil {
+		return nil
+	}
+
+	recorder.Eventf(tnt, corev1.EventTypeWarning, "ForbiddenExternalSecret", "%s %s/%s is forbidden: %s", obj.GetKind(), req.Namespace, obj.GetName(), denied.Error())
+
+	response := admission.Denied(denied.Error())
+
+	return &response
+}
+
+func validateExternalSecret(obj *unstructured.Unstructured, tnt *capsulev1beta1.Tenant) error {
+	var allowed *capsulev1beta1.AllowedListSpec
+	if tnt.Spec.ExternalSecrets != nil {
+		allowed = tnt.Spec.ExternalSecrets.AllowedClusterSecretStores
+	}
+
+	for _, storeName := range clusterSecretStores(obj) {
+		if allowed == nil || (!allowed.ExactMatch(storeName) && !allowed.RegexMatch(storeName)) {
+			return NewClusterSecretStoreForbidden(storeName, allowed)
+		}
+	}
+
+	prefix := tnt.ExternalSecretsKeyPrefix()
+
+	for _, ref := range remoteKeys(obj) {
+		if !keyAllowed(ref.value, prefix) {
+			return NewKeyForbidden(ref.path, ref.value, prefix)
+		}
+	}
+
+	return nil
+}
+
+type reference struct {
+	path  string
+	value string
+}
+
+// clusterSecretStores returns the names of the ClusterSecretStores referenced by the ExternalSecret, by its store
+// reference and by the v1beta1 source references overriding it for each data and dataFrom entry.
+func clusterSecretStores(obj *unstructured.Unstructured) (names []string) {
+	add := func(item map[string]interface{}, fields ...string) {
+		kind, _, _ := unstructured.NestedString(item, append(fields, "kind")...)
+		name, _, _ := unstructured.NestedString(item, append(fields, "name")...)
+
+		if kind == "ClusterSecretStore" {
+			names = append(names, name)
+		}
+	}
+
+	add(obj.Object, "spec", "secretStoreRef")
+
+	for _, field := range []string{"data", "dataFrom"} {
+		items, _, _ := unstructured.NestedSlice(obj.Object, "spec", field)
+		for _, item := range items {
+			if entry, ok := item.(map[string]interface{}); ok {
+				add(entry, "sourceRef", "storeRef")
+			}
+		}
+	}
+
+	return names
+}
+
+// remoteKeys returns the remote keys and paths referenced by the ExternalSecret, for both the v1alpha1 and v1beta1
+// versions: a missing find path is returned as empty, since it matches the whole store.
+func remoteKeys(obj *unstructured.Unstructured) (refs []reference) {
+	data, _, _ := unstructured.NestedSlice(obj.Object, "spec", "data")
+	for i, item := range data {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		key, _, _ := unstructured.NestedString(entry, "remoteRef", "key")
+		refs = append(refs, reference{path: fmt.Sprintf("spec.data[%d].remoteRef.key", i), value: key})
+	}
+
+	dataFrom, _, _ := unstructured.NestedSlice(obj.Object, "spec", "dataFrom")
+	for i, item := range dataFrom {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if key, ok, _ := unstructured.NestedString(entry, "key"); ok {
+			refs = append(refs, reference{path: fmt.Sprintf("spec.dataFrom[%d].key", i), value: key})
+		}
+
+		if _, ok := entry["extract"]; ok {
+			key, _, _ := unstructured.NestedString(entry, "extract", "key")
+			refs = append(refs, reference{path: fmt.Sprintf("spec.dataFrom[%d].extract.key", i), value: key})
+		}
+
+		if _, ok := entry["find"]; ok {
+			path, _, _ := unstructured.NestedString(entry, "find", "path")
+			refs = append(refs, reference{path: fmt.Sprintf("spec.dataFrom[%d].find.path", i), value: path})
+		}
+	}
+
+	return refs
+}
+
+// keyAllowed returns true for the keys starting with the prefix and not escaping it through a parent segment.
+func keyAllowed(key, prefix string) bool {
+	if !strings.HasPrefix(key, prefix) {
+		return false
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// foreignNamespaces returns the namespace fields of the SecretStore provider referencing a different Namespace, as the
+// ones of the credentials Secrets and ServiceAccounts, sorted by path.
+func foreignNamespaces(obj *unstructured.Unstructured, namespace string) (refs []reference) {
+	provider, _, _ := unstructured.NestedMap(obj.Object, "spec", "provider")
+
+	var walk func(value interface{}, path string)
+
+	walk = func(value interface{}, path string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, item := range v {
+				if ns, ok := item.(string); ok && key == "namespace" && ns != namespace {
+					refs = append(refs, reference{path: path + ".namespace", value: ns})
+
+					continue
+				}
+
+				walk(item, path+"."+key)
+			}
+		case []interface{}:
+			for i, item := range v {
+				walk(item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+
+	walk(provider, "spec.provider")
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].path < refs[j].path
+	})
+
+	return refs
+}
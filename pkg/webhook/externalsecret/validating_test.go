@@ -0,0 +1,134 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package externalsecret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func externalSecret(storeKind, storeName string, data []interface{}, dataFrom []interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "external-secrets.io/v1beta1",
+		"kind":       "ExternalSecret",
+		"spec": map[string]interface{}{
+			"secretStoreRef": map[string]interface{}{
+				"kind": storeKind,
+				"name": storeName,
+			},
+			"data":     data,
+			"dataFrom": dataFrom,
+		},
+	}}
+}
+
+func TestRemoteKeys(t *testing.T) {
+	obj := externalSecret("SecretStore", "vault", []interface{}{
+		map[string]interface{}{"secretKey": "password", "remoteRef": map[string]interface{}{"key": "oil/db"}},
+	}, []interface{}{
+		map[string]interface{}{"key": "oil/legacy"},
+		map[string]interface{}{"extract": map[string]interface{}{"key": "oil/app"}},
+		map[string]interface{}{"find": map[string]interface{}{"name": map[string]interface{}{"regexp": ".*"}}},
+	})
+
+	assert.Equal(t, []reference{
+		{path: "spec.data[0].remoteRef.key", value: "oil/db"},
+		{path: "spec.dataFrom[0].key", value: "oil/legacy"},
+		{path: "spec.dataFrom[1].extract.key", value: "oil/app"},
+		{path: "spec.dataFrom[2].find.path", value: ""},
+	}, remoteKeys(obj))
+}
+
+func TestKeyAllowed(t *testing.T) {
+	assert.True(t, keyAllowed("oil/db", "oil/"))
+	assert.False(t, keyAllowed("gas/db", "oil/"))
+	assert.False(t, keyAllowed("oil/../gas/db", "oil/"))
+	assert.False(t, keyAllowed("", "oil/"))
+}
+
+func TestValidateExternalSecret(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil"}}
+
+	data := []interface{}{
+		map[string]interface{}{"remoteRef": map[string]interface{}{"key": "oil/db"}},
+	}
+
+	assert.NoError(t, validateExternalSecret(externalSecret("SecretStore", "vault", data, nil), tnt))
+	assert.Error(t, validateExternalSecret(externalSecret("ClusterSecretStore", "shared", data, nil), tnt))
+
+	tnt.Spec.ExternalSecrets = &capsulev1beta1.ExternalSecretsSpec{
+		AllowedClusterSecretStores: &capsulev1beta1.AllowedListSpec{Exact: []string{"shared"}},
+		KeyPrefix:                  "teams/oil/",
+	}
+
+	assert.Error(t, validateExternalSecret(externalSecret("ClusterSecretStore", "shared", data, nil), tnt))
+
+	data = []interface{}{
+		map[string]interface{}{"remoteRef": map[string]interface{}{"key": "teams/oil/db"}},
+	}
+
+	assert.NoError(t, validateExternalSecret(externalSecret("ClusterSecretStore", "shared", data, nil), tnt))
+	assert.Error(t, validateExternalSecret(externalSecret("ClusterSecretStore", "other", data, nil), tnt))
+	// the v1beta1 source references override the store of each entry
+	data = []interface{}{
+		map[string]interface{}{
+			"remoteRef": map[string]interface{}{"key": "teams/oil/db"},
+			"sourceRef": map[string]interface{}{"storeRef": map[string]interface{}{"kind": "ClusterSecretStore", "name": "other"}},
+		},
+	}
+
+	assert.Error(t, validateExternalSecret(externalSecret("SecretStore", "vault", data, nil), tnt))
+	assert.Error(t, validateExternalSecret(externalSecret("SecretStore", "vault", nil, []interface{}{
+		map[string]interface{}{
+			"extract":   map[string]interface{}{"key": "teams/oil/app"},
+			"sourceRef": map[string]interface{}{"storeRef": map[string]interface{}{"kind": "ClusterSecretStore", "name": "other"}},
+		},
+	}), tnt))
+}
+
+func TestClusterSecretStores(t *testing.T) {
+	sourceRef := func(kind, name string) map[string]interface{} {
+		return map[string]interface{}{"storeRef": map[string]interface{}{"kind": kind, "name": name}}
+	}
+
+	obj := externalSecret("ClusterSecretStore", "shared", []interface{}{
+		map[string]interface{}{"remoteRef": map[string]interface{}{"key": "oil/db"}, "sourceRef": sourceRef("ClusterSecretStore", "data")},
+		map[string]interface{}{"remoteRef": map[string]interface{}{"key": "oil/db"}, "sourceRef": sourceRef("SecretStore", "vault")},
+	}, []interface{}{
+		map[string]interface{}{"extract": map[string]interface{}{"key": "oil/app"}, "sourceRef": sourceRef("ClusterSecretStore", "data-from")},
+	})
+
+	assert.Equal(t, []string{"shared", "data", "data-from"}, clusterSecretStores(obj))
+}
+
+func TestForeignNamespaces(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "SecretStore",
+		"spec": map[string]interface{}{
+			"provider": map[string]interface{}{
+				"vault": map[string]interface{}{
+					"path": "secret",
+					"auth": map[string]interface{}{
+						"tokenSecretRef": map[string]interface{}{"name": "token", "namespace": "gas-production"},
+						"kubernetes": map[string]interface{}{
+							"serviceAccountRef": map[string]interface{}{"name": "vault", "namespace": "oil-production"},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	assert.Equal(t, []reference{
+		{path: "spec.provider.vault.auth.tokenSecretRef.namespace", value: "gas-production"},
+	}, foreignNamespaces(obj, "oil-production"))
+	assert.Equal(t, []reference{
+		{path: "spec.provider.vault.auth.kubernetes.serviceAccountRef.namespace", value: "oil-production"},
+	}, foreignNamespaces(obj, "gas-production"))
+}
@@ -0,0 +1,99 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type hostnames struct{}
+
+// Hostnames denies the wildcard and zone apex hostnames of the Gateway API resources, if not allowed for the Tenant:
+// the Gateway API CRDs are decoded as unstructured objects, avoiding a dependency on a specific release.
+func Hostnames() capsulewebhook.Handler {
+	return &hostnames{}
+}
+
+func (h *hostnames) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *hostnames) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *hostnames) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *hostnames) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if len(tntList.Items) == 0 {
+		return nil
+	}
+
+	tnt := tntList.Items[0]
+
+	if !tnt.IsWildcardDenied() && !tnt.IsApexDenied() {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := decoder.Decode(req, obj); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	for _, hostname := range hostnamesOf(obj) {
+		if response := utils.DenyHostname(&tnt, hostname, req, recorder); response != nil {
+			return response
+		}
+	}
+
+	return nil
+}
+
+// hostnamesOf returns the hostnames of the Gateway listeners, or the ones of the Routes.
+func hostnamesOf(obj *unstructured.Unstructured) (hostnames []string) {
+	if obj.GetKind() != "Gateway" {
+		hostnames, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "hostnames")
+
+		return
+	}
+
+	listeners, _, _ := unstructured.NestedSlice(obj.Object, "spec", "listeners")
+	for _, listener := range listeners {
+		l, ok := listener.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if hostname, ok, _ := unstructured.NestedString(l, "hostname"); ok {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,32 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestHostnamesOf(t *testing.T) {
+	gateway := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Gateway",
+		"spec": map[string]interface{}{
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "http", "hostname": "*.bigorg.com"},
+				map[string]interface{}{"name": "any"},
+			},
+		},
+	}}
+	assert.Equal(t, []string{"*.bigorg.com"}, hostnamesOf(gateway))
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "HTTPRoute",
+		"spec": map[string]interface{}{
+			"hostnames": []interface{}{"oil.bigorg.com", "bigorg.com"},
+		},
+	}}
+	assert.Equal(t, []string{"oil.bigorg.com", "bigorg.com"}, hostnamesOf(route))
+}
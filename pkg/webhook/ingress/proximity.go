@@ -0,0 +1,193 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package ingress
+
+import (
+	"context"
+	"strings"
+
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type proximity struct{}
+
+// Proximity warns about the Ingress hostnames close to the ones used by the other Tenants, as the ones differing by
+// a single character or matched by a wildcard, without denying them: the colliding ones are denied by Collision.
+// The hostnames of the other Tenants are not disclosed in the warnings.
+func Proximity() capsulewebhook.Handler {
+	return &proximity{}
+}
+
+func (r *proximity) OnCreate(client client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return r.warn(client, decoder)
+}
+
+func (r *proximity) OnUpdate(client client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return r.warn(client, decoder)
+}
+
+func (r *proximity) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (r *proximity) warn(clt client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		ing, err := ingressFromRequest(req, decoder)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tenant, err := tenantFromIngress(ctx, clt, ing)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if tenant == nil {
+			return nil
+		}
+
+		others, err := otherTenantsHostnames(ctx, clt, ing, tenant)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		for hostname := range ing.HostnamePathsPairs() {
+			for _, other := range others.List() {
+				if closeHostnames(hostname, other) {
+					capsulewebhook.Warn(ctx, "The hostname %s is close to a hostname used by another Tenant", hostname)
+
+					break
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// otherTenantsHostnames returns the hostnames of the Ingresses, of the same version of the given one, in the
+// Namespaces of the Tenants other than the given one.
+func otherTenantsHostnames(ctx context.Context, clt client.Client, ing Ingress, tenant *capsulev1beta1.Tenant) (sets.String, error) {
+	tenantList := &capsulev1beta1.TenantList{}
+	if err := clt.List(ctx, tenantList); err != nil {
+		return nil, err
+	}
+
+	namespaces := sets.NewString()
+
+	for _, tnt := range tenantList.Items {
+		if tnt.GetName() != tenant.GetName() {
+			namespaces.Insert(tnt.Status.Namespaces...)
+		}
+	}
+
+	hostnames := sets.NewString()
+
+	if namespaces.Len() == 0 {
+		return hostnames, nil
+	}
+
+	switch ing.(type) {
+	case Extension:
+		list := &extensionsv1beta1.IngressList{}
+		if err := clt.List(ctx, list); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			if namespaces.Has(list.Items[i].GetNamespace()) {
+				hostnames.Insert(sets.StringKeySet(Extension{Ingress: &list.Items[i]}.HostnamePathsPairs()).List()...)
+			}
+		}
+	case NetworkingV1:
+		list := &networkingv1.IngressList{}
+		if err := clt.List(ctx, list); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			if namespaces.Has(list.Items[i].GetNamespace()) {
+				hostnames.Insert(sets.StringKeySet(NetworkingV1{Ingress: &list.Items[i]}.HostnamePathsPairs()).List()...)
+			}
+		}
+	case NetworkingV1Beta1:
+		list := &networkingv1beta1.IngressList{}
+		if err := clt.List(ctx, list); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			if namespaces.Has(list.Items[i].GetNamespace()) {
+				hostnames.Insert(sets.StringKeySet(NetworkingV1Beta1{Ingress: &list.Items[i]}.HostnamePathsPairs()).List()...)
+			}
+		}
+	}
+
+	hostnames.Delete("")
+
+	return hostnames, nil
+}
+
+// closeHostnames reports if the hostnames are the same, if either is a wildcard matching the other, or if they differ
+// by a single character, as upon typos or look-alike names.
+func closeHostnames(hostname, other string) bool {
+	if len(hostname) == 0 || len(other) == 0 {
+		return false
+	}
+
+	if hostname == other || wildcardMatches(hostname, other) || wildcardMatches(other, hostname) {
+		return true
+	}
+
+	return withinOneEdit(hostname, other)
+}
+
+// wildcardMatches reports if the wildcard hostname matches the given one, covering a single DNS label.
+func wildcardMatches(wildcard, hostname string) bool {
+	if !strings.HasPrefix(wildcard, "*.") || !strings.HasSuffix(hostname, wildcard[1:]) {
+		return false
+	}
+
+	label := strings.TrimSuffix(hostname, wildcard[1:])
+
+	return len(label) > 0 && !strings.Contains(label, ".")
+}
+
+// withinOneEdit reports if the strings differ by at most a single inserted, removed or replaced character.
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	if len(b)-len(a) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+
+	if i == len(b) {
+		return true
+	}
+
+	if len(a) == len(b) {
+		return a[i+1:] == b[i+1:]
+	}
+
+	return a[i:] == b[i+1:]
+}
@@ -0,0 +1,29 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package ingress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseHostnames(t *testing.T) {
+	for _, tc := range []struct {
+		hostname, other string
+		close           bool
+	}{
+		{"shop.oil.acme.com", "shop.oil.acme.com", true},
+		{"shop.oil.acme.com", "shop.oi1.acme.com", true},
+		{"shop.oil.acme.com", "shop.oill.acme.com", true},
+		{"shop.oil.acme.com", "shop.ol.acme.com", true},
+		{"shop.oil.acme.com", "shop.gas.acme.com", false},
+		{"*.oil.acme.com", "shop.oil.acme.com", true},
+		{"shop.oil.acme.com", "*.oil.acme.com", true},
+		{"*.acme.com", "shop.oil.acme.com", false},
+		{"oil.acme.com", "", false},
+	} {
+		assert.Equal(t, tc.close, closeHostnames(tc.hostname, tc.other), "%s and %s", tc.hostname, tc.other)
+	}
+}
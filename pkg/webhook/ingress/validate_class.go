@@ -45,6 +45,10 @@ func (r *class) OnCreate(client client.Client, decoder *admission.Decoder, recor
 			return nil
 		}
 
+		if deprecatedClassAnnotation(ingress) {
+			capsulewebhook.Warn(ctx, "The %s annotation is deprecated: set the Ingress class with the spec.ingressClassName field", annotationName)
+		}
+
 		if err = r.validateClass(*tenant, ingress.IngressClass()); err == nil {
 			return nil
 		}
@@ -86,6 +90,10 @@ func (r *class) OnUpdate(client client.Client, decoder *admission.Decoder, recor
 			return nil
 		}
 
+		if deprecatedClassAnnotation(ingress) {
+			capsulewebhook.Warn(ctx, "The %s annotation is deprecated: set the Ingress class with the spec.ingressClassName field", annotationName)
+		}
+
 		if err = r.validateClass(*tenant, ingress.IngressClass()); err == nil {
 			return nil
 		}
@@ -136,3 +144,20 @@ func (r *class) validateClass(tenant capsulev1beta1.Tenant, ingressClass *string
 
 	return nil
 }
+
+// deprecatedClassAnnotation reports if the Ingress sets its class with the deprecated annotation, rather than with the
+// ingressClassName field available since networking.k8s.io/v1beta1.
+func deprecatedClassAnnotation(ingress Ingress) bool {
+	var annotations map[string]string
+
+	switch ing := ingress.(type) {
+	case NetworkingV1:
+		annotations = ing.GetAnnotations()
+	case NetworkingV1Beta1:
+		annotations = ing.GetAnnotations()
+	}
+
+	_, ok := annotations[annotationName]
+
+	return ok
+}
@@ -2,10 +2,7 @@ package ingress
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,24 +54,18 @@ func (h *wildcard) wildcardHandler(ctx context.Context, clt client.Client, req a
 
 	tnt := tntList.Items[0]
 
-	// Check if Annotation in manifest has value "capsule.clastix.io/deny-wildcard" set to "true".
-	if tnt.IsWildcardDenied() {
-		// Retrieve ingress resource from request.
-		ingress, err := ingressFromRequest(req, decoder)
-		if err != nil {
-			return utils.ErroredResponse(err)
-		}
-		// Loop over all the hosts present on the ingress.
-		for host := range ingress.HostnamePathsPairs() {
-			// Check if one of the host has wildcard.
-			if strings.HasPrefix(host, "*") {
-				// In case of wildcard, generate an event and then return.
-				recorder.Eventf(&tnt, corev1.EventTypeWarning, "Wildcard denied", "%s %s/%s cannot be %s", req.Kind.String(), req.Namespace, req.Name, strings.ToLower(string(req.Operation)))
-
-				response := admission.Denied(fmt.Sprintf("Wildcard denied for tenant %s\n", tnt.GetName()))
-
-				return &response
-			}
+	if !tnt.IsWildcardDenied() && !tnt.IsApexDenied() {
+		return nil
+	}
+	// Retrieve ingress resource from request.
+	ingress, err := ingressFromRequest(req, decoder)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+	// Loop over all the hosts present on the ingress.
+	for host := range ingress.HostnamePathsPairs() {
+		if response := utils.DenyHostname(&tnt, host, req, recorder); response != nil {
+			return response
 		}
 	}
 
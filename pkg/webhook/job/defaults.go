@@ -0,0 +1,126 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"encoding/json"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type defaults struct{}
+
+// Defaults sets the Tenant ttlSecondsAfterFinished to the Jobs not specifying it,
+// leaving apart the ones spawned by the CronJobs, retained according to their history limits.
+// The Jobs not declaring a Kueue queue are submitted to the Tenant LocalQueue, when the Tenant declares the batch
+// queueing, and created suspended until admitted by Kueue.
+func Defaults() capsulewebhook.Handler {
+	return &defaults{}
+}
+
+func (d *defaults) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		job := &batchv1.Job{}
+		if err := decoder.Decode(req, job); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tnt, err := utils.TenantForNamespace(ctx, c, job.Namespace)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if tnt == nil {
+			return nil
+		}
+
+		original, err := json.Marshal(job)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		queued := applyJobQueue(job, tnt.QueueName())
+
+		if !applyJobTTL(job, tnt) && !queued {
+			return nil
+		}
+
+		mutated, err := json.Marshal(job)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		response := admission.PatchResponseFromRaw(original, mutated)
+
+		return &response
+	}
+}
+
+func (d *defaults) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (d *defaults) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// applyJobTTL sets the Tenant ttlSecondsAfterFinished on the Job, reporting if it has been set.
+func applyJobTTL(job *batchv1.Job, tnt *capsulev1beta1.Tenant) bool {
+	if job.Spec.TTLSecondsAfterFinished != nil || spawnedByCronJob(job) {
+		return false
+	}
+
+	if tnt.Spec.GarbageCollection == nil || tnt.Spec.GarbageCollection.JobTTLSecondsAfterFinished == nil {
+		return false
+	}
+
+	ttl := *tnt.Spec.GarbageCollection.JobTTLSecondsAfterFinished
+	job.Spec.TTLSecondsAfterFinished = &ttl
+
+	return true
+}
+
+// applyJobQueue sets the given Kueue queue on the Job not declaring any, suspending it as Kueue expects,
+// reporting if it has been set.
+func applyJobQueue(job *batchv1.Job, queue string) bool {
+	if len(queue) == 0 {
+		return false
+	}
+
+	if _, ok := job.GetLabels()[capsulev1beta1.QueueNameLabel]; ok {
+		return false
+	}
+
+	labels := job.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[capsulev1beta1.QueueNameLabel] = queue
+	job.SetLabels(labels)
+
+	suspend := true
+	job.Spec.Suspend = &suspend
+
+	return true
+}
+
+func spawnedByCronJob(job *batchv1.Job) bool {
+	owner := metav1.GetControllerOf(job)
+
+	return owner != nil && owner.Kind == "CronJob"
+}
@@ -0,0 +1,51 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestApplyJobTTL(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{Spec: capsulev1beta1.TenantSpec{
+		GarbageCollection: &capsulev1beta1.GarbageCollectionOptions{JobTTLSecondsAfterFinished: pointer.Int32Ptr(3600)},
+	}}
+
+	job := &batchv1.Job{}
+	if assert.True(t, applyJobTTL(job, tnt)) {
+		assert.Equal(t, int32(3600), *job.Spec.TTLSecondsAfterFinished)
+	}
+	// the declared TTL is kept
+	job.Spec.TTLSecondsAfterFinished = pointer.Int32Ptr(60)
+	assert.False(t, applyJobTTL(job, tnt))
+	assert.Equal(t, int32(60), *job.Spec.TTLSecondsAfterFinished)
+	// the Jobs spawned by the CronJobs are retained according to their history limits
+	spawned := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "backup", Controller: pointer.BoolPtr(true)}}}}
+	assert.False(t, applyJobTTL(spawned, tnt))
+	assert.Nil(t, spawned.Spec.TTLSecondsAfterFinished)
+
+	assert.False(t, applyJobTTL(&batchv1.Job{}, &capsulev1beta1.Tenant{}))
+}
+
+func TestApplyJobQueue(t *testing.T) {
+	job := &batchv1.Job{}
+	assert.False(t, applyJobQueue(job, ""))
+
+	if assert.True(t, applyJobQueue(job, "oil")) {
+		assert.Equal(t, "oil", job.GetLabels()[capsulev1beta1.QueueNameLabel])
+		assert.True(t, *job.Spec.Suspend)
+	}
+	// the declared queue is kept
+	queued := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{capsulev1beta1.QueueNameLabel: "gpu"}}}
+	assert.False(t, applyJobQueue(queued, "oil"))
+	assert.Equal(t, "gpu", queued.GetLabels()[capsulev1beta1.QueueNameLabel])
+	assert.Nil(t, queued.Spec.Suspend)
+}
@@ -0,0 +1,79 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package managed
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+// EditableAnnotation opts a Capsule managed object out of the protection, allowing the Tenant owners to edit it:
+// the changes to the fields declared by Capsule are reverted at the next Tenant reconciliation.
+const EditableAnnotation = "capsule.clastix.io/editable"
+
+type handler struct {
+}
+
+// Handler denies the Tenant owners the update and deletion of the objects Capsule manages in the Tenant
+// Namespaces, such as NetworkPolicies, ResourceQuotas, LimitRanges and RoleBindings.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (r *handler) OnCreate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (r *handler) OnDelete(client client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return r.protect(ctx, req, client, decoder, recorder, "deleted")
+	}
+}
+
+func (r *handler) OnUpdate(client client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return r.protect(ctx, req, client, decoder, recorder, "updated")
+	}
+}
+
+func (r *handler) protect(ctx context.Context, req admission.Request, client client.Client, decoder *admission.Decoder, recorder record.EventRecorder, action string) *admission.Response {
+	// the stored object is checked, the Tenant owners cannot opt out their own changes
+	obj := &unstructured.Unstructured{}
+	if err := decoder.DecodeRaw(req.OldObject, obj); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	l, _ := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+
+	tenantName, ok := obj.GetLabels()[l]
+	if !ok || obj.GetAnnotations()[EditableAnnotation] == "true" {
+		return nil
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	if err := client.Get(ctx, types.NamespacedName{Name: tenantName}, tnt); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	kind := req.Kind.Kind
+
+	recorder.Eventf(tnt, corev1.EventTypeWarning, fmt.Sprintf("Managed%sChange", kind), "%s %s/%s managed by Capsule cannot be %s", kind, req.Namespace, req.Name, action)
+
+	response := admission.Denied(fmt.Sprintf("%s %s is managed by Capsule for the Tenant %s and cannot be %s: please, reach out to the system administrators", kind, req.Name, tenantName, action))
+
+	return &response
+}
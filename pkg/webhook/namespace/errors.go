@@ -34,6 +34,30 @@ func (namespaceQuotaExceededError) Error() string {
 	return "Cannot exceed Namespace quota: please, reach out to the system administrators"
 }
 
+type namespaceCreationRateExceededError struct {
+	rate *capsulev1beta1.NamespaceCreationRate
+}
+
+func NewNamespaceCreationRateExceededError(rate *capsulev1beta1.NamespaceCreationRate) error {
+	return &namespaceCreationRateExceededError{rate: rate}
+}
+
+func (e namespaceCreationRateExceededError) Error() string {
+	return fmt.Sprintf("Cannot create more than %d Namespaces every %s in the current Tenant: please, retry later", e.rate.Limit, e.rate.Period.Duration)
+}
+
+type namespaceLabelReservedError struct {
+	label string
+}
+
+func NewNamespaceLabelReservedError(label string) error {
+	return &namespaceLabelReservedError{label: label}
+}
+
+func (e namespaceLabelReservedError) Error() string {
+	return fmt.Sprintf("Label %s is reserved to the cluster administrators: please, reach out to them", e.label)
+}
+
 type namespaceLabelForbiddenError struct {
 	label string
 	spec  *capsulev1beta1.ForbiddenListSpec
@@ -38,30 +38,37 @@ func (r *prefixHandler) OnCreate(clt client.Client, decoder *admission.Decoder,
 			return utils.ErroredResponse(err)
 		}
 
-		if exp, _ := r.configuration.ProtectedNamespaceRegexp(); exp != nil {
-			if matched := exp.MatchString(ns.GetName()); matched {
-				response := admission.Denied(fmt.Sprintf("Creating namespaces with name matching %s regexp is not allowed; please, reach out to the system administrators", exp.String()))
+		rules, err := r.configuration.ProtectedNamespaces()
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		for _, rule := range rules {
+			if rule.Protects(ns.GetName()) {
+				response := admission.Denied(fmt.Sprintf("Creating namespaces with name matching %s regexp is not allowed; please, reach out to the system administrators", rule.Regexp.String()))
 
 				return &response
 			}
 		}
 
-		if r.configuration.ForceTenantPrefix() {
-			tnt := &capsulev1beta1.Tenant{}
+		tnt := &capsulev1beta1.Tenant{}
 
-			for _, or := range ns.ObjectMeta.OwnerReferences {
-				// retrieving the selected Tenant
-				if err := clt.Get(ctx, types.NamespacedName{Name: or.Name}, tnt); err != nil {
-					return utils.ErroredResponse(err)
-				}
+		for _, or := range ns.ObjectMeta.OwnerReferences {
+			// retrieving the selected Tenant
+			if err := clt.Get(ctx, types.NamespacedName{Name: or.Name}, tnt); err != nil {
+				return utils.ErroredResponse(err)
+			}
 
-				if e := fmt.Sprintf("%s-%s", tnt.GetName(), ns.GetName()); !strings.HasPrefix(ns.GetName(), fmt.Sprintf("%s-", tnt.GetName())) {
-					recorder.Eventf(tnt, corev1.EventTypeWarning, "InvalidTenantPrefix", "Namespace %s does not match the expected prefix for the current Tenant", ns.GetName())
+			if !utils.ForceTenantPrefix(r.configuration, tnt) {
+				continue
+			}
+
+			if e := fmt.Sprintf("%s-%s", tnt.GetName(), ns.GetName()); !strings.HasPrefix(ns.GetName(), fmt.Sprintf("%s-", tnt.GetName())) {
+				recorder.Eventf(tnt, corev1.EventTypeWarning, "InvalidTenantPrefix", "Namespace %s does not match the expected prefix for the current Tenant", ns.GetName())
 
-					response := admission.Denied(fmt.Sprintf("The namespace doesn't match the tenant prefix, expected %s", e))
+				response := admission.Denied(fmt.Sprintf("The namespace doesn't match the tenant prefix, expected %s", e))
 
-					return &response
-				}
+				return &response
 			}
 		}
 
@@ -0,0 +1,63 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+func TestPrefixHandlerProtectedNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1alpha1.AddToScheme(scheme))
+
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NoError(t, err)
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Name:      "kube-public",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+		Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"kube-public"}}`)},
+	}}
+
+	for regex, allowed := range map[string]bool{"^kube-.*$": false, "^default$": true} {
+		config := &capsulev1alpha1.CapsuleConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       capsulev1alpha1.CapsuleConfigurationSpec{ProtectedNamespaces: []capsulev1alpha1.ProtectedNamespaceSpec{{Regex: regex}}},
+		}
+		clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(config).Build()
+
+		response := PrefixHandler(configuration.NewCapsuleConfiguration(clt, "default")).OnCreate(clt, decoder, record.NewFakeRecorder(10))(context.Background(), req)
+		if allowed {
+			assert.Nil(t, response, regex)
+		} else if assert.NotNil(t, response, regex) {
+			assert.False(t, response.Allowed, regex)
+		}
+	}
+	// an invalid rule doesn't turn off the other ones
+	config := &capsulev1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       capsulev1alpha1.CapsuleConfigurationSpec{ProtectedNamespaces: []capsulev1alpha1.ProtectedNamespaceSpec{{Regex: "^kube-.*$"}, {Regex: "("}}},
+	}
+	clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(config).Build()
+
+	response := PrefixHandler(configuration.NewCapsuleConfiguration(clt, "default")).OnCreate(clt, decoder, record.NewFakeRecorder(10))(context.Background(), req)
+	if assert.NotNil(t, response) {
+		assert.False(t, response.Allowed)
+	}
+}
@@ -38,13 +38,22 @@ func (r *quotaHandler) OnCreate(client client.Client, decoder *admission.Decoder
 				return utils.ErroredResponse(err)
 			}
 
-			if tnt.IsFull() {
+			allocated, err := childrenQuota(ctx, client, tnt.GetName())
+			if err != nil {
+				return utils.ErroredResponse(err)
+			}
+
+			if quota := tnt.Spec.NamespaceOptions; tnt.IsFull() || (quota != nil && quota.Quota != nil && len(tnt.Status.Namespaces)+allocated >= int(*quota.Quota)) {
 				recorder.Eventf(tnt, corev1.EventTypeWarning, "NamespaceQuotaExceded", "Namespace %s cannot be attached, quota exceeded for the current Tenant", ns.GetName())
 
 				response := admission.Denied(NewNamespaceQuotaExceededError().Error())
 
 				return &response
 			}
+
+			if quota := tnt.Spec.NamespaceOptions; quota != nil && quota.Quota != nil && len(tnt.Status.Namespaces)+allocated+1 == int(*quota.Quota) {
+				capsulewebhook.Warn(ctx, "Namespace %s takes the last slot of the quota of %d Namespaces of the current Tenant", ns.GetName(), *quota.Quota)
+			}
 		}
 		// creating NS that is not bounded to any Tenant
 		return nil
@@ -62,3 +71,22 @@ func (r *quotaHandler) OnUpdate(client.Client, *admission.Decoder, record.EventR
 		return nil
 	}
 }
+
+// childrenQuota returns the sum of the namespace quotas of the child Tenants of the given one, allocated out of its
+// own quota.
+func childrenQuota(ctx context.Context, c client.Client, parent string) (allocated int, err error) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err = c.List(ctx, tntList); err != nil {
+		return 0, err
+	}
+
+	for _, tnt := range tntList.Items {
+		if tnt.Spec.Parent != parent || tnt.Spec.NamespaceOptions == nil || tnt.Spec.NamespaceOptions.Quota == nil {
+			continue
+		}
+
+		allocated += int(*tnt.Spec.NamespaceOptions.Quota)
+	}
+
+	return allocated, nil
+}
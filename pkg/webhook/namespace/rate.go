@@ -0,0 +1,93 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type creationRateHandler struct {
+}
+
+func CreationRateHandler() capsulewebhook.Handler {
+	return &creationRateHandler{}
+}
+
+func (r *creationRateHandler) OnCreate(client client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		ns := &corev1.Namespace{}
+		if err := decoder.Decode(req, ns); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		for _, objectRef := range ns.ObjectMeta.OwnerReferences {
+			// retrieving the selected Tenant
+			tnt := &capsulev1beta1.Tenant{}
+			if err := client.Get(ctx, types.NamespacedName{Name: objectRef.Name}, tnt); err != nil {
+				return utils.ErroredResponse(err)
+			}
+
+			rate := tnt.NamespaceCreationRate()
+			if rate == nil {
+				continue
+			}
+
+			created, err := recentCreations(ctx, client, tnt)
+			if err != nil {
+				return utils.ErroredResponse(err)
+			}
+
+			if created >= int(rate.Limit) {
+				recorder.Eventf(tnt, corev1.EventTypeWarning, "NamespaceCreationRateExceeded", "Namespace %s cannot be attached, creation rate exceeded for the current Tenant", ns.GetName())
+
+				response := admission.Denied(NewNamespaceCreationRateExceededError(rate).Error())
+
+				return &response
+			}
+
+			if created+1 == int(rate.Limit) {
+				capsulewebhook.Warn(ctx, "Namespace %s reaches the limit of %d Namespaces created every %s in the current Tenant", ns.GetName(), rate.Limit, rate.Period.Duration)
+			}
+		}
+
+		return nil
+	}
+}
+
+func (r *creationRateHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (r *creationRateHandler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// recentCreations returns how many Namespaces have been created in the Tenant within the period of its creation rate:
+// the creations reported in the status are merged with the existing Namespaces, not yet reported.
+func recentCreations(ctx context.Context, clt client.Client, tnt *capsulev1beta1.Tenant) (int, error) {
+	nsList := &corev1.NamespaceList{}
+	if err := clt.List(ctx, nsList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".metadata.ownerReferences[*].capsule", tnt.GetName()),
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(tnt.RecentNamespaceCreations(nsList.Items, time.Now())), nil
+}
@@ -63,6 +63,47 @@ func (r *userMetadataHandler) validateUserMetadata(tnt *capsulev1beta1.Tenant, r
 	return nil
 }
 
+// validateReservedLabels denies the requests setting, changing or removing the Namespace labels selecting the Tenant
+// settings, reserved to Capsule and the cluster administrators.
+func (r *userMetadataHandler) validateReservedLabels(tnt *capsulev1beta1.Tenant, recorder record.EventRecorder, oldLabels, labels map[string]string) *admission.Response {
+	for _, label := range reservedLabels(tnt) {
+		oldValue, oldOk := oldLabels[label]
+		value, ok := labels[label]
+
+		if oldOk == ok && oldValue == value {
+			continue
+		}
+
+		if len(tnt.GetName()) > 0 {
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "ReservedNamespaceLabel", "Label %s is reserved to the cluster administrators for the Namespaces of the current Tenant", label)
+		}
+
+		response := admission.Denied(NewNamespaceLabelReservedError(label).Error())
+
+		return &response
+	}
+
+	return nil
+}
+
+// reservedLabels returns the Namespace labels selecting the Tenant settings: the LimitRange profile one, and the ones
+// of the namespace overrides selectors.
+func reservedLabels(tnt *capsulev1beta1.Tenant) []string {
+	labels := []string{capsulev1beta1.LimitRangeProfileLabel}
+
+	for _, override := range tnt.Spec.NamespaceOverrides {
+		for key := range override.NamespaceSelector.MatchLabels {
+			labels = append(labels, key)
+		}
+
+		for _, expression := range override.NamespaceSelector.MatchExpressions {
+			labels = append(labels, expression.Key)
+		}
+	}
+
+	return labels
+}
+
 func (r *userMetadataHandler) OnCreate(client client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) *admission.Response {
 		ns := &corev1.Namespace{}
@@ -82,6 +123,10 @@ func (r *userMetadataHandler) OnCreate(client client.Client, decoder *admission.
 		labels := ns.GetLabels()
 		annotations := ns.GetAnnotations()
 
+		if response := r.validateReservedLabels(tnt, recorder, nil, labels); response != nil {
+			return response
+		}
+
 		return r.validateUserMetadata(tnt, recorder, labels, annotations)
 	}
 }
@@ -113,6 +158,10 @@ func (r *userMetadataHandler) OnUpdate(client client.Client, decoder *admission.
 			}
 		}
 
+		if response := r.validateReservedLabels(tnt, recorder, oldNs.GetLabels(), newNs.GetLabels()); response != nil {
+			return response
+		}
+
 		var labels, annotations map[string]string
 
 		for key, value := range newNs.GetLabels() {
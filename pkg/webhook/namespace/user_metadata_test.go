@@ -0,0 +1,50 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestValidateReservedLabels(t *testing.T) {
+	handler := &userMetadataHandler{}
+	recorder := record.NewFakeRecorder(10)
+
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil"}}
+
+	profile := map[string]string{capsulev1beta1.LimitRangeProfileLabel: "large"}
+
+	assert.Nil(t, handler.validateReservedLabels(tnt, recorder, nil, map[string]string{"team": "backend"}))
+	assert.Nil(t, handler.validateReservedLabels(tnt, recorder, profile, map[string]string{capsulev1beta1.LimitRangeProfileLabel: "large", "team": "backend"}))
+	// setting, changing or removing the label is denied
+	for _, labels := range [][2]map[string]string{
+		{nil, profile},
+		{profile, {capsulev1beta1.LimitRangeProfileLabel: "small"}},
+		{profile, {}},
+	} {
+		if response := handler.validateReservedLabels(tnt, recorder, labels[0], labels[1]); assert.NotNil(t, response) {
+			assert.False(t, response.Allowed)
+		}
+	}
+}
+
+func TestReservedLabels(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			NamespaceOverrides: []capsulev1beta1.NamespaceOverrideSpec{
+				{NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"environment": "production"}}},
+				{NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpExists}}}},
+			},
+		},
+	}
+
+	assert.ElementsMatch(t, []string{capsulev1beta1.LimitRangeProfileLabel, "environment", "tier"}, reservedLabels(tnt))
+	assert.NotNil(t, (&userMetadataHandler{}).validateReservedLabels(tnt, record.NewFakeRecorder(10), map[string]string{"environment": "development"}, map[string]string{"environment": "production"}))
+}
@@ -0,0 +1,157 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+// nodesGroup is the group of the kubelet credentials.
+const nodesGroup = "system:nodes"
+
+type poolHandler struct {
+	configuration configuration.Configuration
+	version       *version.Version
+}
+
+// PoolHandler prevents the Tenant owners and the Node identities from changing the labels selecting the Tenant
+// node pools, along with the protected taints, bypassing the scheduling constraints.
+func PoolHandler(configuration configuration.Configuration, ver *version.Version) capsulewebhook.Handler {
+	return &poolHandler{
+		configuration: configuration,
+		version:       ver,
+	}
+}
+
+func (r *poolHandler) OnCreate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (r *poolHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (r *poolHandler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		if nodeWebhookSupported, _ := utils.NodeWebhookSupported(r.version); !nodeWebhookSupported {
+			return nil
+		}
+
+		if !utils.IsCapsuleUser(req, r.configuration.UserGroups()) && !sets.NewString(req.UserInfo.Groups...).Has(nodesGroup) {
+			return nil
+		}
+
+		oldNode := &corev1.Node{}
+		if err := decoder.DecodeRaw(req.OldObject, oldNode); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		newNode := &corev1.Node{}
+		if err := decoder.Decode(req, newNode); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := c.List(ctx, tntList); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		labels := poolLabels(tntList.Items)
+		taints := labels.Union(sets.NewString(r.configuration.ProtectedNodeTaints()...))
+
+		if keys := changedLabels(oldNode, newNode, labels); len(keys) > 0 {
+			recorder.Eventf(newNode, corev1.EventTypeWarning, "ProtectedNodeLabel", "Denied modifying the Tenant node pool labels %s", strings.Join(keys, ", "))
+
+			response := admission.Denied(fmt.Sprintf("Unable to update node as the labels %s select the Tenant node pools and are protected by the system administrator", strings.Join(keys, ", ")))
+
+			return &response
+		}
+
+		if keys := changedTaints(oldNode, newNode, taints); len(keys) > 0 {
+			recorder.Eventf(newNode, corev1.EventTypeWarning, "ProtectedNodeTaint", "Denied modifying the protected taints %s", strings.Join(keys, ", "))
+
+			response := admission.Denied(fmt.Sprintf("Unable to update node as the taints %s are protected by the system administrator", strings.Join(keys, ", ")))
+
+			return &response
+		}
+
+		return nil
+	}
+}
+
+// poolLabels returns the label keys used by the Tenants to select their node pools.
+func poolLabels(tenants []capsulev1beta1.Tenant) sets.String {
+	keys := sets.NewString()
+
+	for _, tnt := range tenants {
+		for key := range tnt.Spec.NodeSelector {
+			keys.Insert(key)
+		}
+	}
+
+	return keys
+}
+
+// changedLabels returns the sorted protected label keys added, changed or removed by the update.
+func changedLabels(oldNode, newNode *corev1.Node, protected sets.String) (keys []string) {
+	for key := range protected {
+		oldValue, oldOk := oldNode.GetLabels()[key]
+		newValue, newOk := newNode.GetLabels()[key]
+
+		if oldOk != newOk || oldValue != newValue {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// changedTaints returns the sorted protected taint keys added, changed or removed by the update.
+func changedTaints(oldNode, newNode *corev1.Node, protected sets.String) (keys []string) {
+	taintsByKey := func(node *corev1.Node) map[string][]corev1.Taint {
+		taints := make(map[string][]corev1.Taint)
+
+		for _, taint := range node.Spec.Taints {
+			if protected.Has(taint.Key) {
+				taints[taint.Key] = append(taints[taint.Key], corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
+			}
+		}
+
+		return taints
+	}
+
+	oldTaints, newTaints := taintsByKey(oldNode), taintsByKey(newNode)
+
+	for key := range protected {
+		if !reflect.DeepEqual(oldTaints[key], newTaints[key]) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
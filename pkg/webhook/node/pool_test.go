@@ -0,0 +1,51 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestPoolLabels(t *testing.T) {
+	tenants := []capsulev1beta1.Tenant{
+		{Spec: capsulev1beta1.TenantSpec{NodeSelector: map[string]string{"pool": "oil"}}},
+		{Spec: capsulev1beta1.TenantSpec{NodeSelector: map[string]string{"pool": "gas", "zone": "eu"}}},
+		{},
+	}
+
+	assert.Equal(t, []string{"pool", "zone"}, poolLabels(tenants).List())
+}
+
+func TestChangedLabels(t *testing.T) {
+	node := func(labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+	}
+	protected := sets.NewString("pool")
+
+	assert.Empty(t, changedLabels(node(map[string]string{"pool": "oil"}), node(map[string]string{"pool": "oil", "foo": "bar"}), protected))
+	assert.Equal(t, []string{"pool"}, changedLabels(node(map[string]string{"pool": "oil"}), node(map[string]string{"pool": "gas"}), protected))
+	assert.Equal(t, []string{"pool"}, changedLabels(node(map[string]string{"pool": "oil"}), node(nil), protected))
+	assert.Equal(t, []string{"pool"}, changedLabels(node(nil), node(map[string]string{"pool": "oil"}), protected))
+}
+
+func TestChangedTaints(t *testing.T) {
+	node := func(taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{Spec: corev1.NodeSpec{Taints: taints}}
+	}
+	protected := sets.NewString("pool")
+	oil := corev1.Taint{Key: "pool", Value: "oil", Effect: corev1.TaintEffectNoSchedule}
+	other := corev1.Taint{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute}
+
+	assert.Empty(t, changedTaints(node(oil), node(oil, other), protected))
+	assert.Equal(t, []string{"pool"}, changedTaints(node(oil, other), node(other), protected))
+	assert.Equal(t, []string{"pool"}, changedTaints(node(), node(oil), protected))
+	assert.Equal(t, []string{"pool"}, changedTaints(node(oil), node(corev1.Taint{Key: "pool", Value: "gas", Effect: corev1.TaintEffectNoSchedule}), protected))
+}
@@ -0,0 +1,27 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package objectcounts
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type objectCountExceededError struct {
+	name  corev1.ResourceName
+	limit resource.Quantity
+}
+
+func NewObjectCountExceededError(name corev1.ResourceName, limit resource.Quantity) error {
+	return &objectCountExceededError{
+		name:  name,
+		limit: limit,
+	}
+}
+
+func (e objectCountExceededError) Error() string {
+	return fmt.Sprintf("The current Tenant cannot exceed %s %s objects", e.limit.String(), e.name)
+}
@@ -0,0 +1,124 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package objectcounts
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct{}
+
+// Handler denies the creation of the objects exceeding the object counts of the Tenant, summing the usage of the
+// object counts ResourceQuotas across the Tenant Namespaces: the ResourceQuotas recalculated by the controller may
+// allow, for a while, the concurrent creations in different Namespaces exceeding the Tenant counts.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(clt client.Client, _ *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		if len(req.SubResource) > 0 {
+			return nil
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := clt.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		tnt := &tntList.Items[0]
+
+		name := capsulev1beta1.ObjectCountResourceName(req.Resource.Group, req.Resource.Resource)
+
+		limit, ok := tnt.Spec.ResourceQuota.ObjectCounts[name]
+		if !ok {
+			return nil
+		}
+
+		used, err := tenantUsage(ctx, clt, tnt, name)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		switch objectCountCmp(used, limit) {
+		case 1:
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "ObjectCountExceeded", "%s %s/%s cannot be created, %s count exceeded for the current Tenant", req.Kind.Kind, req.Namespace, req.Name, name)
+
+			response := admission.Denied(NewObjectCountExceededError(name, limit).Error())
+
+			return &response
+		case 0:
+			capsulewebhook.Warn(ctx, "The %s %s reaches the limit of %s %s objects of the current Tenant", req.Kind.Kind, req.Name, limit.String(), name)
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *handler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// tenantUsage returns the usage of the given object count, summed across the object counts ResourceQuotas of the
+// Tenant Namespaces.
+func tenantUsage(ctx context.Context, clt client.Client, tnt *capsulev1beta1.Tenant, name corev1.ResourceName) (used resource.Quantity, err error) {
+	var tenantLabel, typeLabel string
+
+	if tenantLabel, err = capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{}); err != nil {
+		return
+	}
+
+	if typeLabel, err = capsulev1beta1.GetTypeLabel(&corev1.ResourceQuota{}); err != nil {
+		return
+	}
+
+	rqList := &corev1.ResourceQuotaList{}
+	if err = clt.List(ctx, rqList, client.MatchingLabels{tenantLabel: tnt.GetName(), typeLabel: capsulev1beta1.ObjectCountsQuotaType}); err != nil {
+		return
+	}
+
+	namespaces := sets.NewString(tnt.Status.Namespaces...)
+
+	for _, rq := range rqList.Items {
+		if namespaces.Has(rq.GetNamespace()) {
+			used.Add(rq.Status.Used[name])
+		}
+	}
+
+	return used, nil
+}
+
+// objectCountCmp compares the usage the created object brings the Tenant to with the limit.
+func objectCountCmp(used, limit resource.Quantity) int {
+	next := used.DeepCopy()
+	next.Add(resource.MustParse("1"))
+
+	return next.Cmp(limit)
+}
@@ -0,0 +1,18 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package objectcounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestObjectCountCmp(t *testing.T) {
+	assert.Equal(t, -1, objectCountCmp(resource.MustParse("3"), resource.MustParse("5")))
+	assert.Equal(t, 0, objectCountCmp(resource.MustParse("4"), resource.MustParse("5")))
+	assert.Equal(t, 1, objectCountCmp(resource.MustParse("5"), resource.MustParse("5")))
+	assert.Equal(t, 1, objectCountCmp(resource.Quantity{}, resource.MustParse("0")))
+}
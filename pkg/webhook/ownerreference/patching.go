@@ -149,14 +149,28 @@ func (h *handler) setOwnerRef(ctx context.Context, req admission.Request, client
 		return &response
 	}
 
-	if h.cfg.ForceTenantPrefix() {
-		for _, tnt := range tenants {
-			if strings.HasPrefix(ns.GetName(), fmt.Sprintf("%s-", tnt.GetName())) {
-				response := h.patchResponseForOwnerRef(tnt.DeepCopy(), ns, recorder)
+	for i := range tenants {
+		tnt := tenants[i].DeepCopy()
 
-				return &response
-			}
+		if utils.ForceTenantPrefix(h.cfg, tnt) && strings.HasPrefix(ns.GetName(), fmt.Sprintf("%s-", tnt.GetName())) {
+			response := h.patchResponseForOwnerRef(tnt, ns, recorder)
+
+			return &response
 		}
+	}
+
+	// the Tenants not forcing the prefix, as opted out of the global setting, can be selected only by the label
+	forcePrefix := true
+
+	for i := range tenants {
+		if !utils.ForceTenantPrefix(h.cfg, &tenants[i]) {
+			forcePrefix = false
+
+			break
+		}
+	}
+
+	if forcePrefix {
 		response := admission.Denied("The Namespace prefix used doesn't match any available Tenant")
 
 		return &response
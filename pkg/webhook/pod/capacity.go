@@ -0,0 +1,306 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type capacity struct {
+	reader client.Reader
+}
+
+// Capacity warns about the Pods that don't fit in any Node of the Tenant node pool, when required by the Tenant,
+// rather than leaving them pending silently: the Nodes are filtered as the scheduler does, by their readiness,
+// the node selector, the required node affinity and the taints, then the Pod requests are checked against the
+// allocatable capacity left by the Pods already bound to them, retrieved through the given reader since the Pods
+// are not cached.
+func Capacity(reader client.Reader) capsulewebhook.Handler {
+	return &capacity{reader: reader}
+}
+
+func (h *capacity) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		var pod = &corev1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return utils.ErroredResponse(err)
+		}
+		// the Pods bound upon the creation skip the scheduler
+		if len(pod.Spec.NodeName) > 0 {
+			return nil
+		}
+
+		var tntList = &capsulev1beta1.TenantList{}
+
+		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		tnt := &tntList.Items[0]
+
+		if options := tnt.Spec.PodOptions; options == nil || !options.WarnOverCapacity {
+			return nil
+		}
+
+		nodeList := &corev1.NodeList{}
+		if err := c.List(ctx, nodeList, client.MatchingLabels(tnt.Spec.NodeSelector)); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		requests := podRequests(pod)
+		// the bound Pods are retrieved once, upon the first feasible Node, and shared by all the Nodes
+		var boundPods map[string][]corev1.Pod
+
+		for i := range nodeList.Items {
+			node := &nodeList.Items[i]
+
+			if !nodeFeasible(pod, node) {
+				continue
+			}
+
+			if boundPods == nil {
+				var err error
+				if boundPods, err = h.boundPods(ctx); err != nil {
+					return utils.ErroredResponse(err)
+				}
+			}
+
+			if len(insufficientResources(requests, node, boundPods[node.GetName()])) == 0 {
+				return nil
+			}
+		}
+
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "InsufficientCapacity", "Pod %s/%s doesn't fit in the Tenant node pool", pod.Namespace, pod.Name)
+
+		capsulewebhook.Warn(ctx, "%s", NewInsufficientCapacity(len(nodeList.Items), requests).Error())
+
+		return nil
+	}
+}
+
+// boundPods returns the Pods bound to a Node and not terminated yet, grouped by the Node name, with a single request.
+func (h *capacity) boundPods(ctx context.Context) (map[string][]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := h.reader.List(ctx, podList, client.MatchingFieldsSelector{
+		Selector: fields.AndSelectors(
+			fields.OneTermNotEqualSelector("spec.nodeName", ""),
+			fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+			fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+		),
+	}); err != nil {
+		return nil, err
+	}
+
+	pods := make(map[string][]corev1.Pod)
+	for _, pod := range podList.Items {
+		pods[pod.Spec.NodeName] = append(pods[pod.Spec.NodeName], pod)
+	}
+
+	return pods, nil
+}
+
+func (h *capacity) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *capacity) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// podRequests returns the resources requested by the Pod, as accounted by the scheduler: the greater between the
+// sum of the containers requests and the largest init container request, plus the Pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; !ok || quantity.Cmp(total) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	for name, quantity := range pod.Spec.Overhead {
+		total := requests[name]
+		total.Add(quantity)
+		requests[name] = total
+	}
+
+	return requests
+}
+
+// nodeFeasible returns true when the Node is ready and schedulable, and the Pod matches its labels and tolerates
+// its scheduling taints.
+func nodeFeasible(pod *corev1.Pod, node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	ready := false
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	if !ready || !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.GetLabels())) {
+		return false
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil && affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		if !matchNodeSelectorTerms(node, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+			return false
+		}
+	}
+
+	for i := range node.Spec.Taints {
+		taint := node.Spec.Taints[i]
+
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerated := false
+
+		for _, toleration := range pod.Spec.Tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+
+				break
+			}
+		}
+
+		if !tolerated {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchNodeSelectorTerms returns true when the Node matches any of the terms, each one requiring all its
+// expressions and fields to match.
+func matchNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+
+	match := func(requirements []corev1.NodeSelectorRequirement, set labels.Set) bool {
+		selector := labels.NewSelector()
+
+		for _, requirement := range requirements {
+			r, err := labels.NewRequirement(requirement.Key, operators[requirement.Operator], requirement.Values)
+			if err != nil {
+				return false
+			}
+
+			selector = selector.Add(*r)
+		}
+
+		return selector.Matches(set)
+	}
+
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		if match(term.MatchExpressions, node.GetLabels()) && match(term.MatchFields, labels.Set{"metadata.name": node.GetName()}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insufficientResources returns the requested resources exceeding the allocatable capacity of the Node left by
+// the given Pods bound to it, including the Pods count.
+func insufficientResources(requests corev1.ResourceList, node *corev1.Node, pods []corev1.Pod) (names []corev1.ResourceName) {
+	used := corev1.ResourceList{}
+	count := int64(0)
+
+	for i := range pods {
+		if phase := pods[i].Status.Phase; phase == corev1.PodSucceeded || phase == corev1.PodFailed {
+			continue
+		}
+
+		count++
+
+		for name, quantity := range podRequests(&pods[i]) {
+			total := used[name]
+			total.Add(quantity)
+			used[name] = total
+		}
+	}
+
+	if allocatable, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && count >= allocatable.Value() {
+		names = append(names, corev1.ResourcePods)
+	}
+
+	for _, name := range sortedResourceNames(requests) {
+		quantity := requests[name]
+		if quantity.IsZero() {
+			continue
+		}
+
+		free := node.Status.Allocatable[name].DeepCopy()
+		free.Sub(used[name])
+
+		if quantity.Cmp(free) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	return names
+}
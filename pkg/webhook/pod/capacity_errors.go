@@ -0,0 +1,37 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type insufficientCapacity struct {
+	nodes    int
+	requests corev1.ResourceList
+}
+
+func NewInsufficientCapacity(nodes int, requests corev1.ResourceList) error {
+	return &insufficientCapacity{
+		nodes:    nodes,
+		requests: requests,
+	}
+}
+
+func (f insufficientCapacity) Error() string {
+	requests := []string{"none"}
+	if len(f.requests) > 0 {
+		requests = make([]string, 0, len(f.requests))
+	}
+
+	for _, name := range sortedResourceNames(f.requests) {
+		quantity := f.requests[name]
+		requests = append(requests, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+
+	return fmt.Sprintf("None of the %d Nodes of the Tenant node pool can fit the Pod requests (%s) right now: the Pod could stay pending, please reduce the requests or reach out to the system administrators", f.nodes, strings.Join(requests, ", "))
+}
@@ -0,0 +1,118 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func requesting(cpu, memory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+}
+
+func TestPodRequests(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "migrate", Resources: requesting("2", "128Mi")},
+			},
+			Containers: []corev1.Container{
+				{Name: "app", Resources: requesting("500m", "256Mi")},
+				{Name: "sidecar", Resources: requesting("500m", "256Mi")},
+			},
+			Overhead: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+		},
+	}
+
+	requests := podRequests(pod)
+
+	cpu, memory := requests[corev1.ResourceCPU], requests[corev1.ResourceMemory]
+	assert.Equal(t, "2", cpu.String())
+	assert.Equal(t, "576Mi", memory.String())
+}
+
+func TestNodeFeasible(t *testing.T) {
+	node := func(unschedulable bool, ready corev1.ConditionStatus, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Labels: map[string]string{"pool": "oil", "zone": "a"}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable, Taints: taints},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			},
+		}
+	}
+	gpu := corev1.Taint{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	affinity := func(operator corev1.NodeSelectorOperator, values ...string) *corev1.Affinity {
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: operator, Values: values}}},
+					},
+				},
+			},
+		}
+	}
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "oil"}}}
+
+	assert.True(t, nodeFeasible(pod, node(false, corev1.ConditionTrue)))
+	assert.False(t, nodeFeasible(pod, node(true, corev1.ConditionTrue)))
+	assert.False(t, nodeFeasible(pod, node(false, corev1.ConditionUnknown)))
+	assert.False(t, nodeFeasible(pod, node(false, corev1.ConditionTrue, gpu)))
+	assert.True(t, nodeFeasible(pod, node(false, corev1.ConditionTrue, corev1.Taint{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule})))
+
+	tolerating := pod.DeepCopy()
+	tolerating.Spec.Tolerations = []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}
+	assert.True(t, nodeFeasible(tolerating, node(false, corev1.ConditionTrue, gpu)))
+
+	other := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "gas"}}}
+	assert.False(t, nodeFeasible(other, node(false, corev1.ConditionTrue)))
+
+	affine := pod.DeepCopy()
+	affine.Spec.Affinity = affinity(corev1.NodeSelectorOpIn, "a", "b")
+	assert.True(t, nodeFeasible(affine, node(false, corev1.ConditionTrue)))
+	affine.Spec.Affinity = affinity(corev1.NodeSelectorOpNotIn, "a")
+	assert.False(t, nodeFeasible(affine, node(false, corev1.ConditionTrue)))
+}
+
+func TestInsufficientResources(t *testing.T) {
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("3"),
+			},
+		},
+	}
+	bound := func(phase corev1.PodPhase, cpu, memory string) corev1.Pod {
+		return corev1.Pod{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: requesting(cpu, memory)}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	pods := []corev1.Pod{
+		bound(corev1.PodRunning, "3", "2Gi"),
+		// the finished Pods don't hold any capacity
+		bound(corev1.PodSucceeded, "4", "8Gi"),
+	}
+
+	assert.Empty(t, insufficientResources(podRequests(&corev1.Pod{Spec: bound("", "1", "6Gi").Spec}), node, pods))
+	assert.Equal(t, []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}, insufficientResources(podRequests(&corev1.Pod{Spec: bound("", "2", "7Gi").Spec}), node, pods))
+
+	pods = append(pods, bound(corev1.PodPending, "0", "0"), bound(corev1.PodRunning, "0", "0"))
+	assert.Equal(t, []corev1.ResourceName{corev1.ResourcePods}, insufficientResources(corev1.ResourceList{}, node, pods))
+}
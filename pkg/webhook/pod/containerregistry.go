@@ -26,64 +26,68 @@ func ContainerRegistry() capsulewebhook.Handler {
 
 func (h *containerRegistryHandler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) *admission.Response {
-		pod := &corev1.Pod{}
-		if err := decoder.Decode(req, pod); err != nil {
-			return utils.ErroredResponse(err)
-		}
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
 
-		tntList := &capsulev1beta1.TenantList{}
-		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
-			Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
-		}); err != nil {
-			return utils.ErroredResponse(err)
-		}
+func (h *containerRegistryHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
 
-		if len(tntList.Items) == 0 {
-			return nil
-		}
+func (h *containerRegistryHandler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
 
-		tnt := tntList.Items[0]
+func (h *containerRegistryHandler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	pod, containers, err := requestContainers(decoder, req)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
 
-		if tnt.Spec.ContainerRegistries != nil {
-			var valid, matched bool
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
+	}); err != nil {
+		return utils.ErroredResponse(err)
+	}
 
-			for _, container := range pod.Spec.Containers {
-				reg := NewRegistry(container.Image)
+	if len(tntList.Items) == 0 {
+		return nil
+	}
 
-				if len(reg.Registry()) == 0 {
-					recorder.Eventf(&tnt, corev1.EventTypeWarning, "MissingFQCI", "Pod %s/%s is not using using a fully qualified container image, cannot enforce registry the current Tenant", req.Namespace, req.Name, reg.Registry())
+	tnt := tntList.Items[0]
 
-					response := admission.Denied(NewContainerRegistryForbidden(container.Image, *tnt.Spec.ContainerRegistries).Error())
+	if registries := tnt.EnforcedPodRestrictions().ContainerRegistries; registries != nil {
+		var valid, matched bool
 
-					return &response
-				}
+		for _, container := range containers {
+			reg := NewRegistry(container.Image)
 
-				valid = tnt.Spec.ContainerRegistries.ExactMatch(reg.Registry())
+			if len(reg.Registry()) == 0 {
+				recorder.Eventf(&tnt, corev1.EventTypeWarning, "MissingFQCI", "Pod %s/%s is not using using a fully qualified container image, cannot enforce registry the current Tenant", req.Namespace, req.Name, reg.Registry())
 
-				matched = tnt.Spec.ContainerRegistries.RegexMatch(reg.Registry())
+				response := admission.Denied(NewContainerRegistryForbidden(container.Image, *registries).Error())
 
-				if !valid && !matched {
-					recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenContainerRegistry", "Pod %s/%s is using a container hosted on registry %s that is forbidden for the current Tenant", req.Namespace, req.Name, reg.Registry())
+				return &response
+			}
 
-					response := admission.Denied(NewContainerRegistryForbidden(container.Image, *tnt.Spec.ContainerRegistries).Error())
+			valid = registries.ExactMatch(reg.Registry())
 
-					return &response
-				}
-			}
-		}
+			matched = registries.RegexMatch(reg.Registry())
 
-		return nil
-	}
-}
+			if !valid && !matched {
+				recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenContainerRegistry", "Pod %s/%s is using a container hosted on registry %s that is forbidden for the current Tenant", req.Namespace, req.Name, reg.Registry())
 
-func (h *containerRegistryHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
-	return func(ctx context.Context, req admission.Request) *admission.Response {
-		return nil
-	}
-}
+				response := admission.Denied(NewContainerRegistryForbidden(container.Image, *registries).Error())
 
-func (h *containerRegistryHandler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
-	return func(ctx context.Context, req admission.Request) *admission.Response {
-		return nil
+				return &response
+			}
+		}
 	}
+
+	return nil
 }
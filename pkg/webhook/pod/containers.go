@@ -0,0 +1,119 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ephemeralContainersKind is the kind of the pods/ephemeralcontainers subresource before Kubernetes 1.22,
+// holding the ephemeral containers of the Pod rather than the whole Pod.
+const ephemeralContainersKind = "EphemeralContainers"
+
+type ephemeralContainersObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	EphemeralContainers []corev1.EphemeralContainer `json:"ephemeralContainers"`
+}
+
+// decodePod decodes the given raw object of the request into the Pod: the EphemeralContainers one is decoded
+// as a Pod with its metadata and ephemeral containers only.
+func decodePod(decoder *admission.Decoder, req admission.Request, raw runtime.RawExtension, pod *corev1.Pod) error {
+	if req.Kind.Kind != ephemeralContainersKind {
+		return decoder.DecodeRaw(raw, pod)
+	}
+
+	obj := &ephemeralContainersObject{}
+	if err := json.Unmarshal(raw.Raw, obj); err != nil {
+		return err
+	}
+
+	pod.ObjectMeta = obj.ObjectMeta
+	pod.Spec.EphemeralContainers = obj.EphemeralContainers
+
+	return nil
+}
+
+// requestContainers decodes the Pod of the request, along with the containers the policies have to be enforced on:
+// all of them upon the creation, the added or changed ones upon the updates, including the ephemeral containers
+// added through the pods/ephemeralcontainers subresource.
+func requestContainers(decoder *admission.Decoder, req admission.Request) (*corev1.Pod, []corev1.Container, error) {
+	pod := &corev1.Pod{}
+	if err := decodePod(decoder, req, req.Object, pod); err != nil {
+		return nil, nil, err
+	}
+
+	if req.Operation != admissionv1.Update {
+		return pod, podContainers(pod), nil
+	}
+
+	oldPod := &corev1.Pod{}
+	if err := decodePod(decoder, req, req.OldObject, oldPod); err != nil {
+		return nil, nil, err
+	}
+
+	return pod, changedContainers(oldPod, pod), nil
+}
+
+// podContainers returns the init, regular and ephemeral containers of the Pod.
+func podContainers(pod *corev1.Pod) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers)+len(pod.Spec.EphemeralContainers))
+
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	for _, ephemeral := range pod.Spec.EphemeralContainers {
+		containers = append(containers, corev1.Container{
+			Name:            ephemeral.Name,
+			Image:           ephemeral.Image,
+			ImagePullPolicy: ephemeral.ImagePullPolicy,
+		})
+	}
+
+	return containers
+}
+
+// changedContainers returns the containers of the updated Pod added or changing the image or the pull policy,
+// so that the updates of the Pods admitted before a policy change are not denied.
+func changedContainers(oldPod, pod *corev1.Pod) (containers []corev1.Container) {
+	old := make(map[string]corev1.Container)
+
+	for _, container := range podContainers(oldPod) {
+		old[container.Name] = container
+	}
+
+	for _, container := range podContainers(pod) {
+		if previous, ok := old[container.Name]; ok && previous.Image == container.Image && previous.ImagePullPolicy == container.ImagePullPolicy {
+			continue
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers
+}
+
+// addedEphemeralContainers returns the names of the ephemeral containers added by the update.
+func addedEphemeralContainers(oldPod, pod *corev1.Pod) (names []string) {
+	old := make(map[string]struct{})
+
+	for _, ephemeral := range oldPod.Spec.EphemeralContainers {
+		old[ephemeral.Name] = struct{}{}
+	}
+
+	for _, ephemeral := range pod.Spec.EphemeralContainers {
+		if _, ok := old[ephemeral.Name]; !ok {
+			names = append(names, ephemeral.Name)
+		}
+	}
+
+	return names
+}
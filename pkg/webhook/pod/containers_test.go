@@ -0,0 +1,81 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestChangedContainers(t *testing.T) {
+	oldPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init", Image: "docker.io/busybox"}},
+			Containers:     []corev1.Container{{Name: "app", Image: "quay.io/app:v1", ImagePullPolicy: corev1.PullAlways}},
+		},
+	}
+
+	pod := oldPod.DeepCopy()
+	assert.Empty(t, changedContainers(oldPod, pod))
+
+	pod.Spec.Containers[0].Image = "quay.io/app:v2"
+	pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "docker.io/busybox", ImagePullPolicy: corev1.PullIfNotPresent},
+	}}
+
+	containers := changedContainers(oldPod, pod)
+	if assert.Len(t, containers, 2) {
+		assert.Equal(t, "app", containers[0].Name)
+		assert.Equal(t, "debugger", containers[1].Name)
+		assert.Equal(t, corev1.PullIfNotPresent, containers[1].ImagePullPolicy)
+	}
+
+	assert.Len(t, podContainers(pod), 3)
+}
+
+func TestAddedEphemeralContainers(t *testing.T) {
+	debugger := func(name string) corev1.EphemeralContainer {
+		return corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: name}}
+	}
+
+	oldPod := &corev1.Pod{Spec: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("debugger-1")}}}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("debugger-1"), debugger("debugger-2")}}}
+
+	assert.Equal(t, []string{"debugger-2"}, addedEphemeralContainers(oldPod, pod))
+	assert.Empty(t, addedEphemeralContainers(pod, pod))
+}
+
+func TestDecodePod(t *testing.T) {
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	assert.NoError(t, err)
+
+	request := func(kind, raw string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Version: "v1", Kind: kind},
+			Object: runtime.RawExtension{Raw: []byte(raw)},
+		}}
+	}
+
+	for name, req := range map[string]admission.Request{
+		"Pod": request("Pod", `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx","namespace":"oil-production"},"spec":{"ephemeralContainers":[{"name":"debugger","image":"docker.io/busybox"}]}}`),
+		// the pods/ephemeralcontainers subresource before Kubernetes 1.22
+		"EphemeralContainers": request("EphemeralContainers", `{"apiVersion":"v1","kind":"EphemeralContainers","metadata":{"name":"nginx","namespace":"oil-production"},"ephemeralContainers":[{"name":"debugger","image":"docker.io/busybox"}]}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			pod := &corev1.Pod{}
+			if assert.NoError(t, decodePod(decoder, req, req.Object, pod)) {
+				assert.Equal(t, "oil-production", pod.Namespace)
+				assert.Equal(t, "nginx", pod.Name)
+				assert.Equal(t, []string{"debugger"}, addedEphemeralContainers(&corev1.Pod{}, pod))
+			}
+		})
+	}
+}
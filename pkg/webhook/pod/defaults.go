@@ -0,0 +1,117 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type defaults struct{}
+
+// Defaults sets the Tenant DNS policy on the Pods using the default ClusterFirst one, and the Tenant DNS config on
+// the Pods not declaring one: the Pods running in the host network are left untouched. The Tenant capacity hints
+// annotations are enforced on the Pods upon both creation and updates.
+func Defaults() capsulewebhook.Handler {
+	return &defaults{}
+}
+
+func (h *defaults) OnCreate(c client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.mutate(ctx, c, decoder, req, true)
+	}
+}
+
+func (h *defaults) OnUpdate(c client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.mutate(ctx, c, decoder, req, false)
+	}
+}
+
+func (h *defaults) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// mutate applies the Tenant defaults to the Pod: the DNS ones only upon the creation, since the Pod spec is immutable.
+func (h *defaults) mutate(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request, create bool) *admission.Response {
+	pod := &corev1.Pod{}
+	if err := decoder.Decode(req, pod); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if len(tntList.Items) == 0 {
+		return nil
+	}
+
+	tnt := tntList.Items[0]
+
+	original, err := json.Marshal(pod)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	var changed bool
+
+	if options := tnt.Spec.PodOptions; create && options != nil && !pod.Spec.HostNetwork {
+		changed = applyDNSDefaults(pod, options)
+	}
+
+	if hints := tnt.Spec.CapacityHints; hints != nil {
+		changed = applyCapacityHints(pod, hints) || changed
+	}
+
+	if !changed {
+		return nil
+	}
+
+	mutated, err := json.Marshal(pod)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	response := admission.PatchResponseFromRaw(original, mutated)
+
+	return &response
+}
+
+// applyCapacityHints sets the capacity hints annotations of the Tenant on the Pod, reporting if any has been changed.
+func applyCapacityHints(pod *corev1.Pod, hints *capsulev1beta1.CapacityHintsSpec) (changed bool) {
+	annotations := pod.GetAnnotations()
+
+	for key, value := range hints.PodAnnotations {
+		if current, ok := annotations[key]; ok && current == value {
+			continue
+		}
+
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[key] = value
+		changed = true
+	}
+
+	pod.SetAnnotations(annotations)
+
+	return changed
+}
@@ -0,0 +1,37 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestApplyCapacityHints(t *testing.T) {
+	hints := &capsulev1beta1.CapacityHintsSpec{
+		PodAnnotations: map[string]string{"cluster-autoscaler.kubernetes.io/safe-to-evict": "true"},
+	}
+
+	pod := &corev1.Pod{}
+	assert.True(t, applyCapacityHints(pod, hints))
+	assert.Equal(t, map[string]string{"cluster-autoscaler.kubernetes.io/safe-to-evict": "true"}, pod.GetAnnotations())
+	assert.False(t, applyCapacityHints(pod, hints))
+
+	pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
+		"prometheus.io/scrape":                           "true",
+	}}}
+	assert.True(t, applyCapacityHints(pod, hints))
+	assert.Equal(t, map[string]string{
+		"cluster-autoscaler.kubernetes.io/safe-to-evict": "true",
+		"prometheus.io/scrape":                           "true",
+	}, pod.GetAnnotations())
+
+	assert.False(t, applyCapacityHints(&corev1.Pod{}, &capsulev1beta1.CapacityHintsSpec{}))
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// applyDNSDefaults sets the DNS policy and config of the Tenant on the Pod, reporting if any has been set: the DNS
+// policy is defaulted by the API server before the admission, so only the ClusterFirst one is replaced.
+func applyDNSDefaults(pod *corev1.Pod, options *capsulev1beta1.PodOptions) (changed bool) {
+	if len(options.DNSPolicy) > 0 && (len(pod.Spec.DNSPolicy) == 0 || pod.Spec.DNSPolicy == corev1.DNSClusterFirst) && options.DNSPolicy != pod.Spec.DNSPolicy {
+		pod.Spec.DNSPolicy = options.DNSPolicy
+		changed = true
+	}
+
+	if options.DNSConfig != nil && pod.Spec.DNSConfig == nil {
+		pod.Spec.DNSConfig = options.DNSConfig.DeepCopy()
+		changed = true
+	}
+
+	return changed
+}
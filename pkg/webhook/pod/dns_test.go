@@ -0,0 +1,41 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestApplyDNSDefaults(t *testing.T) {
+	options := &capsulev1beta1.PodOptions{
+		DNSPolicy: corev1.DNSNone,
+		DNSConfig: &corev1.PodDNSConfig{
+			Nameservers: []string{"10.0.0.53"},
+			Searches:    []string{"oil.acme.corp"},
+		},
+	}
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst}}
+
+	assert.True(t, applyDNSDefaults(pod, options))
+	assert.Equal(t, corev1.DNSNone, pod.Spec.DNSPolicy)
+	assert.Equal(t, options.DNSConfig, pod.Spec.DNSConfig)
+	assert.False(t, applyDNSDefaults(pod, options))
+
+	declared := &corev1.Pod{Spec: corev1.PodSpec{
+		DNSPolicy: corev1.DNSDefault,
+		DNSConfig: &corev1.PodDNSConfig{Searches: []string{"svc.cluster.local"}},
+	}}
+
+	assert.False(t, applyDNSDefaults(declared, options))
+	assert.Equal(t, corev1.DNSDefault, declared.Spec.DNSPolicy)
+	assert.Equal(t, []string{"svc.cluster.local"}, declared.Spec.DNSConfig.Searches)
+
+	assert.False(t, applyDNSDefaults(&corev1.Pod{Spec: corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst}}, &capsulev1beta1.PodOptions{}))
+}
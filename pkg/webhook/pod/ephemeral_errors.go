@@ -0,0 +1,37 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ephemeralStorageLimitsMissing struct {
+	containers []string
+}
+
+func NewEphemeralStorageLimitsMissing(containers []string) error {
+	return &ephemeralStorageLimitsMissing{
+		containers: containers,
+	}
+}
+
+func (f ephemeralStorageLimitsMissing) Error() string {
+	return fmt.Sprintf("The current Tenant requires the ephemeral-storage limits: set them for the containers %s", strings.Join(f.containers, ", "))
+}
+
+type ephemeralContainersForbidden struct {
+	containers []string
+}
+
+func NewEphemeralContainersForbidden(containers []string) error {
+	return &ephemeralContainersForbidden{
+		containers: containers,
+	}
+}
+
+func (f ephemeralContainersForbidden) Error() string {
+	return fmt.Sprintf("The current Tenant forbids the ephemeral containers: %s cannot be added", strings.Join(f.containers, ", "))
+}
@@ -0,0 +1,80 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type ephemeralContainers struct {
+}
+
+// EphemeralContainers denies the ephemeral containers added to the Pods, as the kubectl debug ones,
+// when forbidden by the Tenant.
+func EphemeralContainers() capsulewebhook.Handler {
+	return &ephemeralContainers{}
+}
+
+func (h *ephemeralContainers) OnCreate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *ephemeralContainers) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *ephemeralContainers) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		oldPod := &corev1.Pod{}
+		if err := decodePod(decoder, req, req.OldObject, oldPod); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		pod := &corev1.Pod{}
+		if err := decodePod(decoder, req, req.Object, pod); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		names := addedEphemeralContainers(oldPod, pod)
+		if len(names) == 0 {
+			return nil
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		if options := tntList.Items[0].Spec.PodOptions; options == nil || !options.ForbidEphemeralContainers {
+			return nil
+		}
+
+		recorder.Eventf(&tntList.Items[0], corev1.EventTypeWarning, "ForbiddenEphemeralContainers", "Pod %s/%s cannot run the ephemeral containers %s", pod.Namespace, pod.Name, strings.Join(names, ", "))
+
+		response := admission.Denied(NewEphemeralContainersForbidden(names).Error())
+
+		return &response
+	}
+}
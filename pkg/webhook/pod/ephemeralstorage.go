@@ -0,0 +1,88 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type ephemeralStorage struct {
+}
+
+// EphemeralStorage denies the Pods whose containers don't declare the ephemeral-storage limits,
+// when required by the Tenant.
+func EphemeralStorage() capsulewebhook.Handler {
+	return &ephemeralStorage{}
+}
+
+func (h *ephemeralStorage) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		var pod = &corev1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		var tntList = &capsulev1beta1.TenantList{}
+
+		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		if !tntList.Items[0].EnforcedPodRestrictions().RequireEphemeralStorageLimits {
+			return nil
+		}
+
+		if containers := missingEphemeralStorageLimits(pod); len(containers) > 0 {
+			recorder.Eventf(&tntList.Items[0], corev1.EventTypeWarning, "MissingEphemeralStorageLimits", "Pod %s/%s containers %s are missing the ephemeral-storage limits", pod.Namespace, pod.Name, strings.Join(containers, ", "))
+
+			response := admission.Denied(NewEphemeralStorageLimitsMissing(containers).Error())
+
+			return &response
+		}
+
+		return nil
+	}
+}
+
+func (h *ephemeralStorage) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *ephemeralStorage) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// missingEphemeralStorageLimits returns the names of the containers and init containers without the ephemeral-storage limits.
+func missingEphemeralStorageLimits(pod *corev1.Pod) (names []string) {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			if _, ok := container.Resources.Limits[corev1.ResourceEphemeralStorage]; !ok {
+				names = append(names, container.Name)
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,36 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMissingEphemeralStorageLimits(t *testing.T) {
+	limited := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+	}
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers: []corev1.Container{
+				{Name: "app", Resources: limited},
+				{Name: "sidecar", Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+				}},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"init", "sidecar"}, missingEphemeralStorageLimits(pod))
+
+	pod.Spec.InitContainers[0].Resources = limited
+	pod.Spec.Containers[1].Resources = limited
+	assert.Empty(t, missingEphemeralStorageLimits(pod))
+}
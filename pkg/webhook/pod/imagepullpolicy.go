@@ -25,49 +25,13 @@ func ImagePullPolicy() capsulewebhook.Handler {
 
 func (r *imagePullPolicy) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) *admission.Response {
-		var pod = &corev1.Pod{}
-		if err := decoder.Decode(req, pod); err != nil {
-			return utils.ErroredResponse(err)
-		}
-
-		var tntList = &capsulev1beta1.TenantList{}
-		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
-			Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
-		}); err != nil {
-			return utils.ErroredResponse(err)
-		}
-		// the Pod is not running in a Namespace managed by a Tenant
-		if len(tntList.Items) == 0 {
-			return nil
-		}
-
-		tnt := tntList.Items[0]
-
-		policy := NewPullPolicy(&tnt)
-		// if Tenant doesn't enforce the pull policy, exit
-		if policy == nil {
-			return nil
-		}
-
-		for _, container := range pod.Spec.Containers {
-			usedPullPolicy := string(container.ImagePullPolicy)
-
-			if !policy.IsPolicySupported(usedPullPolicy) {
-				recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenPullPolicy", "Pod %s/%s pull policy %s is forbidden for the current Tenant", req.Namespace, req.Name, usedPullPolicy)
-
-				response := admission.Denied(NewImagePullPolicyForbidden(usedPullPolicy, container.Name, policy.AllowedPullPolicies()).Error())
-
-				return &response
-			}
-		}
-
-		return nil
+		return r.validate(ctx, c, decoder, recorder, req)
 	}
 }
 
-func (r *imagePullPolicy) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+func (r *imagePullPolicy) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) *admission.Response {
-		return nil
+		return r.validate(ctx, c, decoder, recorder, req)
 	}
 }
 
@@ -76,3 +40,43 @@ func (r *imagePullPolicy) OnDelete(client.Client, *admission.Decoder, record.Eve
 		return nil
 	}
 }
+
+func (r *imagePullPolicy) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	pod, containers, err := requestContainers(decoder, req)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	var tntList = &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
+	}); err != nil {
+		return utils.ErroredResponse(err)
+	}
+	// the Pod is not running in a Namespace managed by a Tenant
+	if len(tntList.Items) == 0 {
+		return nil
+	}
+
+	tnt := tntList.Items[0]
+
+	policy := NewPullPolicy(&tnt)
+	// if Tenant doesn't enforce the pull policy, exit
+	if policy == nil {
+		return nil
+	}
+
+	for _, container := range containers {
+		usedPullPolicy := string(container.ImagePullPolicy)
+
+		if !policy.IsPolicySupported(usedPullPolicy) {
+			recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenPullPolicy", "Pod %s/%s pull policy %s is forbidden for the current Tenant", req.Namespace, req.Name, usedPullPolicy)
+
+			response := admission.Denied(NewImagePullPolicyForbidden(usedPullPolicy, container.Name, policy.AllowedPullPolicies()).Error())
+
+			return &response
+		}
+	}
+
+	return nil
+}
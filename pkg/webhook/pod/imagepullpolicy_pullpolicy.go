@@ -33,13 +33,14 @@ func (i imagePullPolicyValidator) AllowedPullPolicies() []string {
 }
 
 func NewPullPolicy(tenant *capsulev1beta1.Tenant) PullPolicy {
+	policies := tenant.EnforcedPodRestrictions().ImagePullPolicies
 	// the Tenant doesn't enforce the allowed image pull policy, returning nil
-	if len(tenant.Spec.ImagePullPolicies) == 0 {
+	if len(policies) == 0 {
 		return nil
 	}
 
 	var allowedPolicies []string
-	for _, policy := range tenant.Spec.ImagePullPolicies {
+	for _, policy := range policies {
 		allowedPolicies = append(allowedPolicies, policy.String())
 	}
 	return &imagePullPolicyValidator{
@@ -43,7 +43,7 @@ func (h *priorityClass) OnCreate(c client.Client, decoder *admission.Decoder, re
 			return nil
 		}
 
-		allowed := tntList.Items[0].Spec.PriorityClasses
+		allowed := tntList.Items[0].EnforcedPodRestrictions().PriorityClasses
 		var priorityClassName = pod.Spec.PriorityClassName
 
 		switch {
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
 )
 
@@ -49,3 +51,49 @@ func NewStorageClassForbidden(className string, storageClasses capsulev1beta1.Al
 func (f storageClassForbidden) Error() string {
 	return fmt.Sprintf("Storage Class %s is forbidden for the current Tenant%s", f.className, appendError(f.spec))
 }
+
+type persistentVolumeForbidden struct {
+	volumeName string
+}
+
+func NewPersistentVolumeForbidden(volumeName string) error {
+	return &persistentVolumeForbidden{
+		volumeName: volumeName,
+	}
+}
+
+func (f persistentVolumeForbidden) Error() string {
+	return fmt.Sprintf("PersistentVolume %s belongs to a different Tenant and cannot be claimed", f.volumeName)
+}
+
+type storageRequestExceeded struct {
+	request resource.Quantity
+	max     resource.Quantity
+}
+
+func NewStorageRequestExceeded(request, max resource.Quantity) error {
+	return &storageRequestExceeded{
+		request: request,
+		max:     max,
+	}
+}
+
+func (e storageRequestExceeded) Error() string {
+	return fmt.Sprintf("The requested storage %s exceeds the maximum %s allowed for the current Tenant", e.request.String(), e.max.String())
+}
+
+type volumeClaimTemplateNotValid struct {
+	name string
+	err  error
+}
+
+func NewVolumeClaimTemplateNotValid(name string, err error) error {
+	return &volumeClaimTemplateNotValid{
+		name: name,
+		err:  err,
+	}
+}
+
+func (e volumeClaimTemplateNotValid) Error() string {
+	return fmt.Sprintf("The volume claim template %s is not valid: %s", e.name, e.err.Error())
+}
@@ -0,0 +1,136 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pvc
+
+import (
+	"context"
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type statefulSetHandler struct{}
+
+// StatefulSetHandler sets the default StorageClass of the Tenant on the volume claim templates of the StatefulSets
+// not declaring any, and denies the templates violating the Tenant StorageClasses or maximum storage request,
+// rather than their claims failing once created by the StatefulSet controller.
+// The templates without a StorageClass are left to the cluster default one, validated upon the claims creation.
+func StatefulSetHandler() capsulewebhook.Handler {
+	return &statefulSetHandler{}
+}
+
+func (h *statefulSetHandler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		sts := &appsv1.StatefulSet{}
+		if err := decoder.Decode(req, sts); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(sts.Spec.VolumeClaimTemplates) == 0 {
+			return nil
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", sts.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		tnt := tntList.Items[0]
+
+		changed := defaultStorageClass(&tnt, sts.Spec.VolumeClaimTemplates)
+
+		for _, template := range sts.Spec.VolumeClaimTemplates {
+			reason, err := volumeClaimTemplateError(&tnt, template.Spec)
+			if err == nil {
+				continue
+			}
+
+			recorder.Eventf(&tnt, corev1.EventTypeWarning, reason, "StatefulSet %s/%s volume claim template %s is not valid: %s", req.Namespace, sts.GetName(), template.GetName(), err.Error())
+
+			response := admission.Denied(NewVolumeClaimTemplateNotValid(template.GetName(), err).Error())
+
+			return &response
+		}
+
+		if !changed {
+			return nil
+		}
+
+		mutated, err := json.Marshal(sts)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		response := admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+
+		return &response
+	}
+}
+
+func (h *statefulSetHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// OnUpdate is a no-op since the volume claim templates of the StatefulSets are immutable.
+func (h *statefulSetHandler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// defaultStorageClass sets the default StorageClass of the Tenant on the templates not declaring any,
+// reporting if any template has been changed.
+func defaultStorageClass(tnt *capsulev1beta1.Tenant, templates []corev1.PersistentVolumeClaim) (changed bool) {
+	if tnt.Spec.PersistentVolumeOptions == nil || len(tnt.Spec.PersistentVolumeOptions.DefaultStorageClass) == 0 {
+		return false
+	}
+
+	for i := range templates {
+		if templates[i].Spec.StorageClassName != nil {
+			continue
+		}
+
+		class := tnt.Spec.PersistentVolumeOptions.DefaultStorageClass
+		templates[i].Spec.StorageClassName = &class
+
+		changed = true
+	}
+
+	return changed
+}
+
+// volumeClaimTemplateError returns the error, and the Event reason, of the template violating the Tenant
+// StorageClasses or maximum storage request.
+func volumeClaimTemplateError(tnt *capsulev1beta1.Tenant, spec corev1.PersistentVolumeClaimSpec) (reason string, err error) {
+	if request, max, exceeded := exceededStorageRequest(tnt, spec); exceeded {
+		return "StorageRequestExceeded", NewStorageRequestExceeded(request, max)
+	}
+
+	if tnt.Spec.StorageClasses == nil || spec.StorageClassName == nil {
+		return "", nil
+	}
+
+	if sc := *spec.StorageClassName; !tnt.Spec.StorageClasses.ExactMatch(sc) && !tnt.Spec.StorageClasses.RegexMatch(sc) {
+		return "ForbiddenStorageClass", NewStorageClassForbidden(sc, *tnt.Spec.StorageClasses)
+	}
+
+	return "", nil
+}
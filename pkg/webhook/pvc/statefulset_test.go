@@ -0,0 +1,67 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pvc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestDefaultStorageClass(t *testing.T) {
+	templates := []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "logs"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: pointer.StringPtr("local-path")}},
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	assert.False(t, defaultStorageClass(tnt, templates))
+	assert.Nil(t, templates[0].Spec.StorageClassName)
+
+	tnt.Spec.PersistentVolumeOptions = &capsulev1beta1.PersistentVolumeOptions{DefaultStorageClass: "ceph-rbd"}
+	assert.True(t, defaultStorageClass(tnt, templates))
+	assert.Equal(t, "ceph-rbd", *templates[0].Spec.StorageClassName)
+	assert.Equal(t, "local-path", *templates[1].Spec.StorageClassName)
+
+	assert.False(t, defaultStorageClass(tnt, templates))
+}
+
+func TestVolumeClaimTemplateError(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			StorageClasses:          &capsulev1beta1.AllowedListSpec{Exact: []string{"ceph-rbd"}},
+			PersistentVolumeOptions: &capsulev1beta1.PersistentVolumeOptions{MaxStorageRequest: resource.NewQuantity(10<<30, resource.BinarySI)},
+		},
+	}
+	spec := func(class *string, storage string) corev1.PersistentVolumeClaimSpec {
+		return corev1.PersistentVolumeClaimSpec{
+			StorageClassName: class,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+			},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		spec   corev1.PersistentVolumeClaimSpec
+		reason string
+	}{
+		"allowed":         {spec(pointer.StringPtr("ceph-rbd"), "10Gi"), ""},
+		"cluster default": {spec(nil, "1Gi"), ""},
+		"forbidden class": {spec(pointer.StringPtr("local-path"), "1Gi"), "ForbiddenStorageClass"},
+		"exceeded":        {spec(pointer.StringPtr("ceph-rbd"), "20Gi"), "StorageRequestExceeded"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			reason, err := volumeClaimTemplateError(tnt, tc.spec)
+			assert.Equal(t, tc.reason, reason)
+			assert.Equal(t, len(tc.reason) > 0, err != nil)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,6 +47,14 @@ func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder, recorder
 
 		tnt := tntList.Items[0]
 
+		if request, max, exceeded := exceededStorageRequest(&tnt, pvc.Spec); exceeded {
+			recorder.Eventf(&tnt, corev1.EventTypeWarning, "StorageRequestExceeded", "PersistentVolumeClaim %s/%s requests %s of storage, exceeding the maximum %s", req.Namespace, req.Name, request.String(), max.String())
+
+			response := admission.Denied(NewStorageRequestExceeded(request, max).Error())
+
+			return &response
+		}
+
 		if tnt.Spec.StorageClasses == nil {
 			return nil
 		}
@@ -79,8 +88,60 @@ func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecord
 	}
 }
 
-func (h *handler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+// OnUpdate denies the expansion of the claims beyond the maximum storage request of the Tenant.
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) *admission.Response {
+		pvc, old := &corev1.PersistentVolumeClaim{}, &corev1.PersistentVolumeClaim{}
+		if err := decoder.Decode(req, pvc); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if pvc.Spec.Resources.Requests.Storage().Cmp(*old.Spec.Resources.Requests.Storage()) <= 0 {
+			return nil
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", pvc.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		tnt := tntList.Items[0]
+
+		if request, max, exceeded := exceededStorageRequest(&tnt, pvc.Spec); exceeded {
+			recorder.Eventf(&tnt, corev1.EventTypeWarning, "StorageRequestExceeded", "PersistentVolumeClaim %s/%s cannot be expanded to %s of storage, exceeding the maximum %s", req.Namespace, req.Name, request.String(), max.String())
+
+			response := admission.Denied(NewStorageRequestExceeded(request, max).Error())
+
+			return &response
+		}
+
 		return nil
 	}
 }
+
+// exceededStorageRequest returns the storage requested by the claim, and the maximum one allowed by the Tenant,
+// reporting if the former exceeds the latter.
+func exceededStorageRequest(tnt *capsulev1beta1.Tenant, spec corev1.PersistentVolumeClaimSpec) (request, max resource.Quantity, exceeded bool) {
+	if tnt.Spec.PersistentVolumeOptions == nil || tnt.Spec.PersistentVolumeOptions.MaxStorageRequest == nil {
+		return request, max, false
+	}
+
+	max = *tnt.Spec.PersistentVolumeOptions.MaxStorageRequest
+
+	request, ok := spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return request, max, false
+	}
+
+	return request, max, request.Cmp(max) > 0
+}
@@ -0,0 +1,220 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pvc
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type volumeHandler struct {
+}
+
+// PersistentVolumeReuseHandler prevents the Tenant claims from binding, by means of the volume name, of the selector,
+// or of the class, size and access modes matched by the volume controller, the PersistentVolumes labelled for a
+// different Tenant, or pre-bound to a Namespace outside of the Tenant.
+func PersistentVolumeReuseHandler() capsulewebhook.Handler {
+	return &volumeHandler{}
+}
+
+func (h *volumeHandler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := decoder.Decode(req, pvc); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tntList := &capsulev1beta1.TenantList{}
+		if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", pvc.Namespace),
+		}); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if len(tntList.Items) == 0 {
+			return nil
+		}
+
+		tnt := tntList.Items[0]
+
+		pvs, err := claimablePersistentVolumes(ctx, c, pvc)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		for i := range pvs {
+			pv := &pvs[i]
+
+			reserved, err := reservedToOtherTenant(pv, tnt)
+			if err != nil {
+				return utils.ErroredResponse(err)
+			}
+
+			if reserved {
+				recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenPersistentVolume", "PersistentVolumeClaim %s/%s cannot claim the PersistentVolume %s of a different Tenant", req.Namespace, req.Name, pv.GetName())
+
+				response := admission.Denied(NewPersistentVolumeForbidden(pv.GetName()).Error())
+
+				return &response
+			}
+		}
+
+		return nil
+	}
+}
+
+// claimablePersistentVolumes returns the PersistentVolumes the claim could be bound to by the volume controller: the
+// one set by the volume name, if any, and the ones matching the selector, unless pre-bound to a different claim.
+// Without both of them, the volume controller binds any available PersistentVolume matching the class, the size and
+// the access modes of the claim: only the labelled ones are returned, since the others are not reserved to any Tenant.
+func claimablePersistentVolumes(ctx context.Context, c client.Client, pvc *corev1.PersistentVolumeClaim) ([]corev1.PersistentVolume, error) {
+	var pvs []corev1.PersistentVolume
+
+	if len(pvc.Spec.VolumeName) > 0 {
+		pv := &corev1.PersistentVolume{}
+		if err := c.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		} else {
+			pvs = append(pvs, *pv)
+		}
+
+		if pvc.Spec.Selector == nil {
+			return pvs, nil
+		}
+	}
+
+	var selector labels.Selector
+
+	if pvc.Spec.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(pvc.Spec.Selector); err != nil {
+			return nil, err
+		}
+	} else {
+		capsuleLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err := labels.NewRequirement(capsuleLabel, selection.Exists, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		selector = labels.NewSelector().Add(*exists)
+	}
+
+	pvList := &corev1.PersistentVolumeList{}
+	if err := c.List(ctx, pvList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	for _, pv := range pvList.Items {
+		if ref := pv.Spec.ClaimRef; ref != nil && (ref.Namespace != pvc.GetNamespace() || ref.Name != pvc.GetName()) {
+			continue
+		}
+
+		if pvc.Spec.Selector == nil && !matchesClaim(pv, pvc) {
+			continue
+		}
+
+		pvs = append(pvs, pv)
+	}
+
+	return pvs, nil
+}
+
+// matchesClaim reports if the PersistentVolume satisfies the class, the volume mode, the access modes and the
+// requested size of the claim, as checked by the volume controller when binding the claims without a selector.
+func matchesClaim(pv corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim) bool {
+	var class string
+	if pvc.Spec.StorageClassName != nil {
+		class = *pvc.Spec.StorageClassName
+	}
+
+	if pv.Spec.StorageClassName != class {
+		return false
+	}
+
+	volumeMode := func(mode *corev1.PersistentVolumeMode) corev1.PersistentVolumeMode {
+		if mode == nil {
+			return corev1.PersistentVolumeFilesystem
+		}
+
+		return *mode
+	}
+
+	if volumeMode(pv.Spec.VolumeMode) != volumeMode(pvc.Spec.VolumeMode) {
+		return false
+	}
+
+	modes := make(map[corev1.PersistentVolumeAccessMode]struct{})
+	for _, mode := range pv.Spec.AccessModes {
+		modes[mode] = struct{}{}
+	}
+
+	for _, mode := range pvc.Spec.AccessModes {
+		if _, ok := modes[mode]; !ok {
+			return false
+		}
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+
+	return capacity.Cmp(requested) >= 0
+}
+
+func (h *volumeHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *volumeHandler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// reservedToOtherTenant reports if the PersistentVolume is labelled for a different Tenant, or its claim
+// reference points to a Namespace not belonging to the given one.
+func reservedToOtherTenant(pv *corev1.PersistentVolume, tnt capsulev1beta1.Tenant) (bool, error) {
+	capsuleLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return false, err
+	}
+
+	if owner, ok := pv.GetLabels()[capsuleLabel]; ok && owner != tnt.GetName() {
+		return true, nil
+	}
+
+	if pv.Spec.ClaimRef == nil || len(pv.Spec.ClaimRef.Namespace) == 0 {
+		return false, nil
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		if ns == pv.Spec.ClaimRef.Namespace {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
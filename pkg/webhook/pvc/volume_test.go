@@ -0,0 +1,111 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package pvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestReservedToOtherTenant(t *testing.T) {
+	tnt := capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Status:     capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production"}},
+	}
+	pv := func(labels map[string]string, claimNamespace string) *corev1.PersistentVolume {
+		pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+		if len(claimNamespace) > 0 {
+			pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: claimNamespace, Name: "data"}
+		}
+
+		return pv
+	}
+
+	for name, tc := range map[string]struct {
+		pv       *corev1.PersistentVolume
+		reserved bool
+	}{
+		"available":             {pv: pv(nil, "")},
+		"labelled for tenant":   {pv: pv(map[string]string{"capsule.clastix.io/tenant": "oil"}, "")},
+		"labelled for other":    {pv: pv(map[string]string{"capsule.clastix.io/tenant": "gas"}, ""), reserved: true},
+		"pre-bound to tenant":   {pv: pv(nil, "oil-production")},
+		"pre-bound to other":    {pv: pv(nil, "gas-production"), reserved: true},
+		"released by other tnt": {pv: pv(map[string]string{"capsule.clastix.io/tenant": "gas"}, "oil-production"), reserved: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			reserved, err := reservedToOtherTenant(tc.pv, tnt)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.reserved, reserved)
+		})
+	}
+}
+
+func TestClaimablePersistentVolumes(t *testing.T) {
+	labels := map[string]string{"disk": "ssd"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0001", Labels: labels}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0002", Labels: labels}, Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{Namespace: "gas-production", Name: "data"},
+		}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0003", Labels: labels}, Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{Namespace: "oil-production", Name: "data"},
+		}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0004"}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0005", Labels: map[string]string{"capsule.clastix.io/tenant": "gas"}}, Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: "standard",
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0006", Labels: map[string]string{"capsule.clastix.io/tenant": "gas"}}, Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: "fast",
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		}},
+	).Build()
+
+	names := func(pvc *corev1.PersistentVolumeClaim) (names []string) {
+		pvs, err := claimablePersistentVolumes(context.Background(), c, pvc)
+		assert.NoError(t, err)
+
+		for _, pv := range pvs {
+			names = append(names, pv.GetName())
+		}
+
+		return names
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "oil-production"}}
+	assert.Empty(t, names(pvc))
+
+	pvc.Spec.VolumeName = "pv-0004"
+	assert.Equal(t, []string{"pv-0004"}, names(pvc))
+	// the volumes pre-bound to a different claim are not claimable by the selector
+	pvc.Spec.VolumeName = ""
+	pvc.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+	assert.Equal(t, []string{"pv-0001", "pv-0003"}, names(pvc))
+	// without volume name and selector, the labelled volumes matching the class, the size and the access modes
+	pvc.Spec.Selector = nil
+	pvc.Spec.StorageClassName = pointer.StringPtr("standard")
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}
+	assert.Equal(t, []string{"pv-0005"}, names(pvc))
+
+	pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}
+	assert.Empty(t, names(pvc))
+
+	pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+	assert.Empty(t, names(pvc))
+}
@@ -0,0 +1,562 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/lookup"
+	"github.com/clastix/capsule/pkg/server"
+)
+
+// maxReplayBodySize is the maximum size of the replayed AdmissionReviews and manifests.
+const maxReplayBodySize = 3 * 1024 * 1024
+
+// ReplayResult is the outcome of a replayed admission request.
+type ReplayResult struct {
+	// Whether the request would be admitted.
+	Allowed bool `json:"allowed"`
+	// The decisions of the webhooks matching the request, in the order they're called.
+	Webhooks []ReplayDecision `json:"webhooks"`
+	// The object as mutated by the webhooks, when any mutated it.
+	Object *runtime.RawExtension `json:"object,omitempty"`
+}
+
+// ReplayDecision is the decision taken by a webhook on a replayed admission request.
+type ReplayDecision struct {
+	// The name of the webhook in the webhook configuration.
+	Name string `json:"name"`
+	// The path the webhook is served at.
+	Path string `json:"path"`
+	// Whether the webhook is a mutating one.
+	Mutating bool `json:"mutating"`
+	// One of allowed, denied, exempted and excepted.
+	Decision string `json:"decision"`
+	// The reason of the denial, if any.
+	Message string `json:"message,omitempty"`
+	// The warnings returned to the user.
+	Warnings []string `json:"warnings,omitempty"`
+	// The JSON patch applied to the object, if any.
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+// Replay evaluates the admission requests against the Capsule webhooks matching them in the live webhook
+// configurations, as the API server would call them, without persisting anything: the handlers Events are discarded,
+// as the denials and the exemptions accounting.
+type Replay struct {
+	client     client.Client
+	mapper     meta.RESTMapper
+	log        logr.Logger
+	mutating   string
+	validating string
+	routers    map[string]*handlerRouter
+}
+
+// NewReplay returns the Replay of the given webhooks, registered in the mutating and validating webhook
+// configurations with the given names.
+func NewReplay(manager controllerruntime.Manager, log logr.Logger, cfg configuration.Configuration, index *lookup.TenantIndex, mutating, validating string, webhookList ...Webhook) (*Replay, error) {
+	decoder, err := admission.NewDecoder(manager.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+
+	c := lookup.Client{Client: manager.GetClient(), Index: index}
+
+	routers := make(map[string]*handlerRouter, len(webhookList))
+
+	for _, wh := range webhookList {
+		routers[wh.GetPath()] = &handlerRouter{
+			path:          wh.GetPath(),
+			log:           log.WithValues("webhook", wh.GetPath()),
+			configuration: cfg,
+			client:        c,
+			index:         index,
+			decoder:       decoder,
+			recorder:      discardRecorder{},
+			dryRun:        true,
+			handlers:      wh.GetHandlers(),
+		}
+	}
+
+	return &Replay{
+		client:     manager.GetClient(),
+		mapper:     manager.GetRESTMapper(),
+		log:        log,
+		mutating:   mutating,
+		validating: validating,
+		routers:    routers,
+	}, nil
+}
+
+// ServeHTTP replays the AdmissionReview, or the manifest, posted as JSON or YAML by the caller authenticated by
+// server.Authenticated: the manifests are replayed as performed by the caller, unless impersonating the user and the
+// groups set with the user and group query parameters, with the operation set by the operation one, CREATE by default.
+// The callers must be allowed to impersonate the users and the groups of the requests they don't perform themselves,
+// as for the AdmissionReviews of the other users.
+func (r *Replay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only the POST method is allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	caller, ok := server.UserInfoFrom(req.Context())
+	if !ok {
+		http.Error(w, "the caller is not authenticated", http.StatusUnauthorized)
+
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxReplayBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	request, err := r.admissionRequest(req.Context(), body, req.URL.Query(), caller)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if err = r.authorizeImpersonation(req.Context(), caller, request.UserInfo); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+
+		return
+	}
+
+	result, err := r.Review(req.Context(), *request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		r.log.Error(err, "Cannot export the replay result")
+	}
+}
+
+// Review returns the decisions of the webhooks matching the given request: the mutating webhooks are called in
+// order, the mutated object being reviewed by the following ones, until any denies it, then all the validating ones.
+func (r *Replay) Review(ctx context.Context, req admission.Request) (*ReplayResult, error) {
+	mutatingCfg := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.mutating}, mutatingCfg); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+
+	validatingCfg := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.validating}, validatingCfg); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{Allowed: true, Webhooks: []ReplayDecision{}}
+
+	var mutated bool
+
+	for _, wh := range mutatingCfg.Webhooks {
+		ok, err := r.matches(ctx, req, wh.ClientConfig, wh.Rules, wh.NamespaceSelector, wh.ObjectSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		decision, response := r.review(ctx, req, wh.Name, wh.ClientConfig)
+		decision.Mutating = true
+
+		if len(response.Patches) > 0 {
+			if decision.Patch, err = json.Marshal(response.Patches); err != nil {
+				return nil, err
+			}
+
+			var patch jsonpatch.Patch
+			if patch, err = jsonpatch.DecodePatch(decision.Patch); err != nil {
+				return nil, err
+			}
+
+			if req.Object.Raw, err = patch.Apply(req.Object.Raw); err != nil {
+				return nil, err
+			}
+
+			req.Object.Object, mutated = nil, true
+		}
+
+		result.Webhooks = append(result.Webhooks, decision)
+
+		if !response.Allowed {
+			result.Allowed = false
+
+			return result, nil
+		}
+	}
+
+	for _, wh := range validatingCfg.Webhooks {
+		ok, err := r.matches(ctx, req, wh.ClientConfig, wh.Rules, wh.NamespaceSelector, wh.ObjectSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		decision, response := r.review(ctx, req, wh.Name, wh.ClientConfig)
+
+		result.Webhooks = append(result.Webhooks, decision)
+		result.Allowed = result.Allowed && response.Allowed
+	}
+
+	if mutated {
+		result.Object = &runtime.RawExtension{Raw: req.Object.Raw}
+	}
+
+	return result, nil
+}
+
+func (r *Replay) review(ctx context.Context, req admission.Request, name string, clientConfig admissionregistrationv1.WebhookClientConfig) (ReplayDecision, admission.Response) {
+	path := webhookPath(clientConfig)
+
+	response, decision := r.routers[path].review(ctx, req)
+
+	replayed := ReplayDecision{
+		Name:     name,
+		Path:     path,
+		Decision: decision,
+		Warnings: response.Warnings,
+	}
+
+	if response.Result != nil {
+		replayed.Message = response.Result.Message
+	}
+
+	return replayed, response
+}
+
+// matches reports if the webhook served by Capsule at the path of the given client configuration would be called
+// by the API server for the given request.
+func (r *Replay) matches(ctx context.Context, req admission.Request, clientConfig admissionregistrationv1.WebhookClientConfig, rules []admissionregistrationv1.RuleWithOperations, namespaceSelector, objectSelector *metav1.LabelSelector) (bool, error) {
+	if _, ok := r.routers[webhookPath(clientConfig)]; !ok {
+		return false, nil
+	}
+
+	if !rulesMatch(rules, req) {
+		return false, nil
+	}
+
+	object, old, err := objectLabels(req)
+	if err != nil {
+		return false, err
+	}
+
+	if ok, err := selectorMatches(objectSelector, object, old); err != nil || !ok {
+		return false, err
+	}
+	// the Namespace selector applies to the namespaced objects and to the Namespaces themselves only
+	switch {
+	case req.Kind.Kind == "Namespace" && len(req.Kind.Group) == 0:
+		return selectorMatches(namespaceSelector, object, old)
+	case len(req.Namespace) > 0:
+		ns := &corev1.Namespace{}
+		if err = r.client.Get(ctx, types.NamespacedName{Name: req.Namespace}, ns); client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+
+		return selectorMatches(namespaceSelector, ns.GetLabels(), nil)
+	default:
+		return true, nil
+	}
+}
+
+// authorizeImpersonation returns an error when the caller is not allowed to impersonate the user, or any of the
+// groups, of the given identity it doesn't hold itself.
+func (r *Replay) authorizeImpersonation(ctx context.Context, caller, user authenticationv1.UserInfo) error {
+	username, groups := impersonated(caller, user)
+
+	attributes := make([]authorizationv1.ResourceAttributes, 0, len(groups)+1)
+	if len(username) > 0 {
+		attributes = append(attributes, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "users", Name: username})
+	}
+
+	for _, group := range groups {
+		attributes = append(attributes, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "groups", Name: group})
+	}
+
+	for i := range attributes {
+		allowed, err := server.Authorized(ctx, r.client, caller, authorizationv1.SubjectAccessReviewSpec{ResourceAttributes: &attributes[i]})
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return fmt.Errorf("%s cannot impersonate the %s %s", caller.Username, strings.TrimSuffix(attributes[i].Resource, "s"), attributes[i].Name)
+		}
+	}
+
+	return nil
+}
+
+// impersonated returns the user and the groups of the given identity differing from the caller ones.
+func impersonated(caller, user authenticationv1.UserInfo) (username string, groups []string) {
+	if user.Username != caller.Username {
+		username = user.Username
+	}
+
+	for _, group := range user.Groups {
+		if !contains(caller.Groups, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	return username, groups
+}
+
+// admissionRequest returns the request of the posted AdmissionReview, or the one performing the operation set by
+// the query parameters on the posted manifest, as the caller unless set otherwise: the objects being updated or
+// deleted are retrieved from the cluster.
+func (r *Replay) admissionRequest(ctx context.Context, body []byte, query map[string][]string, caller authenticationv1.UserInfo) (*admission.Request, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(body), len(body)).Decode(&obj.Object); err != nil {
+		return nil, fmt.Errorf("cannot decode the request body: %w", err)
+	}
+
+	raw, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.GetKind() == "AdmissionReview" {
+		review := &admissionv1.AdmissionReview{}
+		if err = json.Unmarshal(raw, review); err != nil {
+			return nil, err
+		}
+
+		if review.Request == nil {
+			return nil, fmt.Errorf("the AdmissionReview has no request")
+		}
+
+		dryRun := true
+		review.Request.DryRun = &dryRun
+
+		return &admission.Request{AdmissionRequest: *review.Request}, nil
+	}
+
+	operation := admissionv1.Operation(strings.ToUpper(first(query["operation"])))
+	if len(operation) == 0 {
+		operation = admissionv1.Create
+	}
+
+	if operation != admissionv1.Create && operation != admissionv1.Update && operation != admissionv1.Delete {
+		return nil, fmt.Errorf("unsupported operation %s, expected one of CREATE, UPDATE and DELETE", operation)
+	}
+
+	user := caller
+	if username := first(query["user"]); len(username) > 0 {
+		user = authenticationv1.UserInfo{Username: username, Groups: append(query["group"], "system:authenticated")}
+	}
+
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && len(obj.GetNamespace()) == 0 {
+		return nil, fmt.Errorf("the namespace of the %s %s is missing", gvk.Kind, obj.GetName())
+	}
+
+	dryRun := true
+
+	request := admissionv1.AdmissionRequest{
+		UID:             types.UID(uuid.NewUUID()),
+		Kind:            metav1.GroupVersionKind(gvk),
+		Resource:        metav1.GroupVersionResource(mapping.Resource),
+		RequestKind:     &metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		RequestResource: &metav1.GroupVersionResource{Group: mapping.Resource.Group, Version: mapping.Resource.Version, Resource: mapping.Resource.Resource},
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		Operation:       operation,
+		UserInfo:        user,
+		DryRun:          &dryRun,
+	}
+
+	if operation != admissionv1.Create {
+		old := &unstructured.Unstructured{}
+		old.SetGroupVersionKind(gvk)
+
+		if err = r.client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, old); err != nil {
+			return nil, err
+		}
+
+		if request.OldObject.Raw, err = old.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+
+	if operation != admissionv1.Delete {
+		request.Object.Raw = raw
+	}
+
+	return &admission.Request{AdmissionRequest: request}, nil
+}
+
+// rulesMatch reports if any of the rules matches the request, as evaluated by the API server.
+func rulesMatch(rules []admissionregistrationv1.RuleWithOperations, req admission.Request) bool {
+	namespaces := req.Resource.Group == "" && req.Resource.Resource == "namespaces"
+
+	for _, rule := range rules {
+		operations := make([]string, 0, len(rule.Operations))
+		for _, operation := range rule.Operations {
+			operations = append(operations, string(operation))
+		}
+
+		if !contains(operations, string(req.Operation)) || !contains(rule.APIGroups, req.Resource.Group) || !contains(rule.APIVersions, req.Resource.Version) {
+			continue
+		}
+
+		var resource bool
+
+		for _, item := range rule.Resources {
+			res, sub := item, ""
+			if i := strings.Index(item, "/"); i >= 0 {
+				res, sub = item[:i], item[i+1:]
+			}
+
+			if (res == "*" || res == req.Resource.Resource) && (sub == "*" || sub == req.SubResource) {
+				resource = true
+
+				break
+			}
+		}
+
+		if !resource {
+			continue
+		}
+		// the Namespaces are matched by both the cluster and the namespaced scopes
+		if scope := rule.Scope; scope != nil && !namespaces {
+			if *scope == admissionregistrationv1.ClusterScope && len(req.Namespace) > 0 {
+				continue
+			}
+
+			if *scope == admissionregistrationv1.NamespacedScope && len(req.Namespace) == 0 {
+				continue
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// objectLabels returns the labels of the object and of the old object of the request.
+func objectLabels(req admission.Request) (object, old map[string]string, err error) {
+	for _, item := range []struct {
+		raw    []byte
+		labels *map[string]string
+	}{{req.Object.Raw, &object}, {req.OldObject.Raw, &old}} {
+		if len(item.raw) == 0 {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		if err = u.UnmarshalJSON(item.raw); err != nil {
+			return nil, nil, err
+		}
+
+		*item.labels = u.GetLabels()
+	}
+
+	return object, old, nil
+}
+
+// selectorMatches reports if the selector matches the labels of either the object or the old one, as evaluated by
+// the API server: a nil selector matches everything.
+func selectorMatches(selector *metav1.LabelSelector, object, old map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+
+	return s.Matches(labels.Set(object)) || (old != nil && s.Matches(labels.Set(old))), nil
+}
+
+// webhookPath returns the path the webhook of the given client configuration is served at.
+func webhookPath(clientConfig admissionregistrationv1.WebhookClientConfig) string {
+	switch {
+	case clientConfig.Service != nil && clientConfig.Service.Path != nil:
+		return *clientConfig.Service.Path
+	case clientConfig.URL != nil:
+		if i := strings.Index(strings.TrimPrefix(*clientConfig.URL, "https://"), "/"); i >= 0 {
+			return strings.TrimPrefix(*clientConfig.URL, "https://")[i:]
+		}
+	}
+
+	return ""
+}
+
+func contains(items []string, value string) bool {
+	for _, item := range items {
+		if item == value || item == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// discardRecorder drops the Events recorded by the handlers serving the replayed requests.
+type discardRecorder struct{}
+
+func (discardRecorder) Event(runtime.Object, string, string, string) {}
+
+func (discardRecorder) Eventf(runtime.Object, string, string, string, ...interface{}) {}
+
+func (discardRecorder) AnnotatedEventf(runtime.Object, map[string]string, string, string, string, ...interface{}) {
+}
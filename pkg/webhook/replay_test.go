@@ -0,0 +1,88 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func replayRequest(operation admissionv1.Operation, group, resource, subResource, namespace string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation:   operation,
+		Resource:    metav1.GroupVersionResource{Group: group, Version: "v1", Resource: resource},
+		SubResource: subResource,
+		Namespace:   namespace,
+	}}
+}
+
+func TestRulesMatch(t *testing.T) {
+	namespaced, cluster := admissionregistrationv1.NamespacedScope, admissionregistrationv1.ClusterScope
+
+	rules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods", "services/*"}, Scope: &namespaced},
+	}}
+
+	assert.True(t, rulesMatch(rules, replayRequest(admissionv1.Create, "", "pods", "", "oil-production")))
+	assert.True(t, rulesMatch(rules, replayRequest(admissionv1.Update, "", "services", "status", "oil-production")))
+	assert.False(t, rulesMatch(rules, replayRequest(admissionv1.Delete, "", "pods", "", "oil-production")))
+	assert.False(t, rulesMatch(rules, replayRequest(admissionv1.Create, "", "pods", "status", "oil-production")))
+	assert.False(t, rulesMatch(rules, replayRequest(admissionv1.Create, "apps", "pods", "", "oil-production")))
+
+	rules = []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{"*"}, APIVersions: []string{"*"}, Resources: []string{"*"}, Scope: &cluster},
+	}}
+
+	assert.True(t, rulesMatch(rules, replayRequest(admissionv1.Delete, "rbac.authorization.k8s.io", "clusterroles", "", "")))
+	assert.False(t, rulesMatch(rules, replayRequest(admissionv1.Create, "", "pods", "", "oil-production")))
+	assert.True(t, rulesMatch(rules, replayRequest(admissionv1.Create, "", "namespaces", "", "oil-production")))
+}
+
+func TestSelectorMatches(t *testing.T) {
+	ok, err := selectorMatches(nil, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	selector := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "capsule.clastix.io/tenant", Operator: metav1.LabelSelectorOpExists}}}
+
+	ok, err = selectorMatches(selector, map[string]string{"capsule.clastix.io/tenant": "oil"}, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = selectorMatches(selector, nil, map[string]string{"capsule.clastix.io/tenant": "oil"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = selectorMatches(selector, map[string]string{"app": "nginx"}, nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestWebhookPath(t *testing.T) {
+	path, url := "/pods", "https://capsule-webhook-service.capsule-system.svc:443/namespaces"
+
+	assert.Equal(t, "/pods", webhookPath(admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Path: &path}}))
+	assert.Equal(t, "/namespaces", webhookPath(admissionregistrationv1.WebhookClientConfig{URL: &url}))
+	assert.Equal(t, "", webhookPath(admissionregistrationv1.WebhookClientConfig{}))
+}
+
+func TestImpersonated(t *testing.T) {
+	caller := authenticationv1.UserInfo{Username: "ci", Groups: []string{"ci-runners", "system:authenticated"}}
+
+	username, groups := impersonated(caller, caller)
+	assert.Empty(t, username)
+	assert.Empty(t, groups)
+
+	username, groups = impersonated(caller, authenticationv1.UserInfo{Username: "alice", Groups: []string{"capsule.clastix.io", "system:authenticated"}})
+	assert.Equal(t, "alice", username)
+	assert.Equal(t, []string{"capsule.clastix.io"}, groups)
+}
@@ -0,0 +1,44 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/cronjobs,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="batch",resources=cronjobs;jobs,verbs=create;update,versions=v1;v1beta1,name=cronjobs.capsule.clastix.io
+
+type cronJob struct {
+	handlers []capsulewebhook.Handler
+}
+
+func CronJob(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &cronJob{handlers: handler}
+}
+
+func (w *cronJob) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *cronJob) GetPath() string {
+	return "/cronjobs"
+}
+
+// +kubebuilder:webhook:path=/cronjob-defaults,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="batch",resources=cronjobs,verbs=create;update,versions=v1;v1beta1,name=defaults.cronjobs.capsule.clastix.io
+
+type cronJobDefaults struct {
+	handlers []capsulewebhook.Handler
+}
+
+func CronJobDefaults(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &cronJobDefaults{handlers: handler}
+}
+
+func (w *cronJobDefaults) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *cronJobDefaults) GetPath() string {
+	return "/cronjob-defaults"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/customresourcedefinitions,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=create;update;delete,versions=v1,name=customresourcedefinitions.capsule.clastix.io
+
+type customResourceDefinition struct {
+	handlers []capsulewebhook.Handler
+}
+
+func CustomResourceDefinition(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &customResourceDefinition{handlers: handler}
+}
+
+func (w *customResourceDefinition) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *customResourceDefinition) GetPath() string {
+	return "/customresourcedefinitions"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/externalsecrets,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="external-secrets.io",resources=externalsecrets;secretstores,verbs=create;update,versions=v1alpha1;v1beta1,name=externalsecrets.capsule.clastix.io
+
+type externalSecret struct {
+	handlers []capsulewebhook.Handler
+}
+
+func ExternalSecret(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &externalSecret{handlers: handler}
+}
+
+func (w *externalSecret) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *externalSecret) GetPath() string {
+	return "/externalsecrets"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/gateways,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups=gateway.networking.k8s.io,resources=gateways;httproutes;tlsroutes;grpcroutes,verbs=create;update,versions=v1alpha2;v1beta1;v1,name=gateways.capsule.clastix.io
+
+type gateway struct {
+	handlers []capsulewebhook.Handler
+}
+
+func Gateway(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &gateway{handlers: handler}
+}
+
+func (w *gateway) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *gateway) GetPath() string {
+	return "/gateways"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/job-defaults,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="batch",resources=jobs,verbs=create,versions=v1,name=defaults.jobs.capsule.clastix.io
+
+type jobDefaults struct {
+	handlers []capsulewebhook.Handler
+}
+
+func JobDefaults(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &jobDefaults{handlers: handler}
+}
+
+func (w *jobDefaults) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *jobDefaults) GetPath() string {
+	return "/job-defaults"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/managed,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="";networking.k8s.io;rbac.authorization.k8s.io,resources=limitranges;resourcequotas;networkpolicies;rolebindings,verbs=update;delete,versions=v1,name=managed.capsule.clastix.io
+
+type managed struct {
+	handlers []capsulewebhook.Handler
+}
+
+func Managed(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &managed{handlers: handler}
+}
+
+func (w *managed) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *managed) GetPath() string {
+	return "/managed"
+}
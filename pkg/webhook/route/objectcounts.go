@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/object-counts,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=ignore,groups="*",resources="*",verbs=create,versions="*",name=objectcounts.capsule.clastix.io
+
+type objectCounts struct {
+	handlers []capsulewebhook.Handler
+}
+
+func ObjectCounts(handlers ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &objectCounts{handlers: handlers}
+}
+
+func (w objectCounts) GetPath() string {
+	return "/object-counts"
+}
+
+func (w objectCounts) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/pod-defaults,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="",resources=pods,verbs=create;update,versions=v1,name=defaults.pods.capsule.clastix.io
+
+type podDefaults struct {
+	handlers []capsulewebhook.Handler
+}
+
+func PodDefaults(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &podDefaults{handlers: handler}
+}
+
+func (w *podDefaults) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *podDefaults) GetPath() string {
+	return "/pod-defaults"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/secrets,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="",resources=secrets,verbs=create;update,versions=v1,name=secrets.capsule.clastix.io
+
+type secret struct {
+	handlers []capsulewebhook.Handler
+}
+
+func Secret(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &secret{handlers: handler}
+}
+
+func (w *secret) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *secret) GetPath() string {
+	return "/secrets"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/service-cluster-ips,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="",resources=services,verbs=create;update,versions=v1,name=clusterips.services.capsule.clastix.io
+
+type serviceClusterIPs struct {
+	handlers []capsulewebhook.Handler
+}
+
+func ServiceClusterIPs(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &serviceClusterIPs{handlers: handler}
+}
+
+func (w *serviceClusterIPs) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *serviceClusterIPs) GetPath() string {
+	return "/service-cluster-ips"
+}
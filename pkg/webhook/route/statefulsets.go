@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/statefulsets,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups=apps,resources=statefulsets,verbs=create,versions=v1,name=statefulsets.capsule.clastix.io
+
+type statefulSet struct {
+	handlers []capsulewebhook.Handler
+}
+
+func StatefulSet(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &statefulSet{handlers: handler}
+}
+
+func (w *statefulSet) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *statefulSet) GetPath() string {
+	return "/statefulsets"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/tenantrequests,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="capsule.clastix.io",resources=tenantrequests,verbs=create;update,versions=v1beta1,name=tenantrequests.capsule.clastix.io
+
+type tenantRequest struct {
+	handlers []capsulewebhook.Handler
+}
+
+func TenantRequest(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &tenantRequest{handlers: handler}
+}
+
+func (w *tenantRequest) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *tenantRequest) GetPath() string {
+	return "/tenantrequests"
+}
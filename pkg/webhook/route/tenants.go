@@ -24,3 +24,21 @@ func (w *tenant) GetHandlers() []capsulewebhook.Handler {
 func (w *tenant) GetPath() string {
 	return "/tenants"
 }
+
+// +kubebuilder:webhook:path=/tenant-defaults,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="capsule.clastix.io",resources=tenants,verbs=create,versions=v1beta1,name=defaults.tenants.capsule.clastix.io
+
+type tenantDefaults struct {
+	handlers []capsulewebhook.Handler
+}
+
+func TenantDefaults(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &tenantDefaults{handlers: handler}
+}
+
+func (w *tenantDefaults) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *tenantDefaults) GetPath() string {
+	return "/tenant-defaults"
+}
@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/tenantwebhookconfigurations,mutating=false,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="capsule.clastix.io",resources=tenantwebhookconfigurations,verbs=create;update,versions=v1beta1,name=tenantwebhookconfigurations.capsule.clastix.io
+
+type tenantWebhookConfiguration struct {
+	handlers []capsulewebhook.Handler
+}
+
+func TenantWebhookConfiguration(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &tenantWebhookConfiguration{handlers: handler}
+}
+
+func (w *tenantWebhookConfiguration) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *tenantWebhookConfiguration) GetPath() string {
+	return "/tenantwebhookconfigurations"
+}
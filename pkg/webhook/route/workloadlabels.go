@@ -0,0 +1,26 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/workload-labels,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=fail,groups="";apps,resources=pods;deployments;statefulsets,verbs=create;update,versions=v1,name=labels.workloads.capsule.clastix.io
+
+type workloadLabels struct {
+	handlers []capsulewebhook.Handler
+}
+
+func WorkloadLabels(handler ...capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &workloadLabels{handlers: handler}
+}
+
+func (w *workloadLabels) GetHandlers() []capsulewebhook.Handler {
+	return w.handlers
+}
+
+func (w *workloadLabels) GetPath() string {
+	return "/workload-labels"
+}
@@ -6,18 +6,36 @@ package webhook
 import (
 	"context"
 	"io/ioutil"
+	"path/filepath"
 
+	"github.com/go-logr/logr"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/tools/record"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/logging"
+	"github.com/clastix/capsule/pkg/lookup"
+	"github.com/clastix/capsule/pkg/webhook/denials"
 )
 
-func Register(manager controllerruntime.Manager, webhookList ...Webhook) error {
+// Register serves the given webhooks, unless the serving certificate is missing in the given directory, as the one
+// of the manager webhook server: the Tenant lookups performed by the handlers are served
+// by the given index, if any, while the requests of the configured exemptions are allowed straight away, and the
+// denied ones matching an unexpired TenantException are allowed with a warning, besides the ones added by the
+// handlers with Warn.
+// The denials of the requests in the Tenant Namespaces are recorded in the given history, if any, and the decisions
+// are logged along with the webhook and the Tenant of the request.
+func Register(manager controllerruntime.Manager, certDir string, log logr.Logger, cfg configuration.Configuration, index *lookup.TenantIndex, history *denials.History, webhookList ...Webhook) error {
 	// skipping webhook setup if certificate is missing
-	certData, _ := ioutil.ReadFile("/tmp/k8s-webhook-server/serving-certs/tls.crt")
+	certData, _ := ioutil.ReadFile(filepath.Join(certDir, "tls.crt"))
 	if len(certData) == 0 {
 		return nil
 	}
@@ -29,8 +47,13 @@ func Register(manager controllerruntime.Manager, webhookList ...Webhook) error {
 	for _, wh := range webhookList {
 		server.Register(wh.GetPath(), &webhook.Admission{
 			Handler: &handlerRouter{
-				recorder: recorder,
-				handlers: wh.GetHandlers(),
+				path:          wh.GetPath(),
+				log:           log.WithValues("webhook", wh.GetPath()),
+				configuration: cfg,
+				index:         index,
+				history:       history,
+				recorder:      recorder,
+				handlers:      wh.GetHandlers(),
 			},
 		})
 	}
@@ -38,14 +61,91 @@ func Register(manager controllerruntime.Manager, webhookList ...Webhook) error {
 }
 
 type handlerRouter struct {
-	client   client.Client
-	decoder  *admission.Decoder
-	recorder record.EventRecorder
+	path          string
+	log           logr.Logger
+	configuration configuration.Configuration
+	client        client.Client
+	index         *lookup.TenantIndex
+	history       *denials.History
+	decoder       *admission.Decoder
+	recorder      record.EventRecorder
+	// dryRun routers don't account the exemptions, as the ones serving the replayed requests
+	dryRun bool
 
 	handlers []Handler
 }
 
 func (r *handlerRouter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	response, _ := r.review(ctx, req)
+
+	return response
+}
+
+// review returns the response to the request along with the decision taken, one of allowed, denied, exempted and
+// excepted.
+func (r *handlerRouter) review(ctx context.Context, req admission.Request) (admission.Response, string) {
+	log := r.log.WithValues("operation", req.Operation, "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "user", req.UserInfo.Username)
+
+	if kind, name, ok := exemptedBy(req, r.configuration.Exemptions()); ok {
+		if !r.dryRun {
+			exemptionsTotal.WithLabelValues(r.path, kind, name).Inc()
+		}
+
+		log.V(1).Info("Admission request handled", "decision", "exempted", "exemption", kind+"/"+name)
+
+		return admission.Allowed(""), "exempted"
+	}
+
+	var tenant string
+	if len(req.Namespace) > 0 {
+		if tenant = r.tenantName(ctx, req.Namespace); len(tenant) > 0 {
+			log = log.WithValues(logging.TenantKey, tenant)
+		}
+	}
+
+	ctx, warnings := withWarnings(ctx)
+
+	response := r.handle(ctx, req)
+	response.Warnings = append(response.Warnings, warnings.List()...)
+
+	if response.Allowed {
+		log.V(1).Info("Admission request handled", "decision", "allowed", "patched", len(response.Patches) > 0)
+
+		return response, "allowed"
+	}
+
+	var message string
+	if response.Result != nil {
+		message = response.Result.Message
+	}
+
+	if len(req.Namespace) > 0 {
+		if exception, ok := r.exceptedBy(ctx, req); ok {
+			if !r.dryRun {
+				exemptionsTotal.WithLabelValues(r.path, "TenantException", exception.GetName()).Inc()
+			}
+
+			r.recorder.Eventf(exception, corev1.EventTypeWarning, "Exempted", "%s %s of %s %s/%s exempted from the webhook %s", req.UserInfo.Username, req.Operation, req.Kind.Kind, req.Namespace, req.Name, r.path)
+
+			log.Info("Admission request handled", "decision", "excepted", "exception", exception.GetName(), "message", message)
+
+			allowed := admission.Allowed("")
+			allowed.Warnings = append([]string{exceptionWarning(exception, response)}, warnings.List()...)
+
+			return allowed, "excepted"
+		}
+	}
+
+	if r.history != nil && len(tenant) > 0 {
+		r.recordDenial(tenant, req, message)
+	}
+
+	log.Info("Admission request handled", "decision", "denied", "message", message)
+
+	return response, "denied"
+}
+
+func (r *handlerRouter) handle(ctx context.Context, req admission.Request) admission.Response {
 	switch req.Operation {
 	case admissionv1.Create:
 		for _, h := range r.handlers {
@@ -70,8 +170,34 @@ func (r *handlerRouter) Handle(ctx context.Context, req admission.Request) admis
 	return admission.Allowed("")
 }
 
+// tenantName returns the name of the Tenant owning the given Namespace, if any.
+func (r *handlerRouter) tenantName(ctx context.Context, namespace string) string {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := r.client.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", namespace),
+	}); err != nil || len(tntList.Items) == 0 {
+		return ""
+	}
+
+	return tntList.Items[0].GetName()
+}
+
+// recordDenial adds the denied request to the history of the Tenant owning its Namespace.
+func (r *handlerRouter) recordDenial(tenant string, req admission.Request, message string) {
+	r.history.Record(tenant, denials.Denial{
+		Time:      metav1.Now(),
+		User:      req.UserInfo.Username,
+		Operation: string(req.Operation),
+		Kind:      req.Kind.Kind,
+		Namespace: req.Namespace,
+		Name:      req.Name,
+		Webhook:   r.path,
+		Message:   message,
+	})
+}
+
 func (r *handlerRouter) InjectClient(c client.Client) error {
-	r.client = c
+	r.client = lookup.Client{Client: c, Index: r.index}
 
 	return nil
 }
@@ -0,0 +1,68 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package secret
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+type typeForbidden struct {
+	secretType string
+	spec       capsulev1beta1.ForbiddenListSpec
+}
+
+func NewTypeForbidden(secretType string, spec capsulev1beta1.ForbiddenListSpec) error {
+	return &typeForbidden{
+		secretType: secretType,
+		spec:       spec,
+	}
+}
+
+func (f typeForbidden) Error() (err string) {
+	err = fmt.Sprintf("Secret type %s is forbidden for the current Tenant", f.secretType)
+
+	if len(f.spec.Exact) > 0 {
+		err += fmt.Sprintf(", the following ones are denied (%s)", strings.Join(f.spec.Exact, ", "))
+	}
+	if len(f.spec.Regex) > 0 {
+		err += fmt.Sprintf(", along with the ones matching the regex %s", f.spec.Regex)
+	}
+
+	return
+}
+
+type sizeExceeded struct {
+	size resource.Quantity
+	max  resource.Quantity
+}
+
+func NewSizeExceeded(size, max resource.Quantity) error {
+	return &sizeExceeded{
+		size: size,
+		max:  max,
+	}
+}
+
+func (s sizeExceeded) Error() string {
+	return fmt.Sprintf("Secret size %s exceeds the maximum allowed by the current Tenant (%s)", s.size.String(), s.max.String())
+}
+
+type countExceeded struct {
+	max int32
+}
+
+func NewCountExceeded(max int32) error {
+	return &countExceeded{
+		max: max,
+	}
+}
+
+func (c countExceeded) Error() string {
+	return fmt.Sprintf("Cannot create the Secret, the maximum number of Secrets allowed by the current Tenant has been reached (%d)", c.max)
+}
@@ -0,0 +1,136 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package secret
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct {
+	reader client.Reader
+}
+
+// Handler denies the Secrets of a forbidden type or exceeding the maximum size of the Tenant, along with the ones
+// created once the Tenant holds its maximum number of Secrets: these are counted through the given reader, rather
+// than the cache restricted to the Secrets of the Capsule Namespace.
+func Handler(reader client.Reader) capsulewebhook.Handler {
+	return &handler{reader: reader}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req, true)
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req, false)
+	}
+}
+
+func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *handler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request, create bool) *admission.Response {
+	secret := &corev1.Secret{}
+	if err := decoder.Decode(req, secret); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tnt, err := utils.TenantForNamespace(ctx, c, req.Namespace)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if tnt == nil || tnt.Spec.SecretOptions == nil {
+		return nil
+	}
+
+	options := tnt.Spec.SecretOptions
+
+	if forbidden := options.ForbiddenTypes; forbidden != nil && create {
+		secretType := string(secret.Type)
+		if len(secretType) == 0 {
+			secretType = string(corev1.SecretTypeOpaque)
+		}
+
+		if forbidden.ExactMatch(secretType) || forbidden.RegexMatch(secretType) {
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "ForbiddenSecretType", "Secret %s/%s type %s is forbidden for the current Tenant", req.Namespace, req.Name, secretType)
+
+			response := admission.Denied(NewTypeForbidden(secretType, *forbidden).Error())
+
+			return &response
+		}
+	}
+
+	if max := options.MaxSize; max != nil {
+		if size := dataSize(secret); size > max.Value() {
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "SecretSizeExceeded", "Secret %s/%s size %d exceeds the Tenant maximum", req.Namespace, req.Name, size)
+
+			response := admission.Denied(NewSizeExceeded(*resource.NewQuantity(size, resource.BinarySI), *max).Error())
+
+			return &response
+		}
+	}
+
+	if max := options.MaxCount; max != nil && create && secret.Type != corev1.SecretTypeServiceAccountToken {
+		var secrets []corev1.Secret
+
+		for _, ns := range tnt.Status.Namespaces {
+			secretList := &corev1.SecretList{}
+			if err = h.reader.List(ctx, secretList, client.InNamespace(ns)); err != nil {
+				return utils.ErroredResponse(err)
+			}
+
+			secrets = append(secrets, secretList.Items...)
+		}
+
+		if count := countSecrets(secrets); count >= *max {
+			recorder.Eventf(tnt, corev1.EventTypeWarning, "SecretCountExceeded", "Secret %s/%s cannot be created, the Tenant has already %d Secrets", req.Namespace, req.Name, count)
+
+			response := admission.Denied(NewCountExceeded(*max).Error())
+
+			return &response
+		}
+	}
+
+	return nil
+}
+
+// dataSize returns the bytes of the values of the Secret, as accounted by the API server.
+func dataSize(secret *corev1.Secret) (size int64) {
+	for _, value := range secret.Data {
+		size += int64(len(value))
+	}
+
+	for _, value := range secret.StringData {
+		size += int64(len(value))
+	}
+
+	return size
+}
+
+// countSecrets counts the Secrets, skipping the ServiceAccount token ones managed by Kubernetes.
+func countSecrets(secrets []corev1.Secret) (count int32) {
+	for _, secret := range secrets {
+		if secret.Type != corev1.SecretTypeServiceAccountToken {
+			count++
+		}
+	}
+
+	return count
+}
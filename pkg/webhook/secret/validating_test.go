@@ -0,0 +1,85 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package secret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestDataSize(t *testing.T) {
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"tls.crt": []byte("0123456789"),
+			"tls.key": []byte("01234"),
+		},
+		StringData: map[string]string{
+			"token": "abc",
+		},
+	}
+
+	assert.Equal(t, int64(18), dataSize(secret))
+	assert.Equal(t, int64(0), dataSize(&corev1.Secret{}))
+}
+
+func TestCountSecrets(t *testing.T) {
+	secrets := []corev1.Secret{
+		{Type: corev1.SecretTypeOpaque},
+		{Type: corev1.SecretTypeTLS},
+		{Type: corev1.SecretTypeServiceAccountToken},
+		{},
+	}
+
+	assert.Equal(t, int32(3), countSecrets(secrets))
+	assert.Equal(t, int32(0), countSecrets(nil))
+}
+
+func TestMaxCount(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec:       capsulev1beta1.TenantSpec{SecretOptions: &capsulev1beta1.SecretOptions{MaxCount: pointer.Int32Ptr(1)}},
+		Status:     capsulev1beta1.TenantStatus{Namespaces: []string{"oil-production"}},
+	}
+	existing := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "oil-production"}}
+	// the cached client doesn't hold the Secrets of the Tenant Namespaces
+	cached := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tnt).Build()
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tnt, existing).Build()
+
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NoError(t, err)
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Namespace: "oil-production",
+		Name:      "cache",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+		Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"cache","namespace":"oil-production"}}`)},
+	}}
+
+	response := Handler(reader).OnCreate(cached, decoder, record.NewFakeRecorder(10))(context.Background(), req)
+	if assert.NotNil(t, response) {
+		assert.False(t, response.Allowed)
+	}
+
+	// counting through the cached client would never deny
+	response = Handler(cached).OnCreate(cached, decoder, record.NewFakeRecorder(10))(context.Background(), req)
+	assert.Nil(t, response)
+}
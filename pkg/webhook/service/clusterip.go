@@ -0,0 +1,123 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type clusterIPHandler struct{}
+
+// ClusterIPHandler denies the cluster IPs set by the Services out of the Tenant allowed ranges: it's served by a
+// mutating webhook, since the API server allocates the cluster IPs before the validating admission.
+func ClusterIPHandler() capsulewebhook.Handler {
+	return &clusterIPHandler{}
+}
+
+func (h *clusterIPHandler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *clusterIPHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *clusterIPHandler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *clusterIPHandler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	svc := &corev1.Service{}
+	if err := decoder.Decode(req, svc); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", svc.GetNamespace()),
+	}); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if len(tntList.Items) == 0 {
+		return nil
+	}
+
+	tnt := tntList.Items[0]
+
+	if tnt.Spec.ServiceOptions == nil || tnt.Spec.ServiceOptions.ClusterIPs == nil {
+		return nil
+	}
+
+	var old *corev1.Service
+
+	if len(req.OldObject.Raw) > 0 {
+		old = &corev1.Service{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return utils.ErroredResponse(err)
+		}
+	}
+
+	if ip := forbiddenClusterIP(svc, old, tnt.Spec.ServiceOptions.ClusterIPs.Allowed); len(ip) > 0 {
+		recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenClusterIP", "Service %s/%s cluster IP %s is forbidden for the current Tenant", req.Namespace, req.Name, ip)
+
+		response := admission.Denied(NewClusterIPForbidden(ip, tnt.Spec.ServiceOptions.ClusterIPs.Allowed).Error())
+
+		return &response
+	}
+
+	return nil
+}
+
+// forbiddenClusterIP returns the cluster IP set by the Service out of the allowed ranges, if any: the cluster IPs
+// allocated by the API server are not set upon the creation, and the ones unchanged by the updates are skipped.
+func forbiddenClusterIP(svc, old *corev1.Service, allowed []capsulev1beta1.AllowedIP) string {
+	clusterIPs := svc.Spec.ClusterIPs
+	if len(clusterIPs) == 0 && len(svc.Spec.ClusterIP) > 0 {
+		clusterIPs = []string{svc.Spec.ClusterIP}
+	}
+
+	for _, clusterIP := range clusterIPs {
+		if clusterIP == corev1.ClusterIPNone || len(clusterIP) == 0 {
+			continue
+		}
+
+		if old != nil && (old.Spec.ClusterIP == clusterIP || containsString(old.Spec.ClusterIPs, clusterIP)) {
+			continue
+		}
+
+		if !ipInCIDR(net.ParseIP(clusterIP), allowed) {
+			return clusterIP
+		}
+	}
+
+	return ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
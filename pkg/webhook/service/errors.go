@@ -61,3 +61,60 @@ func NewLoadBalancerDisabled() error {
 func (loadBalancerDisabled) Error() string {
 	return "LoadBalancer service types are forbidden for the tenant: please, reach out to the system administrators"
 }
+
+type trafficPolicyForbidden struct {
+	field    string
+	required string
+}
+
+func NewTrafficPolicyForbidden(field, required string) error {
+	return &trafficPolicyForbidden{
+		field:    field,
+		required: required,
+	}
+}
+
+func (t trafficPolicyForbidden) Error() string {
+	return fmt.Sprintf("The current Tenant requires the Service %s to be %s", t.field, t.required)
+}
+
+type clusterIPForbidden struct {
+	ip   string
+	cidr []string
+}
+
+func NewClusterIPForbidden(ip string, allowedIps []capsulev1beta1.AllowedIP) error {
+	var cidr []string
+	for _, i := range allowedIps {
+		cidr = append(cidr, string(i))
+	}
+
+	return &clusterIPForbidden{
+		ip:   ip,
+		cidr: cidr,
+	}
+}
+
+func (e clusterIPForbidden) Error() string {
+	if len(e.cidr) == 0 {
+		return fmt.Sprintf("The cluster IP %s cannot be set for the current Tenant, leave it to the API server allocation", e.ip)
+	}
+
+	return fmt.Sprintf("The cluster IP %s of the current Service is violating the following enforced CIDRs: %s", e.ip, strings.Join(e.cidr, ", "))
+}
+
+type serviceLimitExceeded struct {
+	resource string
+	limit    int32
+}
+
+func NewServiceLimitExceeded(resource string, limit int32) error {
+	return &serviceLimitExceeded{
+		resource: resource,
+		limit:    limit,
+	}
+}
+
+func (e serviceLimitExceeded) Error() string {
+	return fmt.Sprintf("The current Tenant cannot exceed %d %s", e.limit, e.resource)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// limitsViolation returns the error reporting the Service making the Tenant exceed its limits, if any: the other
+// Services of the Tenant are counted only when the Service increases the LoadBalancers or the node ports.
+func limitsViolation(ctx context.Context, clt client.Client, tnt *capsulev1beta1.Tenant, svc, old *corev1.Service) (violation, err error) {
+	var current, previous capsulev1beta1.ServiceUsage
+
+	current.Add(svc)
+	previous.Add(old)
+
+	if current.LoadBalancers <= previous.LoadBalancers && current.NodePorts <= previous.NodePorts {
+		return nil, nil
+	}
+
+	var others capsulev1beta1.ServiceUsage
+
+	for _, ns := range tnt.Status.Namespaces {
+		svcList := &corev1.ServiceList{}
+		if err = clt.List(ctx, svcList, client.InNamespace(ns)); err != nil {
+			return nil, err
+		}
+
+		for i := range svcList.Items {
+			if svcList.Items[i].GetNamespace() == svc.GetNamespace() && svcList.Items[i].GetName() == svc.GetName() {
+				continue
+			}
+
+			others.Add(&svcList.Items[i])
+		}
+	}
+
+	if violation = serviceLimitsViolation(tnt.ServiceLimits(), others, current, previous); violation != nil {
+		return violation, nil
+	}
+
+	for _, warning := range serviceLimitsWarnings(tnt.ServiceLimits(), others, current, previous) {
+		capsulewebhook.Warn(ctx, warning)
+	}
+
+	return nil, nil
+}
+
+// serviceLimitsViolation returns the error reporting the limit exceeded by the Service, given the usage of the other
+// Tenant Services: the Services not increasing their usage are allowed, as when the limits have been lowered.
+func serviceLimitsViolation(limits *capsulev1beta1.ServiceLimitsSpec, others, current, previous capsulev1beta1.ServiceUsage) error {
+	if limits == nil {
+		return nil
+	}
+
+	if limit := limits.LoadBalancers; limit != nil && current.LoadBalancers > previous.LoadBalancers && others.LoadBalancers+current.LoadBalancers > *limit {
+		return NewServiceLimitExceeded("LoadBalancer Services", *limit)
+	}
+
+	if limit := limits.NodePorts; limit != nil && current.NodePorts > previous.NodePorts && others.NodePorts+current.NodePorts > *limit {
+		return NewServiceLimitExceeded("node ports", *limit)
+	}
+
+	return nil
+}
+
+// serviceLimitsWarnings returns the warnings reporting the limits reached by the Service, given the usage of the other
+// Tenant Services, leaving no room for further Services of the same kind.
+func serviceLimitsWarnings(limits *capsulev1beta1.ServiceLimitsSpec, others, current, previous capsulev1beta1.ServiceUsage) (warnings []string) {
+	if limits == nil {
+		return nil
+	}
+
+	if limit := limits.LoadBalancers; limit != nil && current.LoadBalancers > previous.LoadBalancers && others.LoadBalancers+current.LoadBalancers == *limit {
+		warnings = append(warnings, fmt.Sprintf("The Service reaches the limit of %d LoadBalancer Services of the current Tenant", *limit))
+	}
+
+	if limit := limits.NodePorts; limit != nil && current.NodePorts > previous.NodePorts && others.NodePorts+current.NodePorts == *limit {
+		warnings = append(warnings, fmt.Sprintf("The Service reaches the limit of %d node ports of the current Tenant", *limit))
+	}
+
+	return warnings
+}
@@ -68,29 +68,46 @@ func (r *handler) handleService(ctx context.Context, clt client.Client, decoder
 		return &response
 	}
 
-	if svc.Spec.ExternalIPs == nil || (tnt.Spec.ServiceOptions == nil || tnt.Spec.ServiceOptions.ExternalServiceIPs == nil) {
-		return nil
+	if tnt.ServiceLimits() != nil {
+		old := &corev1.Service{}
+		if len(req.OldObject.Raw) > 0 {
+			if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+				return utils.ErroredResponse(err)
+			}
+		}
+
+		violation, err := limitsViolation(ctx, clt, &tnt, svc, old)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if violation != nil {
+			recorder.Eventf(&tnt, corev1.EventTypeWarning, "ServiceLimitExceeded", "Service %s/%s exceeds the Service limits of the current Tenant", req.Namespace, req.Name)
+
+			response := admission.Denied(violation.Error())
+
+			return &response
+		}
 	}
 
-	ipInCIDR := func(ip net.IP) bool {
-		for _, allowed := range tnt.Spec.ServiceOptions.ExternalServiceIPs.Allowed {
-			if !strings.Contains(string(allowed), "/") {
-				allowed += "/32"
-			}
+	if tnt.Spec.ServiceOptions != nil && tnt.Spec.ServiceOptions.TrafficPolicies != nil {
+		if err := trafficPoliciesViolation(svc, tnt.Spec.ServiceOptions.TrafficPolicies); err != nil {
+			recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenTrafficPolicy", "Service %s/%s traffic policy is forbidden for the current Tenant", req.Namespace, req.Name)
 
-			_, allowedIP, _ := net.ParseCIDR(string(allowed))
+			response := admission.Denied(err.Error())
 
-			if allowedIP.Contains(ip) {
-				return true
-			}
+			return &response
 		}
-		return false
+	}
+
+	if svc.Spec.ExternalIPs == nil || (tnt.Spec.ServiceOptions == nil || tnt.Spec.ServiceOptions.ExternalServiceIPs == nil) {
+		return nil
 	}
 
 	for _, externalIP := range svc.Spec.ExternalIPs {
 		ip := net.ParseIP(externalIP)
 
-		if !ipInCIDR(ip) {
+		if !ipInCIDR(ip, tnt.Spec.ServiceOptions.ExternalServiceIPs.Allowed) {
 			recorder.Eventf(&tnt, corev1.EventTypeWarning, "ForbiddenExternalServiceIP", "Service %s/%s external IP %s is forbidden for the current Tenant", req.Namespace, req.Name, ip.String())
 
 			response := admission.Denied(NewExternalServiceIPForbidden(tnt.Spec.ServiceOptions.ExternalServiceIPs.Allowed).Error())
@@ -102,6 +119,49 @@ func (r *handler) handleService(ctx context.Context, clt client.Client, decoder
 	return nil
 }
 
+// ipInCIDR returns true if the IP belongs to any of the allowed addresses and CIDR ranges.
+func ipInCIDR(ip net.IP, allowedIPs []capsulev1beta1.AllowedIP) bool {
+	for _, allowed := range allowedIPs {
+		if !strings.Contains(string(allowed), "/") {
+			allowed += "/32"
+		}
+
+		_, allowedIP, err := net.ParseCIDR(string(allowed))
+		if err != nil {
+			continue
+		}
+
+		if allowedIP.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trafficPoliciesViolation returns the error reporting the traffic policy of the Service differing from the required
+// one, if any: the external traffic policy applies only to the NodePort and LoadBalancer Services, while the internal
+// one is skipped when not supported by the API server.
+func trafficPoliciesViolation(svc *corev1.Service, policies *capsulev1beta1.ServiceTrafficPoliciesSpec) error {
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return nil
+	}
+
+	if required := policies.Internal; required != nil && svc.Spec.InternalTrafficPolicy != nil && *svc.Spec.InternalTrafficPolicy != *required {
+		return NewTrafficPolicyForbidden("internalTrafficPolicy", string(*required))
+	}
+
+	if svc.Spec.Type != corev1.ServiceTypeNodePort && svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+
+	if required := policies.External; required != nil && svc.Spec.ExternalTrafficPolicy != *required {
+		return NewTrafficPolicyForbidden("externalTrafficPolicy", string(*required))
+	}
+
+	return nil
+}
+
 func (r *handler) OnCreate(client client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) *admission.Response {
 		return r.handleService(ctx, client, decoder, req, recorder)
@@ -0,0 +1,86 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestTrafficPoliciesViolation(t *testing.T) {
+	local, cluster := corev1.ServiceInternalTrafficPolicyLocal, corev1.ServiceInternalTrafficPolicyCluster
+	externalLocal := corev1.ServiceExternalTrafficPolicyTypeLocal
+
+	policies := &capsulev1beta1.ServiceTrafficPoliciesSpec{Internal: &local, External: &externalLocal}
+
+	service := func(svcType corev1.ServiceType, internal *corev1.ServiceInternalTrafficPolicyType, external corev1.ServiceExternalTrafficPolicyType) *corev1.Service {
+		return &corev1.Service{
+			Spec: corev1.ServiceSpec{Type: svcType, InternalTrafficPolicy: internal, ExternalTrafficPolicy: external},
+		}
+	}
+
+	assert.NoError(t, trafficPoliciesViolation(service(corev1.ServiceTypeClusterIP, &local, ""), policies))
+	assert.Error(t, trafficPoliciesViolation(service(corev1.ServiceTypeClusterIP, &cluster, ""), policies))
+	// the API servers not supporting the internal traffic policy drop the field
+	assert.NoError(t, trafficPoliciesViolation(service(corev1.ServiceTypeClusterIP, nil, ""), policies))
+	assert.NoError(t, trafficPoliciesViolation(service(corev1.ServiceTypeLoadBalancer, &local, externalLocal), policies))
+	assert.Error(t, trafficPoliciesViolation(service(corev1.ServiceTypeNodePort, &local, corev1.ServiceExternalTrafficPolicyTypeCluster), policies))
+	assert.NoError(t, trafficPoliciesViolation(service(corev1.ServiceTypeExternalName, nil, ""), policies))
+}
+
+func TestForbiddenClusterIP(t *testing.T) {
+	allowed := []capsulev1beta1.AllowedIP{"10.96.10.0/24", "10.96.20.10"}
+
+	service := func(clusterIPs ...string) *corev1.Service {
+		svc := &corev1.Service{Spec: corev1.ServiceSpec{ClusterIPs: clusterIPs}}
+		if len(clusterIPs) > 0 {
+			svc.Spec.ClusterIP = clusterIPs[0]
+		}
+
+		return svc
+	}
+
+	assert.Empty(t, forbiddenClusterIP(service(), nil, allowed))
+	assert.Empty(t, forbiddenClusterIP(service(corev1.ClusterIPNone), nil, allowed))
+	assert.Empty(t, forbiddenClusterIP(service("10.96.10.42"), nil, allowed))
+	assert.Empty(t, forbiddenClusterIP(service("10.96.20.10"), nil, allowed))
+	assert.Equal(t, "10.96.30.1", forbiddenClusterIP(service("10.96.30.1"), nil, allowed))
+	assert.Equal(t, "10.96.10.1", forbiddenClusterIP(service("10.96.10.1"), nil, nil))
+	// the cluster IPs allocated before the Tenant ranges are not denied upon the updates
+	assert.Empty(t, forbiddenClusterIP(service("10.96.30.1"), service("10.96.30.1"), allowed))
+}
+
+func TestServiceLimitsViolation(t *testing.T) {
+	two, five := int32(2), int32(5)
+	limits := &capsulev1beta1.ServiceLimitsSpec{LoadBalancers: &two, NodePorts: &five}
+
+	others := capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 3}
+
+	assert.NoError(t, serviceLimitsViolation(nil, others, capsulev1beta1.ServiceUsage{LoadBalancers: 5}, capsulev1beta1.ServiceUsage{}))
+	assert.NoError(t, serviceLimitsViolation(limits, others, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 2}, capsulev1beta1.ServiceUsage{}))
+	assert.EqualError(t, serviceLimitsViolation(limits, others, capsulev1beta1.ServiceUsage{NodePorts: 3}, capsulev1beta1.ServiceUsage{}), "The current Tenant cannot exceed 5 node ports")
+	assert.EqualError(t, serviceLimitsViolation(limits, capsulev1beta1.ServiceUsage{LoadBalancers: 2}, capsulev1beta1.ServiceUsage{LoadBalancers: 1}, capsulev1beta1.ServiceUsage{}), "The current Tenant cannot exceed 2 LoadBalancer Services")
+	// the Services exceeding the lowered limits can be updated, as long as they don't increase their usage
+	assert.NoError(t, serviceLimitsViolation(limits, capsulev1beta1.ServiceUsage{LoadBalancers: 3, NodePorts: 9}, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 1}, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 1}))
+}
+
+func TestServiceLimitsWarnings(t *testing.T) {
+	two, five := int32(2), int32(5)
+	limits := &capsulev1beta1.ServiceLimitsSpec{LoadBalancers: &two, NodePorts: &five}
+
+	others := capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 3}
+
+	assert.Empty(t, serviceLimitsWarnings(nil, others, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 2}, capsulev1beta1.ServiceUsage{}))
+	assert.Empty(t, serviceLimitsWarnings(limits, others, capsulev1beta1.ServiceUsage{NodePorts: 1}, capsulev1beta1.ServiceUsage{}))
+	assert.Equal(t, []string{
+		"The Service reaches the limit of 2 LoadBalancer Services of the current Tenant",
+		"The Service reaches the limit of 5 node ports of the current Tenant",
+	}, serviceLimitsWarnings(limits, others, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 2}, capsulev1beta1.ServiceUsage{}))
+	// the Services not increasing their usage are not reported
+	assert.Empty(t, serviceLimitsWarnings(limits, others, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 2}, capsulev1beta1.ServiceUsage{LoadBalancers: 1, NodePorts: 2}))
+}
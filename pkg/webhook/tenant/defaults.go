@@ -0,0 +1,160 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type defaultsHandler struct {
+	configuration configuration.Configuration
+}
+
+// DefaultsHandler fills the restrictions of the child Tenants created without them with the ones of their parent,
+// then the namespace quota, the ResourceQuotas, the NetworkPolicies and the allowed PriorityClasses of the Tenants
+// created without them with the ones of the TenantClass they reference, if any, and with the defaults of the Capsule
+// configuration.
+func DefaultsHandler(configuration configuration.Configuration) capsulewebhook.Handler {
+	return &defaultsHandler{
+		configuration: configuration,
+	}
+}
+
+func (h *defaultsHandler) OnCreate(c client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		tnt := &capsulev1beta1.Tenant{}
+		if err := decoder.Decode(req, tnt); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		var changed bool
+
+		if len(tnt.Spec.Parent) > 0 {
+			parent := &capsulev1beta1.Tenant{}
+
+			switch err := c.Get(ctx, types.NamespacedName{Name: tnt.Spec.Parent}, parent); {
+			case apierrors.IsNotFound(err):
+				// the missing parent is reported by the Tenant validation
+			case err != nil:
+				return utils.ErroredResponse(err)
+			default:
+				changed = inherit(tnt, parent)
+			}
+		}
+
+		if name, ok := tnt.GetLabels()[capsulev1beta1.TenantClassLabel]; ok {
+			class := &capsulev1beta1.TenantClass{}
+
+			switch err := c.Get(ctx, types.NamespacedName{Name: name}, class); {
+			case apierrors.IsNotFound(err):
+				response := admission.Denied(fmt.Sprintf("The TenantClass %s referenced by the %s label does not exist", name, capsulev1beta1.TenantClassLabel))
+
+				return &response
+			case err != nil:
+				return utils.ErroredResponse(err)
+			default:
+				changed = applyDefaults(tnt, classDefaults(class)) || changed
+			}
+		}
+
+		if defaults := h.configuration.TenantDefaults(); defaults != nil && applyDefaults(tnt, defaults) {
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+
+		response := patchResponse(req.Object.Raw, tnt)
+
+		return &response
+	}
+}
+
+// patchResponse returns the response patching the raw object of the request into the given Tenant: the patch is
+// computed against the raw object, rather than the decoded one, since the latter has the empty fields the former
+// could lack, as the ResourceQuotas and the NetworkPolicies ones.
+func patchResponse(raw []byte, tnt *capsulev1beta1.Tenant) admission.Response {
+	mutated, err := json.Marshal(tnt)
+	if err != nil {
+		return *utils.ErroredResponse(err)
+	}
+
+	return admission.PatchResponseFromRaw(raw, mutated)
+}
+
+func (h *defaultsHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *defaultsHandler) OnUpdate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// classDefaults returns the defaults provided by the TenantClass.
+func classDefaults(class *capsulev1beta1.TenantClass) *capsulev1alpha1.TenantDefaultsSpec {
+	defaults := &capsulev1alpha1.TenantDefaultsSpec{
+		ResourceQuotas:  &class.Spec.ResourceQuota,
+		NetworkPolicies: &class.Spec.NetworkPolicies,
+		PriorityClasses: class.Spec.PriorityClasses,
+	}
+
+	if class.Spec.NamespaceOptions != nil {
+		defaults.NamespaceQuota = class.Spec.NamespaceOptions.Quota
+	}
+
+	return defaults
+}
+
+// applyDefaults fills the Tenant fields left empty with a copy of the defaults, reporting if any has been set.
+func applyDefaults(tnt *capsulev1beta1.Tenant, defaults *capsulev1alpha1.TenantDefaultsSpec) (changed bool) {
+	if defaults.NamespaceQuota != nil && (tnt.Spec.NamespaceOptions == nil || tnt.Spec.NamespaceOptions.Quota == nil) {
+		if tnt.Spec.NamespaceOptions == nil {
+			tnt.Spec.NamespaceOptions = &capsulev1beta1.NamespaceOptions{}
+		}
+
+		quota := *defaults.NamespaceQuota
+		tnt.Spec.NamespaceOptions.Quota = &quota
+		changed = true
+	}
+
+	if defaults.ResourceQuotas != nil && len(defaults.ResourceQuotas.Items) > 0 && len(tnt.Spec.ResourceQuota.Items) == 0 {
+		tnt.Spec.ResourceQuota = *defaults.ResourceQuotas.DeepCopy()
+		if tnt.Spec.ResourceQuota.Scope == "" {
+			tnt.Spec.ResourceQuota.Scope = capsulev1beta1.ResourceQuotaScopeTenant
+		}
+
+		changed = true
+	}
+
+	if defaults.NetworkPolicies != nil && len(defaults.NetworkPolicies.Items) > 0 && len(tnt.Spec.NetworkPolicies.Items) == 0 {
+		tnt.Spec.NetworkPolicies = *defaults.NetworkPolicies.DeepCopy()
+		changed = true
+	}
+
+	if defaults.PriorityClasses != nil && tnt.Spec.PriorityClasses == nil {
+		tnt.Spec.PriorityClasses = defaults.PriorityClasses.DeepCopy()
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,115 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	defaults := &capsulev1alpha1.TenantDefaultsSpec{
+		NamespaceQuota: pointer.Int32Ptr(3),
+		ResourceQuotas: &capsulev1beta1.ResourceQuotaSpec{
+			Items: []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}},
+		},
+		NetworkPolicies: &capsulev1beta1.NetworkPolicySpec{
+			Items: []networkingv1.NetworkPolicySpec{{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}}},
+		},
+		PriorityClasses: &capsulev1beta1.AllowedListSpec{Exact: []string{"tenant"}},
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	if assert.True(t, applyDefaults(tnt, defaults)) {
+		assert.Equal(t, int32(3), *tnt.Spec.NamespaceOptions.Quota)
+		assert.Equal(t, capsulev1beta1.ResourceQuotaScopeTenant, tnt.Spec.ResourceQuota.Scope)
+		assert.Len(t, tnt.Spec.ResourceQuota.Items, 1)
+		assert.Len(t, tnt.Spec.NetworkPolicies.Items, 1)
+		assert.Equal(t, []string{"tenant"}, tnt.Spec.PriorityClasses.Exact)
+	}
+	// the defaults are copied, not shared
+	*tnt.Spec.NamespaceOptions.Quota = 5
+	assert.Equal(t, int32(3), *defaults.NamespaceQuota)
+
+	tnt = &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			NamespaceOptions: &capsulev1beta1.NamespaceOptions{Quota: pointer.Int32Ptr(1)},
+			ResourceQuota: capsulev1beta1.ResourceQuotaSpec{
+				Scope: capsulev1beta1.ResourceQuotaScopeNamespace,
+				Items: []corev1.ResourceQuotaSpec{{}},
+			},
+			NetworkPolicies: capsulev1beta1.NetworkPolicySpec{Items: []networkingv1.NetworkPolicySpec{{}, {}}},
+			PriorityClasses: &capsulev1beta1.AllowedListSpec{},
+		},
+	}
+	assert.False(t, applyDefaults(tnt, defaults))
+	assert.Equal(t, int32(1), *tnt.Spec.NamespaceOptions.Quota)
+	assert.Equal(t, capsulev1beta1.ResourceQuotaScopeNamespace, tnt.Spec.ResourceQuota.Scope)
+	assert.Len(t, tnt.Spec.NetworkPolicies.Items, 2)
+	assert.Empty(t, tnt.Spec.PriorityClasses.Exact)
+}
+
+func TestClassDefaults(t *testing.T) {
+	class := &capsulev1beta1.TenantClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gold"},
+		Spec: capsulev1beta1.TenantClassSpec{
+			NamespaceOptions: &capsulev1beta1.NamespaceOptions{Quota: pointer.Int32Ptr(10)},
+			PriorityClasses:  &capsulev1beta1.AllowedListSpec{Exact: []string{"gold"}},
+		},
+	}
+
+	tnt := &capsulev1beta1.Tenant{}
+	if assert.True(t, applyDefaults(tnt, classDefaults(class))) {
+		assert.Equal(t, int32(10), *tnt.Spec.NamespaceOptions.Quota)
+		assert.Equal(t, []string{"gold"}, tnt.Spec.PriorityClasses.Exact)
+		assert.Empty(t, tnt.Spec.ResourceQuota.Items)
+	}
+}
+
+func TestPatchResponse(t *testing.T) {
+	raw := []byte(`{"apiVersion":"capsule.clastix.io/v1beta1","kind":"Tenant","metadata":{"name":"oil"},"spec":{"owners":[{"kind":"User","name":"alice"}]}}`)
+
+	tnt := &capsulev1beta1.Tenant{}
+	assert.NoError(t, json.Unmarshal(raw, tnt))
+
+	assert.True(t, applyDefaults(tnt, &capsulev1alpha1.TenantDefaultsSpec{
+		NamespaceQuota: pointer.Int32Ptr(3),
+		ResourceQuotas: &capsulev1beta1.ResourceQuotaSpec{
+			Items: []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}},
+		},
+	}))
+
+	response := patchResponse(raw, tnt)
+	if !assert.True(t, response.Allowed) {
+		return
+	}
+	// the patch must apply to the raw object, lacking the empty fields of the decoded one
+	operations, err := json.Marshal(response.Patches)
+	assert.NoError(t, err)
+
+	patch, err := jsonpatch.DecodePatch(operations)
+	assert.NoError(t, err)
+
+	patched, err := patch.Apply(raw)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	result := &capsulev1beta1.Tenant{}
+	assert.NoError(t, json.Unmarshal(patched, result))
+	assert.Equal(t, int32(3), *result.Spec.NamespaceOptions.Quota)
+	assert.Equal(t, capsulev1beta1.ResourceQuotaScopeTenant, result.Spec.ResourceQuota.Scope)
+	assert.Len(t, result.Spec.ResourceQuota.Items, 1)
+}
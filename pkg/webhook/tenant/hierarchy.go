@@ -0,0 +1,782 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type hierarchyHandler struct {
+	configuration configuration.Configuration
+}
+
+// HierarchyHandler validates the parent of the Tenants, preventing cycles, the child Tenants from exceeding the
+// restrictions of their parent and the parent changes exceeded by its children, or the deletion of a parent.
+// The Capsule users granted the Tenant edit rights can manage the child Tenants of the Tenants they own, without
+// setting the fields reserved to the cluster administrators.
+func HierarchyHandler(configuration configuration.Configuration) capsulewebhook.Handler {
+	return &hierarchyHandler{
+		configuration: configuration,
+	}
+}
+
+func (h *hierarchyHandler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *hierarchyHandler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *hierarchyHandler) OnDelete(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		tnt := &capsulev1beta1.Tenant{}
+		if err := decoder.DecodeRaw(req.OldObject, tnt); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tenants, err := h.tenants(ctx, c)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if response := h.authorize(req, tenants, recorder, tnt); response != nil {
+			return response
+		}
+
+		if children := childrenOf(tnt.GetName(), tenants); len(children) > 0 {
+			response := admission.Denied(fmt.Sprintf("The Tenant %s is the parent of the Tenants %s: delete them first", tnt.GetName(), strings.Join(children, ", ")))
+
+			return &response
+		}
+
+		return nil
+	}
+}
+
+func (h *hierarchyHandler) tenants(ctx context.Context, c client.Client) (map[string]*capsulev1beta1.Tenant, error) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList); err != nil {
+		return nil, err
+	}
+
+	tenants := make(map[string]*capsulev1beta1.Tenant, len(tntList.Items))
+	for i := range tntList.Items {
+		tenants[tntList.Items[i].GetName()] = &tntList.Items[i]
+	}
+
+	return tenants, nil
+}
+
+// authorize denies the Capsule users creating a Tenant without a parent, or managing a child Tenant whose parent
+// they don't own: the changes to the Tenants without a parent are left to the RBAC granted by the cluster
+// administrators.
+func (h *hierarchyHandler) authorize(req admission.Request, tenants map[string]*capsulev1beta1.Tenant, recorder record.EventRecorder, tnts ...*capsulev1beta1.Tenant) *admission.Response {
+	if !utils.IsCapsuleUser(req, h.configuration.UserGroups()) || (!hasParent(tnts...) && req.Operation != admissionv1.Create) {
+		return nil
+	}
+
+	for _, tnt := range tnts {
+		parent, ok := tenants[tnt.Spec.Parent]
+		if ok && utils.IsTenantOwner(parent.Spec.Owners, req.UserInfo) {
+			continue
+		}
+
+		if ok {
+			recorder.Eventf(parent, corev1.EventTypeWarning, "NonOwnedParentTenant", "User %s cannot manage the child Tenant %s", req.UserInfo.Username, tnt.GetName())
+		}
+
+		response := admission.Denied(fmt.Sprintf("The Tenant %s can be managed only by the owners of its parent Tenant", tnt.GetName()))
+
+		return &response
+	}
+
+	return nil
+}
+
+func hasParent(tnts ...*capsulev1beta1.Tenant) bool {
+	for _, tnt := range tnts {
+		if len(tnt.Spec.Parent) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *hierarchyHandler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	tnt := &capsulev1beta1.Tenant{}
+	if err := decoder.Decode(req, tnt); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	var old *capsulev1beta1.Tenant
+
+	if len(req.OldObject.Raw) > 0 {
+		old = &capsulev1beta1.Tenant{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return utils.ErroredResponse(err)
+		}
+	}
+
+	tenants, err := h.tenants(ctx, c)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tnts := []*capsulev1beta1.Tenant{tnt}
+	if old != nil {
+		tnts = append(tnts, old)
+	}
+
+	if response := h.authorize(req, tenants, recorder, tnts...); response != nil {
+		return response
+	}
+
+	var errs field.ErrorList
+
+	if utils.IsCapsuleUser(req, h.configuration.UserGroups()) && hasParent(tnts...) {
+		for _, path := range changedReservedFields(old, tnt) {
+			errs = append(errs, field.Forbidden(path, "can be set only by the cluster administrators"))
+		}
+
+		if parent, ok := tenants[tnt.Spec.Parent]; ok {
+			errs = append(errs, foreignOwners(tnt, parent)...)
+		}
+	}
+
+	errs = append(errs, validateParent(tnt, tenants)...)
+
+	if parent, ok := tenants[tnt.Spec.Parent]; ok && len(errs) == 0 {
+		errs = append(errs, exceededRestrictions(tnt, parent)...)
+
+		siblings := []*capsulev1beta1.Tenant{tnt}
+		for _, name := range childrenOf(parent.GetName(), tenants) {
+			if name != tnt.GetName() {
+				siblings = append(siblings, tenants[name])
+			}
+		}
+
+		if len(errs) == 0 {
+			errs = append(errs, exceededAllocations(parent, siblings)...)
+		}
+	}
+
+	if old != nil {
+		var children []*capsulev1beta1.Tenant
+
+		for _, name := range childrenOf(tnt.GetName(), tenants) {
+			children = append(children, tenants[name])
+
+			for _, err := range exceededRestrictions(tenants[name], tnt) {
+				exceeded := *err
+				exceeded.Detail = fmt.Sprintf("would be exceeded by the child Tenant %s: %s", name, err.Detail)
+				errs = append(errs, &exceeded)
+			}
+		}
+
+		for _, err := range exceededAllocations(tnt, children) {
+			exceeded := *err
+			exceeded.Detail = fmt.Sprintf("would be exceeded by the child Tenants: %s", err.Detail)
+			errs = append(errs, &exceeded)
+		}
+	}
+
+	if len(errs) > 0 {
+		sortErrors(errs)
+
+		return utils.InvalidResponse(capsulev1beta1.GroupVersion.WithKind("Tenant").GroupKind(), tnt.GetName(), errs)
+	}
+
+	return nil
+}
+
+// childrenOf returns the names of the Tenants having the given parent.
+func childrenOf(parent string, tenants map[string]*capsulev1beta1.Tenant) (children []string) {
+	for name, tnt := range tenants {
+		if tnt.Spec.Parent == parent {
+			children = append(children, name)
+		}
+	}
+
+	sort.Strings(children)
+
+	return children
+}
+
+// validateParent checks the parent of the Tenant exists and doesn't descend from the Tenant itself.
+func validateParent(tnt *capsulev1beta1.Tenant, tenants map[string]*capsulev1beta1.Tenant) (errs field.ErrorList) {
+	if len(tnt.Spec.Parent) == 0 {
+		return nil
+	}
+
+	path := field.NewPath("spec", "parent")
+
+	for name, depth := tnt.Spec.Parent, 0; len(name) > 0 && depth <= len(tenants); depth++ {
+		if name == tnt.GetName() {
+			return append(errs, field.Invalid(path, tnt.Spec.Parent, "the Tenant cannot descend from itself"))
+		}
+
+		parent, ok := tenants[name]
+		if !ok {
+			return append(errs, field.NotFound(path, name))
+		}
+
+		name = parent.Spec.Parent
+	}
+
+	return nil
+}
+
+// unrelated filters out the ancestors and the descendants of the Tenant, allowed to share its hostnames since the
+// child Tenants are delegated a subset of the parent ones.
+func unrelated(tnt *capsulev1beta1.Tenant, others []capsulev1beta1.Tenant) (filtered []capsulev1beta1.Tenant) {
+	parents := make(map[string]string, len(others)+1)
+	parents[tnt.GetName()] = tnt.Spec.Parent
+
+	for _, other := range others {
+		parents[other.GetName()] = other.Spec.Parent
+	}
+
+	descends := func(name, ancestor string) bool {
+		for depth := 0; len(name) > 0 && depth <= len(parents); depth++ {
+			if name = parents[name]; name == ancestor {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, other := range others {
+		if !descends(tnt.GetName(), other.GetName()) && !descends(other.GetName(), tnt.GetName()) {
+			filtered = append(filtered, other)
+		}
+	}
+
+	return filtered
+}
+
+// inherit fills the restrictions left empty by the child Tenant with a copy of the parent ones, reporting if any has
+// been set: the node selector labels are merged, since the child can narrow its nodes down.
+func inherit(child, parent *capsulev1beta1.Tenant) (changed bool) {
+	if quota := namespaceQuota(parent); quota != nil && namespaceQuota(child) == nil {
+		if child.Spec.NamespaceOptions == nil {
+			child.Spec.NamespaceOptions = &capsulev1beta1.NamespaceOptions{}
+		}
+
+		q := *quota
+		child.Spec.NamespaceOptions.Quota = &q
+		changed = true
+	}
+
+	for key, value := range parent.Spec.NodeSelector {
+		if _, ok := child.Spec.NodeSelector[key]; !ok {
+			if child.Spec.NodeSelector == nil {
+				child.Spec.NodeSelector = map[string]string{}
+			}
+
+			child.Spec.NodeSelector[key] = value
+			changed = true
+		}
+	}
+
+	for _, list := range []struct {
+		child  **capsulev1beta1.AllowedListSpec
+		parent *capsulev1beta1.AllowedListSpec
+	}{
+		{&child.Spec.ContainerRegistries, parent.Spec.ContainerRegistries},
+		{&child.Spec.IngressOptions.AllowedClasses, parent.Spec.IngressOptions.AllowedClasses},
+		{&child.Spec.IngressOptions.AllowedHostnames, parent.Spec.IngressOptions.AllowedHostnames},
+		{&child.Spec.PriorityClasses, parent.Spec.PriorityClasses},
+		{&child.Spec.StorageClasses, parent.Spec.StorageClasses},
+	} {
+		if list.parent != nil && *list.child == nil {
+			*list.child = list.parent.DeepCopy()
+			changed = true
+		}
+	}
+
+	if len(parent.Spec.ImagePullPolicies) > 0 && len(child.Spec.ImagePullPolicies) == 0 {
+		child.Spec.ImagePullPolicies = append([]capsulev1beta1.ImagePullPolicySpec{}, parent.Spec.ImagePullPolicies...)
+		changed = true
+	}
+
+	if len(parent.Spec.ResourceQuota.Items) > 0 && len(child.Spec.ResourceQuota.Items) == 0 {
+		child.Spec.ResourceQuota = *parent.Spec.ResourceQuota.DeepCopy()
+		changed = true
+	}
+
+	if len(parent.Spec.NetworkPolicies.Items) > 0 && len(child.Spec.NetworkPolicies.Items) == 0 {
+		child.Spec.NetworkPolicies = *parent.Spec.NetworkPolicies.DeepCopy()
+		changed = true
+	}
+
+	if len(parent.Spec.LimitRanges.Items) > 0 && len(child.Spec.LimitRanges.Items) == 0 {
+		child.Spec.LimitRanges = *parent.Spec.LimitRanges.DeepCopy()
+		changed = true
+	}
+
+	if parent.Spec.ServiceOptions != nil && child.Spec.ServiceOptions == nil {
+		child.Spec.ServiceOptions = parent.Spec.ServiceOptions.DeepCopy()
+		changed = true
+	}
+
+	if parent.Spec.PodOptions != nil && child.Spec.PodOptions == nil {
+		child.Spec.PodOptions = parent.Spec.PodOptions.DeepCopy()
+		changed = true
+	}
+
+	if parent.Spec.PersistentVolumeOptions != nil && child.Spec.PersistentVolumeOptions == nil {
+		child.Spec.PersistentVolumeOptions = parent.Spec.PersistentVolumeOptions.DeepCopy()
+		changed = true
+	}
+
+	return changed
+}
+
+// changedReservedFields returns the paths of the fields reserved to the cluster administrators changed by the request,
+// since granting cluster-wide capabilities or bypassing the Tenant isolation.
+func changedReservedFields(old, tnt *capsulev1beta1.Tenant) (paths []*field.Path) {
+	if old == nil {
+		old = &capsulev1beta1.Tenant{}
+	}
+
+	spec := field.NewPath("spec")
+
+	reserved := map[string][2]interface{}{
+		"accessBundle":              {old.Spec.AccessBundle, tnt.Spec.AccessBundle},
+		"additionalRoleBindings":    {old.Spec.AdditionalRoleBindings, tnt.Spec.AdditionalRoleBindings},
+		"apiPriorityAndFairness":    {old.Spec.APIPriorityAndFairness, tnt.Spec.APIPriorityAndFairness},
+		"backup":                    {old.Spec.Backup, tnt.Spec.Backup},
+		"batchQueueing":             {old.Spec.BatchQueueing, tnt.Spec.BatchQueueing},
+		"customResourceDefinitions": {old.Spec.CustomResourceDefinitions, tnt.Spec.CustomResourceDefinitions},
+		"externalSecrets":           {old.Spec.ExternalSecrets, tnt.Spec.ExternalSecrets},
+		"kyvernoPolicies":           {old.Spec.KyvernoPolicies, tnt.Spec.KyvernoPolicies},
+		"namespaces":                {old.Spec.Namespaces, tnt.Spec.Namespaces},
+		"webhookConfigurations":     {old.Spec.WebhookConfigurations, tnt.Spec.WebhookConfigurations},
+	}
+
+	for _, name := range []string{"accessBundle", "additionalRoleBindings", "apiPriorityAndFairness", "backup", "batchQueueing", "customResourceDefinitions", "externalSecrets", "kyvernoPolicies", "namespaces", "webhookConfigurations"} {
+		if values := reserved[name]; !equality.Semantic.DeepDerivative(values[0], values[1]) || !equality.Semantic.DeepDerivative(values[1], values[0]) {
+			paths = append(paths, spec.Child(name))
+		}
+	}
+	// the proxy settings grant the owners cluster-wide capabilities through capsule-proxy
+	for i, owner := range tnt.Spec.Owners {
+		var settings []capsulev1beta1.ProxySettings
+		if previous, ok := findOwner(old.Spec.Owners, owner); ok {
+			settings = previous.ProxyOperations
+		}
+
+		if !equality.Semantic.DeepDerivative(settings, owner.ProxyOperations) || !equality.Semantic.DeepDerivative(owner.ProxyOperations, settings) {
+			paths = append(paths, spec.Child("owners").Index(i).Child("proxySettings"))
+		}
+	}
+
+	return paths
+}
+
+// foreignOwners returns the errors reporting the owners of the child Tenant not owning its parent: the Capsule users
+// cannot hand a child Tenant, along with the credentials issued for its owners, to other identities.
+func foreignOwners(child, parent *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	for i, owner := range child.Spec.Owners {
+		if _, ok := findOwner(parent.Spec.Owners, owner); !ok {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "owners").Index(i), fmt.Sprintf("the %s %s is not an owner of the parent Tenant", owner.Kind, owner.Name)))
+		}
+	}
+
+	return errs
+}
+
+// findOwner returns the owner among the given ones with the same kind and name, without sorting them as
+// OwnerListSpec.FindOwner does.
+func findOwner(owners capsulev1beta1.OwnerListSpec, owner capsulev1beta1.OwnerSpec) (capsulev1beta1.OwnerSpec, bool) {
+	for _, o := range owners {
+		if o.Kind == owner.Kind && o.Name == owner.Name {
+			return o, true
+		}
+	}
+
+	return capsulev1beta1.OwnerSpec{}, false
+}
+
+// exceededRestrictions returns the errors reporting the restrictions of the parent the child Tenant exceeds, or
+// doesn't declare at all: the namespace quota, the node selector, the allowed lists, the Tenant ResourceQuotas,
+// the Service and Pod options, the maximum storage request, and the NetworkPolicies and LimitRanges the child must
+// include.
+func exceededRestrictions(child, parent *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	spec := field.NewPath("spec")
+
+	if quota := namespaceQuota(parent); quota != nil {
+		if childQuota := namespaceQuota(child); childQuota == nil || *childQuota > *quota {
+			errs = append(errs, field.Invalid(spec.Child("namespaceOptions", "quota"), childQuota, fmt.Sprintf("cannot exceed the quota %d of the parent Tenant", *quota)))
+		}
+	}
+
+	for key, value := range parent.Spec.NodeSelector {
+		if child.Spec.NodeSelector[key] != value {
+			errs = append(errs, field.Invalid(spec.Child("nodeSelector"), child.Spec.NodeSelector, fmt.Sprintf("must include the label %s=%s of the parent Tenant", key, value)))
+
+			break
+		}
+	}
+
+	allowedLists := []struct {
+		path          *field.Path
+		child, parent *capsulev1beta1.AllowedListSpec
+	}{
+		{spec.Child("containerRegistries"), child.Spec.ContainerRegistries, parent.Spec.ContainerRegistries},
+		{spec.Child("ingressOptions", "allowedClasses"), child.Spec.IngressOptions.AllowedClasses, parent.Spec.IngressOptions.AllowedClasses},
+		{spec.Child("ingressOptions", "allowedHostnames"), child.Spec.IngressOptions.AllowedHostnames, parent.Spec.IngressOptions.AllowedHostnames},
+		{spec.Child("priorityClasses"), child.Spec.PriorityClasses, parent.Spec.PriorityClasses},
+		{spec.Child("storageClasses"), child.Spec.StorageClasses, parent.Spec.StorageClasses},
+	}
+
+	for _, list := range allowedLists {
+		errs = append(errs, exceededAllowedList(list.path, list.child, list.parent)...)
+	}
+
+	if len(parent.Spec.ImagePullPolicies) > 0 {
+		for i, policy := range child.Spec.ImagePullPolicies {
+			if !containsImagePullPolicy(parent.Spec.ImagePullPolicies, policy) {
+				errs = append(errs, field.NotSupported(spec.Child("imagePullPolicies").Index(i), policy, imagePullPolicies(parent.Spec.ImagePullPolicies)))
+			}
+		}
+
+		if len(child.Spec.ImagePullPolicies) == 0 {
+			errs = append(errs, field.Required(spec.Child("imagePullPolicies"), "the parent Tenant restricts the image pull policies"))
+		}
+	}
+
+	errs = append(errs, exceededResourceQuotas(spec.Child("resourceQuotas"), child.Spec.ResourceQuota, parent.Spec.ResourceQuota)...)
+	errs = append(errs, exceededServiceOptions(spec.Child("serviceOptions"), child.Spec.ServiceOptions, parent.Spec.ServiceOptions)...)
+
+	if options := parent.Spec.PodOptions; options != nil {
+		childOptions := child.Spec.PodOptions
+		if childOptions == nil {
+			childOptions = &capsulev1beta1.PodOptions{}
+		}
+
+		for name, required := range map[string][2]bool{
+			"requireEphemeralStorageLimits": {options.RequireEphemeralStorageLimits, childOptions.RequireEphemeralStorageLimits},
+			"forbidEphemeralContainers":     {options.ForbidEphemeralContainers, childOptions.ForbidEphemeralContainers},
+			"warnOverCapacity":              {options.WarnOverCapacity, childOptions.WarnOverCapacity},
+		} {
+			if required[0] && !required[1] {
+				errs = append(errs, field.Invalid(spec.Child("podOptions", name), false, "is required by the parent Tenant"))
+			}
+		}
+	}
+
+	if options := parent.Spec.PersistentVolumeOptions; options != nil && options.MaxStorageRequest != nil {
+		var childMax *resource.Quantity
+		if child.Spec.PersistentVolumeOptions != nil {
+			childMax = child.Spec.PersistentVolumeOptions.MaxStorageRequest
+		}
+
+		if childMax == nil || childMax.Cmp(*options.MaxStorageRequest) > 0 {
+			errs = append(errs, field.Invalid(spec.Child("persistentVolumeOptions", "maxStorageRequest"), childMax, fmt.Sprintf("cannot exceed the maximum storage request %s of the parent Tenant", options.MaxStorageRequest.String())))
+		}
+	}
+
+	for _, item := range parent.Spec.NetworkPolicies.Items {
+		if !containsItem(child.Spec.NetworkPolicies.Items, item) {
+			errs = append(errs, field.Required(spec.Child("networkPolicies", "items"), "must include the NetworkPolicies of the parent Tenant"))
+
+			break
+		}
+	}
+
+	for _, item := range parent.Spec.LimitRanges.Items {
+		if !containsItem(child.Spec.LimitRanges.Items, item) {
+			errs = append(errs, field.Required(spec.Child("limitRanges", "items"), "must include the LimitRanges of the parent Tenant"))
+
+			break
+		}
+	}
+
+	sortErrors(errs)
+
+	return errs
+}
+
+// exceededAllocations returns the errors reporting the restrictions of the parent exceeded by all its children
+// together, since each child is allocated a share of them: the namespace quota, along with the Namespaces of the
+// parent itself, and the total hard quotas of the Tenant ResourceQuotas.
+func exceededAllocations(parent *capsulev1beta1.Tenant, children []*capsulev1beta1.Tenant) (errs field.ErrorList) {
+	spec := field.NewPath("spec")
+
+	if quota := namespaceQuota(parent); quota != nil {
+		allocated := int64(len(parent.Status.Namespaces))
+
+		for _, child := range children {
+			if childQuota := namespaceQuota(child); childQuota != nil {
+				allocated += int64(*childQuota)
+			}
+		}
+
+		if allocated > int64(*quota) {
+			errs = append(errs, field.Invalid(spec.Child("namespaceOptions", "quota"), allocated, fmt.Sprintf("the quotas of the child Tenants along with the %d Namespaces of the parent Tenant cannot exceed its quota %d", len(parent.Status.Namespaces), *quota)))
+		}
+	}
+
+	if len(parent.Spec.ResourceQuota.Items) == 0 {
+		return errs
+	}
+
+	allocated := corev1.ResourceList{}
+
+	for _, child := range children {
+		for name, quantity := range totalHard(child.Spec.ResourceQuota) {
+			sum := allocated[name]
+			sum.Add(quantity)
+			allocated[name] = sum
+		}
+	}
+
+	parentHard := totalHard(parent.Spec.ResourceQuota)
+
+	for _, name := range sortedResourceNames(parentHard) {
+		limit := parentHard[name]
+
+		if quantity, ok := allocated[name]; ok && quantity.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(spec.Child("resourceQuotas", "items"), name, fmt.Sprintf("the total hard quotas of the child Tenants cannot exceed the %s one of the parent Tenant", limit.String())))
+		}
+	}
+
+	return errs
+}
+
+func namespaceQuota(tnt *capsulev1beta1.Tenant) *int32 {
+	if tnt.Spec.NamespaceOptions == nil {
+		return nil
+	}
+
+	return tnt.Spec.NamespaceOptions.Quota
+}
+
+// exceededAllowedList checks the child list allows a subset of the parent one: the exact values must be allowed
+// by the parent, while the regular expression, whose matches can't be compared, must be the parent one.
+func exceededAllowedList(path *field.Path, child, parent *capsulev1beta1.AllowedListSpec) (errs field.ErrorList) {
+	if parent == nil {
+		return nil
+	}
+
+	if child == nil {
+		return append(errs, field.Required(path, "the parent Tenant restricts the allowed values"))
+	}
+
+	parentList := parent.DeepCopy()
+
+	for i, value := range child.Exact {
+		if !parentList.ExactMatch(value) && !parentList.RegexMatch(value) {
+			errs = append(errs, field.Invalid(path.Child("allowed").Index(i), value, "is not allowed by the parent Tenant"))
+		}
+	}
+
+	if len(child.Regex) > 0 && child.Regex != parent.Regex {
+		errs = append(errs, field.Invalid(path.Child("allowedRegex"), child.Regex, "must be the regular expression of the parent Tenant, if any"))
+	}
+
+	return errs
+}
+
+// exceededResourceQuotas checks the child declares the same scope and, for each resource limited by the parent,
+// a total hard quota not greater than the parent one.
+func exceededResourceQuotas(path *field.Path, child, parent capsulev1beta1.ResourceQuotaSpec) (errs field.ErrorList) {
+	if len(parent.Items) == 0 {
+		return nil
+	}
+
+	if child.Scope != parent.Scope {
+		return append(errs, field.Invalid(path.Child("scope"), child.Scope, fmt.Sprintf("must be the scope %s of the parent Tenant", parent.Scope)))
+	}
+
+	childHard, parentHard := totalHard(child), totalHard(parent)
+
+	for _, name := range sortedResourceNames(parentHard) {
+		limit := parentHard[name]
+
+		if quantity, ok := childHard[name]; !ok || quantity.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("items"), name, fmt.Sprintf("the total hard quota cannot exceed the %s one of the parent Tenant", limit.String())))
+		}
+	}
+
+	return errs
+}
+
+func totalHard(spec capsulev1beta1.ResourceQuotaSpec) corev1.ResourceList {
+	total := corev1.ResourceList{}
+
+	for _, item := range spec.Items {
+		for name, quantity := range item.Hard {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+
+	return total
+}
+
+// exceededServiceOptions checks the child forbids the Service types forbidden by the parent, requires its traffic
+// policies, doesn't exceed its limits, and allows a subset of its cluster and external IPs.
+func exceededServiceOptions(path *field.Path, child, parent *capsulev1beta1.ServiceOptions) (errs field.ErrorList) {
+	if parent == nil {
+		return nil
+	}
+
+	if child == nil {
+		child = &capsulev1beta1.ServiceOptions{}
+	}
+
+	if allowed := parent.AllowedServices; allowed != nil {
+		childAllowed := child.AllowedServices
+		if childAllowed == nil {
+			childAllowed = &capsulev1beta1.AllowedServices{}
+		}
+
+		for name, types := range map[string][2]*bool{
+			"nodePort":     {allowed.NodePort, childAllowed.NodePort},
+			"externalName": {allowed.ExternalName, childAllowed.ExternalName},
+			"loadBalancer": {allowed.LoadBalancer, childAllowed.LoadBalancer},
+		} {
+			if types[0] != nil && !*types[0] && (types[1] == nil || *types[1]) {
+				errs = append(errs, field.Invalid(path.Child("allowedServices", name), types[1], "is forbidden by the parent Tenant"))
+			}
+		}
+	}
+
+	if policies := parent.TrafficPolicies; policies != nil && !reflect.DeepEqual(policies, child.TrafficPolicies) {
+		errs = append(errs, field.Invalid(path.Child("trafficPolicies"), child.TrafficPolicies, "must be the traffic policies of the parent Tenant"))
+	}
+
+	if limits := parent.Limits; limits != nil {
+		childLimits := child.Limits
+		if childLimits == nil {
+			childLimits = &capsulev1beta1.ServiceLimitsSpec{}
+		}
+
+		for name, limit := range map[string][2]*int32{
+			"loadBalancers": {limits.LoadBalancers, childLimits.LoadBalancers},
+			"nodePorts":     {limits.NodePorts, childLimits.NodePorts},
+		} {
+			if limit[0] != nil && (limit[1] == nil || *limit[1] > *limit[0]) {
+				errs = append(errs, field.Invalid(path.Child("limits", name), limit[1], fmt.Sprintf("cannot exceed the limit %d of the parent Tenant", *limit[0])))
+			}
+		}
+	}
+
+	if ips := parent.ClusterIPs; ips != nil {
+		if child.ClusterIPs == nil {
+			errs = append(errs, field.Required(path.Child("clusterIPs"), "the parent Tenant restricts the cluster IPs"))
+		} else {
+			for i, ip := range child.ClusterIPs.Allowed {
+				if !cidrWithin(ip, ips.Allowed) {
+					errs = append(errs, field.Invalid(path.Child("clusterIPs", "allowed").Index(i), ip, "is not allowed by the parent Tenant"))
+				}
+			}
+		}
+	}
+
+	if ips := parent.ExternalServiceIPs; ips != nil {
+		if child.ExternalServiceIPs == nil {
+			return append(errs, field.Required(path.Child("externalIPs"), "the parent Tenant restricts the external IPs"))
+		}
+
+		for i, ip := range child.ExternalServiceIPs.Allowed {
+			if !cidrWithin(ip, ips.Allowed) {
+				errs = append(errs, field.Invalid(path.Child("externalIPs", "allowed").Index(i), ip, "is not allowed by the parent Tenant"))
+			}
+		}
+	}
+
+	return errs
+}
+
+// cidrWithin returns true when the given address or CIDR is included in any of the allowed ones.
+func cidrWithin(ip capsulev1beta1.AllowedIP, allowed []capsulev1beta1.AllowedIP) bool {
+	parse := func(ip capsulev1beta1.AllowedIP) *net.IPNet {
+		cidr := string(ip)
+		if !strings.Contains(cidr, "/") {
+			cidr += "/32"
+		}
+
+		_, network, _ := net.ParseCIDR(cidr)
+
+		return network
+	}
+
+	network := parse(ip)
+	if network == nil {
+		return false
+	}
+
+	size, _ := network.Mask.Size()
+
+	for _, a := range allowed {
+		if allowedNetwork := parse(a); allowedNetwork != nil {
+			if allowedSize, _ := allowedNetwork.Mask.Size(); allowedSize <= size && allowedNetwork.Contains(network.IP) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsImagePullPolicy(policies []capsulev1beta1.ImagePullPolicySpec, policy capsulev1beta1.ImagePullPolicySpec) bool {
+	for _, p := range policies {
+		if p == policy {
+			return true
+		}
+	}
+
+	return false
+}
+
+func imagePullPolicies(policies []capsulev1beta1.ImagePullPolicySpec) []string {
+	values := make([]string, 0, len(policies))
+	for _, p := range policies {
+		values = append(values, p.String())
+	}
+
+	return values
+}
+
+func containsItem(items interface{}, item interface{}) bool {
+	list := reflect.ValueOf(items)
+
+	for i := 0; i < list.Len(); i++ {
+		if equality.Semantic.DeepEqual(list.Index(i).Interface(), item) {
+			return true
+		}
+	}
+
+	return false
+}
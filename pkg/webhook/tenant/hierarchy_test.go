@@ -0,0 +1,217 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func newTenant(name, parent string) *capsulev1beta1.Tenant {
+	return &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       capsulev1beta1.TenantSpec{Parent: parent},
+	}
+}
+
+func TestValidateParent(t *testing.T) {
+	tenants := map[string]*capsulev1beta1.Tenant{
+		"oil":     newTenant("oil", ""),
+		"oil-dev": newTenant("oil-dev", "oil"),
+		"oil-qa":  newTenant("oil-qa", "oil-dev"),
+	}
+
+	assert.Empty(t, validateParent(newTenant("gas", ""), tenants))
+	assert.Empty(t, validateParent(newTenant("oil-ci", "oil-dev"), tenants))
+
+	errs := validateParent(newTenant("oil-ci", "water"), tenants)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "spec.parent", errs[0].Field)
+		assert.Contains(t, errs[0].Error(), "Not found")
+	}
+
+	assert.Len(t, validateParent(newTenant("oil", "oil"), tenants), 1)
+	// the root Tenant cannot be moved below its descendants
+	assert.Len(t, validateParent(newTenant("oil", "oil-qa"), tenants), 1)
+}
+
+func TestExceededRestrictions(t *testing.T) {
+	parent := newTenant("oil", "")
+	parent.Spec.NamespaceOptions = &capsulev1beta1.NamespaceOptions{Quota: pointer.Int32Ptr(5)}
+	parent.Spec.NodeSelector = map[string]string{"pool": "oil"}
+	parent.Spec.StorageClasses = &capsulev1beta1.AllowedListSpec{Exact: []string{"ceph-rbd"}, Regex: "^ceph-.*$"}
+	parent.Spec.ImagePullPolicies = []capsulev1beta1.ImagePullPolicySpec{"Always"}
+	parent.Spec.ResourceQuota = capsulev1beta1.ResourceQuotaSpec{
+		Scope: capsulev1beta1.ResourceQuotaScopeTenant,
+		Items: []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}},
+	}
+	parent.Spec.ServiceOptions = &capsulev1beta1.ServiceOptions{
+		AllowedServices:    &capsulev1beta1.AllowedServices{LoadBalancer: pointer.BoolPtr(false)},
+		ExternalServiceIPs: &capsulev1beta1.ExternalServiceIPsSpec{Allowed: []capsulev1beta1.AllowedIP{"10.0.0.0/24"}},
+		ClusterIPs:         &capsulev1beta1.ClusterIPsSpec{Allowed: []capsulev1beta1.AllowedIP{"10.96.0.0/24"}},
+		Limits:             &capsulev1beta1.ServiceLimitsSpec{LoadBalancers: pointer.Int32Ptr(2), NodePorts: pointer.Int32Ptr(10)},
+	}
+	parent.Spec.PodOptions = &capsulev1beta1.PodOptions{WarnOverCapacity: true}
+	parent.Spec.PersistentVolumeOptions = &capsulev1beta1.PersistentVolumeOptions{MaxStorageRequest: resource.NewQuantity(10<<30, resource.BinarySI)}
+
+	child := newTenant("oil-dev", "oil")
+	assert.True(t, inherit(child, parent))
+	assert.Empty(t, exceededRestrictions(child, parent))
+
+	child.Spec.NamespaceOptions.Quota = pointer.Int32Ptr(3)
+	child.Spec.NodeSelector["zone"] = "a"
+	child.Spec.StorageClasses = &capsulev1beta1.AllowedListSpec{Exact: []string{"ceph-nfs"}}
+	child.Spec.ResourceQuota.Items = append(child.Spec.ResourceQuota.Items, corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("-5")}})
+	child.Spec.ServiceOptions.ExternalServiceIPs.Allowed = []capsulev1beta1.AllowedIP{"10.0.0.8/29", "10.0.0.1"}
+	child.Spec.PersistentVolumeOptions.MaxStorageRequest = resource.NewQuantity(1<<30, resource.BinarySI)
+	assert.Empty(t, exceededRestrictions(child, parent))
+
+	child.Spec.NamespaceOptions.Quota = pointer.Int32Ptr(6)
+	child.Spec.NodeSelector = map[string]string{"zone": "a"}
+	child.Spec.StorageClasses = &capsulev1beta1.AllowedListSpec{Exact: []string{"local-path"}, Regex: ".*"}
+	child.Spec.ImagePullPolicies = []capsulev1beta1.ImagePullPolicySpec{"Always", "IfNotPresent"}
+	child.Spec.ResourceQuota.Items[1].Hard[corev1.ResourcePods] = resource.MustParse("5")
+	child.Spec.ServiceOptions.AllowedServices = nil
+	child.Spec.ServiceOptions.ExternalServiceIPs.Allowed = []capsulev1beta1.AllowedIP{"10.0.0.0/16"}
+	child.Spec.ServiceOptions.ClusterIPs.Allowed = []capsulev1beta1.AllowedIP{"10.96.1.10"}
+	child.Spec.ServiceOptions.Limits = &capsulev1beta1.ServiceLimitsSpec{LoadBalancers: pointer.Int32Ptr(1), NodePorts: pointer.Int32Ptr(20)}
+	child.Spec.PodOptions = nil
+	child.Spec.PersistentVolumeOptions.MaxStorageRequest = resource.NewQuantity(20<<30, resource.BinarySI)
+
+	var fields []string
+	for _, err := range exceededRestrictions(child, parent) {
+		fields = append(fields, err.Field)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"spec.imagePullPolicies[1]",
+		"spec.namespaceOptions.quota",
+		"spec.nodeSelector",
+		"spec.persistentVolumeOptions.maxStorageRequest",
+		"spec.podOptions.warnOverCapacity",
+		"spec.resourceQuotas.items",
+		"spec.serviceOptions.allowedServices.loadBalancer",
+		"spec.serviceOptions.clusterIPs.allowed[0]",
+		"spec.serviceOptions.externalIPs.allowed[0]",
+		"spec.serviceOptions.limits.nodePorts",
+		"spec.storageClasses.allowed[0]",
+		"spec.storageClasses.allowedRegex",
+	}, fields)
+}
+
+func TestExceededAllocations(t *testing.T) {
+	parent := newTenant("oil", "")
+	parent.Spec.NamespaceOptions = &capsulev1beta1.NamespaceOptions{Quota: pointer.Int32Ptr(5)}
+	parent.Spec.ResourceQuota = capsulev1beta1.ResourceQuotaSpec{
+		Scope: capsulev1beta1.ResourceQuotaScopeTenant,
+		Items: []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}},
+	}
+	parent.Status.Namespaces = []string{"oil-production"}
+
+	sibling := func(name string, quota int32, pods string) *capsulev1beta1.Tenant {
+		child := newTenant(name, "oil")
+		child.Spec.NamespaceOptions = &capsulev1beta1.NamespaceOptions{Quota: pointer.Int32Ptr(quota)}
+		child.Spec.ResourceQuota = capsulev1beta1.ResourceQuotaSpec{
+			Scope: capsulev1beta1.ResourceQuotaScopeTenant,
+			Items: []corev1.ResourceQuotaSpec{{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(pods)}}},
+		}
+
+		return child
+	}
+
+	dev, qa := sibling("oil-dev", 2, "5"), sibling("oil-qa", 2, "5")
+	// each sibling is within the parent restrictions, as their sum along with the parent Namespaces
+	assert.Empty(t, exceededRestrictions(dev, parent))
+	assert.Empty(t, exceededAllocations(parent, []*capsulev1beta1.Tenant{dev, qa}))
+
+	ci := sibling("oil-ci", 1, "1")
+	assert.Empty(t, exceededRestrictions(ci, parent))
+
+	var fields []string
+	for _, err := range exceededAllocations(parent, []*capsulev1beta1.Tenant{dev, qa, ci}) {
+		fields = append(fields, err.Field)
+	}
+
+	assert.ElementsMatch(t, []string{"spec.namespaceOptions.quota", "spec.resourceQuotas.items"}, fields)
+	// the Namespaces of the parent itself are accounted
+	parent.Status.Namespaces = append(parent.Status.Namespaces, "oil-staging")
+	assert.Len(t, exceededAllocations(parent, []*capsulev1beta1.Tenant{dev, qa}), 1)
+}
+
+func TestChangedReservedFields(t *testing.T) {
+	old := newTenant("oil-dev", "oil")
+	old.Spec.Owners = capsulev1beta1.OwnerListSpec{{Kind: "User", Name: "alice"}}
+
+	tnt := old.DeepCopy()
+	tnt.Spec.Owners = append(tnt.Spec.Owners, capsulev1beta1.OwnerSpec{Kind: "Group", Name: "oil-developers"})
+	assert.Empty(t, changedReservedFields(old, tnt))
+
+	tnt.Spec.AccessBundle = &capsulev1beta1.AccessBundleSpec{HomeNamespace: "oil-dev-home"}
+	tnt.Spec.BatchQueueing = &capsulev1beta1.BatchQueueingSpec{Cohort: "gas"}
+	tnt.Spec.Owners[1].ProxyOperations = []capsulev1beta1.ProxySettings{{Kind: "Nodes", Operations: []capsulev1beta1.ProxyOperation{"List"}}}
+
+	var fields []string
+	for _, path := range changedReservedFields(old, tnt) {
+		fields = append(fields, path.String())
+	}
+
+	assert.Equal(t, []string{"spec.accessBundle", "spec.batchQueueing", "spec.owners[1].proxySettings"}, fields)
+	assert.Len(t, changedReservedFields(nil, tnt), 3)
+}
+
+func TestForeignOwners(t *testing.T) {
+	parent := newTenant("oil", "")
+	parent.Spec.Owners = capsulev1beta1.OwnerListSpec{{Kind: "User", Name: "alice"}, {Kind: "Group", Name: "oil-developers"}}
+
+	child := newTenant("oil-dev", "oil")
+	child.Spec.Owners = capsulev1beta1.OwnerListSpec{{Kind: "Group", Name: "oil-developers"}}
+	assert.Empty(t, foreignOwners(child, parent))
+
+	child.Spec.Owners = append(child.Spec.Owners, capsulev1beta1.OwnerSpec{Kind: "User", Name: "oil-developers"}, capsulev1beta1.OwnerSpec{Kind: "ServiceAccount", Name: "system:serviceaccount:kube-system:default"})
+
+	errs := foreignOwners(child, parent)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "spec.owners[1]", errs[0].Field)
+		assert.Equal(t, "spec.owners[2]", errs[1].Field)
+	}
+}
+
+func TestInherit(t *testing.T) {
+	parent := newTenant("oil", "")
+	parent.Spec.NodeSelector = map[string]string{"pool": "oil"}
+	parent.Spec.PriorityClasses = &capsulev1beta1.AllowedListSpec{Exact: []string{"tenant"}}
+
+	child := newTenant("oil-dev", "oil")
+	child.Spec.NodeSelector = map[string]string{"pool": "oil-dev"}
+	child.Spec.PriorityClasses = &capsulev1beta1.AllowedListSpec{}
+
+	assert.False(t, inherit(child, parent))
+	assert.Equal(t, map[string]string{"pool": "oil-dev"}, child.Spec.NodeSelector)
+
+	child.Spec.PriorityClasses = nil
+	if assert.True(t, inherit(child, parent)) {
+		assert.Equal(t, []string{"tenant"}, child.Spec.PriorityClasses.Exact)
+	}
+	// the restrictions are copied, not shared
+	child.Spec.PriorityClasses.Exact[0] = "system"
+	assert.Equal(t, []string{"tenant"}, parent.Spec.PriorityClasses.Exact)
+}
+
+func TestUnrelated(t *testing.T) {
+	others := []capsulev1beta1.Tenant{*newTenant("oil", ""), *newTenant("oil-qa", "oil-dev"), *newTenant("gas", ""), *newTenant("gas-dev", "gas")}
+
+	var names []string
+	for _, tnt := range unrelated(newTenant("oil-dev", "oil"), others) {
+		names = append(names, tnt.GetName())
+	}
+
+	assert.Equal(t, []string{"gas", "gas-dev"}, names)
+}
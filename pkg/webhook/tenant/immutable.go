@@ -0,0 +1,103 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type immutableFieldsHandler struct {
+	configuration configuration.Configuration
+}
+
+// ImmutableFieldsHandler prevents the Capsule users granted the Tenant edit rights from changing the Tenant name
+// prefix enforcement and node pool assignment, self-escalating: the cluster administrators can change them only
+// with the override annotation.
+func ImmutableFieldsHandler(configuration configuration.Configuration) capsulewebhook.Handler {
+	return &immutableFieldsHandler{
+		configuration: configuration,
+	}
+}
+
+func (h *immutableFieldsHandler) OnCreate(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *immutableFieldsHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *immutableFieldsHandler) OnUpdate(_ client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		oldTnt := &capsulev1beta1.Tenant{}
+		if err := decoder.DecodeRaw(req.OldObject, oldTnt); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tnt := &capsulev1beta1.Tenant{}
+		if err := decoder.Decode(req, tnt); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		fields := changedImmutableFields(oldTnt, tnt)
+		if len(fields) == 0 {
+			return nil
+		}
+
+		var detail string
+
+		switch {
+		case utils.IsCapsuleUser(req, h.configuration.UserGroups()):
+			detail = "can be changed only by the cluster administrators"
+		case tnt.GetAnnotations()[capsulev1beta1.OverrideImmutableFieldsAnnotation] != "true":
+			detail = fmt.Sprintf("can be changed only with the %s annotation set to true", capsulev1beta1.OverrideImmutableFieldsAnnotation)
+		default:
+			return nil
+		}
+
+		errs := make(field.ErrorList, 0, len(fields))
+
+		for _, path := range fields {
+			errs = append(errs, field.Forbidden(path, detail))
+		}
+
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "ImmutableTenantField", "User %s cannot change the immutable fields of the Tenant", req.UserInfo.Username)
+
+		return utils.InvalidResponse(capsulev1beta1.GroupVersion.WithKind("Tenant").GroupKind(), tnt.GetName(), errs)
+	}
+}
+
+// changedImmutableFields returns the paths of the immutable Tenant fields changed by the update.
+func changedImmutableFields(oldTnt, tnt *capsulev1beta1.Tenant) (paths []*field.Path) {
+	spec := field.NewPath("spec")
+
+	if !reflect.DeepEqual(oldTnt.Spec.ForceTenantPrefix, tnt.Spec.ForceTenantPrefix) {
+		paths = append(paths, spec.Child("forceTenantPrefix"))
+	}
+
+	if len(oldTnt.Spec.NodeSelector) > 0 || len(tnt.Spec.NodeSelector) > 0 {
+		if !reflect.DeepEqual(oldTnt.Spec.NodeSelector, tnt.Spec.NodeSelector) {
+			paths = append(paths, spec.Child("nodeSelector"))
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,37 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestChangedImmutableFields(t *testing.T) {
+	oldTnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			NodeSelector: map[string]string{"pool": "oil"},
+		},
+	}
+
+	tnt := oldTnt.DeepCopy()
+	tnt.Spec.Owners = capsulev1beta1.OwnerListSpec{{Kind: capsulev1beta1.UserOwner, Name: "alice"}}
+	assert.Empty(t, changedImmutableFields(oldTnt, tnt))
+
+	tnt.Spec.NodeSelector["pool"] = "gas"
+	tnt.Spec.ForceTenantPrefix = pointer.BoolPtr(false)
+
+	paths := changedImmutableFields(oldTnt, tnt)
+	if assert.Len(t, paths, 2) {
+		assert.Equal(t, "spec.forceTenantPrefix", paths[0].String())
+		assert.Equal(t, "spec.nodeSelector", paths[1].String())
+	}
+
+	// an empty node selector is the same as a missing one
+	assert.Empty(t, changedImmutableFields(&capsulev1beta1.Tenant{}, &capsulev1beta1.Tenant{Spec: capsulev1beta1.TenantSpec{NodeSelector: map[string]string{}}}))
+}
@@ -0,0 +1,455 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type specHandler struct {
+}
+
+// SpecHandler validates the Tenant spec as a whole, rejecting invalid regular expressions, duplicated owners and peers,
+// allowed hostnames overlapping the ones of other Tenants outside of its hierarchy, CustomResourceDefinition groups
+// overlapping the ones of other Tenants, quotas lowered below the current usage, invalid object counts, and the None
+// DNS policy without nameservers.
+func SpecHandler() capsulewebhook.Handler {
+	return &specHandler{}
+}
+
+func (h *specHandler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request) *admission.Response {
+	tnt := &capsulev1beta1.Tenant{}
+	if err := decoder.Decode(req, tnt); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	var old *capsulev1beta1.Tenant
+
+	if len(req.OldObject.Raw) > 0 {
+		old = &capsulev1beta1.Tenant{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return utils.ErroredResponse(err)
+		}
+	}
+
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	others := make([]capsulev1beta1.Tenant, 0, len(tntList.Items))
+
+	for _, item := range tntList.Items {
+		if item.GetName() != tnt.GetName() {
+			others = append(others, item)
+		}
+	}
+
+	usage, err := quotaUsage(ctx, c, tnt)
+	if err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	errs := validateRegexes(tnt)
+	errs = append(errs, validateOwners(tnt)...)
+	errs = append(errs, validatePeers(tnt)...)
+	errs = append(errs, validatePersistentVolumeOptions(tnt)...)
+	errs = append(errs, validatePodOptions(tnt)...)
+	errs = append(errs, validateHostnames(tnt, old, unrelated(tnt, others))...)
+	errs = append(errs, validateCustomResourceGroups(tnt, others)...)
+	errs = append(errs, validateQuotas(tnt, old, usage)...)
+	errs = append(errs, validateObjectCounts(tnt)...)
+
+	if len(errs) > 0 {
+		return utils.InvalidResponse(capsulev1beta1.GroupVersion.WithKind("Tenant").GroupKind(), tnt.GetName(), errs)
+	}
+
+	return nil
+}
+
+func (h *specHandler) OnCreate(c client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, req)
+	}
+}
+
+func (h *specHandler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *specHandler) OnUpdate(c client.Client, decoder *admission.Decoder, _ record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, req)
+	}
+}
+
+// quotaUsage returns the usage of each Tenant scoped ResourceQuota item, summed across the Tenant Namespaces.
+func quotaUsage(ctx context.Context, c client.Client, tnt *capsulev1beta1.Tenant) (map[int]corev1.ResourceList, error) {
+	if tnt.Spec.ResourceQuota.Scope != capsulev1beta1.ResourceQuotaScopeTenant || len(tnt.Status.Namespaces) == 0 {
+		return nil, nil
+	}
+
+	tenantLabel, err := capsulev1beta1.GetTypeLabel(&capsulev1beta1.Tenant{})
+	if err != nil {
+		return nil, err
+	}
+
+	typeLabel, err := capsulev1beta1.GetTypeLabel(&corev1.ResourceQuota{})
+	if err != nil {
+		return nil, err
+	}
+
+	rqList := &corev1.ResourceQuotaList{}
+	if err = c.List(ctx, rqList, client.MatchingLabels{tenantLabel: tnt.GetName()}); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[int]corev1.ResourceList)
+
+	for _, rq := range rqList.Items {
+		index, err := strconv.Atoi(rq.GetLabels()[typeLabel])
+		if err != nil {
+			continue
+		}
+
+		if _, ok := usage[index]; !ok {
+			usage[index] = corev1.ResourceList{}
+		}
+
+		for name, used := range rq.Status.Used {
+			quantity := usage[index][name]
+			quantity.Add(used)
+			usage[index][name] = quantity
+		}
+	}
+
+	return usage, nil
+}
+
+func validateRegexes(tnt *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	spec := field.NewPath("spec")
+
+	check := func(path *field.Path, regex string) {
+		if len(regex) == 0 {
+			return
+		}
+
+		if _, err := regexp.Compile(regex); err != nil {
+			errs = append(errs, field.Invalid(path, regex, err.Error()))
+		}
+	}
+
+	allowedLists := map[*field.Path]*capsulev1beta1.AllowedListSpec{
+		spec.Child("ingressOptions", "allowedClasses"):   tnt.Spec.IngressOptions.AllowedClasses,
+		spec.Child("ingressOptions", "allowedHostnames"): tnt.Spec.IngressOptions.AllowedHostnames,
+		spec.Child("storageClasses"):                     tnt.Spec.StorageClasses,
+		spec.Child("containerRegistries"):                tnt.Spec.ContainerRegistries,
+		spec.Child("priorityClasses"):                    tnt.Spec.PriorityClasses,
+	}
+
+	for path, list := range allowedLists {
+		if list != nil {
+			check(path.Child("allowedRegex"), list.Regex)
+		}
+	}
+
+	if opts := tnt.Spec.CronJobOptions; opts != nil && opts.ForbiddenSchedules != nil {
+		check(spec.Child("cronJobOptions", "forbiddenSchedules", "deniedRegex"), opts.ForbiddenSchedules.Regex)
+	}
+
+	sortErrors(errs)
+
+	return errs
+}
+
+func validateOwners(tnt *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	seen := make(map[capsulev1beta1.OwnerKind]map[string]struct{})
+
+	for i, owner := range tnt.Spec.Owners {
+		if _, ok := seen[owner.Kind]; !ok {
+			seen[owner.Kind] = make(map[string]struct{})
+		}
+
+		if _, ok := seen[owner.Kind][owner.Name]; ok {
+			errs = append(errs, field.Duplicate(field.NewPath("spec", "owners").Index(i), owner.Kind.String()+"/"+owner.Name))
+
+			continue
+		}
+
+		seen[owner.Kind][owner.Name] = struct{}{}
+	}
+
+	return errs
+}
+
+// validatePeers rejects the Tenant declaring itself as a peer, or the same peer more than once.
+func validatePeers(tnt *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	seen := make(map[string]struct{})
+
+	for i, peer := range tnt.Spec.Peers {
+		path := field.NewPath("spec", "peers").Index(i).Child("tenant")
+
+		if peer.Tenant == tnt.GetName() {
+			errs = append(errs, field.Invalid(path, peer.Tenant, "the Tenant cannot peer with itself"))
+
+			continue
+		}
+
+		if _, ok := seen[peer.Tenant]; ok {
+			errs = append(errs, field.Duplicate(path, peer.Tenant))
+
+			continue
+		}
+
+		seen[peer.Tenant] = struct{}{}
+	}
+
+	return errs
+}
+
+// validatePersistentVolumeOptions rejects the default StorageClass not allowed by the Tenant itself.
+func validatePersistentVolumeOptions(tnt *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	options, classes := tnt.Spec.PersistentVolumeOptions, tnt.Spec.StorageClasses
+	if options == nil || len(options.DefaultStorageClass) == 0 || classes == nil {
+		return nil
+	}
+
+	if !classes.ExactMatch(options.DefaultStorageClass) && !classes.RegexMatch(options.DefaultStorageClass) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "persistentVolumeOptions", "defaultStorageClass"), options.DefaultStorageClass, "must be one of the allowed StorageClasses"))
+	}
+
+	return errs
+}
+
+// validatePodOptions rejects the None DNS policy without the nameservers of the DNS config, since the Pods not
+// declaring their own DNS config would be rejected by the API server validation.
+func validatePodOptions(tnt *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	options := tnt.Spec.PodOptions
+	if options == nil || options.DNSPolicy != corev1.DNSNone {
+		return nil
+	}
+
+	if options.DNSConfig == nil || len(options.DNSConfig.Nameservers) == 0 {
+		errs = append(errs, field.Required(field.NewPath("spec", "podOptions", "dnsConfig", "nameservers"), "must be set with the None DNS policy"))
+	}
+
+	return errs
+}
+
+// validateHostnames rejects the allowed hostnames overlapping the ones of other Tenants: the exact hostnames
+// allowed to, or matching the regular expression of, another Tenant, or the same regular expression.
+// The check runs only upon changes, not to block the Tenants overlapping since before.
+func validateHostnames(tnt, old *capsulev1beta1.Tenant, others []capsulev1beta1.Tenant) (errs field.ErrorList) {
+	hostnames := tnt.Spec.IngressOptions.AllowedHostnames
+	if hostnames == nil {
+		return nil
+	}
+
+	if old != nil && old.Spec.IngressOptions.AllowedHostnames != nil && equalAllowedLists(*hostnames, *old.Spec.IngressOptions.AllowedHostnames) {
+		return nil
+	}
+
+	path := field.NewPath("spec", "ingressOptions", "allowedHostnames")
+
+	var regex *regexp.Regexp
+	if len(hostnames.Regex) > 0 {
+		regex, _ = regexp.Compile(hostnames.Regex)
+	}
+
+	for _, other := range others {
+		otherHostnames := other.Spec.IngressOptions.AllowedHostnames
+		if otherHostnames == nil {
+			continue
+		}
+
+		for i, hostname := range hostnames.Exact {
+			if otherHostnames.ExactMatch(hostname) || otherHostnames.RegexMatch(hostname) {
+				errs = append(errs, field.Invalid(path.Child("allowed").Index(i), hostname, "overlaps with the allowed hostnames of the Tenant "+other.GetName()))
+			}
+		}
+
+		if len(hostnames.Regex) == 0 {
+			continue
+		}
+
+		if hostnames.Regex == otherHostnames.Regex {
+			errs = append(errs, field.Invalid(path.Child("allowedRegex"), hostnames.Regex, "is the same of the Tenant "+other.GetName()))
+
+			continue
+		}
+
+		if regex == nil {
+			continue
+		}
+
+		for _, hostname := range otherHostnames.Exact {
+			if regex.MatchString(hostname) {
+				errs = append(errs, field.Invalid(path.Child("allowedRegex"), hostnames.Regex, "matches the hostname "+hostname+" allowed to the Tenant "+other.GetName()))
+
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// reservedGroups are the API groups, along with their subdomains, the Tenant owners cannot install
+// CustomResourceDefinitions for.
+var reservedGroups = []string{"k8s.io", "kubernetes.io", "x-k8s.io", capsulev1beta1.GroupVersion.Group}
+
+// validateCustomResourceGroups rejects the invalid or reserved CustomResourceDefinition groups, and the ones
+// overlapping the groups of other Tenants, so that each group is owned by a single Tenant.
+func validateCustomResourceGroups(tnt *capsulev1beta1.Tenant, others []capsulev1beta1.Tenant) (errs field.ErrorList) {
+	crds := tnt.Spec.CustomResourceDefinitions
+	if crds == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "customResourceDefinitions", "allowedGroups")
+
+	for i, group := range crds.AllowedGroups {
+		if msgs := validation.IsDNS1123Subdomain(group); len(msgs) > 0 || !strings.Contains(group, ".") {
+			errs = append(errs, field.Invalid(path.Index(i), group, "must be a fully qualified domain name"))
+
+			continue
+		}
+
+		for _, reserved := range reservedGroups {
+			if capsulev1beta1.GroupOverlaps(group, reserved) {
+				errs = append(errs, field.Invalid(path.Index(i), group, "overlaps with the reserved group "+reserved))
+			}
+		}
+
+		for _, other := range others {
+			if other.Spec.CustomResourceDefinitions == nil {
+				continue
+			}
+
+			for _, otherGroup := range other.Spec.CustomResourceDefinitions.AllowedGroups {
+				if capsulev1beta1.GroupOverlaps(group, otherGroup) {
+					errs = append(errs, field.Invalid(path.Index(i), group, "overlaps with the allowed groups of the Tenant "+other.GetName()))
+
+					break
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateQuotas rejects the Namespace quota, and the hard quota of the Tenant scoped ResourceQuota items,
+// lowered below the current usage: the check runs only upon changes, not to block the Tenants already over quota.
+func validateQuotas(tnt, old *capsulev1beta1.Tenant, usage map[int]corev1.ResourceList) (errs field.ErrorList) {
+	if old == nil {
+		return nil
+	}
+
+	if opts := tnt.Spec.NamespaceOptions; opts != nil && opts.Quota != nil {
+		changed := old.Spec.NamespaceOptions == nil || old.Spec.NamespaceOptions.Quota == nil || *old.Spec.NamespaceOptions.Quota != *opts.Quota
+
+		if changed && uint(*opts.Quota) < tnt.Status.Size {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "namespaceOptions", "quota"), *opts.Quota, "is lower than the "+strconv.Itoa(int(tnt.Status.Size))+" Namespaces of the Tenant"))
+		}
+	}
+
+	if tnt.Spec.ResourceQuota.Scope != capsulev1beta1.ResourceQuotaScopeTenant {
+		return errs
+	}
+
+	path := field.NewPath("spec", "resourceQuotas", "items")
+
+	for i, item := range tnt.Spec.ResourceQuota.Items {
+		var oldHard corev1.ResourceList
+		if i < len(old.Spec.ResourceQuota.Items) {
+			oldHard = old.Spec.ResourceQuota.Items[i].Hard
+		}
+
+		for _, name := range sortedResourceNames(item.Hard) {
+			hard := item.Hard[name]
+
+			if previous, ok := oldHard[name]; ok && previous.Cmp(hard) == 0 {
+				continue
+			}
+
+			used, ok := usage[i][name]
+			if ok && hard.Cmp(used) < 0 {
+				errs = append(errs, field.Invalid(path.Index(i).Child("hard").Key(name.String()), hard.String(), "is lower than the current usage "+used.String()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateObjectCounts rejects the object counts not in the count/<resource>.<group> form, or not whole numbers.
+func validateObjectCounts(tnt *capsulev1beta1.Tenant) (errs field.ErrorList) {
+	path := field.NewPath("spec", "resourceQuotas", "objectCounts")
+
+	for _, name := range sortedResourceNames(tnt.Spec.ResourceQuota.ObjectCounts) {
+		if resource := strings.TrimPrefix(name.String(), "count/"); resource == name.String() || len(resource) == 0 || strings.HasPrefix(resource, ".") {
+			errs = append(errs, field.Invalid(path.Key(name.String()), name.String(), "must be in the count/<resource>.<group> form"))
+
+			continue
+		}
+
+		count := tnt.Spec.ResourceQuota.ObjectCounts[name]
+		if count.Sign() < 0 || count.MilliValue()%1000 != 0 {
+			errs = append(errs, field.Invalid(path.Key(name.String()), count.String(), "must be a non-negative whole number"))
+		}
+	}
+
+	return errs
+}
+
+func equalAllowedLists(a, b capsulev1beta1.AllowedListSpec) bool {
+	if a.Regex != b.Regex || len(a.Exact) != len(b.Exact) {
+		return false
+	}
+
+	for i := range a.Exact {
+		if a.Exact[i] != b.Exact[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func sortErrors(errs field.ErrorList) {
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+}
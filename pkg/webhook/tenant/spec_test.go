@@ -0,0 +1,230 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestValidateRegexes(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			StorageClasses:      &capsulev1beta1.AllowedListSpec{Regex: "^ceph-.*$"},
+			ContainerRegistries: &capsulev1beta1.AllowedListSpec{Regex: "(docker.io"},
+			PriorityClasses:     &capsulev1beta1.AllowedListSpec{Regex: "[a-z"},
+		},
+	}
+
+	errs := validateRegexes(tnt)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "spec.containerRegistries.allowedRegex", errs[0].Field)
+		assert.Equal(t, "spec.priorityClasses.allowedRegex", errs[1].Field)
+	}
+}
+
+func TestValidateOwners(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			Owners: capsulev1beta1.OwnerListSpec{
+				{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+				{Kind: capsulev1beta1.GroupOwner, Name: "alice"},
+				{Kind: capsulev1beta1.UserOwner, Name: "alice"},
+			},
+		},
+	}
+
+	errs := validateOwners(tnt)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "spec.owners[2]", errs[0].Field)
+	}
+}
+
+func TestValidatePeers(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "oil"},
+		Spec: capsulev1beta1.TenantSpec{
+			Peers: []capsulev1beta1.TenantPeerSpec{{Tenant: "gas"}, {Tenant: "oil"}, {Tenant: "gas"}},
+		},
+	}
+
+	errs := validatePeers(tnt)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "spec.peers[1].tenant", errs[0].Field)
+		assert.Equal(t, "spec.peers[2].tenant", errs[1].Field)
+	}
+}
+
+func TestValidatePersistentVolumeOptions(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			StorageClasses:          &capsulev1beta1.AllowedListSpec{Exact: []string{"ceph-rbd"}, Regex: "^local-.*$"},
+			PersistentVolumeOptions: &capsulev1beta1.PersistentVolumeOptions{DefaultStorageClass: "local-path"},
+		},
+	}
+	assert.Empty(t, validatePersistentVolumeOptions(tnt))
+
+	tnt.Spec.PersistentVolumeOptions.DefaultStorageClass = "nfs"
+	errs := validatePersistentVolumeOptions(tnt)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "spec.persistentVolumeOptions.defaultStorageClass", errs[0].Field)
+	}
+
+	tnt.Spec.StorageClasses = nil
+	assert.Empty(t, validatePersistentVolumeOptions(tnt))
+}
+
+func TestValidatePodOptions(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			PodOptions: &capsulev1beta1.PodOptions{DNSPolicy: corev1.DNSNone},
+		},
+	}
+
+	errs := validatePodOptions(tnt)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "spec.podOptions.dnsConfig.nameservers", errs[0].Field)
+	}
+
+	tnt.Spec.PodOptions.DNSConfig = &corev1.PodDNSConfig{Searches: []string{"acme.corp"}}
+	assert.Len(t, validatePodOptions(tnt), 1)
+
+	tnt.Spec.PodOptions.DNSConfig.Nameservers = []string{"10.10.0.53"}
+	assert.Empty(t, validatePodOptions(tnt))
+
+	tnt.Spec.PodOptions = &capsulev1beta1.PodOptions{DNSPolicy: corev1.DNSDefault}
+	assert.Empty(t, validatePodOptions(tnt))
+}
+
+func TestValidateHostnames(t *testing.T) {
+	tenant := func(name string, exact []string, regex string) capsulev1beta1.Tenant {
+		return capsulev1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: capsulev1beta1.TenantSpec{
+				IngressOptions: capsulev1beta1.IngressOptions{
+					AllowedHostnames: &capsulev1beta1.AllowedListSpec{Exact: exact, Regex: regex},
+				},
+			},
+		}
+	}
+
+	others := []capsulev1beta1.Tenant{
+		tenant("gas", []string{"gas.bigorg.com"}, `.*\.gas\.bigorg\.com`),
+	}
+
+	for name, tc := range map[string]struct {
+		tenant capsulev1beta1.Tenant
+		fields []string
+	}{
+		"disjoint":         {tenant: tenant("oil", []string{"oil.bigorg.com"}, `.*\.oil\.bigorg\.com`)},
+		"same exact":       {tenant: tenant("oil", []string{"oil.bigorg.com", "gas.bigorg.com"}, ""), fields: []string{"spec.ingressOptions.allowedHostnames.allowed[1]"}},
+		"exact matching":   {tenant: tenant("oil", []string{"www.gas.bigorg.com"}, ""), fields: []string{"spec.ingressOptions.allowedHostnames.allowed[0]"}},
+		"regex matching":   {tenant: tenant("oil", nil, `.*\.bigorg\.com`), fields: []string{"spec.ingressOptions.allowedHostnames.allowedRegex"}},
+		"same regex":       {tenant: tenant("oil", nil, `.*\.gas\.bigorg\.com`), fields: []string{"spec.ingressOptions.allowedHostnames.allowedRegex"}},
+		"unrelated tenant": {tenant: capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tnt := tc.tenant
+
+			var fields []string
+			for _, err := range validateHostnames(&tnt, nil, others) {
+				fields = append(fields, err.Field)
+			}
+
+			assert.Equal(t, tc.fields, fields)
+			// the unchanged hostnames are not validated again
+			assert.Empty(t, validateHostnames(&tnt, tnt.DeepCopy(), others))
+		})
+	}
+}
+
+func TestValidateCustomResourceGroups(t *testing.T) {
+	tenant := func(name string, groups ...string) capsulev1beta1.Tenant {
+		return capsulev1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: capsulev1beta1.TenantSpec{
+				CustomResourceDefinitions: &capsulev1beta1.CustomResourceDefinitionsSpec{AllowedGroups: groups},
+			},
+		}
+	}
+
+	others := []capsulev1beta1.Tenant{tenant("gas", "gas.bigorg.com")}
+
+	tnt := tenant("oil", "oil.bigorg.com", "bigorg.com", "ci.gas.bigorg.com", "apps.k8s.io", "capsule.clastix.io", "oil", "Oil.io")
+
+	var fields []string
+	for _, err := range validateCustomResourceGroups(&tnt, others) {
+		fields = append(fields, err.Field)
+	}
+
+	assert.Equal(t, []string{
+		"spec.customResourceDefinitions.allowedGroups[1]",
+		"spec.customResourceDefinitions.allowedGroups[2]",
+		"spec.customResourceDefinitions.allowedGroups[3]",
+		"spec.customResourceDefinitions.allowedGroups[4]",
+		"spec.customResourceDefinitions.allowedGroups[5]",
+		"spec.customResourceDefinitions.allowedGroups[6]",
+	}, fields)
+}
+
+func TestValidateQuotas(t *testing.T) {
+	quota := func(namespaces int32, pods string) *capsulev1beta1.Tenant {
+		return &capsulev1beta1.Tenant{
+			Spec: capsulev1beta1.TenantSpec{
+				NamespaceOptions: &capsulev1beta1.NamespaceOptions{Quota: &namespaces},
+				ResourceQuota: capsulev1beta1.ResourceQuotaSpec{
+					Scope: capsulev1beta1.ResourceQuotaScopeTenant,
+					Items: []corev1.ResourceQuotaSpec{
+						{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(pods)}},
+					},
+				},
+			},
+			Status: capsulev1beta1.TenantStatus{Size: 3},
+		}
+	}
+	usage := map[int]corev1.ResourceList{
+		0: {corev1.ResourcePods: resource.MustParse("8")},
+	}
+
+	assert.Empty(t, validateQuotas(quota(3, "8"), nil, usage))
+	assert.Empty(t, validateQuotas(quota(4, "10"), quota(5, "20"), usage))
+	// the Tenants already over quota are not blocked upon unrelated changes
+	assert.Empty(t, validateQuotas(quota(2, "5"), quota(2, "5"), usage))
+
+	errs := validateQuotas(quota(2, "5"), quota(5, "20"), usage)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "spec.namespaceOptions.quota", errs[0].Field)
+		assert.Equal(t, "spec.resourceQuotas.items[0].hard[pods]", errs[1].Field)
+	}
+}
+
+func TestValidateObjectCounts(t *testing.T) {
+	tnt := &capsulev1beta1.Tenant{
+		Spec: capsulev1beta1.TenantSpec{
+			ResourceQuota: capsulev1beta1.ResourceQuotaSpec{
+				ObjectCounts: corev1.ResourceList{
+					"count/deployments.apps": resource.MustParse("20"),
+					"count/secrets":          resource.MustParse("50"),
+				},
+			},
+		},
+	}
+
+	assert.Empty(t, validateObjectCounts(tnt))
+
+	tnt.Spec.ResourceQuota.ObjectCounts["services"] = resource.MustParse("10")
+	tnt.Spec.ResourceQuota.ObjectCounts["count/configmaps"] = resource.MustParse("500m")
+
+	errs := validateObjectCounts(tnt)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "spec.resourceQuotas.objectCounts[count/configmaps]", errs[0].Field)
+		assert.Equal(t, "spec.resourceQuotas.objectCounts[services]", errs[1].Field)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenantrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct {
+	configuration configuration.Configuration
+}
+
+// Handler defaults the owners of the TenantRequests to the requester, and lets only the approvers set or remove the
+// approval label once created: the TenantRequests cannot be created approved, nor changed once approved.
+func Handler(configuration configuration.Configuration) capsulewebhook.Handler {
+	return &handler{
+		configuration: configuration,
+	}
+}
+
+func (h *handler) OnCreate(_ client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		tntReq := &capsulev1beta1.TenantRequest{}
+		if err := decoder.Decode(req, tntReq); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		// the approval is a separate step, even for the approvers requesting a Tenant
+		if tntReq.IsApproved() {
+			recorder.Eventf(tntReq, corev1.EventTypeWarning, "TenantRequestApprovalForbidden", "User %s cannot create an approved TenantRequest", req.UserInfo.Username)
+
+			response := admission.Denied(fmt.Sprintf("The TenantRequests cannot be created with the %s label, set by the approvers once created", capsulev1beta1.TenantRequestApprovedLabel))
+
+			return &response
+		}
+
+		if len(tntReq.Spec.Owners) > 0 {
+			return nil
+		}
+
+		original, err := json.Marshal(tntReq)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tntReq.Spec.Owners = capsulev1beta1.OwnerListSpec{requester(req.UserInfo)}
+
+		mutated, err := json.Marshal(tntReq)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		response := admission.PatchResponseFromRaw(original, mutated)
+
+		return &response
+	}
+}
+
+func (h *handler) OnUpdate(_ client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		old := &capsulev1beta1.TenantRequest{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tntReq := &capsulev1beta1.TenantRequest{}
+		if err := decoder.Decode(req, tntReq); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if old.GetLabels()[capsulev1beta1.TenantRequestApprovedLabel] != tntReq.GetLabels()[capsulev1beta1.TenantRequestApprovedLabel] && !h.isApprover(req) {
+			return h.deny(tntReq, req, recorder)
+		}
+
+		if old.IsApproved() && !equality.Semantic.DeepEqual(old.Spec, tntReq.Spec) {
+			return utils.InvalidResponse(capsulev1beta1.GroupVersion.WithKind("TenantRequest").GroupKind(), tntReq.GetName(), field.ErrorList{
+				field.Forbidden(field.NewPath("spec"), "cannot be changed once the TenantRequest is approved"),
+			})
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *handler) deny(tntReq *capsulev1beta1.TenantRequest, req admission.Request, recorder record.EventRecorder) *admission.Response {
+	recorder.Eventf(tntReq, corev1.EventTypeWarning, "TenantRequestApprovalForbidden", "User %s cannot change the approval of the TenantRequest", req.UserInfo.Username)
+
+	response := admission.Denied(fmt.Sprintf("Only the approvers can change the %s label of the TenantRequests", capsulev1beta1.TenantRequestApprovedLabel))
+
+	return &response
+}
+
+// isApprover returns true for the members of the approver groups: none is an approver when no group is configured.
+func (h *handler) isApprover(req admission.Request) bool {
+	for _, group := range h.configuration.TenantRequestApproverGroups() {
+		for _, userGroup := range req.UserInfo.Groups {
+			if group == userGroup {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// requester returns the owner matching the user of the request, a ServiceAccount one for the ServiceAccount tokens.
+func requester(userInfo authenticationv1.UserInfo) capsulev1beta1.OwnerSpec {
+	if strings.HasPrefix(userInfo.Username, "system:serviceaccount:") {
+		return capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.ServiceAccountOwner, Name: userInfo.Username}
+	}
+
+	return capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: userInfo.Username}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package tenantrequest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+func TestRequester(t *testing.T) {
+	assert.Equal(t, capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.UserOwner, Name: "alice"}, requester(authenticationv1.UserInfo{Username: "alice"}))
+	assert.Equal(t, capsulev1beta1.OwnerSpec{Kind: capsulev1beta1.ServiceAccountOwner, Name: "system:serviceaccount:portal:onboarding"}, requester(authenticationv1.UserInfo{Username: "system:serviceaccount:portal:onboarding"}))
+}
+
+func TestIsApprover(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, capsulev1alpha1.AddToScheme(scheme))
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UserInfo: authenticationv1.UserInfo{Username: "bill", Groups: []string{"platform-admins", "system:authenticated"}},
+	}}
+
+	for groups, approver := range map[string]bool{"": false, "platform-admins": true, "security-admins": false} {
+		config := &capsulev1alpha1.CapsuleConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		if len(groups) > 0 {
+			config.Spec.TenantRequestApproverGroups = []string{groups}
+		}
+
+		h := &handler{configuration: configuration.NewCapsuleConfiguration(fake.NewClientBuilder().WithScheme(scheme).WithObjects(config).Build(), "default")}
+		assert.Equal(t, approver, h.isApprover(req), groups)
+	}
+}
@@ -6,6 +6,10 @@ package utils
 import (
 	"net/http"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -14,3 +18,15 @@ func ErroredResponse(err error) *admission.Response {
 
 	return &response
 }
+
+// InvalidResponse denies the request reporting the field-level errors of the given object, as the API server does.
+func InvalidResponse(kind schema.GroupKind, name string, errs field.ErrorList) *admission.Response {
+	status := apierrors.NewInvalid(kind, name, errs).ErrStatus
+
+	return &admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		},
+	}
+}
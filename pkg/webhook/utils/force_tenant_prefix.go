@@ -0,0 +1,19 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+// ForceTenantPrefix returns if the Namespaces of the Tenant must be prefixed by its name, as set by the Tenant
+// or, falling back, by the Capsule configuration.
+func ForceTenantPrefix(configuration configuration.Configuration, tnt *capsulev1beta1.Tenant) bool {
+	if tnt.Spec.ForceTenantPrefix != nil {
+		return *tnt.Spec.ForceTenantPrefix
+	}
+
+	return configuration.ForceTenantPrefix()
+}
@@ -0,0 +1,52 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// IsWildcardHostname returns true if the hostname matches any subdomain, as *.bigorg.com.
+func IsWildcardHostname(hostname string) bool {
+	return strings.HasPrefix(hostname, "*")
+}
+
+// IsApexHostname returns true if the hostname is a registrable domain, as bigorg.com or bigorg.co.uk,
+// according to the public suffix list.
+func IsApexHostname(hostname string) bool {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+
+	apex, err := publicsuffix.EffectiveTLDPlusOne(hostname)
+
+	return err == nil && apex == hostname
+}
+
+// DenyHostname returns a denial response if the hostname is a wildcard or a zone apex one, and they're not allowed
+// for the given Tenant.
+func DenyHostname(tnt *capsulev1beta1.Tenant, hostname string, req admission.Request, recorder record.EventRecorder) *admission.Response {
+	switch {
+	case tnt.IsWildcardDenied() && IsWildcardHostname(hostname):
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "Wildcard denied", "%s %s/%s cannot be %s", req.Kind.String(), req.Namespace, req.Name, strings.ToLower(string(req.Operation)))
+
+		response := admission.Denied(fmt.Sprintf("Wildcard denied for tenant %s\n", tnt.GetName()))
+
+		return &response
+	case tnt.IsApexDenied() && IsApexHostname(hostname):
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "ApexDenied", "%s %s/%s cannot use the zone apex hostname %s", req.Kind.String(), req.Namespace, req.Name, hostname)
+
+		response := admission.Denied(fmt.Sprintf("Zone apex hostname %s denied for tenant %s", hostname, tnt.GetName()))
+
+		return &response
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostname(t *testing.T) {
+	assert.True(t, IsWildcardHostname("*.bigorg.com"))
+	assert.False(t, IsWildcardHostname("oil.bigorg.com"))
+
+	for _, apex := range []string{"bigorg.com", "bigorg.co.uk", "BigOrg.com."} {
+		assert.True(t, IsApexHostname(apex), apex)
+	}
+
+	for _, host := range []string{"oil.bigorg.com", "oil.bigorg.co.uk", "*.bigorg.com", "com", "localhost"} {
+		assert.False(t, IsApexHostname(host), host)
+	}
+}
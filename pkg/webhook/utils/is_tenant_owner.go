@@ -7,20 +7,5 @@ import (
 )
 
 func IsTenantOwner(owners capsulev1beta1.OwnerListSpec, userInfo authenticationv1.UserInfo) bool {
-	for _, owner := range owners {
-		switch owner.Kind {
-		case "User", "ServiceAccount":
-			if userInfo.Username == owner.Name {
-				return true
-			}
-		case "Group":
-			for _, group := range userInfo.Groups {
-				if group == owner.Name {
-					return true
-				}
-			}
-		}
-	}
-
-	return false
+	return owners.IsOwner(userInfo)
 }
@@ -0,0 +1,29 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+// TenantForNamespace returns the Tenant the given Namespace belongs to, nil if none.
+func TenantForNamespace(ctx context.Context, c client.Client, namespace string) (*capsulev1beta1.Tenant, error) {
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", namespace),
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(tntList.Items) == 0 {
+		return nil, nil
+	}
+
+	return &tntList.Items[0], nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type warningsKey struct{}
+
+// warnings collects the warnings of the handlers serving an admission request.
+type warnings struct {
+	mu    sync.Mutex
+	items []string
+}
+
+// withWarnings returns the context collecting the warnings added by the handlers with Warn.
+func withWarnings(ctx context.Context) (context.Context, *warnings) {
+	w := &warnings{}
+
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// Warn adds a warning to the response of the admission request served with the given context: the warnings are
+// displayed by kubectl along with the decision, reporting the allowed but suspicious requests, as the ones getting
+// close to the Tenant limits. The warnings added out of an admission request are ignored.
+func Warn(ctx context.Context, format string, args ...interface{}) {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, item := range w.items {
+		if item == message {
+			return
+		}
+	}
+
+	w.items = append(w.items, message)
+}
+
+// List returns the collected warnings, in the order they've been added.
+func (w *warnings) List() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.items...)
+}
@@ -0,0 +1,24 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarn(t *testing.T) {
+	// ignored out of an admission request
+	Warn(context.Background(), "Namespace %s takes the last slot of the quota", "oil-test")
+
+	ctx, w := withWarnings(context.Background())
+
+	Warn(ctx, "Namespace %s takes the last slot of the quota", "oil-test")
+	Warn(ctx, "Ingress class annotation is deprecated")
+	Warn(ctx, "Namespace %s takes the last slot of the quota", "oil-test")
+
+	assert.Equal(t, []string{"Namespace oil-test takes the last slot of the quota", "Ingress class annotation is deprecated"}, w.List())
+}
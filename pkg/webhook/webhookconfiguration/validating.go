@@ -0,0 +1,140 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type handler struct{}
+
+// Handler admits the TenantWebhookConfiguration resources of the Tenants allowing them, declaring webhooks served
+// by the Services of their Namespace and intercepting the namespaced resources only.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return h.validate(ctx, c, decoder, recorder, req)
+	}
+}
+
+func (h *handler) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+func (h *handler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, recorder record.EventRecorder, req admission.Request) *admission.Response {
+	twc := &capsulev1beta1.TenantWebhookConfiguration{}
+	if err := decoder.Decode(req, twc); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	tntList := &capsulev1beta1.TenantList{}
+	if err := c.List(ctx, tntList, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return utils.ErroredResponse(err)
+	}
+
+	if len(tntList.Items) == 0 {
+		return nil
+	}
+
+	tnt := &tntList.Items[0]
+
+	if tnt.Spec.WebhookConfigurations == nil || !tnt.Spec.WebhookConfigurations.Allowed {
+		recorder.Eventf(tnt, corev1.EventTypeWarning, "ForbiddenWebhookConfiguration", "TenantWebhookConfiguration %s/%s is not allowed for the current Tenant", req.Namespace, req.Name)
+
+		response := admission.Denied("The current Tenant doesn't allow the registration of admission webhooks: please, reach out to the system administrators")
+
+		return &response
+	}
+
+	var existing []client.Object
+
+	for _, obj := range []client.Object{&admissionregistrationv1.ValidatingWebhookConfiguration{}, &admissionregistrationv1.MutatingWebhookConfiguration{}} {
+		if err := c.Get(ctx, types.NamespacedName{Name: twc.GeneratedName()}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return utils.ErroredResponse(err)
+		}
+
+		existing = append(existing, obj)
+	}
+
+	if errs := validateSpec(req.Namespace, twc, tnt, existing...); len(errs) > 0 {
+		return utils.InvalidResponse(capsulev1beta1.GroupVersion.WithKind("TenantWebhookConfiguration").GroupKind(), twc.GetName(), errs)
+	}
+
+	return nil
+}
+
+// validateSpec checks the webhooks are served by the Services of the given Namespace and intercept the namespaced
+// resources only, besides the given existing webhook configurations named as the generated ones are controlled by
+// the Tenant.
+func validateSpec(namespace string, twc *capsulev1beta1.TenantWebhookConfiguration, tnt *capsulev1beta1.Tenant, existing ...client.Object) (errs field.ErrorList) {
+	for _, obj := range existing {
+		if !metav1.IsControlledBy(obj, tnt) {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), twc.GetName(), fmt.Sprintf("the generated webhook configuration %s already exists and is not controlled by the Tenant", obj.GetName())))
+		}
+	}
+
+	spec := field.NewPath("spec")
+
+	for i, webhook := range twc.Spec.ValidatingWebhooks {
+		errs = append(errs, validateWebhook(spec.Child("validatingWebhooks").Index(i), namespace, webhook.ClientConfig, webhook.Rules)...)
+	}
+
+	for i, webhook := range twc.Spec.MutatingWebhooks {
+		errs = append(errs, validateWebhook(spec.Child("mutatingWebhooks").Index(i), namespace, webhook.ClientConfig, webhook.Rules)...)
+	}
+
+	return errs
+}
+
+func validateWebhook(path *field.Path, namespace string, clientConfig admissionregistrationv1.WebhookClientConfig, rules []admissionregistrationv1.RuleWithOperations) (errs field.ErrorList) {
+	switch {
+	case clientConfig.URL != nil:
+		errs = append(errs, field.Forbidden(path.Child("clientConfig", "url"), "the webhooks must be served by a Service of the Namespace"))
+	case clientConfig.Service == nil:
+		errs = append(errs, field.Required(path.Child("clientConfig", "service"), "the webhooks must be served by a Service of the Namespace"))
+	case len(clientConfig.Service.Namespace) > 0 && clientConfig.Service.Namespace != namespace:
+		errs = append(errs, field.Invalid(path.Child("clientConfig", "service", "namespace"), clientConfig.Service.Namespace, "the webhooks must be served by a Service of the Namespace"))
+	}
+
+	for i, rule := range rules {
+		if rule.Scope != nil && *rule.Scope == admissionregistrationv1.ClusterScope {
+			errs = append(errs, field.NotSupported(path.Child("rules").Index(i).Child("scope"), *rule.Scope, []string{string(admissionregistrationv1.NamespacedScope)}))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,61 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfiguration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestValidateSpec(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, capsulev1beta1.AddToScheme(scheme))
+
+	clusterScope := admissionregistrationv1.ClusterScope
+
+	twc := &capsulev1beta1.TenantWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator"},
+		Spec: capsulev1beta1.TenantWebhookConfigurationSpec{
+			ValidatingWebhooks: []admissionregistrationv1.ValidatingWebhook{
+				{ClientConfig: admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Name: "operator"}}},
+				{ClientConfig: admissionregistrationv1.WebhookClientConfig{URL: pointer.StringPtr("https://example.com")}},
+			},
+			MutatingWebhooks: []admissionregistrationv1.MutatingWebhook{
+				{
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Namespace: "gas-production", Name: "operator"}},
+					Rules:        []admissionregistrationv1.RuleWithOperations{{Rule: admissionregistrationv1.Rule{Scope: &clusterScope}}},
+				},
+			},
+		},
+	}
+
+	tnt := &capsulev1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "oil"}}
+
+	errs := validateSpec("oil-production", twc, tnt)
+	if assert.Len(t, errs, 3) {
+		assert.Equal(t, "spec.validatingWebhooks[1].clientConfig.url", errs[0].Field)
+		assert.Equal(t, "spec.mutatingWebhooks[0].clientConfig.service.namespace", errs[1].Field)
+		assert.Equal(t, "spec.mutatingWebhooks[0].rules[0].scope", errs[2].Field)
+	}
+
+	twc.Spec = capsulev1beta1.TenantWebhookConfigurationSpec{}
+	assert.Empty(t, validateSpec("oil-production", twc, tnt))
+
+	owned := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: twc.GeneratedName()}}
+	assert.NoError(t, controllerutil.SetControllerReference(tnt, owned, scheme))
+	assert.Empty(t, validateSpec("oil-production", twc, tnt, owned))
+
+	foreign := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: twc.GeneratedName()}}
+	if errs = validateSpec("oil-production", twc, tnt, owned, foreign); assert.Len(t, errs, 1) {
+		assert.Equal(t, "metadata.name", errs[0].Field)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/cronjob"
+	"github.com/clastix/capsule/pkg/webhook/customresourcedefinition"
+	"github.com/clastix/capsule/pkg/webhook/externalsecret"
+	"github.com/clastix/capsule/pkg/webhook/gateway"
+	"github.com/clastix/capsule/pkg/webhook/ingress"
+	"github.com/clastix/capsule/pkg/webhook/job"
+	"github.com/clastix/capsule/pkg/webhook/managed"
+	namespacewebhook "github.com/clastix/capsule/pkg/webhook/namespace"
+	"github.com/clastix/capsule/pkg/webhook/node"
+	"github.com/clastix/capsule/pkg/webhook/objectcounts"
+	"github.com/clastix/capsule/pkg/webhook/ownerreference"
+	"github.com/clastix/capsule/pkg/webhook/pod"
+	"github.com/clastix/capsule/pkg/webhook/pvc"
+	"github.com/clastix/capsule/pkg/webhook/route"
+	"github.com/clastix/capsule/pkg/webhook/secret"
+	"github.com/clastix/capsule/pkg/webhook/service"
+	"github.com/clastix/capsule/pkg/webhook/tenant"
+	"github.com/clastix/capsule/pkg/webhook/tenantrequest"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+	"github.com/clastix/capsule/pkg/webhook/webhookconfiguration"
+	"github.com/clastix/capsule/pkg/webhook/workload"
+)
+
+// List returns the Capsule webhooks along with their handlers, as served by the manager and the test environment:
+// the given reader retrieves the objects not cached, as the Secrets and the Jobs of the Tenant Namespaces.
+func List(cfg configuration.Configuration, kubeVersion *version.Version, reader client.Reader) []webhook.Webhook {
+	// the order matters, don't change it and just append
+	return append(
+		make([]webhook.Webhook, 0),
+		route.Pod(pod.ImagePullPolicy(), pod.ContainerRegistry(), pod.PriorityClass(), pod.EphemeralStorage(), pod.EphemeralContainers(), pod.Capacity(reader)),
+		route.Namespace(utils.InCapsuleGroups(cfg, namespacewebhook.QuotaHandler(), namespacewebhook.CreationRateHandler(), namespacewebhook.FreezeHandler(cfg), namespacewebhook.PrefixHandler(cfg), namespacewebhook.UserMetadataHandler())),
+		route.Ingress(ingress.Class(cfg), ingress.Hostnames(cfg), ingress.Collision(cfg), ingress.Wildcard(), ingress.Proximity()),
+		route.PVC(pvc.Handler(), pvc.PersistentVolumeReuseHandler()),
+		route.Service(service.Handler()),
+		route.Managed(utils.InCapsuleGroups(cfg, managed.Handler())),
+		route.Tenant(tenant.NameHandler(), tenant.RoleBindingRegexHandler(), tenant.SpecHandler(), tenant.ImmutableFieldsHandler(cfg), tenant.FreezedEmitter(), tenant.ServiceAccountNameHandler(), tenant.HierarchyHandler(cfg)),
+		route.OwnerReference(utils.InCapsuleGroups(cfg, ownerreference.Handler(cfg))),
+		route.Cordoning(tenant.CordoningHandler(cfg)),
+		route.Node(utils.InCapsuleGroups(cfg, node.UserMetadataHandler(cfg, kubeVersion)), node.PoolHandler(cfg, kubeVersion)),
+		route.CronJob(cronjob.Handler(reader)),
+		route.CronJobDefaults(cronjob.Defaults()),
+		route.Gateway(gateway.Hostnames()),
+		route.TenantDefaults(tenant.DefaultsHandler(cfg)),
+		route.JobDefaults(job.Defaults()),
+		route.TenantWebhookConfiguration(webhookconfiguration.Handler()),
+		route.CustomResourceDefinition(utils.InCapsuleGroups(cfg, customresourcedefinition.Handler())),
+		route.TenantRequest(tenantrequest.Handler(cfg)),
+		route.WorkloadLabels(workload.Labels(reader)),
+		route.Secret(secret.Handler(reader)),
+		route.ExternalSecret(externalsecret.Handler()),
+		route.PodDefaults(pod.Defaults()),
+		route.ServiceClusterIPs(service.ClusterIPHandler()),
+		route.StatefulSet(pvc.StatefulSetHandler()),
+		route.ObjectCounts(objectcounts.Handler()),
+	)
+}
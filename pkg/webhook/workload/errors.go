@@ -0,0 +1,27 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package workload
+
+import (
+	"fmt"
+	"strings"
+)
+
+type labelsMissing struct {
+	kind    string
+	path    string
+	missing []string
+}
+
+func NewMissingLabels(kind, path string, missing []string) error {
+	return &labelsMissing{
+		kind:    kind,
+		path:    path,
+		missing: missing,
+	}
+}
+
+func (m labelsMissing) Error() string {
+	return fmt.Sprintf("%s %s must declare the labels required by the current Tenant: %s", m.kind, m.path, strings.Join(m.missing, ", "))
+}
@@ -0,0 +1,240 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/utils"
+)
+
+type labels struct {
+	reader client.Reader
+}
+
+// Labels sets the default workload labels of the Tenant on the Deployments, StatefulSets and Pods created without
+// them, along with their Pod templates, and denies the ones missing the required labels. The Pods controlled by
+// an existing workload are checked through it, not to block the scaling of the workloads created before the
+// labels were required: the controllers are retrieved through the given reader, since they are not cached.
+// Upon updates, the removal of the required labels is denied.
+func Labels(reader client.Reader) capsulewebhook.Handler {
+	return &labels{reader: reader}
+}
+
+// workloadKinds are the kinds of the controllers whose Pods are checked through them.
+var workloadKinds = map[schema.GroupKind]struct{}{
+	{Group: "apps", Kind: "ReplicaSet"}:        {},
+	{Group: "apps", Kind: "StatefulSet"}:       {},
+	{Group: "apps", Kind: "DaemonSet"}:         {},
+	{Group: "batch", Kind: "Job"}:              {},
+	{Group: "", Kind: "ReplicationController"}: {},
+}
+
+func (h *labels) OnCreate(c client.Client, _ *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tnt, err := utils.TenantForNamespace(ctx, c, req.Namespace)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if tnt == nil || tnt.Spec.WorkloadLabels == nil {
+			return nil
+		}
+
+		spec := tnt.Spec.WorkloadLabels
+
+		original, err := json.Marshal(obj)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		controlled, err := h.controlledByWorkload(ctx, obj)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		changed := false
+
+		for _, path := range labelPaths(obj) {
+			current, _, _ := unstructured.NestedStringMap(obj.Object, path...)
+			if current == nil {
+				current = map[string]string{}
+			}
+
+			if applyDefaults(current, spec, tnt.GetName(), req.Namespace) {
+				if err = unstructured.SetNestedStringMap(obj.Object, current, path...); err != nil {
+					return utils.ErroredResponse(err)
+				}
+
+				changed = true
+			}
+
+			if controlled {
+				continue
+			}
+
+			if missing := missingLabels(current, spec.Required); len(missing) > 0 {
+				recorder.Eventf(tnt, corev1.EventTypeWarning, "MissingWorkloadLabels", "%s %s/%s is missing the required labels %s", obj.GetKind(), req.Namespace, obj.GetName(), strings.Join(missing, ", "))
+
+				response := admission.Denied(NewMissingLabels(obj.GetKind(), strings.Join(path, "."), missing).Error())
+
+				return &response
+			}
+		}
+
+		if !changed {
+			return nil
+		}
+
+		mutated, err := json.Marshal(obj)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		response := admission.PatchResponseFromRaw(original, mutated)
+
+		return &response
+	}
+}
+
+func (h *labels) OnUpdate(c client.Client, _ *admission.Decoder, recorder record.EventRecorder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) *admission.Response {
+		old, obj := &unstructured.Unstructured{}, &unstructured.Unstructured{}
+		if err := old.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		tnt, err := utils.TenantForNamespace(ctx, c, req.Namespace)
+		if err != nil {
+			return utils.ErroredResponse(err)
+		}
+
+		if tnt == nil || tnt.Spec.WorkloadLabels == nil {
+			return nil
+		}
+
+		for _, path := range labelPaths(obj) {
+			previous, _, _ := unstructured.NestedStringMap(old.Object, path...)
+			current, _, _ := unstructured.NestedStringMap(obj.Object, path...)
+
+			if removed := removedLabels(previous, current, tnt.Spec.WorkloadLabels.Required); len(removed) > 0 {
+				recorder.Eventf(tnt, corev1.EventTypeWarning, "MissingWorkloadLabels", "%s %s/%s cannot remove the required labels %s", obj.GetKind(), req.Namespace, obj.GetName(), strings.Join(removed, ", "))
+
+				response := admission.Denied(NewMissingLabels(obj.GetKind(), strings.Join(path, "."), removed).Error())
+
+				return &response
+			}
+		}
+
+		return nil
+	}
+}
+
+func (h *labels) OnDelete(client.Client, *admission.Decoder, record.EventRecorder) capsulewebhook.Func {
+	return func(context.Context, admission.Request) *admission.Response {
+		return nil
+	}
+}
+
+// controlledByWorkload reports if the object is controlled by an existing workload, matching the UID of the controller
+// reference, not to trust the references set by the users.
+func (h *labels) controlledByWorkload(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return false, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false, nil
+	}
+
+	if _, ok := workloadKinds[gv.WithKind(ref.Kind).GroupKind()]; !ok {
+		return false, nil
+	}
+
+	controller := &metav1.PartialObjectMetadata{}
+	controller.SetGroupVersionKind(gv.WithKind(ref.Kind))
+
+	if err = h.reader.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}, controller); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return controller.GetUID() == ref.UID, nil
+}
+
+// labelPaths returns the paths of the labels of the object and, for the workloads, of their Pod template.
+func labelPaths(obj *unstructured.Unstructured) [][]string {
+	paths := [][]string{{"metadata", "labels"}}
+
+	if obj.GetKind() != "Pod" {
+		paths = append(paths, []string{"spec", "template", "metadata", "labels"})
+	}
+
+	return paths
+}
+
+// applyDefaults sets the default labels missing from the given ones, reporting if any has been set.
+func applyDefaults(current map[string]string, spec *capsulev1beta1.WorkloadLabelsSpec, tenant, namespace string) (changed bool) {
+	for key := range spec.Defaults {
+		if _, ok := current[key]; ok {
+			continue
+		}
+
+		current[key] = spec.DefaultValue(key, tenant, namespace)
+		changed = true
+	}
+
+	return changed
+}
+
+func missingLabels(current map[string]string, required []string) (missing []string) {
+	for _, key := range required {
+		if _, ok := current[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+func removedLabels(previous, current map[string]string, required []string) (removed []string) {
+	for _, key := range missingLabels(current, required) {
+		if _, ok := previous[key]; ok {
+			removed = append(removed, key)
+		}
+	}
+
+	return removed
+}
@@ -0,0 +1,81 @@
+// Copyright 2020-2021 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1beta1 "github.com/clastix/capsule/api/v1beta1"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	spec := &capsulev1beta1.WorkloadLabelsSpec{
+		Required: []string{"cost-center"},
+		Defaults: map[string]string{
+			"app.kubernetes.io/owner": "$(tenant)",
+			"environment":             "$(namespace)",
+		},
+	}
+
+	current := map[string]string{"environment": "production"}
+	assert.True(t, applyDefaults(current, spec, "oil", "oil-staging"))
+	assert.Equal(t, map[string]string{"app.kubernetes.io/owner": "oil", "environment": "production"}, current)
+	assert.False(t, applyDefaults(current, spec, "oil", "oil-staging"))
+}
+
+func TestMissingLabels(t *testing.T) {
+	required := []string{"team", "cost-center"}
+
+	assert.Equal(t, []string{"cost-center", "team"}, missingLabels(map[string]string{}, required))
+	assert.Empty(t, missingLabels(map[string]string{"team": "a", "cost-center": "b"}, required))
+	// only the labels removed by the update are reported, not the ones missing since before
+	assert.Equal(t, []string{"team"}, removedLabels(map[string]string{"team": "a"}, map[string]string{}, required))
+}
+
+func TestControlledByWorkload(t *testing.T) {
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-6799fc88d8", Namespace: "oil-production", UID: "6799fc88d8"}}
+
+	h := &labels{reader: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(rs).Build()}
+
+	pod := func(refs ...metav1.OwnerReference) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace("oil-production")
+		obj.SetOwnerReferences(refs)
+
+		return obj
+	}
+	ref := func(apiVersion, kind, name, uid string) metav1.OwnerReference {
+		controller := true
+
+		return metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(uid), Controller: &controller}
+	}
+
+	for name, tc := range map[string]struct {
+		obj        *unstructured.Unstructured
+		controlled bool
+	}{
+		"uncontrolled":        {obj: pod()},
+		"existing workload":   {obj: pod(ref("apps/v1", "ReplicaSet", "nginx-6799fc88d8", "6799fc88d8")), controlled: true},
+		"mismatching uid":     {obj: pod(ref("apps/v1", "ReplicaSet", "nginx-6799fc88d8", "fake"))},
+		"missing workload":    {obj: pod(ref("apps/v1", "ReplicaSet", "nginx-fake", "6799fc88d8"))},
+		"not a workload kind": {obj: pod(ref("v1", "ConfigMap", "nginx-6799fc88d8", "6799fc88d8"))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			controlled, err := h.controlledByWorkload(context.Background(), tc.obj)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.controlled, controlled)
+		})
+	}
+}